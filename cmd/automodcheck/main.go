@@ -0,0 +1,113 @@
+package main
+
+import (
+	"flag"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+	"os"
+	"redditclone/internal/model"
+	"redditclone/internal/service"
+	"strings"
+	"time"
+)
+
+// sample is a post or a comment the rules are checked against, Expect lists the rules which must match it
+type sample struct {
+	Name       string   `yaml:"name"`
+	Target     string   `yaml:"target"`
+	Title      string   `yaml:"title"`
+	Body       string   `yaml:"body"`
+	URL        string   `yaml:"url"`
+	Community  string   `yaml:"community"`
+	AccountAge string   `yaml:"account_age"`
+	Karma      int      `yaml:"karma"`
+	Reports    int      `yaml:"reports"`
+	Edited     bool     `yaml:"edited"`
+	Expect     []string `yaml:"expect"`
+}
+
+type samplesFile struct {
+	Samples []sample `yaml:"samples"`
+}
+
+// subject makes the author as old as the account age of the sample, without it the age is unknown
+func (s sample) subject(now time.Time) (model.AutomodSubject, error) {
+	author := model.User{Credential: model.Credential{Username: "sample"}}
+	author.Karma.Post = s.Karma
+
+	if s.AccountAge != "" {
+		age, err := time.ParseDuration(s.AccountAge)
+		if err != nil {
+			return model.AutomodSubject{}, err
+		}
+		author.Created = now.Add(-age).Format("2006-01-02T15:04:05.000Z")
+	}
+
+	target := s.Target
+	if target == "" {
+		target = model.AutomodTargetPost
+	}
+
+	return model.AutomodSubject{
+		Target:    target,
+		Title:     s.Title,
+		Body:      s.Body,
+		URL:       s.URL,
+		Community: s.Community,
+		Author:    author,
+		Reports:   s.Reports,
+		Edited:    s.Edited,
+	}, nil
+}
+
+func main() {
+	rulesPath := flag.String("rules", "configs/automod.yml", "the automod rules file")
+	samplesPath := flag.String("samples", "configs/automod_samples.yml", "the samples to check the rules against")
+	flag.Parse()
+
+	automod, err := service.NewAutomod(*rulesPath)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	ymlFile, err := ioutil.ReadFile(*samplesPath)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	var file samplesFile
+	if err = yaml.Unmarshal(ymlFile, &file); err != nil {
+		logrus.Fatalln(err)
+	}
+
+	now := time.Now().UTC()
+	failed := 0
+	for _, s := range file.Samples {
+		subject, err := s.subject(now)
+		if err != nil {
+			logrus.Fatalf("automod check: sample %s: %s", s.Name, err)
+		}
+
+		matched := make([]string, 0)
+		for _, match := range automod.Evaluate(subject) {
+			name := match.Rule.Name
+			if match.DryRun {
+				name += " (dry run)"
+			}
+			logrus.Infof("automod check: %s: %s -> %s", s.Name, name, match.Rule.Action)
+			matched = append(matched, match.Rule.Name)
+		}
+
+		if strings.Join(matched, ",") != strings.Join(s.Expect, ",") {
+			logrus.Errorf("automod check: %s: expected [%s], matched [%s]",
+				s.Name, strings.Join(s.Expect, ", "), strings.Join(matched, ", "))
+			failed++
+		}
+	}
+
+	logrus.Infof("automod check: %d samples, %d failed", len(file.Samples), failed)
+	if failed != 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+	"os"
+	"redditclone/internal/app"
+	"redditclone/internal/model"
+)
+
+type Config struct {
+	app.MongoConfig `yaml:"mongo"`
+}
+
+// embeddedPost is the post document as it was stored before comments got their own collection
+type embeddedPost struct {
+	ID       string          `bson:"id"`
+	Comments []model.Comment `bson:"comments"`
+}
+
+// migratePost copies the embedded comments and drops them from the post only after all of them are stored,
+// so the command can be run again after a failure
+func migratePost(posts, comments *mongo.Collection, post embeddedPost) error {
+	for _, comment := range post.Comments {
+		comment.PostID = post.ID
+		filter := bson.M{"id": comment.ID}
+		opt := options.Replace().SetUpsert(true)
+		if _, err := comments.ReplaceOne(context.TODO(), filter, comment, opt); err != nil {
+			return err
+		}
+	}
+
+	filter := bson.M{"id": post.ID}
+	update := bson.M{
+		"$set":   bson.M{"commentCount": len(post.Comments)},
+		"$unset": bson.M{"comments": ""},
+	}
+	_, err := posts.UpdateOne(context.TODO(), filter, update)
+	return err
+}
+
+func main() {
+	ymlFile, err := ioutil.ReadFile("configs/config.yml")
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	var cfg Config
+	if err = yaml.Unmarshal(ymlFile, &cfg); err != nil {
+		logrus.Fatalln(err)
+	}
+
+	if err = godotenv.Load(".env"); err != nil {
+		logrus.Fatalln(err)
+	}
+
+	cfg.Host = os.Getenv("MONGO_HOST")
+	cfg.Port = os.Getenv("MONGO_PORT")
+	cfg.Username = os.Getenv("MONGO_INITDB_ROOT_USERNAME")
+	cfg.Password = os.Getenv("MONGO_INITDB_ROOT_PASSWORD")
+	cfg.DBName = os.Getenv("MONGO_DATABASE")
+
+	mongoURL := fmt.Sprintf("mongodb://%s:%s", cfg.Host, cfg.Port)
+	credential := options.Credential{
+		Username: cfg.Username,
+		Password: cfg.Password,
+	}
+	client, err := mongo.Connect(context.TODO(), options.Client().ApplyURI(mongoURL).SetAuth(credential))
+	if err != nil {
+		logrus.Fatalf("comments migration: mongo connect error: %s", err)
+	}
+	defer func() {
+		if err := client.Disconnect(context.TODO()); err != nil {
+			logrus.Errorln(err)
+		}
+	}()
+
+	posts := client.Database(cfg.DBName).Collection(cfg.CollectionName)
+	comments := client.Database(cfg.DBName).Collection(cfg.CommentsCollectionName)
+
+	cursor, err := posts.Find(context.TODO(), bson.M{"comments": bson.M{"$exists": true}})
+	if err != nil {
+		logrus.Fatalf("comments migration: find error: %s", err)
+	}
+	defer cursor.Close(context.TODO())
+
+	migrated := 0
+	for cursor.Next(context.TODO()) {
+		var post embeddedPost
+		if err = cursor.Decode(&post); err != nil {
+			logrus.Fatalf("comments migration: decode error: %s", err)
+		}
+
+		if err = migratePost(posts, comments, post); err != nil {
+			logrus.Fatalf("comments migration: post %s: %s", post.ID, err)
+		}
+		migrated++
+	}
+
+	if err = cursor.Err(); err != nil {
+		logrus.Fatalf("comments migration: cursor error: %s", err)
+	}
+
+	logrus.Infof("comments migration: %d posts migrated", migrated)
+}
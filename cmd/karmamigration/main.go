@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+	"os"
+	"redditclone/internal/app"
+	"redditclone/internal/model"
+)
+
+type Config struct {
+	app.MySQLConfig `yaml:"mysql"`
+	app.MongoConfig `yaml:"mongo"`
+}
+
+type authorScore struct {
+	AuthorID string `bson:"_id"`
+	Score    int    `bson:"score"`
+}
+
+// sumScores groups the scores by the author, the deleted comments have no author
+func sumScores(collection *mongo.Collection) (map[string]int, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"author.id": bson.M{"$nin": bson.A{"", nil}}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$author.id", "score": bson.M{"$sum": "$score"}}}},
+	}
+	cursor, err := collection.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	scores := make(map[string]int)
+	for cursor.Next(context.TODO()) {
+		var score authorScore
+		if err = cursor.Decode(&score); err != nil {
+			return nil, err
+		}
+		scores[score.AuthorID] = score.Score
+	}
+
+	return scores, cursor.Err()
+}
+
+// the karma is kept up to date on every vote, the command fills it from the scores stored before it existed,
+// so it has to run after the votes migration
+func main() {
+	ymlFile, err := ioutil.ReadFile("configs/config.yml")
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	var cfg Config
+	if err = yaml.Unmarshal(ymlFile, &cfg); err != nil {
+		logrus.Fatalln(err)
+	}
+
+	if err = godotenv.Load(".env"); err != nil {
+		logrus.Fatalln(err)
+	}
+
+	cfg.MySQLConfig.Host = os.Getenv("MYSQL_HOST")
+	cfg.MySQLConfig.Port = os.Getenv("MYSQL_PORT")
+	cfg.MySQLConfig.Username = os.Getenv("MYSQL_USER")
+	cfg.MySQLConfig.Password = os.Getenv("MYSQL_PASSWORD")
+	cfg.MySQLConfig.DBName = os.Getenv("MYSQL_DATABASE")
+
+	cfg.MongoConfig.Host = os.Getenv("MONGO_HOST")
+	cfg.MongoConfig.Port = os.Getenv("MONGO_PORT")
+	cfg.MongoConfig.Username = os.Getenv("MONGO_INITDB_ROOT_USERNAME")
+	cfg.MongoConfig.Password = os.Getenv("MONGO_INITDB_ROOT_PASSWORD")
+	cfg.MongoConfig.DBName = os.Getenv("MONGO_DATABASE")
+
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s",
+		cfg.MySQLConfig.Username,
+		cfg.MySQLConfig.Password,
+		cfg.MySQLConfig.Host,
+		cfg.MySQLConfig.Port,
+		cfg.MySQLConfig.DBName,
+	)
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		logrus.Fatalf("karma migration: mysql connect error: %s", err)
+	}
+	defer db.Close()
+
+	mongoURL := fmt.Sprintf("mongodb://%s:%s", cfg.MongoConfig.Host, cfg.MongoConfig.Port)
+	credential := options.Credential{
+		Username: cfg.MongoConfig.Username,
+		Password: cfg.MongoConfig.Password,
+	}
+	client, err := mongo.Connect(context.TODO(), options.Client().ApplyURI(mongoURL).SetAuth(credential))
+	if err != nil {
+		logrus.Fatalf("karma migration: mongo connect error: %s", err)
+	}
+	defer func() {
+		if err := client.Disconnect(context.TODO()); err != nil {
+			logrus.Errorln(err)
+		}
+	}()
+
+	posts := client.Database(cfg.MongoConfig.DBName).Collection(cfg.CollectionName)
+	comments := client.Database(cfg.MongoConfig.DBName).Collection(cfg.CommentsCollectionName)
+
+	postScores, err := sumScores(posts)
+	if err != nil {
+		logrus.Fatalf("karma migration: posts: %s", err)
+	}
+
+	commentScores, err := sumScores(comments)
+	if err != nil {
+		logrus.Fatalf("karma migration: comments: %s", err)
+	}
+
+	karma := make(map[string]model.Karma)
+	for userID, score := range postScores {
+		karma[userID] = model.Karma{Post: score}
+	}
+	for userID, score := range commentScores {
+		k := karma[userID]
+		k.Comment = score
+		karma[userID] = k
+	}
+
+	for userID, k := range karma {
+		_, err = db.Exec("UPDATE user SET post_karma = ?, comment_karma = ? WHERE id = ?", k.Post, k.Comment, userID)
+		if err != nil {
+			logrus.Fatalf("karma migration: user %s: %s", userID, err)
+		}
+	}
+
+	logrus.Infof("karma migration: %d users migrated", len(karma))
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+	"os"
+	"redditclone/internal/app"
+	"redditclone/internal/model"
+)
+
+type Config struct {
+	app.MongoConfig `yaml:"mongo"`
+}
+
+// the ranks are kept up to date on every vote, the command fills them for the posts stored before they existed
+func main() {
+	ymlFile, err := ioutil.ReadFile("configs/config.yml")
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	var cfg Config
+	if err = yaml.Unmarshal(ymlFile, &cfg); err != nil {
+		logrus.Fatalln(err)
+	}
+
+	if err = godotenv.Load(".env"); err != nil {
+		logrus.Fatalln(err)
+	}
+
+	cfg.Host = os.Getenv("MONGO_HOST")
+	cfg.Port = os.Getenv("MONGO_PORT")
+	cfg.Username = os.Getenv("MONGO_INITDB_ROOT_USERNAME")
+	cfg.Password = os.Getenv("MONGO_INITDB_ROOT_PASSWORD")
+	cfg.DBName = os.Getenv("MONGO_DATABASE")
+
+	mongoURL := fmt.Sprintf("mongodb://%s:%s", cfg.Host, cfg.Port)
+	credential := options.Credential{
+		Username: cfg.Username,
+		Password: cfg.Password,
+	}
+	client, err := mongo.Connect(context.TODO(), options.Client().ApplyURI(mongoURL).SetAuth(credential))
+	if err != nil {
+		logrus.Fatalf("ranks migration: mongo connect error: %s", err)
+	}
+	defer func() {
+		if err := client.Disconnect(context.TODO()); err != nil {
+			logrus.Errorln(err)
+		}
+	}()
+
+	posts := client.Database(cfg.DBName).Collection(cfg.CollectionName)
+
+	cursor, err := posts.Find(context.TODO(), bson.M{})
+	if err != nil {
+		logrus.Fatalf("ranks migration: find error: %s", err)
+	}
+	defer cursor.Close(context.TODO())
+
+	migrated := 0
+	for cursor.Next(context.TODO()) {
+		var post model.Post
+		if err = cursor.Decode(&post); err != nil {
+			logrus.Fatalf("ranks migration: decode error: %s", err)
+		}
+
+		post.RecalculateRanks()
+		filter := bson.M{"id": post.ID}
+		update := bson.M{"$set": bson.M{"hot": post.Ranks.Hot, "controversial": post.Ranks.Controversial}}
+		if _, err = posts.UpdateOne(context.TODO(), filter, update); err != nil {
+			logrus.Fatalf("ranks migration: post %s: %s", post.ID, err)
+		}
+		migrated++
+	}
+
+	if err = cursor.Err(); err != nil {
+		logrus.Fatalf("ranks migration: cursor error: %s", err)
+	}
+
+	logrus.Infof("ranks migration: %d posts migrated", migrated)
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+	"os"
+	"redditclone/internal/app"
+	"redditclone/internal/model"
+)
+
+type Config struct {
+	app.MongoConfig `yaml:"mongo"`
+}
+
+type embeddedVote struct {
+	UserID string `bson:"user"`
+	Vote   int    `bson:"vote"`
+}
+
+// votedDocument is a post or a comment as it was stored before the votes got their own collection
+type votedDocument struct {
+	ID      string         `bson:"id"`
+	PostID  string         `bson:"postId"`
+	Created string         `bson:"created"`
+	Votes   []embeddedVote `bson:"votes"`
+}
+
+// migrateVotes copies the embedded votes and replaces them with the counts only after all of them are stored,
+// so the command can be run again after a failure. The time of a vote is unknown, the creation time is used instead.
+func migrateVotes(collection, votes *mongo.Collection, doc votedDocument, isPost bool) error {
+	postID, commentID := doc.PostID, doc.ID
+	if isPost {
+		postID, commentID = doc.ID, ""
+	}
+
+	voting := model.NewVoting()
+	for _, vote := range doc.Votes {
+		filter := bson.M{"user": vote.UserID, "postId": postID, "commentId": commentID}
+		update := bson.M{"$set": bson.M{"vote": vote.Vote, "created": doc.Created}}
+		if _, err := votes.UpdateOne(context.TODO(), filter, update, options.Update().SetUpsert(true)); err != nil {
+			return err
+		}
+		voting.AddCounts(model.VoteDelta(model.VoteNone, vote.Vote))
+	}
+
+	set := bson.M{
+		"ups":              voting.Ups,
+		"downs":            voting.Downs,
+		"score":            voting.Score,
+		"upvotePercentage": voting.UpvotePercentage,
+	}
+	if isPost {
+		post := model.Post{Created: doc.Created, Voting: voting}
+		post.RecalculateRanks()
+		set["hot"] = post.Ranks.Hot
+		set["controversial"] = post.Ranks.Controversial
+	}
+
+	filter := bson.M{"id": doc.ID}
+	update := bson.M{"$set": set, "$unset": bson.M{"votes": ""}}
+	_, err := collection.UpdateOne(context.TODO(), filter, update)
+	return err
+}
+
+func migrateCollection(collection, votes *mongo.Collection, isPost bool) (int, error) {
+	cursor, err := collection.Find(context.TODO(), bson.M{"votes": bson.M{"$exists": true}})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(context.TODO())
+
+	migrated := 0
+	for cursor.Next(context.TODO()) {
+		var doc votedDocument
+		if err = cursor.Decode(&doc); err != nil {
+			return migrated, err
+		}
+
+		if err = migrateVotes(collection, votes, doc, isPost); err != nil {
+			return migrated, fmt.Errorf("%s: %s", doc.ID, err)
+		}
+		migrated++
+	}
+
+	return migrated, cursor.Err()
+}
+
+func main() {
+	ymlFile, err := ioutil.ReadFile("configs/config.yml")
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	var cfg Config
+	if err = yaml.Unmarshal(ymlFile, &cfg); err != nil {
+		logrus.Fatalln(err)
+	}
+
+	if err = godotenv.Load(".env"); err != nil {
+		logrus.Fatalln(err)
+	}
+
+	cfg.Host = os.Getenv("MONGO_HOST")
+	cfg.Port = os.Getenv("MONGO_PORT")
+	cfg.Username = os.Getenv("MONGO_INITDB_ROOT_USERNAME")
+	cfg.Password = os.Getenv("MONGO_INITDB_ROOT_PASSWORD")
+	cfg.DBName = os.Getenv("MONGO_DATABASE")
+
+	mongoURL := fmt.Sprintf("mongodb://%s:%s", cfg.Host, cfg.Port)
+	credential := options.Credential{
+		Username: cfg.Username,
+		Password: cfg.Password,
+	}
+	client, err := mongo.Connect(context.TODO(), options.Client().ApplyURI(mongoURL).SetAuth(credential))
+	if err != nil {
+		logrus.Fatalf("votes migration: mongo connect error: %s", err)
+	}
+	defer func() {
+		if err := client.Disconnect(context.TODO()); err != nil {
+			logrus.Errorln(err)
+		}
+	}()
+
+	posts := client.Database(cfg.DBName).Collection(cfg.CollectionName)
+	comments := client.Database(cfg.DBName).Collection(cfg.CommentsCollectionName)
+	votes := client.Database(cfg.DBName).Collection(cfg.VotesCollectionName)
+
+	migratedPosts, err := migrateCollection(posts, votes, true)
+	if err != nil {
+		logrus.Fatalf("votes migration: post %s", err)
+	}
+
+	migratedComments, err := migrateCollection(comments, votes, false)
+	if err != nil {
+		logrus.Fatalf("votes migration: comment %s", err)
+	}
+
+	logrus.Infof("votes migration: %d posts and %d comments migrated", migratedPosts, migratedComments)
+}
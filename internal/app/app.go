@@ -9,6 +9,8 @@ import (
 	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"io"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -16,9 +18,17 @@ import (
 	"redditclone/internal/repository/mongorepo"
 	"redditclone/internal/repository/mysqlrepo"
 	"redditclone/internal/service"
+	"redditclone/pkg/broker"
 	"redditclone/pkg/cookie"
+	"redditclone/pkg/lockout"
+	"redditclone/pkg/ratelimit"
+	"redditclone/pkg/session"
+	cookiesession "redditclone/pkg/session/cookie"
+	jwtsession "redditclone/pkg/session/jwt"
+	opaquesession "redditclone/pkg/session/opaque"
 	"redditclone/pkg/token"
 	"syscall"
+	"time"
 )
 
 func initMySQL(cfg MySQLConfig) (*sql.DB, error) {
@@ -68,6 +78,101 @@ func initMongoDB(cfg MongoConfig) (*mongo.Client, error) {
 	return client, nil
 }
 
+func initRedis(cfg RedisConfig) (*redis.Pool, error) {
+	redisAddress := fmt.Sprintf("%s:%s", cfg.Host, cfg.Port)
+	pool := &redis.Pool{
+		MaxIdle: cfg.MaxIdleConnections,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", redisAddress)
+		},
+	}
+	conn := pool.Get()
+	defer conn.Close()
+	if _, err := conn.Do("PING"); err != nil {
+		if err := pool.Close(); err != nil {
+			logrus.Errorln(err)
+		}
+		return nil, err
+	}
+	return pool, nil
+}
+
+func initPasswordHasher(cfg PasswordConfig) (service.PasswordHasher, error) {
+	switch cfg.Algorithm {
+	case "", "argon2id":
+		return service.NewArgon2idHasher(), nil
+	case "bcrypt":
+		return service.NewBcryptHasher(cfg.BcryptCost), nil
+	default:
+		return nil, fmt.Errorf("unknown password hashing algorithm: %s", cfg.Algorithm)
+	}
+}
+
+func initSessionManager(cfg SessionConfig, signerCfg SignerConfig, redisPool *redis.Pool) (session.Manager, error) {
+	switch cfg.Strategy {
+	case "jwt":
+		return jwtsession.NewManager(signerCfg.SigningKey, cfg.TTL), nil
+	case "cookie":
+		cookies := cookie.NewManager(cookie.NewRedisStorage(redisPool, "session", cfg.TTL))
+		return cookiesession.NewManager(cookies, cfg.TTL), nil
+	case "", "opaque-redis":
+		cookies := cookie.NewManager(cookie.NewRedisStorage(redisPool, "session", cfg.TTL))
+		signer := token.NewSigner(signerCfg.SigningKey, time.Duration(cfg.TTL)*time.Second)
+		return opaquesession.NewManager(signer, cookies), nil
+	default:
+		return nil, fmt.Errorf("unknown session strategy: %s", cfg.Strategy)
+	}
+}
+
+// initEventBroker picks the fan-out of the events, the memory broker serves only a single instance
+func initEventBroker(cfg EventsConfig, redisPool *redis.Pool) (service.EventBroker, error) {
+	switch cfg.Broker {
+	case "", "redis":
+		return broker.NewRedisBroker(redisPool, "events"), nil
+	case "memory":
+		return broker.NewMemoryBroker(), nil
+	default:
+		return nil, fmt.Errorf("unknown event broker: %s", cfg.Broker)
+	}
+}
+
+// initAutomod turns the automod off when no rules file is set, the rules are watched until stop is closed
+func initAutomod(cfg AutomodConfig, stop <-chan struct{}) (service.Automoderator, error) {
+	if cfg.RulesFile == "" {
+		return nil, nil
+	}
+
+	automod, err := service.NewAutomod(cfg.RulesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.ReloadInterval > 0 {
+		go automod.Watch(time.Duration(cfg.ReloadInterval)*time.Second, stop)
+	}
+
+	return automod, nil
+}
+
+func initRateLimitStore(cfg RateLimitConfig, redisPool *redis.Pool) (handler.RateLimitStore, error) {
+	switch cfg.Store {
+	case "", "redis":
+		return ratelimit.NewRedisStore(redisPool, "ratelimit"), nil
+	case "memory":
+		return ratelimit.NewMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit store: %s", cfg.Store)
+	}
+}
+
+func rateLimits(cfg RateLimitConfig) handler.RateLimits {
+	limits := make(handler.RateLimits)
+	for name, budget := range cfg.Limits {
+		limits[name] = ratelimit.Limit{Requests: budget.Requests, Window: time.Duration(budget.Window) * time.Second}
+	}
+	return limits
+}
+
 func Run(cfg Config) {
 	// init MySQL
 	db, err := initMySQL(cfg.MySQLConfig)
@@ -95,12 +200,19 @@ func Run(cfg Config) {
 	}()
 	logrus.Infoln("connected to mongo")
 	collection := client.Database(cfg.MongoConfig.DBName).Collection(cfg.MongoConfig.CollectionName)
+	communitiesCollection := client.Database(cfg.MongoConfig.DBName).Collection(cfg.MongoConfig.CommunitiesCollectionName)
+	commentsCollection := client.Database(cfg.MongoConfig.DBName).Collection(cfg.MongoConfig.CommentsCollectionName)
+	votesCollection := client.Database(cfg.MongoConfig.DBName).Collection(cfg.MongoConfig.VotesCollectionName)
+	notificationsCollection := client.Database(cfg.MongoConfig.DBName).Collection(cfg.MongoConfig.NotificationsCollectionName)
+	reportsCollection := client.Database(cfg.MongoConfig.DBName).Collection(cfg.MongoConfig.ReportsCollectionName)
 
 	// init Redis
-	redisAddress := fmt.Sprintf("%s:%s", cfg.RedisConfig.Host, cfg.RedisConfig.Port)
-	conn, err := redis.Dial("tcp", redisAddress)
+	redisPool, err := initRedis(cfg.RedisConfig)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
 	defer func() {
-		if err := conn.Close(); err != nil {
+		if err := redisPool.Close(); err != nil {
 			logrus.Errorln(err)
 		}
 		logrus.Infoln("connection with redis closed")
@@ -110,19 +222,127 @@ func Run(cfg Config) {
 	// declare app objects
 	usersRepo := mysqlrepo.NewUsersRepo(db)
 	postsRepo := mongorepo.NewPostsRepo(collection)
+	commentsRepo := mongorepo.NewCommentsRepo(commentsCollection)
+	votesRepo := mongorepo.NewVotesRepo(votesCollection)
+	searchRepo := mongorepo.NewSearchRepo(collection, commentsCollection)
+	notificationsRepo := mongorepo.NewNotificationsRepo(notificationsCollection)
+	reportsRepo := mongorepo.NewReportsRepo(reportsCollection)
+	communitiesRepo := mongorepo.NewCommunitiesRepo(communitiesCollection)
+	modActionsRepo := mysqlrepo.NewModActionsRepo(db)
+	messagesRepo := mysqlrepo.NewMessagesRepo(db)
+	bansRepo := mysqlrepo.NewBansRepo(db)
+	loginAttemptsRepo := lockout.NewRedisStore(redisPool, "lockout")
 	//usersRepo := slicerepo.NewUsersRepo()
 	//postsRepo := slicerepo.NewPostsRepo()
+	//commentsRepo := slicerepo.NewCommentsRepo()
+	//votesRepo := slicerepo.NewVotesRepo()
+	//searchRepo := slicerepo.NewSearchRepo(postsRepo, commentsRepo)
+	//notificationsRepo := slicerepo.NewNotificationsRepo()
+	//reportsRepo := slicerepo.NewReportsRepo()
+	//communitiesRepo := slicerepo.NewCommunitiesRepo()
+	//modActionsRepo := slicerepo.NewModActionsRepo()
+	//messagesRepo := slicerepo.NewMessagesRepo()
+	//bansRepo := slicerepo.NewBansRepo()
+	//loginAttemptsRepo := lockout.NewMemoryStore()
+
+	if err = postsRepo.CreateIndexes(); err != nil {
+		logrus.Fatalln(err)
+	}
 
-	services := service.NewService(usersRepo, postsRepo)
+	if err = communitiesRepo.CreateIndexes(); err != nil {
+		logrus.Fatalln(err)
+	}
 
-	cookieStorage := cookie.NewRedisStorage(conn)
-	sessions := cookie.NewManager(cookieStorage)
-	signer := token.NewSigner(cfg.SignerConfig.SigningKey)
-	handlers := handler.NewHandler(signer, sessions, services)
+	if err = commentsRepo.CreateIndexes(); err != nil {
+		logrus.Fatalln(err)
+	}
+
+	if err = votesRepo.CreateIndexes(); err != nil {
+		logrus.Fatalln(err)
+	}
+
+	if err = searchRepo.CreateIndexes(); err != nil {
+		logrus.Fatalln(err)
+	}
+
+	if err = notificationsRepo.CreateIndexes(); err != nil {
+		logrus.Fatalln(err)
+	}
+
+	if err = reportsRepo.CreateIndexes(); err != nil {
+		logrus.Fatalln(err)
+	}
+
+	hasher, err := initPasswordHasher(cfg.PasswordConfig)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	events, err := initEventBroker(cfg.EventsConfig, redisPool)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+	if closer, ok := events.(io.Closer); ok {
+		defer func() {
+			if err := closer.Close(); err != nil {
+				logrus.Errorln(err)
+			}
+		}()
+	}
+
+	stopAutomod := make(chan struct{})
+	defer close(stopAutomod)
+	automod, err := initAutomod(cfg.AutomodConfig, stopAutomod)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	rateLimitStore, err := initRateLimitStore(cfg.RateLimitConfig, redisPool)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	services := service.NewService(service.Repositories{
+		Users:         usersRepo,
+		Posts:         postsRepo,
+		Comments:      commentsRepo,
+		Votes:         votesRepo,
+		Search:        searchRepo,
+		Notifications: notificationsRepo,
+		Communities:   communitiesRepo,
+		ModActions:    modActionsRepo,
+		Messages:      messagesRepo,
+		MessageRates:  rateLimitStore,
+		Reports:       reportsRepo,
+		Bans:          bansRepo,
+		LoginAttempts: loginAttemptsRepo,
+	}, hasher, events, automod)
+
+	if err = services.SeedCommunities(); err != nil {
+		logrus.Fatalln(err)
+	}
+
+	sessions, err := initSessionManager(cfg.SessionConfig, cfg.SignerConfig, redisPool)
+	if err != nil {
+		logrus.Fatalln(err)
+	}
+
+	refresher := token.NewRefresher(cookie.NewRedisStorage(redisPool, "refresh", cfg.SessionConfig.RefreshTTL))
+
+	limiter := handler.NewRateLimiter(rateLimitStore, rateLimits(cfg.RateLimitConfig))
+
+	handlers := handler.NewHandler(sessions, refresher, services, limiter)
 
 	router := handlers.CreateRouter()
 	apiAddress := fmt.Sprintf("%s:%s", cfg.ApiConfig.Host, cfg.ApiConfig.Port)
-	server := &http.Server{Addr: apiAddress, Handler: router}
+	// the event streams end with the base context, otherwise the shutdown waits for them forever
+	baseCtx, cancelStreams := context.WithCancel(context.Background())
+	server := &http.Server{
+		Addr:        apiAddress,
+		Handler:     router,
+		BaseContext: func(net.Listener) context.Context { return baseCtx },
+	}
+	server.RegisterOnShutdown(cancelStreams)
 
 	// run application
 	go func() {
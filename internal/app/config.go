@@ -19,23 +19,67 @@ type MySQLConfig struct {
 }
 
 type MongoConfig struct {
-	Host           string `yaml:"-"`
-	Port           string `yaml:"-"`
-	Username       string `yaml:"-"`
-	Password       string `yaml:"-"`
-	DBName         string `yaml:"dbname"`
-	CollectionName string `yaml:"collection_name"`
+	Host                        string `yaml:"-"`
+	Port                        string `yaml:"-"`
+	Username                    string `yaml:"-"`
+	Password                    string `yaml:"-"`
+	DBName                      string `yaml:"dbname"`
+	CollectionName              string `yaml:"collection_name"`
+	CommunitiesCollectionName   string `yaml:"communities_collection_name"`
+	CommentsCollectionName      string `yaml:"comments_collection_name"`
+	VotesCollectionName         string `yaml:"votes_collection_name"`
+	NotificationsCollectionName string `yaml:"notifications_collection_name"`
+	ReportsCollectionName       string `yaml:"reports_collection_name"`
 }
 
 type RedisConfig struct {
-	Host string `yaml:"-"`
-	Port string `yaml:"-"`
+	Host               string `yaml:"-"`
+	Port               string `yaml:"-"`
+	MaxIdleConnections int    `yaml:"max_idle_connections"`
+}
+
+type PasswordConfig struct {
+	Algorithm  string `yaml:"algorithm"`
+	BcryptCost int    `yaml:"bcrypt_cost"`
+}
+
+type SessionConfig struct {
+	Strategy   string `yaml:"strategy"`
+	TTL        int    `yaml:"ttl"`
+	RefreshTTL int    `yaml:"refresh_ttl"`
+}
+
+type EventsConfig struct {
+	Broker string `yaml:"broker"`
+}
+
+// AutomodConfig points at the rules, the file is checked for changes every ReloadInterval seconds
+type AutomodConfig struct {
+	RulesFile      string `yaml:"rules_file"`
+	ReloadInterval int    `yaml:"reload_interval"`
+}
+
+// RateLimitBudget lets Requests requests through in any Window of seconds
+type RateLimitBudget struct {
+	Requests int `yaml:"requests"`
+	Window   int `yaml:"window"`
+}
+
+// RateLimitConfig keeps the budgets by the route name: register, login, posts, comments and votes
+type RateLimitConfig struct {
+	Store  string                     `yaml:"store"`
+	Limits map[string]RateLimitBudget `yaml:"limits"`
 }
 
 type Config struct {
-	ApiConfig    ApiConfig    `yaml:"api"`
-	MySQLConfig  MySQLConfig  `yaml:"mysql"`
-	MongoConfig  MongoConfig  `yaml:"mongo"`
-	RedisConfig  RedisConfig  `yaml:"redis"`
-	SignerConfig SignerConfig `yaml:"-"`
+	ApiConfig       ApiConfig       `yaml:"api"`
+	MySQLConfig     MySQLConfig     `yaml:"mysql"`
+	MongoConfig     MongoConfig     `yaml:"mongo"`
+	RedisConfig     RedisConfig     `yaml:"redis"`
+	SignerConfig    SignerConfig    `yaml:"-"`
+	PasswordConfig  PasswordConfig  `yaml:"password"`
+	SessionConfig   SessionConfig   `yaml:"session"`
+	EventsConfig    EventsConfig    `yaml:"events"`
+	AutomodConfig   AutomodConfig   `yaml:"automod"`
+	RateLimitConfig RateLimitConfig `yaml:"rate_limit"`
 }
@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"net/http"
+	"redditclone/internal/model"
+	"redditclone/internal/model/customerr"
+	"strconv"
+)
+
+type userRole struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+func (h *Handler) setUserRole(w http.ResponseWriter, r *http.Request) {
+	admin := r.Context().Value("user").(model.User)
+
+	vars := mux.Vars(r)
+	userID := vars["user_id"]
+
+	if errs := h.validator.ValidatePathValue("user_id", userID); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	var input map[string]string
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		h.handleError(w, customerr.RequestNotParsed{Message: err.Error()})
+		return
+	}
+
+	if errs := h.validator.ValidateBody("Role", input); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	usr, err := h.service.SetUserRole(userID, input["role"], admin)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp, err := json.Marshal(userRole{ID: usr.ID, Username: usr.Username, Role: usr.Role})
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if _, err = w.Write(resp); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
+
+type userSuspension struct {
+	ID         string            `json:"id"`
+	Username   string            `json:"username"`
+	Suspension *model.Suspension `json:"suspension"`
+}
+
+func writeUserSuspension(w http.ResponseWriter, usr model.User) error {
+	resp, err := json.Marshal(userSuspension{ID: usr.ID, Username: usr.Username, Suspension: usr.Suspension})
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(resp)
+	return err
+}
+
+func (h *Handler) suspendUser(w http.ResponseWriter, r *http.Request) {
+	admin := r.Context().Value("user").(model.User)
+	userID := mux.Vars(r)["user_id"]
+
+	if errs := h.validator.ValidatePathValue("user_id", userID); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	_, banInput, ok := h.decodeBanInput(w, r, "Suspension")
+	if !ok {
+		return
+	}
+
+	usr, err := h.service.SuspendUser(userID, banInput, admin)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if err = writeUserSuspension(w, usr); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) unsuspendUser(w http.ResponseWriter, r *http.Request) {
+	admin := r.Context().Value("user").(model.User)
+	userID := mux.Vars(r)["user_id"]
+
+	if errs := h.validator.ValidatePathValue("user_id", userID); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	usr, err := h.service.UnsuspendUser(userID, admin)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if err = writeUserSuspension(w, usr); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
+
+// unlockUser lifts the login lockout of the user and forgets the failed logins
+func (h *Handler) unlockUser(w http.ResponseWriter, r *http.Request) {
+	admin := r.Context().Value("user").(model.User)
+	userID := mux.Vars(r)["user_id"]
+
+	if errs := h.validator.ValidatePathValue("user_id", userID); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	if err := h.service.UnlockUser(userID, admin); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp := []byte(fmt.Sprintf("{\"message\": \"success\"}"))
+	if _, err := w.Write(resp); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) getModLog(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+
+	errs := h.validator.ValidateQueryValueAs("community_filter", "community", values.Get("community"))
+	errs = append(errs, h.validator.ValidateQueryValueAs("mod_action", "action", values.Get("action"))...)
+	errs = append(errs, h.validator.ValidateQueryValue("limit", values.Get("limit"))...)
+	errs = append(errs, h.validator.ValidateQueryValueAs("modlog_cursor", "cursor", values.Get("cursor"))...)
+	if len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	query := model.ModLogQuery{
+		Community: values.Get("community"),
+		Moderator: values.Get("moderator"),
+		Action:    values.Get("action"),
+		Cursor:    values.Get("cursor"),
+	}
+	query.Limit, _ = strconv.Atoi(values.Get("limit"))
+
+	page, err := h.service.GetModLog(query)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp, err := json.Marshal(page)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if _, err = w.Write(resp); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
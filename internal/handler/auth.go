@@ -3,19 +3,27 @@ package handler
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/sirupsen/logrus"
+	"io"
 	"net/http"
 	"redditclone/internal/model"
 	"redditclone/internal/model/customerr"
+	"redditclone/pkg/session"
 	"redditclone/pkg/token"
 )
 
-func writeToken(w http.ResponseWriter, t string) error {
-	resp := []byte(fmt.Sprintf("{\"token\": \"%s\"}", t))
-	if _, err := w.Write(resp); err != nil {
-		return err
+func (h *Handler) createSession(usr model.User) (session.Token, error) {
+	t, err := h.sessions.CreateToken(session.AuthUser{ID: usr.ID, Username: usr.Username, Role: usr.Role})
+	if err != nil {
+		return session.Token{}, err
+	}
+
+	t.Refresh, err = h.refresher.Issue(token.AuthUser{ID: usr.ID, Username: usr.Username, Role: usr.Role})
+	if err != nil {
+		return session.Token{}, err
 	}
 
-	return nil
+	return t, nil
 }
 
 func (h *Handler) signUp(w http.ResponseWriter, r *http.Request) {
@@ -39,23 +47,49 @@ func (h *Handler) signUp(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	authUser := token.AuthUser{ID: usr.ID, Username: usr.Username}
-	t, err := h.signer.CreateToken(authUser)
+	t, err := h.createSession(usr)
 	if err != nil {
 		h.handleError(w, err)
 		return
 	}
-	serialized, err := json.Marshal(authUser)
+
+	if err = h.sessions.WriteToken(w, t); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (h *Handler) signIn(w http.ResponseWriter, r *http.Request) {
+	var input map[string]string
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		h.handleError(w, customerr.RequestNotParsed{Message: err.Error()})
+		return
+	}
+
+	if errs := h.validator.ValidateBody("Credential", input); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	cred := model.Credential{Username: input["username"], Password: input["password"]}
+
+	usr, err := h.service.LoginUser(cred, clientIP(r))
 	if err != nil {
 		h.handleError(w, err)
 		return
 	}
-	if err = h.sessions.AddCookie(t, serialized); err != nil {
+
+	t, err := h.createSession(usr)
+	if err != nil {
 		h.handleError(w, err)
 		return
 	}
 
-	if err = writeToken(w, t); err != nil {
+	if err = h.sessions.WriteToken(w, t); err != nil {
 		h.handleError(w, err)
 		return
 	}
@@ -64,7 +98,7 @@ func (h *Handler) signUp(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusCreated)
 }
 
-func (h *Handler) signIn(w http.ResponseWriter, r *http.Request) {
+func (h *Handler) refreshToken(w http.ResponseWriter, r *http.Request) {
 	var input map[string]string
 	err := json.NewDecoder(r.Body).Decode(&input)
 	if err != nil {
@@ -72,40 +106,109 @@ func (h *Handler) signIn(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if errs := h.validator.ValidateBody("Credential", input); len(errs) != 0 {
+	if errs := h.validator.ValidateBody("RefreshToken", input); len(errs) != 0 {
 		h.handleValidationErrors(w, errs)
 		return
 	}
 
-	cred := model.Credential{Username: input["username"], Password: input["password"]}
+	authUser, refresh, err := h.refresher.Rotate(input["refresh_token"])
+	if err == token.ErrRefreshTokenInvalid || err == token.ErrRefreshTokenReused {
+		h.handleError(w, customerr.Unauthorized{Message: err.Error()})
+		return
+	}
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
 
-	usr, err := h.service.LoginUser(cred)
+	usr, err := h.service.GetUserByID(authUser.ID)
 	if err != nil {
+		if _, ok := err.(customerr.UserNotFoundByID); ok {
+			h.handleError(w, customerr.Unauthorized{Message: err.Error()})
+			return
+		}
 		h.handleError(w, err)
 		return
 	}
 
-	authUser := token.AuthUser{ID: usr.ID, Username: usr.Username}
-	t, err := h.signer.CreateToken(authUser)
+	t, err := h.sessions.CreateToken(session.AuthUser{ID: usr.ID, Username: usr.Username, Role: usr.Role})
 	if err != nil {
 		h.handleError(w, err)
 		return
 	}
-	serialized, err := json.Marshal(authUser)
+	t.Refresh = refresh
+
+	if err = h.sessions.WriteToken(w, t); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) logout(w http.ResponseWriter, r *http.Request) {
+	usr := r.Context().Value("user").(model.User)
+
+	t, err := h.sessions.GetTokenFromRequest(r)
 	if err != nil {
+		h.handleError(w, customerr.Unauthorized{Message: err.Error()})
+		return
+	}
+
+	// the refresh token is optional, a client without one only drops the access token
+	var input map[string]string
+	if err = json.NewDecoder(r.Body).Decode(&input); err != nil && err != io.EOF {
+		h.handleError(w, customerr.RequestNotParsed{Message: err.Error()})
+		return
+	}
+
+	if input["refresh_token"] != "" {
+		if err = h.refresher.Revoke(input["refresh_token"], usr.ID); err != nil {
+			h.handleError(w, err)
+			return
+		}
+	}
+
+	// stateless access tokens can't be revoked and expire on their own
+	if err = h.sessions.DeleteToken(usr.ID, t); err != nil && err != session.ErrNotRevocable {
 		h.handleError(w, err)
 		return
 	}
-	if err = h.sessions.AddCookie(t, serialized); err != nil {
+
+	logrus.Infof("user logged out: %s", usr.Username)
+
+	resp := []byte(fmt.Sprintf("{\"message\": \"success\"}"))
+	if _, err = w.Write(resp); err != nil {
 		h.handleError(w, err)
 		return
 	}
 
-	if err = writeToken(w, t); err != nil {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) logoutAll(w http.ResponseWriter, r *http.Request) {
+	usr := r.Context().Value("user").(model.User)
+
+	if err := h.refresher.RevokeUser(usr.ID); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if err := h.sessions.DeleteUserTokens(usr.ID); err != nil && err != session.ErrNotRevocable {
+		h.handleError(w, err)
+		return
+	}
+
+	logrus.Infof("user logged out from all sessions: %s", usr.Username)
+
+	resp := []byte(fmt.Sprintf("{\"message\": \"success\"}"))
+	if _, err := w.Write(resp); err != nil {
 		h.handleError(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	w.WriteHeader(http.StatusCreated)
+	w.WriteHeader(http.StatusOK)
 }
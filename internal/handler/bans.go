@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"net/http"
+	"redditclone/internal/model"
+	"redditclone/internal/model/customerr"
+	"strconv"
+)
+
+// decodeBanInput parses and validates the body of a ban or a suspension, no days make it permanent
+func (h *Handler) decodeBanInput(w http.ResponseWriter, r *http.Request, template string) (map[string]string, model.BanInput, bool) {
+	var input map[string]string
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		h.handleError(w, customerr.RequestNotParsed{Message: err.Error()})
+		return nil, model.BanInput{}, false
+	}
+
+	if errs := h.validator.ValidateBody(template, input); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return nil, model.BanInput{}, false
+	}
+
+	banInput := model.BanInput{Reason: input["reason"]}
+	banInput.Days, _ = strconv.Atoi(input["days"])
+
+	return input, banInput, true
+}
+
+func (h *Handler) banUser(w http.ResponseWriter, r *http.Request) {
+	moderator := r.Context().Value("user").(model.User)
+	community := mux.Vars(r)["community"]
+
+	if errs := h.validator.ValidatePathValue("community", community); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	input, banInput, ok := h.decodeBanInput(w, r, "Ban")
+	if !ok {
+		return
+	}
+
+	ban, err := h.service.BanUser(community, input["username"], banInput, moderator)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp, err := json.Marshal(ban)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if _, err = w.Write(resp); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (h *Handler) getBans(w http.ResponseWriter, r *http.Request) {
+	community := mux.Vars(r)["community"]
+
+	if errs := h.validator.ValidatePathValue("community", community); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	bans, err := h.service.GetBans(community)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp, err := json.Marshal(bans)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if _, err = w.Write(resp); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) unbanUser(w http.ResponseWriter, r *http.Request) {
+	moderator := r.Context().Value("user").(model.User)
+	vars := mux.Vars(r)
+	community := vars["community"]
+	username := vars["username"]
+
+	errs := h.validator.ValidatePathValue("community", community)
+	errs = append(errs, h.validator.ValidatePathValue("username", username)...)
+	if len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	if err := h.service.UnbanUser(community, username, moderator); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp := []byte(fmt.Sprintf("{\"message\": \"success\"}"))
+	if _, err := w.Write(resp); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
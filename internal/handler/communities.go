@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"net/http"
+	"redditclone/internal/model"
+	"redditclone/internal/model/customerr"
+)
+
+func writeCommunities(w http.ResponseWriter, communities []model.Community) error {
+	resp, err := json.Marshal(communities)
+	if err != nil {
+		return err
+	}
+
+	if _, err = w.Write(resp); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func writeCommunity(w http.ResponseWriter, c model.Community) error {
+	resp, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	if _, err = w.Write(resp); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (h *Handler) getCommunities(w http.ResponseWriter, r *http.Request) {
+	communities, err := h.service.GetCommunities()
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if err = writeCommunities(w, communities); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) getCommunity(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	name := vars["community"]
+
+	if errs := h.validator.ValidatePathValue("community", name); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	community, err := h.service.GetCommunity(name)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if err = writeCommunity(w, community); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) createCommunity(w http.ResponseWriter, r *http.Request) {
+	usr := r.Context().Value("user").(model.User)
+
+	var input map[string]string
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		h.handleError(w, customerr.RequestNotParsed{Message: err.Error()})
+		return
+	}
+
+	if errs := h.validator.ValidateBody("Community", input); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	community, err := h.service.CreateCommunity(model.CommunityInput{
+		Name:        input["name"],
+		Description: input["description"],
+	}, usr)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if err = writeCommunity(w, community); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (h *Handler) updateCommunity(w http.ResponseWriter, r *http.Request) {
+	usr := r.Context().Value("user").(model.User)
+
+	vars := mux.Vars(r)
+	name := vars["community"]
+
+	if errs := h.validator.ValidatePathValue("community", name); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	var input map[string]string
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		h.handleError(w, customerr.RequestNotParsed{Message: err.Error()})
+		return
+	}
+
+	if errs := h.validator.ValidateBody("CommunityUpdate", input); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	community, err := h.service.UpdateCommunity(name, input["description"], usr)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if err = writeCommunity(w, community); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) deleteCommunity(w http.ResponseWriter, r *http.Request) {
+	usr := r.Context().Value("user").(model.User)
+
+	vars := mux.Vars(r)
+	name := vars["community"]
+
+	if errs := h.validator.ValidatePathValue("community", name); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	if err := h.service.DeleteCommunity(name, usr); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp := []byte(fmt.Sprintf("{\"message\": \"success\"}"))
+	if _, err := w.Write(resp); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
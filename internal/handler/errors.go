@@ -2,6 +2,7 @@ package handler
 
 import (
 	"github.com/sirupsen/logrus"
+	"math"
 	"net/http"
 	"redditclone/internal/model/customerr"
 	"redditclone/pkg/httperr"
@@ -35,6 +36,60 @@ func (h *Handler) handleError(w http.ResponseWriter, err error) {
 				Message:  "already exists",
 			}},
 		})
+	case customerr.CommunityAlreadyExists:
+		httperr.HandleError(w, httperr.UnprocessableEntity{
+			Errors: []httperr.UnprocessableEntityItem{{
+				Location: "body",
+				Param:    "name",
+				Value:    err.(customerr.CommunityAlreadyExists).Name,
+				Message:  "already exists",
+			}},
+		})
+	case customerr.CommunityNotEmpty:
+		httperr.HandleError(w, httperr.UnprocessableEntity{
+			Errors: []httperr.UnprocessableEntityItem{{
+				Location: "path",
+				Param:    "community",
+				Value:    err.(customerr.CommunityNotEmpty).Name,
+				Message:  "community still has posts",
+			}},
+		})
+	case customerr.CommentTooDeep:
+		httperr.HandleError(w, httperr.UnprocessableEntity{
+			Errors: []httperr.UnprocessableEntityItem{{
+				Location: "body",
+				Param:    "parent_id",
+				Value:    err.(customerr.CommentTooDeep).ParentID,
+				Message:  "comment thread is too deep",
+			}},
+		})
+	case customerr.InvalidCursor:
+		httperr.HandleError(w, httperr.UnprocessableEntity{
+			Errors: []httperr.UnprocessableEntityItem{{
+				Location: "query",
+				Param:    "cursor",
+				Value:    err.(customerr.InvalidCursor).Cursor,
+				Message:  "cursor must be a value of next_cursor",
+			}},
+		})
+	case customerr.TargetIsSelf:
+		httperr.HandleError(w, httperr.UnprocessableEntity{
+			Errors: []httperr.UnprocessableEntityItem{{
+				Location: "path",
+				Param:    "username",
+				Value:    err.(customerr.TargetIsSelf).Username,
+				Message:  "user can't be yourself",
+			}},
+		})
+	case customerr.PostNotEditable:
+		httperr.HandleError(w, httperr.UnprocessableEntity{
+			Errors: []httperr.UnprocessableEntityItem{{
+				Location: "path",
+				Param:    "post_id",
+				Value:    err.(customerr.PostNotEditable).PostID,
+				Message:  "only text posts can be edited",
+			}},
+		})
 	case customerr.WrongCredential:
 		httperr.HandleError(w, httperr.Unauthorized{Message: "wrong credential"})
 	case customerr.Unauthorized:
@@ -45,10 +100,43 @@ func (h *Handler) handleError(w http.ResponseWriter, err error) {
 		httperr.HandleError(w, httperr.NotFound{Message: "user not found"})
 	case customerr.PostNotFoundByID:
 		httperr.HandleError(w, httperr.NotFound{Message: "post not found"})
+	case customerr.CommunityNotFoundByName:
+		httperr.HandleError(w, httperr.NotFound{Message: "community not found"})
 	case customerr.CommentNotFoundByID:
 		httperr.HandleError(w, httperr.NotFound{Message: "comment not found"})
+	case customerr.ReportsNotFound:
+		httperr.HandleError(w, httperr.NotFound{Message: "reports not found"})
+	case customerr.BanNotFound:
+		httperr.HandleError(w, httperr.NotFound{Message: "ban not found"})
+	case customerr.NotificationNotFoundByID:
+		httperr.HandleError(w, httperr.NotFound{Message: "notification not found"})
 	case customerr.NotOwner:
 		httperr.HandleError(w, httperr.Forbidden{Message: "user not own this resource"})
+	case customerr.PermissionDenied:
+		httperr.HandleError(w, httperr.Forbidden{Message: "permission denied"})
+	case customerr.Banned:
+		banned := err.(customerr.Banned)
+		message := "user is banned from the community"
+		if banned.Community == "" {
+			message = "user is suspended"
+		}
+		httperr.HandleError(w, httperr.Forbidden{Message: message, Expires: banned.Expires})
+	case customerr.PostChanged:
+		httperr.HandleError(w, httperr.Conflict{Message: "post was changed by another request, reload it"})
+	case customerr.PostLocked:
+		httperr.HandleError(w, httperr.Forbidden{Message: "post is locked"})
+	case customerr.UserBlocked:
+		httperr.HandleError(w, httperr.Forbidden{Message: "messages with this user are blocked"})
+	case customerr.RateLimited:
+		httperr.HandleError(w, httperr.TooManyRequests{
+			Message:    "too many requests",
+			RetryAfter: int(math.Ceil(err.(customerr.RateLimited).RetryAfter.Seconds())),
+		})
+	case customerr.LoginLocked:
+		httperr.HandleError(w, httperr.TooManyRequests{
+			Message:    "too many failed logins",
+			RetryAfter: int(math.Ceil(err.(customerr.LoginLocked).RetryAfter.Seconds())),
+		})
 	case customerr.RequestNotParsed:
 		httperr.HandleError(w, httperr.BadRequest{Message: "bad request"})
 	default:
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gorilla/mux"
+	"net/http"
+	"redditclone/internal/model"
+	"redditclone/pkg/broker"
+	"time"
+)
+
+// eventsHeartbeat keeps the idle streams open through the proxies
+const eventsHeartbeat = 15 * time.Second
+
+// streamEvents writes the events as Server-Sent Events until the client leaves or the subscription ends
+func (h *Handler) streamEvents(w http.ResponseWriter, r *http.Request, sub *broker.Subscription) {
+	defer sub.Close()
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		h.handleError(w, errors.New("streaming is not supported"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(eventsHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case payload, ok := <-sub.Messages():
+			if !ok {
+				return
+			}
+
+			var event model.Event
+			if err := json.Unmarshal(payload, &event); err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, payload); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func (h *Handler) getPostEvents(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	postID := vars["post_id"]
+
+	if errs := h.validator.ValidatePathValue("post_id", postID); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	sub, err := h.service.SubscribePost(postID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	h.streamEvents(w, r, sub)
+}
+
+func (h *Handler) getCommunityEvents(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	name := vars["community"]
+
+	if errs := h.validator.ValidatePathValue("community", name); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	sub, err := h.service.SubscribeCommunity(name)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	h.streamEvents(w, r, sub)
+}
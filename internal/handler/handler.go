@@ -4,55 +4,130 @@ import (
 	"github.com/gorilla/mux"
 	"net/http"
 	"redditclone/internal/model"
-	"redditclone/pkg/cookie"
+	"redditclone/pkg/broker"
 	"redditclone/pkg/httpvalidator"
+	"redditclone/pkg/session"
 	"redditclone/pkg/token"
 )
 
-const (
-	authorizationHeader = "Authorization"
-)
-
 type authService interface {
 	RegisterUser(cred model.Credential) (model.User, error)
-	LoginUser(cred model.Credential) (model.User, error)
+	LoginUser(cred model.Credential, ip string) (model.User, error)
 }
 
 type postsService interface {
 	GetAllPosts() ([]model.Post, error)
 	GetPostsByCategory(category string) ([]model.Post, error)
 	GetPostsByAuthor(username string) ([]model.Post, error)
+	GetPosts(query model.PostsQuery) (model.PostsPage, error)
+	ListPosts(query model.PostsQuery) ([]model.Post, error)
 	CreateTextPost(input model.TextPostInput, usr model.User) (model.Post, error)
 	CreateURLPost(input model.URLPostInput, usr model.User) (model.Post, error)
 	GetPostByID(postID string) (model.Post, error)
-	DeletePost(postID string, usr model.User) error
-	AddComment(postID string, commentText string, usr model.User) (model.Post, error)
-	DeleteComment(postID, commentID string, usr model.User) (model.Post, error)
+	DeletePost(postID string, reason string, usr model.User) error
+	UpdatePost(postID string, input model.PostUpdateInput, usr model.User) (model.Post, error)
+	GetPostRevisions(postID string) ([]model.PostRevision, error)
+	AddComment(postID string, commentText string, parentID string, usr model.User) (model.Post, error)
+	DeleteComment(postID, commentID string, reason string, usr model.User) (model.Post, error)
+	EditComment(postID, commentID string, commentText string, usr model.User) (model.Post, error)
+	UpvoteComment(postID, commentID string, usr model.User) (model.Post, error)
+	DownvoteComment(postID, commentID string, usr model.User) (model.Post, error)
+	UnvoteComment(postID, commentID string, usr model.User) (model.Post, error)
 	UpvotePost(postID string, usr model.User) (model.Post, error)
 	DownvotePost(postID string, usr model.User) (model.Post, error)
 	UnvotePost(postID string, usr model.User) (model.Post, error)
+	FillUserVotes(posts []model.Post, usr model.User) ([]model.Post, error)
+	GetVotesByUser(usr model.User) ([]model.Vote, error)
+}
+
+type communitiesService interface {
+	GetCommunities() ([]model.Community, error)
+	GetCommunity(name string) (model.Community, error)
+	CreateCommunity(input model.CommunityInput, usr model.User) (model.Community, error)
+	UpdateCommunity(name string, description string, usr model.User) (model.Community, error)
+	DeleteCommunity(name string, usr model.User) error
 }
 
 type usersService interface {
 	GetUserByID(userID string) (model.User, error)
+	GetProfile(username string) (model.Profile, error)
+	SetUserRole(userID string, role string, admin model.User) (model.User, error)
+	GetModLog(query model.ModLogQuery) (model.ModLogPage, error)
+	SuspendUser(userID string, input model.BanInput, admin model.User) (model.User, error)
+	UnsuspendUser(userID string, admin model.User) (model.User, error)
+	UnlockUser(userID string, admin model.User) error
+}
+
+type searchService interface {
+	Search(query model.SearchQuery) (model.SearchPage, error)
+}
+
+type eventsService interface {
+	SubscribePost(postID string) (*broker.Subscription, error)
+	SubscribeCommunity(name string) (*broker.Subscription, error)
+}
+
+type notificationsService interface {
+	GetNotifications(query model.NotificationsQuery, usr model.User) (model.NotificationsPage, error)
+	MarkNotificationRead(notificationID string, usr model.User) (model.Notification, error)
+	MarkAllNotificationsRead(usr model.User) error
+}
+
+type messagesService interface {
+	SendMessage(username string, body string, usr model.User) (model.Message, error)
+	GetConversations(usr model.User) ([]model.Conversation, error)
+	GetThread(username string, usr model.User) ([]model.Message, error)
+	MarkThreadRead(username string, usr model.User) error
+	BlockUser(username string, usr model.User) error
+	UnblockUser(username string, usr model.User) error
+	GetBlocks(usr model.User) ([]model.Block, error)
+}
+
+type bansService interface {
+	BanUser(community, username string, input model.BanInput, moderator model.User) (model.Ban, error)
+	GetBans(community string) ([]model.Ban, error)
+	UnbanUser(community, username string, moderator model.User) error
+}
+
+type reportsService interface {
+	ReportPost(postID string, reason string, text string, usr model.User) (model.Report, error)
+	ReportComment(postID, commentID string, reason string, text string, usr model.User) (model.Report, error)
+	GetReportQueue(query model.ReportsQuery) ([]model.ReportedItem, error)
+	ResolveReports(postID, commentID string, action string, reason string, moderator model.User) (model.ReportResolution, error)
 }
 
 type appService interface {
 	authService
 	postsService
+	communitiesService
 	usersService
+	searchService
+	eventsService
+	notificationsService
+	messagesService
+	reportsService
+	bansService
+}
+
+type tokenRefresher interface {
+	Issue(usr token.AuthUser) (string, error)
+	Rotate(refreshToken string) (token.AuthUser, string, error)
+	Revoke(refreshToken string, userID string) error
+	RevokeUser(userID string) error
 }
 
 type Handler struct {
-	sessions  cookie.Manager
-	signer    token.Signer
+	sessions  session.Manager
+	refresher tokenRefresher
 	validator httpvalidator.Validator
 	service   appService
+	limiter   *rateLimiter
 }
 
-func NewHandler(signer token.Signer, sessions cookie.Manager, service appService) *Handler {
+// NewHandler takes a nil limiter when no route is limited
+func NewHandler(sessions session.Manager, refresher tokenRefresher, service appService, limiter *rateLimiter) *Handler {
 	validator := httpvalidator.NewValidator()
-	handler := &Handler{signer: signer, sessions: sessions, validator: validator, service: service}
+	handler := &Handler{sessions: sessions, refresher: refresher, validator: validator, service: service, limiter: limiter}
 	handler.initValidator()
 	return handler
 }
@@ -68,24 +143,74 @@ func (h *Handler) CreateRouter() http.Handler {
 	fs = http.FileServer(http.Dir("./static/"))
 	router.PathPrefix("/static/").Handler(http.StripPrefix("/static/", fs))
 
-	router.HandleFunc("/api/register", h.signUp).Methods("POST")
-	router.HandleFunc("/api/login", h.signIn).Methods("POST")
-
-	router.HandleFunc("/api/posts/", h.getAllPosts).Methods("GET")
-	router.HandleFunc("/api/posts/{category}", h.getPostsByCategory).Methods("GET")
-	router.HandleFunc("/api/post/{post_id}", h.getPost).Methods("GET")
+	router.HandleFunc("/api/register", h.limitByIP(rateLimitRegister, h.signUp)).Methods("POST")
+	router.HandleFunc("/api/login", h.limitByIP(rateLimitLogin, h.signIn)).Methods("POST")
+	router.HandleFunc("/api/token/refresh", h.refreshToken).Methods("POST")
+
+	routerForViewers := router.PathPrefix("/api").Subrouter()
+	routerForViewers.Use(h.identifyMiddleware)
+	routerForViewers.HandleFunc("/posts/", h.getAllPosts).Methods("GET")
+	routerForViewers.HandleFunc("/posts/{category}", h.getPostsByCategory).Methods("GET")
+	routerForViewers.HandleFunc("/post/{post_id}", h.getPost).Methods("GET")
+	router.HandleFunc("/api/search", h.search).Methods("GET")
+	router.HandleFunc("/api/post/{post_id}/revisions", h.getPostRevisions).Methods("GET")
+	router.HandleFunc("/api/post/{post_id}/events", h.getPostEvents).Methods("GET")
+	router.HandleFunc("/api/communities", h.getCommunities).Methods("GET")
+	router.HandleFunc("/api/communities/{community}", h.getCommunity).Methods("GET")
+	router.HandleFunc("/api/communities/{community}/events", h.getCommunityEvents).Methods("GET")
 
 	routerForAuthorized := router.PathPrefix("/api").Subrouter()
 	routerForAuthorized.Use(h.authorizeMiddleware)
-	routerForAuthorized.HandleFunc("/posts", h.createPost).Methods("POST")
+	routerForAuthorized.HandleFunc("/logout", h.logout).Methods("POST")
+	routerForAuthorized.HandleFunc("/logout/all", h.logoutAll).Methods("POST")
+	routerForAuthorized.HandleFunc("/posts", h.limitByUser(rateLimitPosts, h.createPost)).Methods("POST")
+	routerForAuthorized.HandleFunc("/post/{post_id}", h.updatePost).Methods("PATCH")
 	routerForAuthorized.HandleFunc("/post/{post_id}", h.deletePost).Methods("DELETE")
-	routerForAuthorized.HandleFunc("/post/{post_id}", h.createComment).Methods("POST")
+	routerForAuthorized.HandleFunc("/post/{post_id}", h.limitByUser(rateLimitComments, h.createComment)).Methods("POST")
+	routerForAuthorized.HandleFunc("/post/{post_id}/{comment_id}", h.editComment).Methods("PATCH")
 	routerForAuthorized.HandleFunc("/post/{post_id}/{comment_id}", h.deleteComment).Methods("DELETE")
-	routerForAuthorized.HandleFunc("/post/{post_id}/upvote", h.upvotePost).Methods("GET")
-	routerForAuthorized.HandleFunc("/post/{post_id}/downvote", h.downvotePost).Methods("GET")
-	routerForAuthorized.HandleFunc("/post/{post_id}/unvote", h.unvotePost).Methods("GET")
-
-	router.HandleFunc("/api/user/{username}", h.getPostsByUsername).Methods("GET")
+	routerForAuthorized.HandleFunc("/post/{post_id}/upvote", h.limitByUser(rateLimitVotes, h.upvotePost)).Methods("GET")
+	routerForAuthorized.HandleFunc("/post/{post_id}/downvote", h.limitByUser(rateLimitVotes, h.downvotePost)).Methods("GET")
+	routerForAuthorized.HandleFunc("/post/{post_id}/unvote", h.limitByUser(rateLimitVotes, h.unvotePost)).Methods("GET")
+	routerForAuthorized.HandleFunc("/post/{post_id}/{comment_id}/upvote", h.limitByUser(rateLimitVotes, h.upvoteComment)).Methods("GET")
+	routerForAuthorized.HandleFunc("/post/{post_id}/{comment_id}/downvote", h.limitByUser(rateLimitVotes, h.downvoteComment)).Methods("GET")
+	routerForAuthorized.HandleFunc("/post/{post_id}/{comment_id}/unvote", h.limitByUser(rateLimitVotes, h.unvoteComment)).Methods("GET")
+	routerForAuthorized.HandleFunc("/post/{post_id}/report", h.reportPost).Methods("POST")
+	routerForAuthorized.HandleFunc("/post/{post_id}/{comment_id}/report", h.reportComment).Methods("POST")
+	routerForAuthorized.HandleFunc("/user/me/votes", h.getUserVotes).Methods("GET")
+	routerForAuthorized.HandleFunc("/notifications", h.getNotifications).Methods("GET")
+	routerForAuthorized.HandleFunc("/notifications/read", h.markAllNotificationsRead).Methods("POST")
+	routerForAuthorized.HandleFunc("/notifications/{notification_id}/read", h.markNotificationRead).Methods("POST")
+	routerForAuthorized.HandleFunc("/messages", h.getConversations).Methods("GET")
+	routerForAuthorized.HandleFunc("/messages/{username}", h.getThread).Methods("GET")
+	routerForAuthorized.HandleFunc("/messages/{username}", h.sendMessage).Methods("POST")
+	routerForAuthorized.HandleFunc("/messages/{username}/read", h.markThreadRead).Methods("POST")
+	routerForAuthorized.HandleFunc("/user/me/blocks", h.getBlocks).Methods("GET")
+	routerForAuthorized.HandleFunc("/user/{username}/block", h.blockUser).Methods("POST")
+	routerForAuthorized.HandleFunc("/user/{username}/block", h.unblockUser).Methods("DELETE")
+	routerForAuthorized.HandleFunc("/communities", h.createCommunity).Methods("POST")
+	routerForAuthorized.HandleFunc("/communities/{community}", h.updateCommunity).Methods("PATCH")
+	routerForAuthorized.HandleFunc("/communities/{community}", h.deleteCommunity).Methods("DELETE")
+
+	routerForModerators := routerForAuthorized.NewRoute().Subrouter()
+	routerForModerators.Use(h.moderatorMiddleware)
+	routerForModerators.HandleFunc("/reports", h.getReportQueue).Methods("GET")
+	routerForModerators.HandleFunc("/reports/{post_id}", h.resolveReports).Methods("POST")
+	routerForModerators.HandleFunc("/reports/{post_id}/{comment_id}", h.resolveReports).Methods("POST")
+	routerForModerators.HandleFunc("/modlog", h.getModLog).Methods("GET")
+	routerForModerators.HandleFunc("/communities/{community}/bans", h.getBans).Methods("GET")
+	routerForModerators.HandleFunc("/communities/{community}/bans", h.banUser).Methods("POST")
+	routerForModerators.HandleFunc("/communities/{community}/bans/{username}", h.unbanUser).Methods("DELETE")
+
+	routerForAdmins := routerForAuthorized.PathPrefix("/admin").Subrouter()
+	routerForAdmins.Use(h.adminMiddleware)
+	routerForAdmins.HandleFunc("/user/{user_id}/role", h.setUserRole).Methods("PUT")
+	routerForAdmins.HandleFunc("/user/{user_id}/suspension", h.suspendUser).Methods("PUT")
+	routerForAdmins.HandleFunc("/user/{user_id}/suspension", h.unsuspendUser).Methods("DELETE")
+	routerForAdmins.HandleFunc("/user/{user_id}/lockout", h.unlockUser).Methods("DELETE")
+
+	router.HandleFunc("/api/user/{username}", h.getProfile).Methods("GET")
+	routerForViewers.HandleFunc("/user/{username}/posts", h.getPostsByUsername).Methods("GET")
 
 	router.UseEncodedPath().NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		http.ServeFile(w, r, "static/html/index.html")
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/golang/mock/gomock"
 	"github.com/gorilla/mux"
 	"io/ioutil"
@@ -13,22 +14,793 @@ import (
 	mock "redditclone/internal/handler/mocks"
 	"redditclone/internal/model"
 	"redditclone/internal/model/customerr"
+	"redditclone/internal/repository/slicerepo"
+	"redditclone/internal/service"
+	"redditclone/pkg/broker"
 	"redditclone/pkg/cookie"
+	"redditclone/pkg/lockout"
+	"redditclone/pkg/ratelimit"
+	"redditclone/pkg/session"
+	cookiesession "redditclone/pkg/session/cookie"
+	jwtsession "redditclone/pkg/session/jwt"
+	opaquesession "redditclone/pkg/session/opaque"
 	"redditclone/pkg/token"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
+type sessionStrategy struct {
+	name      string
+	revocable bool
+	sessions  func() session.Manager
+}
+
+func sessionStrategies() []sessionStrategy {
+	return []sessionStrategy{
+		{
+			name:      "jwt",
+			revocable: false,
+			sessions: func() session.Manager {
+				return jwtsession.NewManager("love", 86400)
+			},
+		},
+		{
+			name:      "cookie",
+			revocable: true,
+			sessions: func() session.Manager {
+				return cookiesession.NewManager(cookie.NewManager(cookie.NewMapStorage()), 86400)
+			},
+		},
+		{
+			name:      "opaque-redis",
+			revocable: true,
+			sessions: func() session.Manager {
+				return opaquesession.NewManager(token.NewSigner("love", time.Hour), cookie.NewManager(cookie.NewMapStorage()))
+			},
+		},
+	}
+}
+
+func newRefresher() token.Refresher {
+	return token.NewRefresher(cookie.NewMapStorage())
+}
+
 func initHandler(ctrl *gomock.Controller, service *mock.MockappService) *Handler {
-	cookieStorage := cookie.NewMapStorage()
-	sessions := cookie.NewManager(cookieStorage)
-	signer := token.NewSigner("love")
-	return NewHandler(signer, sessions, service)
+	sessions := opaquesession.NewManager(token.NewSigner("love", time.Hour), cookie.NewManager(cookie.NewMapStorage()))
+	return NewHandler(sessions, newRefresher(), service, nil)
+}
+
+// tokenIssued reports whether the response carries a token in any of the supported transports
+func tokenIssued(resp *http.Response, body []byte) bool {
+	if len(resp.Cookies()) != 0 {
+		return true
+	}
+	matched, _ := regexp.MatchString("^{\"token\": \".+\", \"refresh_token\": \".+\"}$", string(body))
+	return matched
+}
+
+// authorizeRequest passes the issued token back the same way a client does
+func authorizeRequest(r *http.Request, resp *http.Response, body []byte) *http.Request {
+	for _, c := range resp.Cookies() {
+		r.AddCookie(c)
+	}
+
+	var t struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(body, &t); err == nil && t.Token != "" {
+		r.Header.Set("Authorization", "Bearer "+t.Token)
+	}
+
+	return r
+}
+
+// expectCommunities lets the category validation find only the given communities
+func expectCommunities(service *mock.MockappService, names ...string) {
+	service.EXPECT().GetCommunity(gomock.Any()).DoAndReturn(func(name string) (model.Community, error) {
+		for _, existed := range names {
+			if existed == name {
+				return model.Community{Name: name}, nil
+			}
+		}
+		return model.Community{}, customerr.CommunityNotFoundByName{Name: name}
+	}).AnyTimes()
+}
+
+func issueToken(sessions session.Manager, usr session.AuthUser) (*http.Response, []byte) {
+	w := httptest.NewRecorder()
+	t, _ := sessions.CreateToken(usr)
+	_ = sessions.WriteToken(w, t)
+	resp := w.Result()
+	body, _ := ioutil.ReadAll(resp.Body)
+	return resp, body
 }
 
 func TestSignUp(t *testing.T) {
+	for _, strategy := range sessionStrategies() {
+		t.Run(strategy.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			service := mock.NewMockappService(ctrl)
+			handler := NewHandler(strategy.sessions(), newRefresher(), service, nil)
+
+			cases := []struct {
+				request *http.Request
+				writer  *httptest.ResponseRecorder
+				run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+				check   func(resp *http.Response, body []byte) bool
+			}{
+				{
+					request: httptest.NewRequest("POST", "/register", strings.NewReader("{\"username\":\"van\",\"password\":\"qqq\"}")),
+					writer:  httptest.NewRecorder(),
+					run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+						service.EXPECT().
+							RegisterUser(model.Credential{Username: "van", Password: "qqq"}).
+							Return(model.User{ID: "1", Credential: model.Credential{Username: "van"}}, nil)
+						handler.signUp(w, r)
+						return w.Result()
+					},
+					check: func(resp *http.Response, body []byte) bool {
+						return tokenIssued(resp, body)
+					},
+				},
+				{
+					request: httptest.NewRequest("POST", "/register", strings.NewReader("invalid json")),
+					writer:  httptest.NewRecorder(),
+					run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+						handler.signUp(w, r)
+						return w.Result()
+					},
+					check: func(resp *http.Response, body []byte) bool {
+						data := []byte("{\"message\":\"bad request\"}\n")
+						return reflect.DeepEqual(body, data)
+					},
+				},
+				{
+					request: httptest.NewRequest("POST", "/register", strings.NewReader("{\"username\":\"van\"}")),
+					writer:  httptest.NewRecorder(),
+					run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+						handler.signUp(w, r)
+						return w.Result()
+					},
+					check: func(resp *http.Response, body []byte) bool {
+						data := []byte("{\"errors\":[{\"location\":\"body\",\"param\":\"password\",\"value\":\"\",\"msg\":\"field is required\"}]}\n")
+						return reflect.DeepEqual(body, data)
+					},
+				},
+				{
+					request: httptest.NewRequest("POST", "/register", strings.NewReader("{\"username\":\"van\",\"password\":\"qqq\"}")),
+					writer:  httptest.NewRecorder(),
+					run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+						service.EXPECT().
+							RegisterUser(model.Credential{Username: "van", Password: "qqq"}).
+							Return(model.User{}, customerr.UserAlreadyExists{Username: "van"})
+						handler.signUp(w, r)
+						return w.Result()
+					},
+					check: func(resp *http.Response, body []byte) bool {
+						data := []byte("{\"errors\":[{\"location\":\"body\",\"param\":\"username\",\"value\":\"van\",\"msg\":\"already exists\"}]}\n")
+						return reflect.DeepEqual(body, data)
+					},
+				},
+			}
+
+			for i, item := range cases {
+				resp := item.run(item.writer, item.request)
+				body, _ := ioutil.ReadAll(resp.Body)
+				if !item.check(resp, body) {
+					t.Errorf("[%d] unexpected body: %s", i, string(body))
+				}
+			}
+		})
+	}
+}
+
+func TestSignIn(t *testing.T) {
+	for _, strategy := range sessionStrategies() {
+		t.Run(strategy.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			service := mock.NewMockappService(ctrl)
+			handler := NewHandler(strategy.sessions(), newRefresher(), service, nil)
+
+			cases := []struct {
+				request *http.Request
+				writer  *httptest.ResponseRecorder
+				run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+				check   func(resp *http.Response, body []byte) bool
+			}{
+				{
+					request: httptest.NewRequest("POST", "/login", strings.NewReader("{\"username\":\"van\",\"password\":\"qqq\"}")),
+					writer:  httptest.NewRecorder(),
+					run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+						service.EXPECT().
+							LoginUser(model.Credential{Username: "van", Password: "qqq"}, "192.0.2.1").
+							Return(model.User{ID: "1", Credential: model.Credential{Username: "van"}}, nil)
+						handler.signIn(w, r)
+						return w.Result()
+					},
+					check: func(resp *http.Response, body []byte) bool {
+						return tokenIssued(resp, body)
+					},
+				},
+				{
+					request: httptest.NewRequest("POST", "/login", strings.NewReader("invalid json")),
+					writer:  httptest.NewRecorder(),
+					run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+						handler.signIn(w, r)
+						return w.Result()
+					},
+					check: func(resp *http.Response, body []byte) bool {
+						data := []byte("{\"message\":\"bad request\"}\n")
+						return reflect.DeepEqual(body, data)
+					},
+				},
+				{
+					request: httptest.NewRequest("POST", "/login", strings.NewReader("{\"username\":\"van\"}")),
+					writer:  httptest.NewRecorder(),
+					run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+						handler.signIn(w, r)
+						return w.Result()
+					},
+					check: func(resp *http.Response, body []byte) bool {
+						data := []byte("{\"errors\":[{\"location\":\"body\",\"param\":\"password\",\"value\":\"\",\"msg\":\"field is required\"}]}\n")
+						return reflect.DeepEqual(body, data)
+					},
+				},
+				{
+					request: httptest.NewRequest("POST", "/login", strings.NewReader("{\"username\":\"van\",\"password\":\"qqq\"}")),
+					writer:  httptest.NewRecorder(),
+					run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+						service.EXPECT().
+							LoginUser(model.Credential{Username: "van", Password: "qqq"}, "192.0.2.1").
+							Return(model.User{}, customerr.WrongCredential{Username: "van"})
+						handler.signIn(w, r)
+						return w.Result()
+					},
+					check: func(resp *http.Response, body []byte) bool {
+						data := []byte("{\"message\":\"wrong credential\"}\n")
+						return reflect.DeepEqual(body, data)
+					},
+				},
+				{
+					request: httptest.NewRequest("POST", "/login", strings.NewReader("{\"username\":\"van\",\"password\":\"qqq\"}")),
+					writer:  httptest.NewRecorder(),
+					run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+						service.EXPECT().
+							LoginUser(model.Credential{Username: "van", Password: "qqq"}, "192.0.2.1").
+							Return(model.User{}, customerr.LoginLocked{Username: "van", IP: "192.0.2.1", RetryAfter: 90 * time.Second})
+						handler.signIn(w, r)
+						return w.Result()
+					},
+					check: func(resp *http.Response, body []byte) bool {
+						data := []byte("{\"message\":\"too many failed logins\"}\n")
+						return resp.StatusCode == http.StatusTooManyRequests &&
+							resp.Header.Get("Retry-After") == "90" &&
+							reflect.DeepEqual(body, data)
+					},
+				},
+			}
+
+			for i, item := range cases {
+				resp := item.run(item.writer, item.request)
+				body, _ := ioutil.ReadAll(resp.Body)
+				if !item.check(resp, body) {
+					t.Errorf("[%d] unexpected body: %s", i, string(body))
+				}
+			}
+		})
+	}
+}
+
+func TestAuthorizeMiddleware(t *testing.T) {
+	for _, strategy := range sessionStrategies() {
+		t.Run(strategy.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			service := mock.NewMockappService(ctrl)
+			sessions := strategy.sessions()
+			handler := NewHandler(sessions, newRefresher(), service, nil)
+
+			next := handler.authorizeMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				usr := r.Context().Value("user").(model.User)
+				_, _ = w.Write([]byte(usr.ID))
+			}))
+
+			issuedResp, issuedBody := issueToken(sessions, session.AuthUser{ID: "1", Username: "van"})
+
+			cases := []struct {
+				request *http.Request
+				writer  *httptest.ResponseRecorder
+				run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+				check   func(body []byte) bool
+			}{
+				{
+					request: httptest.NewRequest("POST", "/api/posts", nil),
+					writer:  httptest.NewRecorder(),
+					run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+						service.EXPECT().GetUserByID("1").Return(model.User{ID: "1"}, nil)
+						next.ServeHTTP(w, authorizeRequest(r, issuedResp, issuedBody))
+						return w.Result()
+					},
+					check: func(body []byte) bool {
+						return reflect.DeepEqual([]byte("1"), body)
+					},
+				},
+				{
+					request: httptest.NewRequest("POST", "/api/posts", nil),
+					writer:  httptest.NewRecorder(),
+					run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+						next.ServeHTTP(w, r)
+						return w.Result()
+					},
+					check: func(body []byte) bool {
+						data := []byte("{\"message\":\"user unauthorized\"}\n")
+						return reflect.DeepEqual(data, body)
+					},
+				},
+				{
+					request: httptest.NewRequest("POST", "/api/posts", nil),
+					writer:  httptest.NewRecorder(),
+					run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+						service.EXPECT().GetUserByID("1").Return(model.User{}, customerr.UserNotFoundByID{UserID: "1"})
+						next.ServeHTTP(w, authorizeRequest(r, issuedResp, issuedBody))
+						return w.Result()
+					},
+					check: func(body []byte) bool {
+						data := []byte("{\"message\":\"user unauthorized\"}\n")
+						return reflect.DeepEqual(data, body)
+					},
+				},
+				{
+					request: httptest.NewRequest("POST", "/api/posts", nil),
+					writer:  httptest.NewRecorder(),
+					run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+						suspended := model.User{ID: "1", Suspension: &model.Suspension{Expires: "2999-01-01T00:00:00.000Z", Created: "2022-01-01T00:00:00.000Z"}}
+						service.EXPECT().GetUserByID("1").Return(suspended, nil)
+						next.ServeHTTP(w, authorizeRequest(r, issuedResp, issuedBody))
+						return w.Result()
+					},
+					check: func(body []byte) bool {
+						data := []byte("{\"message\":\"user is suspended\",\"expires\":\"2999-01-01T00:00:00.000Z\"}\n")
+						return reflect.DeepEqual(data, body)
+					},
+				},
+				{
+					request: httptest.NewRequest("POST", "/api/posts", nil),
+					writer:  httptest.NewRecorder(),
+					run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+						expired := model.User{ID: "1", Suspension: &model.Suspension{Expires: "2022-01-08T00:00:00.000Z", Created: "2022-01-01T00:00:00.000Z"}}
+						service.EXPECT().GetUserByID("1").Return(expired, nil)
+						next.ServeHTTP(w, authorizeRequest(r, issuedResp, issuedBody))
+						return w.Result()
+					},
+					check: func(body []byte) bool {
+						return reflect.DeepEqual([]byte("1"), body)
+					},
+				},
+				{
+					request: httptest.NewRequest("POST", "/api/posts", nil),
+					writer:  httptest.NewRecorder(),
+					run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+						service.EXPECT().GetUserByID("1").Return(model.User{}, errors.New("internal error"))
+						next.ServeHTTP(w, authorizeRequest(r, issuedResp, issuedBody))
+						return w.Result()
+					},
+					check: func(body []byte) bool {
+						data := []byte("{\"message\":\"internal error\"}\n")
+						return reflect.DeepEqual(data, body)
+					},
+				},
+			}
+
+			for i, item := range cases {
+				resp := item.run(item.writer, item.request)
+				body, _ := ioutil.ReadAll(resp.Body)
+				if !item.check(body) {
+					t.Errorf("[%d] unexpected body: %s", i, string(body))
+				}
+			}
+		})
+	}
+}
+
+func TestIdentifyMiddleware(t *testing.T) {
+	for _, strategy := range sessionStrategies() {
+		t.Run(strategy.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			service := mock.NewMockappService(ctrl)
+			sessions := strategy.sessions()
+			handler := NewHandler(sessions, newRefresher(), service, nil)
+
+			next := handler.identifyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				usr, ok := r.Context().Value("user").(model.User)
+				if !ok {
+					_, _ = w.Write([]byte("anonymous"))
+					return
+				}
+				_, _ = w.Write([]byte(usr.ID))
+			}))
+
+			issuedResp, issuedBody := issueToken(sessions, session.AuthUser{ID: "1", Username: "van"})
+
+			cases := []struct {
+				request *http.Request
+				writer  *httptest.ResponseRecorder
+				run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+				check   func(body []byte) bool
+			}{
+				{
+					request: httptest.NewRequest("GET", "/api/posts/", nil),
+					writer:  httptest.NewRecorder(),
+					run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+						service.EXPECT().GetUserByID("1").Return(model.User{ID: "1"}, nil)
+						next.ServeHTTP(w, authorizeRequest(r, issuedResp, issuedBody))
+						return w.Result()
+					},
+					check: func(body []byte) bool {
+						return reflect.DeepEqual([]byte("1"), body)
+					},
+				},
+				{
+					request: httptest.NewRequest("GET", "/api/posts/", nil),
+					writer:  httptest.NewRecorder(),
+					run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+						next.ServeHTTP(w, r)
+						return w.Result()
+					},
+					check: func(body []byte) bool {
+						return reflect.DeepEqual([]byte("anonymous"), body)
+					},
+				},
+				{
+					request: httptest.NewRequest("GET", "/api/posts/", nil),
+					writer:  httptest.NewRecorder(),
+					run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+						service.EXPECT().GetUserByID("1").Return(model.User{}, customerr.UserNotFoundByID{UserID: "1"})
+						next.ServeHTTP(w, authorizeRequest(r, issuedResp, issuedBody))
+						return w.Result()
+					},
+					check: func(body []byte) bool {
+						return reflect.DeepEqual([]byte("anonymous"), body)
+					},
+				},
+				{
+					request: httptest.NewRequest("GET", "/api/posts/", nil),
+					writer:  httptest.NewRecorder(),
+					run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+						service.EXPECT().GetUserByID("1").Return(model.User{}, errors.New("internal error"))
+						next.ServeHTTP(w, authorizeRequest(r, issuedResp, issuedBody))
+						return w.Result()
+					},
+					check: func(body []byte) bool {
+						data := []byte("{\"message\":\"internal error\"}\n")
+						return reflect.DeepEqual(data, body)
+					},
+				},
+			}
+
+			for i, item := range cases {
+				resp := item.run(item.writer, item.request)
+				body, _ := ioutil.ReadAll(resp.Body)
+				if !item.check(body) {
+					t.Errorf("[%d] unexpected body: %s", i, string(body))
+				}
+			}
+		})
+	}
+}
+
+func TestRefreshToken(t *testing.T) {
+	for _, strategy := range sessionStrategies() {
+		t.Run(strategy.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			service := mock.NewMockappService(ctrl)
+			refresher := newRefresher()
+			handler := NewHandler(strategy.sessions(), refresher, service, nil)
+
+			issued, _ := refresher.Issue(token.AuthUser{ID: "1", Username: "van"})
+			var rotated string
+
+			cases := []struct {
+				request *http.Request
+				writer  *httptest.ResponseRecorder
+				run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+				check   func(resp *http.Response, body []byte) bool
+			}{
+				{
+					request: httptest.NewRequest("POST", "/api/token/refresh", strings.NewReader("{\"refresh_token\":\""+issued+"\"}")),
+					writer:  httptest.NewRecorder(),
+					run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+						service.EXPECT().GetUserByID("1").Return(model.User{ID: "1", Credential: model.Credential{Username: "van"}}, nil)
+						handler.refreshToken(w, r)
+						return w.Result()
+					},
+					check: func(resp *http.Response, body []byte) bool {
+						var t struct {
+							RefreshToken string `json:"refresh_token"`
+						}
+						_ = json.Unmarshal(body, &t)
+						rotated = t.RefreshToken
+						return tokenIssued(resp, body) && rotated != "" && rotated != issued
+					},
+				},
+				{
+					request: httptest.NewRequest("POST", "/api/token/refresh", strings.NewReader("{\"refresh_token\":\""+issued+"\"}")),
+					writer:  httptest.NewRecorder(),
+					run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+						handler.refreshToken(w, r)
+						return w.Result()
+					},
+					check: func(resp *http.Response, body []byte) bool {
+						data := []byte("{\"message\":\"user unauthorized\"}\n")
+						return reflect.DeepEqual(data, body)
+					},
+				},
+				{
+					request: httptest.NewRequest("POST", "/api/token/refresh", nil),
+					writer:  httptest.NewRecorder(),
+					run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+						// reuse of the first token has revoked the whole family
+						r = httptest.NewRequest("POST", "/api/token/refresh", strings.NewReader("{\"refresh_token\":\""+rotated+"\"}"))
+						handler.refreshToken(w, r)
+						return w.Result()
+					},
+					check: func(resp *http.Response, body []byte) bool {
+						data := []byte("{\"message\":\"user unauthorized\"}\n")
+						return reflect.DeepEqual(data, body)
+					},
+				},
+				{
+					request: httptest.NewRequest("POST", "/api/token/refresh", strings.NewReader("invalid json")),
+					writer:  httptest.NewRecorder(),
+					run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+						handler.refreshToken(w, r)
+						return w.Result()
+					},
+					check: func(resp *http.Response, body []byte) bool {
+						data := []byte("{\"message\":\"bad request\"}\n")
+						return reflect.DeepEqual(data, body)
+					},
+				},
+				{
+					request: httptest.NewRequest("POST", "/api/token/refresh", strings.NewReader("{}")),
+					writer:  httptest.NewRecorder(),
+					run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+						handler.refreshToken(w, r)
+						return w.Result()
+					},
+					check: func(resp *http.Response, body []byte) bool {
+						data := []byte("{\"errors\":[{\"location\":\"body\",\"param\":\"refresh_token\",\"value\":\"\",\"msg\":\"field is required\"}]}\n")
+						return reflect.DeepEqual(data, body)
+					},
+				},
+			}
+
+			for i, item := range cases {
+				resp := item.run(item.writer, item.request)
+				body, _ := ioutil.ReadAll(resp.Body)
+				if !item.check(resp, body) {
+					t.Errorf("[%d] unexpected body: %s", i, string(body))
+				}
+			}
+		})
+	}
+}
+
+// TestConcurrentRefresh rotates the same refresh token in parallel, only one of the rotations may pass
+func TestConcurrentRefresh(t *testing.T) {
+	refresher := newRefresher()
+
+	for round := 0; round < 50; round++ {
+		issued, err := refresher.Issue(token.AuthUser{ID: "1", Username: "van"})
+		if err != nil {
+			t.Fatalf("cant issue a refresh token: %s", err)
+		}
+
+		var wg sync.WaitGroup
+		start := make(chan struct{})
+		results := make([]error, 2)
+		for i := range results {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				<-start
+				_, _, results[i] = refresher.Rotate(issued)
+			}(i)
+		}
+		close(start)
+		wg.Wait()
+
+		passed := 0
+		for _, err = range results {
+			if err == nil {
+				passed++
+			} else if err != token.ErrRefreshTokenReused && err != token.ErrRefreshTokenInvalid {
+				t.Errorf("[%d] unexpected error: %s", round, err)
+			}
+		}
+		if passed != 1 {
+			t.Errorf("[%d] expected one rotation to pass, %d passed", round, passed)
+		}
+	}
+}
+
+func TestLogout(t *testing.T) {
+	for _, strategy := range sessionStrategies() {
+		t.Run(strategy.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			service := mock.NewMockappService(ctrl)
+			sessions := strategy.sessions()
+			refresher := newRefresher()
+			handler := NewHandler(sessions, refresher, service, nil)
+
+			firstResp, firstBody := issueToken(sessions, session.AuthUser{ID: "1", Username: "van"})
+			secondResp, secondBody := issueToken(sessions, session.AuthUser{ID: "1", Username: "van"})
+			refresh, _ := refresher.Issue(token.AuthUser{ID: "1", Username: "van"})
+			thirdResp, thirdBody := issueToken(sessions, session.AuthUser{ID: "1", Username: "van"})
+			foreignRefresh, _ := refresher.Issue(token.AuthUser{ID: "2", Username: "ivan"})
+
+			cases := []struct {
+				request *http.Request
+				writer  *httptest.ResponseRecorder
+				run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+				check   func(body []byte) bool
+			}{
+				{
+					request: httptest.NewRequest("POST", "/api/logout", strings.NewReader("{\"refresh_token\":\""+foreignRefresh+"\"}")),
+					writer:  httptest.NewRecorder(),
+					run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+						r = authorizeRequest(r, thirdResp, thirdBody)
+						ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+						handler.logout(w, r.WithContext(ctx))
+						return w.Result()
+					},
+					check: func(body []byte) bool {
+						// the token of another user is ignored, its family stays alive
+						data := []byte("{\"message\": \"success\"}")
+						_, _, refreshErr := refresher.Rotate(foreignRefresh)
+						return reflect.DeepEqual(data, body) && refreshErr == nil
+					},
+				},
+				{
+					request: httptest.NewRequest("POST", "/api/logout", strings.NewReader("{\"refresh_token\":\""+refresh+"\"}")),
+					writer:  httptest.NewRecorder(),
+					run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+						r = authorizeRequest(r, firstResp, firstBody)
+						ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+						handler.logout(w, r.WithContext(ctx))
+						return w.Result()
+					},
+					check: func(body []byte) bool {
+						data := []byte("{\"message\": \"success\"}")
+						_, _, refreshErr := refresher.Rotate(refresh)
+						if !reflect.DeepEqual(data, body) || refreshErr != token.ErrRefreshTokenInvalid {
+							return false
+						}
+						if !strategy.revocable {
+							return true
+						}
+
+						revoked, _ := sessions.GetTokenFromRequest(authorizeRequest(httptest.NewRequest("GET", "/", nil), firstResp, firstBody))
+						alive, _ := sessions.GetTokenFromRequest(authorizeRequest(httptest.NewRequest("GET", "/", nil), secondResp, secondBody))
+						_, revokedErr := sessions.GetUserByToken(revoked)
+						_, aliveErr := sessions.GetUserByToken(alive)
+						return revokedErr == cookie.ErrNotFound && aliveErr == nil
+					},
+				},
+				{
+					request: httptest.NewRequest("POST", "/api/logout", nil),
+					writer:  httptest.NewRecorder(),
+					run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+						r = authorizeRequest(r, secondResp, secondBody)
+						ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+						handler.logout(w, r.WithContext(ctx))
+						return w.Result()
+					},
+					check: func(body []byte) bool {
+						data := []byte("{\"message\": \"success\"}")
+						return reflect.DeepEqual(data, body)
+					},
+				},
+				{
+					request: httptest.NewRequest("POST", "/api/logout", nil),
+					writer:  httptest.NewRecorder(),
+					run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+						ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+						handler.logout(w, r.WithContext(ctx))
+						return w.Result()
+					},
+					check: func(body []byte) bool {
+						data := []byte("{\"message\":\"user unauthorized\"}\n")
+						return reflect.DeepEqual(data, body)
+					},
+				},
+			}
+
+			for i, item := range cases {
+				resp := item.run(item.writer, item.request)
+				body, _ := ioutil.ReadAll(resp.Body)
+				if !item.check(body) {
+					t.Errorf("[%d] unexpected body: %s", i, string(body))
+				}
+			}
+		})
+	}
+}
+
+func TestLogoutAll(t *testing.T) {
+	for _, strategy := range sessionStrategies() {
+		t.Run(strategy.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			service := mock.NewMockappService(ctrl)
+			sessions := strategy.sessions()
+			refresher := newRefresher()
+			handler := NewHandler(sessions, refresher, service, nil)
+
+			firstResp, firstBody := issueToken(sessions, session.AuthUser{ID: "1", Username: "van"})
+			secondResp, secondBody := issueToken(sessions, session.AuthUser{ID: "1", Username: "van"})
+			otherResp, otherBody := issueToken(sessions, session.AuthUser{ID: "2", Username: "ivan"})
+			refresh, _ := refresher.Issue(token.AuthUser{ID: "1", Username: "van"})
+			otherRefresh, _ := refresher.Issue(token.AuthUser{ID: "2", Username: "ivan"})
+
+			cases := []struct {
+				request *http.Request
+				writer  *httptest.ResponseRecorder
+				run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+				check   func(body []byte) bool
+			}{
+				{
+					request: httptest.NewRequest("POST", "/api/logout/all", nil),
+					writer:  httptest.NewRecorder(),
+					run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+						ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+						handler.logoutAll(w, r.WithContext(ctx))
+						return w.Result()
+					},
+					check: func(body []byte) bool {
+						data := []byte("{\"message\": \"success\"}")
+						_, _, refreshErr := refresher.Rotate(refresh)
+						_, _, otherRefreshErr := refresher.Rotate(otherRefresh)
+						if !reflect.DeepEqual(data, body) || refreshErr != token.ErrRefreshTokenInvalid || otherRefreshErr != nil {
+							return false
+						}
+						if !strategy.revocable {
+							return true
+						}
+
+						first, _ := sessions.GetTokenFromRequest(authorizeRequest(httptest.NewRequest("GET", "/", nil), firstResp, firstBody))
+						second, _ := sessions.GetTokenFromRequest(authorizeRequest(httptest.NewRequest("GET", "/", nil), secondResp, secondBody))
+						other, _ := sessions.GetTokenFromRequest(authorizeRequest(httptest.NewRequest("GET", "/", nil), otherResp, otherBody))
+						_, firstErr := sessions.GetUserByToken(first)
+						_, secondErr := sessions.GetUserByToken(second)
+						_, otherUserErr := sessions.GetUserByToken(other)
+						return firstErr == cookie.ErrNotFound && secondErr == cookie.ErrNotFound && otherUserErr == nil
+					},
+				},
+			}
+
+			for i, item := range cases {
+				resp := item.run(item.writer, item.request)
+				body, _ := ioutil.ReadAll(resp.Body)
+				if !item.check(body) {
+					t.Errorf("[%d] unexpected body: %s", i, string(body))
+				}
+			}
+		})
+	}
+}
+
+func TestGetAllPosts(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	service := mock.NewMockappService(ctrl)
@@ -41,173 +813,134 @@ func TestSignUp(t *testing.T) {
 		check   func(body []byte) bool
 	}{
 		{
-			request: httptest.NewRequest("POST", "/register", strings.NewReader("{\"username\":\"van\",\"password\":\"qqq\"}")),
+			request: httptest.NewRequest("GET", "/api/posts", nil),
 			writer:  httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
-				service.EXPECT().RegisterUser(model.Credential{Username: "van", Password: "qqq"})
-				handler.signUp(w, r)
+				service.EXPECT().GetAllPosts().Return([]model.Post{{ID: "1"}, {ID: "2"}, {ID: "3"}}, nil)
+				handler.getAllPosts(w, r)
 				return w.Result()
 			},
 			check: func(body []byte) bool {
-				matched, _ := regexp.MatchString("^{\"token\": \".*\"}$", string(body))
-				return matched
+				data, _ := json.Marshal([]model.Post{{ID: "1"}, {ID: "2"}, {ID: "3"}})
+				return reflect.DeepEqual(data, body)
 			},
 		},
 		{
-			request: httptest.NewRequest("POST", "/register", strings.NewReader("invalid json")),
+			request: httptest.NewRequest("GET", "/api/posts", nil),
 			writer:  httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
-				handler.signUp(w, r)
+				service.EXPECT().GetAllPosts().Return(nil, errors.New("internal error"))
+				handler.getAllPosts(w, r)
 				return w.Result()
 			},
 			check: func(body []byte) bool {
-				data := []byte("{\"message\":\"bad request\"}\n")
-				return reflect.DeepEqual(body, data)
+				data := []byte("{\"message\":\"internal error\"}\n")
+				return reflect.DeepEqual(data, body)
 			},
 		},
 		{
-			request: httptest.NewRequest("POST", "/register", strings.NewReader("{\"username\":\"van\"}")),
+			request: httptest.NewRequest("GET", "/api/posts?limit=2", nil),
 			writer:  httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
-				handler.signUp(w, r)
+				service.EXPECT().GetPosts(model.PostsQuery{Limit: 2}).
+					Return(model.PostsPage{Posts: []model.Post{{ID: "1"}, {ID: "2"}}, NextCursor: "next"}, nil)
+				handler.getAllPosts(w, r)
 				return w.Result()
 			},
 			check: func(body []byte) bool {
-				data := []byte("{\"errors\":[{\"location\":\"body\",\"param\":\"password\",\"value\":\"\",\"msg\":\"field is required\"}]}\n")
-				return reflect.DeepEqual(body, data)
+				data, _ := json.Marshal(model.PostsPage{Posts: []model.Post{{ID: "1"}, {ID: "2"}}, NextCursor: "next"})
+				return reflect.DeepEqual(data, body)
 			},
 		},
 		{
-			request: httptest.NewRequest("POST", "/register", strings.NewReader("{\"username\":\"van\",\"password\":\"qqq\"}")),
+			request: httptest.NewRequest("GET", "/api/posts?cursor=eyJjcmVhdGVkIjoiMSIsImlkIjoiMSJ9", nil),
 			writer:  httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
-				service.EXPECT().
-					RegisterUser(model.Credential{Username: "van", Password: "qqq"}).
-					Return(model.User{}, customerr.UserAlreadyExists{Username: "van"})
-				handler.signUp(w, r)
+				service.EXPECT().GetPosts(model.PostsQuery{Cursor: "eyJjcmVhdGVkIjoiMSIsImlkIjoiMSJ9"}).
+					Return(model.PostsPage{Posts: []model.Post{{ID: "3"}}}, nil)
+				handler.getAllPosts(w, r)
 				return w.Result()
 			},
 			check: func(body []byte) bool {
-				data := []byte("{\"errors\":[{\"location\":\"body\",\"param\":\"username\",\"value\":\"van\",\"msg\":\"already exists\"}]}\n")
-				return reflect.DeepEqual(body, data)
+				data, _ := json.Marshal(model.PostsPage{Posts: []model.Post{{ID: "3"}}})
+				return reflect.DeepEqual(data, body)
 			},
 		},
-	}
-
-	for i, item := range cases {
-		resp := item.run(item.writer, item.request)
-		body, _ := ioutil.ReadAll(resp.Body)
-		if !item.check(body) {
-			t.Errorf("[%d] unexpected body: %s", i, string(body))
-		}
-	}
-}
-
-func TestSignIn(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-	service := mock.NewMockappService(ctrl)
-	handler := initHandler(ctrl, service)
-
-	cases := []struct {
-		request *http.Request
-		writer  *httptest.ResponseRecorder
-		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
-		check   func(body []byte) bool
-	}{
 		{
-			request: httptest.NewRequest("POST", "/register", strings.NewReader("{\"username\":\"van\",\"password\":\"qqq\"}")),
+			request: httptest.NewRequest("GET", "/api/posts?limit=1000&cursor=bad", nil),
 			writer:  httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
-				service.EXPECT().LoginUser(model.Credential{Username: "van", Password: "qqq"})
-				handler.signIn(w, r)
+				handler.getAllPosts(w, r)
 				return w.Result()
 			},
 			check: func(body []byte) bool {
-				matched, _ := regexp.MatchString("^{\"token\": \".*\"}$", string(body))
-				return matched
+				data := []byte(
+					"{\"errors\":[{\"location\":\"query\",\"param\":\"limit\",\"value\":\"1000\",\"msg\":\"limit must be an integer from 1 to 100\"}," +
+						"{\"location\":\"query\",\"param\":\"cursor\",\"value\":\"bad\",\"msg\":\"cursor must be a value of next_cursor\"}]}\n",
+				)
+				return reflect.DeepEqual(data, body)
 			},
 		},
 		{
-			request: httptest.NewRequest("POST", "/register", strings.NewReader("invalid json")),
+			request: httptest.NewRequest("GET", "/api/posts?limit=2", nil),
 			writer:  httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
-				handler.signIn(w, r)
+				service.EXPECT().GetPosts(model.PostsQuery{Limit: 2}).Return(model.PostsPage{}, errors.New("internal error"))
+				handler.getAllPosts(w, r)
 				return w.Result()
 			},
 			check: func(body []byte) bool {
-				data := []byte("{\"message\":\"bad request\"}\n")
-				return reflect.DeepEqual(body, data)
+				data := []byte("{\"message\":\"internal error\"}\n")
+				return reflect.DeepEqual(data, body)
 			},
 		},
 		{
-			request: httptest.NewRequest("POST", "/register", strings.NewReader("{\"username\":\"van\"}")),
+			request: httptest.NewRequest("GET", "/api/posts?sort=hot", nil),
 			writer:  httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
-				handler.signIn(w, r)
+				service.EXPECT().ListPosts(model.PostsQuery{Sort: model.SortHot}).Return([]model.Post{{ID: "2"}, {ID: "1"}}, nil)
+				handler.getAllPosts(w, r)
 				return w.Result()
 			},
 			check: func(body []byte) bool {
-				data := []byte("{\"errors\":[{\"location\":\"body\",\"param\":\"password\",\"value\":\"\",\"msg\":\"field is required\"}]}\n")
-				return reflect.DeepEqual(body, data)
+				data, _ := json.Marshal([]model.Post{{ID: "2"}, {ID: "1"}})
+				return reflect.DeepEqual(data, body)
 			},
 		},
 		{
-			request: httptest.NewRequest("POST", "/register", strings.NewReader("{\"username\":\"van\",\"password\":\"qqq\"}")),
+			request: httptest.NewRequest("GET", "/api/posts?sort=top&t=week&limit=1", nil),
 			writer:  httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
-				service.EXPECT().
-					LoginUser(model.Credential{Username: "van", Password: "qqq"}).
-					Return(model.User{}, customerr.WrongCredential{Username: "van"})
-				handler.signIn(w, r)
+				service.EXPECT().GetPosts(model.PostsQuery{Sort: model.SortTop, Window: model.TopWindowWeek, Limit: 1}).
+					Return(model.PostsPage{Posts: []model.Post{{ID: "1"}}, NextCursor: "next"}, nil)
+				handler.getAllPosts(w, r)
 				return w.Result()
 			},
 			check: func(body []byte) bool {
-				data := []byte("{\"message\":\"wrong credential\"}\n")
-				return reflect.DeepEqual(body, data)
+				data, _ := json.Marshal(model.PostsPage{Posts: []model.Post{{ID: "1"}}, NextCursor: "next"})
+				return reflect.DeepEqual(data, body)
 			},
 		},
-	}
-
-	for i, item := range cases {
-		resp := item.run(item.writer, item.request)
-		body, _ := ioutil.ReadAll(resp.Body)
-		if !item.check(body) {
-			t.Errorf("[%d] unexpected body: %s", i, string(body))
-		}
-	}
-}
-
-func TestGetAllPosts(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-	service := mock.NewMockappService(ctrl)
-	handler := initHandler(ctrl, service)
-
-	cases := []struct {
-		request *http.Request
-		writer  *httptest.ResponseRecorder
-		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
-		check   func(body []byte) bool
-	}{
 		{
-			request: httptest.NewRequest("GET", "/api/posts", nil),
+			request: httptest.NewRequest("GET", "/api/posts?sort=best&t=decade", nil),
 			writer:  httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
-				service.EXPECT().GetAllPosts().Return([]model.Post{{ID: "1"}, {ID: "2"}, {ID: "3"}}, nil)
 				handler.getAllPosts(w, r)
 				return w.Result()
 			},
 			check: func(body []byte) bool {
-				data, _ := json.Marshal([]model.Post{{ID: "1"}, {ID: "2"}, {ID: "3"}})
+				data := []byte(
+					"{\"errors\":[{\"location\":\"query\",\"param\":\"sort\",\"value\":\"best\",\"msg\":\"sort must be a hot, a new, a top, a rising or a controversial\"}," +
+						"{\"location\":\"query\",\"param\":\"t\",\"value\":\"decade\",\"msg\":\"t must be an hour, a day, a week, a month, a year or all\"}]}\n",
+				)
 				return reflect.DeepEqual(data, body)
 			},
 		},
 		{
-			request: httptest.NewRequest("GET", "/api/posts", nil),
+			request: httptest.NewRequest("GET", "/api/posts?sort=rising", nil),
 			writer:  httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
-				service.EXPECT().GetAllPosts().Return(nil, errors.New("internal error"))
+				service.EXPECT().ListPosts(model.PostsQuery{Sort: model.SortRising}).Return(nil, errors.New("internal error"))
 				handler.getAllPosts(w, r)
 				return w.Result()
 			},
@@ -232,6 +965,7 @@ func TestGetPostsByCategory(t *testing.T) {
 	defer ctrl.Finish()
 	service := mock.NewMockappService(ctrl)
 	handler := initHandler(ctrl, service)
+	expectCommunities(service, "funny")
 
 	cases := []struct {
 		request *http.Request
@@ -253,6 +987,21 @@ func TestGetPostsByCategory(t *testing.T) {
 				return reflect.DeepEqual(data, body)
 			},
 		},
+		{
+			request: httptest.NewRequest("GET", "/api/posts/category?limit=1", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().GetPosts(model.PostsQuery{Category: "funny", Limit: 1}).
+					Return(model.PostsPage{Posts: []model.Post{{ID: "1"}}, NextCursor: "next"}, nil)
+				r = mux.SetURLVars(r, map[string]string{"category": "funny"})
+				handler.getPostsByCategory(w, r)
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data, _ := json.Marshal(model.PostsPage{Posts: []model.Post{{ID: "1"}}, NextCursor: "next"})
+				return reflect.DeepEqual(data, body)
+			},
+		},
 		{
 			request: httptest.NewRequest("GET", "/api/posts/category", nil),
 			writer:  httptest.NewRecorder(),
@@ -263,7 +1012,7 @@ func TestGetPostsByCategory(t *testing.T) {
 			},
 			check: func(body []byte) bool {
 				data := []byte(
-					"{\"errors\":[{\"location\":\"path\",\"param\":\"category\",\"value\":\"bad_category\",\"msg\":\"category must has specific type\"}]}\n",
+					"{\"errors\":[{\"location\":\"path\",\"param\":\"category\",\"value\":\"bad_category\",\"msg\":\"category must be an existing community\"}]}\n",
 				)
 				return reflect.DeepEqual(data, body)
 			},
@@ -306,7 +1055,7 @@ func TestGetPostsByUsername(t *testing.T) {
 		check   func(body []byte) bool
 	}{
 		{
-			request: httptest.NewRequest("GET", "/api/user/username", nil),
+			request: httptest.NewRequest("GET", "/api/user/username/posts", nil),
 			writer:  httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
 				service.EXPECT().GetPostsByAuthor("username").Return([]model.Post{{ID: "1"}, {ID: "2"}, {ID: "3"}}, nil)
@@ -320,7 +1069,22 @@ func TestGetPostsByUsername(t *testing.T) {
 			},
 		},
 		{
-			request: httptest.NewRequest("GET", "/api/user/username", nil),
+			request: httptest.NewRequest("GET", "/api/user/username/posts?limit=1", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().GetPosts(model.PostsQuery{Author: "username", Limit: 1}).
+					Return(model.PostsPage{Posts: []model.Post{{ID: "1"}}}, nil)
+				r = mux.SetURLVars(r, map[string]string{"username": "username"})
+				handler.getPostsByUsername(w, r)
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data, _ := json.Marshal(model.PostsPage{Posts: []model.Post{{ID: "1"}}})
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/user/username/posts", nil),
 			writer:  httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
 				r = mux.SetURLVars(r, map[string]string{"username": ""})
@@ -333,7 +1097,7 @@ func TestGetPostsByUsername(t *testing.T) {
 			},
 		},
 		{
-			request: httptest.NewRequest("GET", "/api/user/username", nil),
+			request: httptest.NewRequest("GET", "/api/user/username/posts", nil),
 			writer:  httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
 				service.EXPECT().GetPostsByAuthor("username").Return(nil, errors.New("internal error"))
@@ -357,49 +1121,24 @@ func TestGetPostsByUsername(t *testing.T) {
 	}
 }
 
-func TestCreatePost(t *testing.T) {
+func TestGetProfile(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	service := mock.NewMockappService(ctrl)
 	handler := initHandler(ctrl, service)
 
-	textPostInput := model.TextPostInput{
-		Type:     "text",
-		Category: "funny",
-		Title:    "bebe",
-		Text:     "bubu",
-	}
-	textPostInputBody, _ := json.Marshal(textPostInput)
-
-	urlPostInput := model.URLPostInput{
-		Type:     "link",
-		Category: "funny",
-		Title:    "bebe",
-		URL:      "bubu",
-	}
-	urlPostInputBody, _ := json.Marshal(urlPostInput)
-
-	untypedPostInput := model.TextPostInput{
-		Category: "funny",
-		Title:    "bebe",
-		Text:     "bubu",
-	}
-	untypedPostInputBody, _ := json.Marshal(untypedPostInput)
-
-	textPostInputWithoutCategory := model.TextPostInput{
-		Type:     "text",
-		Category: "kek",
-		Title:    "bebe",
-		Text:     "bubu",
-	}
-	textPostInputWithoutCategoryBody, _ := json.Marshal(textPostInputWithoutCategory)
-
-	invalidTextPostInput := model.TextPostInput{
-		Category: "funny",
-		Type:     "text",
-		Title:    "bebe",
+	profile := model.Profile{
+		ID:           "1",
+		Username:     "van",
+		Created:      "2022-01-01T00:00:00.000Z",
+		Karma:        model.Karma{Post: 5, Comment: -1},
+		PostCount:    1,
+		CommentCount: 1,
+		RecentActivity: []model.Activity{
+			{Type: model.ActivityComment, PostID: "2", CommentID: "3", Body: "body", Score: -1, Created: "2022-01-03T00:00:00.000Z"},
+			{Type: model.ActivityPost, PostID: "2", Title: "title", Score: 5, Created: "2022-01-02T00:00:00.000Z"},
+		},
 	}
-	invalidTextPostInputBody, _ := json.Marshal(invalidTextPostInput)
 
 	cases := []struct {
 		request *http.Request
@@ -408,50 +1147,229 @@ func TestCreatePost(t *testing.T) {
 		check   func(body []byte) bool
 	}{
 		{
-			request: httptest.NewRequest("POST", "/api/posts", bytes.NewReader(textPostInputBody)),
+			request: httptest.NewRequest("GET", "/api/user/van", nil),
 			writer:  httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
-				service.
-					EXPECT().
-					CreateTextPost(model.TextPostInput{
-						Category: "funny",
-						Type:     "text",
-						Title:    "bebe",
-						Text:     "bubu",
-					}, model.User{ID: "1"}).Return(model.Post{ID: "1"}, nil)
-				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
-				handler.createPost(w, r.WithContext(ctx))
+				service.EXPECT().GetProfile("van").Return(profile, nil)
+				r = mux.SetURLVars(r, map[string]string{"username": "van"})
+				handler.getProfile(w, r)
 				return w.Result()
 			},
 			check: func(body []byte) bool {
-				data, _ := json.Marshal(model.Post{ID: "1"})
+				data := []byte("{\"id\":\"1\",\"username\":\"van\",\"created\":\"2022-01-01T00:00:00.000Z\",\"karma\":{\"post\":5,\"comment\":-1},\"postCount\":1,\"commentCount\":1,\"recentActivity\":[{\"type\":\"comment\",\"postId\":\"2\",\"commentId\":\"3\",\"body\":\"body\",\"score\":-1,\"created\":\"2022-01-03T00:00:00.000Z\"},{\"type\":\"post\",\"postId\":\"2\",\"title\":\"title\",\"score\":5,\"created\":\"2022-01-02T00:00:00.000Z\"}]}")
 				return reflect.DeepEqual(data, body)
 			},
 		},
 		{
-			request: httptest.NewRequest("POST", "/api/posts", strings.NewReader("invalid json")),
+			request: httptest.NewRequest("GET", "/api/user/ivan", nil),
 			writer:  httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
-				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
-				handler.createPost(w, r.WithContext(ctx))
+				service.EXPECT().GetProfile("ivan").Return(model.Profile{}, customerr.UserNotFoundByUsername{Username: "ivan"})
+				r = mux.SetURLVars(r, map[string]string{"username": "ivan"})
+				handler.getProfile(w, r)
 				return w.Result()
 			},
 			check: func(body []byte) bool {
-				data := []byte("{\"message\":\"bad request\"}\n")
+				data := []byte("{\"message\":\"user not found\"}\n")
 				return reflect.DeepEqual(data, body)
 			},
 		},
-		{
-			request: httptest.NewRequest("POST", "/api/posts", bytes.NewReader(urlPostInputBody)),
-			writer:  httptest.NewRecorder(),
-			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
-				service.
-					EXPECT().
-					CreateURLPost(model.URLPostInput{
-						Category: "funny",
-						Type:     "link",
-						Title:    "bebe",
-						URL:      "bubu",
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	page := model.SearchPage{
+		Results: []model.SearchResult{
+			model.NewCommentResult(model.Comment{ID: "1", PostID: "2", Body: "gopher", Created: "2022-01-02T00:00:00.000Z"}, 1.5),
+		},
+		NextCursor: "eyJzb3J0IjoibmV3IiwicG9zdHMiOjAsImNvbW1lbnRzIjoxfQ",
+	}
+	pageBody, _ := json.Marshal(page)
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest("GET", "/api/search?q=gopher&community=programming&author=van&from=2022-01-01&to=2022-01-03T03:00:00%2B03:00&sort=new&limit=1", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().Search(model.SearchQuery{
+					Text:      "gopher",
+					Community: "programming",
+					Author:    "van",
+					From:      "2022-01-01T00:00:00.000Z",
+					To:        "2022-01-03T00:00:00.000Z",
+					Sort:      model.SearchSortNew,
+					Limit:     1,
+				}).Return(page, nil)
+				handler.search(w, r)
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				return reflect.DeepEqual(pageBody, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/search?q=+", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				handler.search(w, r)
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"query\",\"param\":\"q\",\"value\":\" \",\"msg\":\"q must be a non-empty string\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/search?q=gopher&sort=hot&from=yesterday", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				handler.search(w, r)
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"query\",\"param\":\"from\",\"value\":\"yesterday\",\"msg\":\"time must be a date or an RFC 3339 time\"},{\"location\":\"query\",\"param\":\"sort\",\"value\":\"hot\",\"msg\":\"sort must be a relevance or a new\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/search?q=gopher&cursor="+page.NextCursor, nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().Search(model.SearchQuery{Text: "gopher", Cursor: page.NextCursor}).
+					Return(model.SearchPage{}, customerr.InvalidCursor{Cursor: page.NextCursor})
+				handler.search(w, r)
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"query\",\"param\":\"cursor\",\"value\":\"" + page.NextCursor + "\",\"msg\":\"cursor must be a value of next_cursor\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestCreatePost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+	expectCommunities(service, "funny")
+
+	textPostInput := model.TextPostInput{
+		Type:     "text",
+		Category: "funny",
+		Title:    "bebe",
+		Text:     "bubu",
+	}
+	textPostInputBody, _ := json.Marshal(textPostInput)
+
+	urlPostInput := model.URLPostInput{
+		Type:     "link",
+		Category: "funny",
+		Title:    "bebe",
+		URL:      "bubu",
+	}
+	urlPostInputBody, _ := json.Marshal(urlPostInput)
+
+	untypedPostInput := model.TextPostInput{
+		Category: "funny",
+		Title:    "bebe",
+		Text:     "bubu",
+	}
+	untypedPostInputBody, _ := json.Marshal(untypedPostInput)
+
+	textPostInputWithoutCategory := model.TextPostInput{
+		Type:     "text",
+		Category: "kek",
+		Title:    "bebe",
+		Text:     "bubu",
+	}
+	textPostInputWithoutCategoryBody, _ := json.Marshal(textPostInputWithoutCategory)
+
+	invalidTextPostInput := model.TextPostInput{
+		Category: "funny",
+		Type:     "text",
+		Title:    "bebe",
+	}
+	invalidTextPostInputBody, _ := json.Marshal(invalidTextPostInput)
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest("POST", "/api/posts", bytes.NewReader(textPostInputBody)),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.
+					EXPECT().
+					CreateTextPost(model.TextPostInput{
+						Category: "funny",
+						Type:     "text",
+						Title:    "bebe",
+						Text:     "bubu",
+					}, model.User{ID: "1"}).Return(model.Post{ID: "1"}, nil)
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.createPost(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data, _ := json.Marshal(model.Post{ID: "1"})
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("POST", "/api/posts", strings.NewReader("invalid json")),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.createPost(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"bad request\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("POST", "/api/posts", bytes.NewReader(urlPostInputBody)),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.
+					EXPECT().
+					CreateURLPost(model.URLPostInput{
+						Category: "funny",
+						Type:     "link",
+						Title:    "bebe",
+						URL:      "bubu",
 					}, model.User{ID: "1"}).Return(model.Post{ID: "1"}, nil)
 				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
 				handler.createPost(w, r.WithContext(ctx))
@@ -471,7 +1389,7 @@ func TestCreatePost(t *testing.T) {
 				return w.Result()
 			},
 			check: func(body []byte) bool {
-				data := []byte("{\"errors\":[{\"location\":\"body\",\"param\":\"category\",\"value\":\"kek\",\"msg\":\"category must has specific type\"}]}\n")
+				data := []byte("{\"errors\":[{\"location\":\"body\",\"param\":\"category\",\"value\":\"kek\",\"msg\":\"category must be an existing community\"}]}\n")
 				return reflect.DeepEqual(data, body)
 			},
 		},
@@ -559,6 +1477,25 @@ func TestGetPost(t *testing.T) {
 				return reflect.DeepEqual(data, body)
 			},
 		},
+		{
+			request: httptest.NewRequest("GET", "/api/post/111111111111111111111111", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				post := model.Post{ID: "111111111111111111111111"}
+				voted := post
+				voted.SetUserVote(model.Vote{UserID: "1", PostID: post.ID, Vote: model.VoteUp})
+				service.EXPECT().GetPostByID("111111111111111111111111").Return(post, nil)
+				service.EXPECT().FillUserVotes([]model.Post{post}, model.User{ID: "1"}).Return([]model.Post{voted}, nil)
+				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.getPost(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"id\":\"111111111111111111111111\",\"views\":0,\"type\":\"\",\"title\":\"\",\"author\":{\"id\":\"\",\"username\":\"\"},\"category\":\"\",\"commentCount\":0,\"created\":\"\",\"score\":0,\"ups\":0,\"downs\":0,\"upvotePercentage\":0,\"votes\":[{\"user\":\"1\",\"postId\":\"111111111111111111111111\",\"vote\":1,\"created\":\"\"}]}")
+				return reflect.DeepEqual(data, body)
+			},
+		},
 		{
 			request: httptest.NewRequest("GET", "/api/post/1", nil),
 			writer:  httptest.NewRecorder(),
@@ -613,7 +1550,7 @@ func TestDeletePost(t *testing.T) {
 			request: httptest.NewRequest("DELETE", "/api/post/111111111111111111111111", nil),
 			writer:  httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
-				service.EXPECT().DeletePost("111111111111111111111111", model.User{ID: "1"}).Return(nil)
+				service.EXPECT().DeletePost("111111111111111111111111", "", model.User{ID: "1"}).Return(nil)
 				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
 				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
 				handler.deletePost(w, r.WithContext(ctx))
@@ -624,6 +1561,36 @@ func TestDeletePost(t *testing.T) {
 				return reflect.DeepEqual(data, body)
 			},
 		},
+		{
+			request: httptest.NewRequest("DELETE", "/api/post/111111111111111111111111?reason=spam", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				moderator := model.User{ID: "2", Role: model.RoleModerator}
+				service.EXPECT().DeletePost("111111111111111111111111", "spam", moderator).Return(nil)
+				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
+				ctx := context.WithValue(r.Context(), "user", moderator)
+				handler.deletePost(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\": \"success\"}")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("DELETE", "/api/post/111111111111111111111111?reason="+strings.Repeat("a", 256), nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.deletePost(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"query\",\"param\":\"reason\",\"value\":\"" + strings.Repeat("a", 256) + "\",\"msg\":\"reason must be at most 255 symbols\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
 		{
 			request: httptest.NewRequest("DELETE", "/api/post/1", nil),
 			writer:  httptest.NewRecorder(),
@@ -642,7 +1609,7 @@ func TestDeletePost(t *testing.T) {
 			request: httptest.NewRequest("DELETE", "/api/post/111111111111111111111111", nil),
 			writer:  httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
-				service.EXPECT().DeletePost("111111111111111111111111", model.User{ID: "1"}).Return(customerr.PostNotFoundByID{PostID: "111111111111111111111111"})
+				service.EXPECT().DeletePost("111111111111111111111111", "", model.User{ID: "1"}).Return(customerr.PostNotFoundByID{PostID: "111111111111111111111111"})
 				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
 				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
 				handler.deletePost(w, r.WithContext(ctx))
@@ -664,7 +1631,7 @@ func TestDeletePost(t *testing.T) {
 	}
 }
 
-func TestCreateComment(t *testing.T) {
+func TestUpdatePost(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	service := mock.NewMockappService(ctrl)
@@ -678,97 +1645,121 @@ func TestCreateComment(t *testing.T) {
 	}{
 		{
 			request: httptest.NewRequest(
-				"POST",
+				"PATCH",
 				"/api/post/111111111111111111111111",
-				bytes.NewReader([]byte("{\"comment\": \"comment\"}")),
+				bytes.NewReader([]byte("{\"text\": \"new text\"}")),
 			),
 			writer: httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
 				service.EXPECT().
-					AddComment("111111111111111111111111", "comment", model.User{ID: "1"}).
-					Return(model.Post{ID: "111111111111111111111111"}, nil)
+					UpdatePost("111111111111111111111111", model.PostUpdateInput{Text: "new text"}, model.User{ID: "1"}).
+					Return(model.Post{ID: "111111111111111111111111", Text: "new text", Edited: "2022-01-01T00:00:00.000Z"}, nil)
 				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
 				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
-				handler.createComment(w, r.WithContext(ctx))
+				handler.updatePost(w, r.WithContext(ctx))
 				return w.Result()
 			},
 			check: func(body []byte) bool {
-				data, _ := json.Marshal(model.Post{ID: "111111111111111111111111"})
+				data, _ := json.Marshal(model.Post{ID: "111111111111111111111111", Text: "new text", Edited: "2022-01-01T00:00:00.000Z"})
 				return reflect.DeepEqual(data, body)
 			},
 		},
 		{
 			request: httptest.NewRequest(
-				"POST",
-				"/api/post/1",
-				bytes.NewReader([]byte("{\"comment\": \"comment\"}")),
+				"PATCH",
+				"/api/post/111111111111111111111111",
+				bytes.NewReader([]byte("{\"title\": \"\"}")),
 			),
 			writer: httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
-				r = mux.SetURLVars(r, map[string]string{"post_id": "1"})
+				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
 				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
-				handler.createComment(w, r.WithContext(ctx))
+				handler.updatePost(w, r.WithContext(ctx))
 				return w.Result()
 			},
 			check: func(body []byte) bool {
-				data := []byte("{\"errors\":[{\"location\":\"path\",\"param\":\"post_id\",\"value\":\"1\",\"msg\":\"post_id must be a hexadecimal 24-symbols string\"}]}\n")
+				data := []byte("{\"errors\":[{\"location\":\"body\",\"param\":\"text\",\"value\":\"\",\"msg\":\"title or text must be a non-empty string\"}]}\n")
 				return reflect.DeepEqual(data, body)
 			},
 		},
 		{
 			request: httptest.NewRequest(
-				"POST",
+				"PATCH",
 				"/api/post/111111111111111111111111",
-				bytes.NewReader([]byte("invalid json")),
+				bytes.NewReader([]byte("{\"title\": \"new title\"}")),
 			),
 			writer: httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().
+					UpdatePost("111111111111111111111111", model.PostUpdateInput{Title: "new title"}, model.User{ID: "2", Credential: model.Credential{Username: "other"}}).
+					Return(model.Post{}, customerr.NotOwner{Username: "other"})
 				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
-				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
-				handler.createComment(w, r.WithContext(ctx))
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "2", Credential: model.Credential{Username: "other"}})
+				handler.updatePost(w, r.WithContext(ctx))
 				return w.Result()
 			},
 			check: func(body []byte) bool {
-				data := []byte("{\"message\":\"bad request\"}\n")
+				data := []byte("{\"message\":\"user not own this resource\"}\n")
 				return reflect.DeepEqual(data, body)
 			},
 		},
 		{
 			request: httptest.NewRequest(
-				"POST",
+				"PATCH",
 				"/api/post/111111111111111111111111",
-				bytes.NewReader([]byte("{\"comment\": \"\"}")),
+				bytes.NewReader([]byte("{\"title\": \"new title\"}")),
 			),
 			writer: httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().
+					UpdatePost("111111111111111111111111", model.PostUpdateInput{Title: "new title"}, model.User{ID: "1"}).
+					Return(model.Post{}, customerr.PostNotEditable{PostID: "111111111111111111111111"})
 				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
 				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
-				handler.createComment(w, r.WithContext(ctx))
+				handler.updatePost(w, r.WithContext(ctx))
 				return w.Result()
 			},
 			check: func(body []byte) bool {
-				data := []byte("{\"errors\":[{\"location\":\"body\",\"param\":\"comment\",\"value\":\"\",\"msg\":\"comment must be a non-empty string\"}]}\n")
+				data := []byte("{\"errors\":[{\"location\":\"path\",\"param\":\"post_id\",\"value\":\"111111111111111111111111\",\"msg\":\"only text posts can be edited\"}]}\n")
 				return reflect.DeepEqual(data, body)
 			},
 		},
 		{
 			request: httptest.NewRequest(
-				"POST",
+				"PATCH",
 				"/api/post/111111111111111111111111",
-				bytes.NewReader([]byte("{\"comment\": \"comment\"}")),
+				bytes.NewReader([]byte("{\"title\": \"new title\"}")),
 			),
 			writer: httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
 				service.EXPECT().
-					AddComment("111111111111111111111111", "comment", model.User{ID: "1"}).
-					Return(model.Post{}, customerr.PostNotFoundByID{PostID: "111111111111111111111111"})
+					UpdatePost("111111111111111111111111", model.PostUpdateInput{Title: "new title"}, model.User{ID: "1"}).
+					Return(model.Post{}, customerr.PostChanged{PostID: "111111111111111111111111"})
 				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
 				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
-				handler.createComment(w, r.WithContext(ctx))
+				handler.updatePost(w, r.WithContext(ctx))
+				resp := w.Result()
+				if resp.StatusCode != http.StatusConflict {
+					t.Errorf("unexpected status %d", resp.StatusCode)
+				}
+				return resp
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"post was changed by another request, reload it\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("PATCH", "/api/post/111111111111111111111111", bytes.NewReader([]byte("{"))),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.updatePost(w, r.WithContext(ctx))
 				return w.Result()
 			},
 			check: func(body []byte) bool {
-				data := []byte("{\"message\":\"post not found\"}\n")
+				data := []byte("{\"message\":\"bad request\"}\n")
 				return reflect.DeepEqual(data, body)
 			},
 		},
@@ -783,7 +1774,7 @@ func TestCreateComment(t *testing.T) {
 	}
 }
 
-func TestDeleteComment(t *testing.T) {
+func TestGetPostRevisions(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	service := mock.NewMockappService(ctrl)
@@ -796,66 +1787,2634 @@ func TestDeleteComment(t *testing.T) {
 		check   func(body []byte) bool
 	}{
 		{
-			request: httptest.NewRequest(
-				"DELETE",
-				"/api/post/111111111111111111111111/111111111111111111111111",
-				nil,
-			),
-			writer: httptest.NewRecorder(),
+			request: httptest.NewRequest("GET", "/api/post/111111111111111111111111/revisions", nil),
+			writer:  httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
-				service.EXPECT().DeleteComment(
-					"111111111111111111111111",
-					"111111111111111111111111",
-					model.User{ID: "1"},
-				).Return(model.Post{ID: "111111111111111111111111"}, nil)
-				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111", "comment_id": "111111111111111111111111"})
+				service.EXPECT().GetPostRevisions("111111111111111111111111").
+					Return([]model.PostRevision{{Title: "title", Text: "text", Created: "2022-01-01T00:00:00.000Z"}}, nil)
+				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
+				handler.getPostRevisions(w, r)
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data, _ := json.Marshal([]model.PostRevision{{Title: "title", Text: "text", Created: "2022-01-01T00:00:00.000Z"}})
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/post/1/revisions", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				r = mux.SetURLVars(r, map[string]string{"post_id": "1"})
+				handler.getPostRevisions(w, r)
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"path\",\"param\":\"post_id\",\"value\":\"1\",\"msg\":\"post_id must be a hexadecimal 24-symbols string\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/post/111111111111111111111111/revisions", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().GetPostRevisions("111111111111111111111111").Return(nil, customerr.PostNotFoundByID{PostID: "111111111111111111111111"})
+				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
+				handler.getPostRevisions(w, r)
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"post not found\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestCreateComment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest(
+				"POST",
+				"/api/post/111111111111111111111111",
+				bytes.NewReader([]byte("{\"comment\": \"comment\"}")),
+			),
+			writer: httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().
+					AddComment("111111111111111111111111", "comment", "", model.User{ID: "1"}).
+					Return(model.Post{ID: "111111111111111111111111"}, nil)
+				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
 				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
-				handler.deleteComment(w, r.WithContext(ctx))
+				handler.createComment(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data, _ := json.Marshal(model.Post{ID: "111111111111111111111111"})
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest(
+				"POST",
+				"/api/post/1",
+				bytes.NewReader([]byte("{\"comment\": \"comment\"}")),
+			),
+			writer: httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				r = mux.SetURLVars(r, map[string]string{"post_id": "1"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.createComment(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"path\",\"param\":\"post_id\",\"value\":\"1\",\"msg\":\"post_id must be a hexadecimal 24-symbols string\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest(
+				"POST",
+				"/api/post/111111111111111111111111",
+				bytes.NewReader([]byte("invalid json")),
+			),
+			writer: httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.createComment(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"bad request\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest(
+				"POST",
+				"/api/post/111111111111111111111111",
+				bytes.NewReader([]byte("{\"comment\": \"\"}")),
+			),
+			writer: httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.createComment(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"body\",\"param\":\"comment\",\"value\":\"\",\"msg\":\"comment must be a non-empty string\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest(
+				"POST",
+				"/api/post/111111111111111111111111",
+				bytes.NewReader([]byte("{\"comment\": \"comment\"}")),
+			),
+			writer: httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().
+					AddComment("111111111111111111111111", "comment", "", model.User{ID: "1"}).
+					Return(model.Post{}, customerr.PostNotFoundByID{PostID: "111111111111111111111111"})
+				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.createComment(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"post not found\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest(
+				"POST",
+				"/api/post/111111111111111111111111",
+				bytes.NewReader([]byte("{\"comment\": \"comment\"}")),
+			),
+			writer: httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().
+					AddComment("111111111111111111111111", "comment", "", model.User{ID: "1"}).
+					Return(model.Post{}, customerr.PostLocked{PostID: "111111111111111111111111"})
+				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.createComment(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"post is locked\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest(
+				"POST",
+				"/api/post/111111111111111111111111?view=tree",
+				bytes.NewReader([]byte("{\"comment\": \"reply\", \"parent_id\": \"222222222222222222222222\"}")),
+			),
+			writer: httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().
+					AddComment("111111111111111111111111", "reply", "222222222222222222222222", model.User{ID: "1"}).
+					Return(model.Post{ID: "111111111111111111111111", Comments: []model.Comment{
+						{ID: "222222222222222222222222"},
+						{ID: "333333333333333333333333", ParentID: "222222222222222222222222", Depth: 1},
+					}}, nil)
+				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.createComment(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data, _ := json.Marshal(model.Post{ID: "111111111111111111111111", Comments: []model.Comment{
+					{ID: "222222222222222222222222", Replies: []model.Comment{
+						{ID: "333333333333333333333333", ParentID: "222222222222222222222222", Depth: 1},
+					}},
+				}})
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest(
+				"POST",
+				"/api/post/111111111111111111111111",
+				bytes.NewReader([]byte("{\"comment\": \"reply\", \"parent_id\": \"1\"}")),
+			),
+			writer: httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.createComment(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"body\",\"param\":\"parent_id\",\"value\":\"1\",\"msg\":\"parent_id must be a hexadecimal 24-symbols string\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest(
+				"POST",
+				"/api/post/111111111111111111111111",
+				bytes.NewReader([]byte("{\"comment\": \"reply\", \"parent_id\": \"222222222222222222222222\"}")),
+			),
+			writer: httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().
+					AddComment("111111111111111111111111", "reply", "222222222222222222222222", model.User{ID: "1"}).
+					Return(model.Post{}, customerr.CommentTooDeep{ParentID: "222222222222222222222222", MaxDepth: model.MaxCommentDepth})
+				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.createComment(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"body\",\"param\":\"parent_id\",\"value\":\"222222222222222222222222\",\"msg\":\"comment thread is too deep\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest(
+				"POST",
+				"/api/post/111111111111111111111111?view=graph",
+				bytes.NewReader([]byte("{\"comment\": \"comment\"}")),
+			),
+			writer: httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.createComment(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"query\",\"param\":\"view\",\"value\":\"graph\",\"msg\":\"view must be a flat or a tree\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestDeleteComment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest(
+				"DELETE",
+				"/api/post/111111111111111111111111/111111111111111111111111",
+				nil,
+			),
+			writer: httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().DeleteComment(
+					"111111111111111111111111",
+					"111111111111111111111111",
+					"",
+					model.User{ID: "1"},
+				).Return(model.Post{ID: "111111111111111111111111"}, nil)
+				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111", "comment_id": "111111111111111111111111"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.deleteComment(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data, _ := json.Marshal(model.Post{ID: "111111111111111111111111"})
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest(
+				"DELETE",
+				"/api/post/111111111111111111111111/1",
+				nil,
+			),
+			writer: httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111", "comment_id": "1"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.deleteComment(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"path\",\"param\":\"comment_id\",\"value\":\"1\",\"msg\":\"comment_id must be a hexadecimal 24-symbols string\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest(
+				"DELETE",
+				"/api/post/111111111111111111111111/111111111111111111111111",
+				nil,
+			),
+			writer: httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().DeleteComment(
+					"111111111111111111111111",
+					"111111111111111111111111",
+					"",
+					model.User{ID: "1"},
+				).Return(model.Post{}, customerr.CommentNotFoundByID{PostID: "111111111111111111111111", CommentID: "111111111111111111111111"})
+				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111", "comment_id": "111111111111111111111111"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.deleteComment(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"comment not found\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestUpvotePost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest("GET", "/api/post/111111111111111111111111/upvote", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().UpvotePost(
+					"111111111111111111111111",
+					model.User{ID: "1"},
+				).Return(model.Post{ID: "111111111111111111111111"}, nil)
+				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.upvotePost(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data, _ := json.Marshal(model.Post{ID: "111111111111111111111111"})
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/post/1/upvote", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				r = mux.SetURLVars(r, map[string]string{"post_id": "1"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.upvotePost(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"path\",\"param\":\"post_id\",\"value\":\"1\",\"msg\":\"post_id must be a hexadecimal 24-symbols string\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/post/111111111111111111111111/upvote", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().UpvotePost(
+					"111111111111111111111111",
+					model.User{ID: "1"},
+				).Return(model.Post{}, customerr.PostNotFoundByID{PostID: "111111111111111111111111"})
+				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.upvotePost(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"post not found\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestDownvotePost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest("GET", "/api/post/111111111111111111111111/downvote", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().DownvotePost(
+					"111111111111111111111111",
+					model.User{ID: "1"},
+				).Return(model.Post{ID: "111111111111111111111111"}, nil)
+				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.downvotePost(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data, _ := json.Marshal(model.Post{ID: "111111111111111111111111"})
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/post/1/downvote", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				r = mux.SetURLVars(r, map[string]string{"post_id": "1"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.downvotePost(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"path\",\"param\":\"post_id\",\"value\":\"1\",\"msg\":\"post_id must be a hexadecimal 24-symbols string\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/post/111111111111111111111111/downvote", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().DownvotePost(
+					"111111111111111111111111",
+					model.User{ID: "1"},
+				).Return(model.Post{}, customerr.PostNotFoundByID{PostID: "111111111111111111111111"})
+				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.downvotePost(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"post not found\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestUnvotePost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest("GET", "/api/post/111111111111111111111111/unvote", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().UnvotePost(
+					"111111111111111111111111",
+					model.User{ID: "1"},
+				).Return(model.Post{ID: "111111111111111111111111"}, nil)
+				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.unvotePost(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data, _ := json.Marshal(model.Post{ID: "111111111111111111111111"})
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/post/1/unvote", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				r = mux.SetURLVars(r, map[string]string{"post_id": "1"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.unvotePost(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"path\",\"param\":\"post_id\",\"value\":\"1\",\"msg\":\"post_id must be a hexadecimal 24-symbols string\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/post/111111111111111111111111/unvote", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().UnvotePost(
+					"111111111111111111111111",
+					model.User{ID: "1"},
+				).Return(model.Post{}, customerr.PostNotFoundByID{PostID: "111111111111111111111111"})
+				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.unvotePost(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"post not found\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestGetUserVotes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest("GET", "/api/user/me/votes", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().GetVotesByUser(model.User{ID: "1"}).Return([]model.Vote{
+					{UserID: "1", PostID: "1", CommentID: "2", Vote: model.VoteDown},
+					{UserID: "1", PostID: "1", Vote: model.VoteUp},
+				}, nil)
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.getUserVotes(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("[{\"user\":\"1\",\"postId\":\"1\",\"commentId\":\"2\",\"vote\":-1,\"created\":\"\"},{\"user\":\"1\",\"postId\":\"1\",\"vote\":1,\"created\":\"\"}]")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/user/me/votes", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().GetVotesByUser(model.User{ID: "1"}).Return(nil, errors.New("internal error"))
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.getUserVotes(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"internal error\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestGetNotifications(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	usr := model.User{ID: "1"}
+	page := model.NotificationsPage{
+		Notifications: []model.Notification{
+			{ID: "2", UserID: "1", Type: model.NotificationMention, Actor: &model.Author{ID: "2", Username: "van"}, PostID: "1", CommentID: "3", Body: "hi @ivan", Created: "2022-01-02T00:00:00.000Z"},
+		},
+		Unread:     2,
+		NextCursor: "eyJjcmVhdGVkIjoiMjAyMi0wMS0wMlQwMDowMDowMC4wMDBaIiwiaWQiOiIyIn0",
+	}
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest("GET", "/api/notifications?unread=true&limit=1", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().GetNotifications(model.NotificationsQuery{UnreadOnly: true, Limit: 1}, usr).Return(page, nil)
+				handler.getNotifications(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"notifications\":[{\"id\":\"2\",\"type\":\"mention\",\"actor\":{\"id\":\"2\",\"username\":\"van\"},\"post_id\":\"1\",\"comment_id\":\"3\",\"body\":\"hi @ivan\",\"read\":false,\"created\":\"2022-01-02T00:00:00.000Z\"}],\"unread\":2,\"next_cursor\":\"" + page.NextCursor + "\"}")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/notifications?unread=yes&cursor=bad", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				handler.getNotifications(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"query\",\"param\":\"unread\",\"value\":\"yes\",\"msg\":\"unread must be a true or a false\"},{\"location\":\"query\",\"param\":\"cursor\",\"value\":\"bad\",\"msg\":\"cursor must be a value of next_cursor\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/notifications", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().GetNotifications(model.NotificationsQuery{}, usr).Return(model.NotificationsPage{}, errors.New("internal error"))
+				handler.getNotifications(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"internal error\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestMarkNotificationRead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	usr := model.User{ID: "1"}
+	notificationID := "111111111111111111111111"
+	read := model.Notification{ID: notificationID, UserID: "1", Type: model.NotificationScoreMilestone, PostID: "2", Score: 10, Read: true, Created: "2022-01-01T00:00:00.000Z"}
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest("POST", "/api/notifications/"+notificationID+"/read", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().MarkNotificationRead(notificationID, usr).Return(read, nil)
+				r = mux.SetURLVars(r, map[string]string{"notification_id": notificationID})
+				handler.markNotificationRead(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"id\":\"" + notificationID + "\",\"type\":\"score_milestone\",\"post_id\":\"2\",\"score\":10,\"read\":true,\"created\":\"2022-01-01T00:00:00.000Z\"}")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("POST", "/api/notifications/"+notificationID+"/read", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().MarkNotificationRead(notificationID, usr).Return(model.Notification{}, customerr.NotificationNotFoundByID{NotificationID: notificationID})
+				r = mux.SetURLVars(r, map[string]string{"notification_id": notificationID})
+				handler.markNotificationRead(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"notification not found\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("POST", "/api/notifications/1/read", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				r = mux.SetURLVars(r, map[string]string{"notification_id": "1"})
+				handler.markNotificationRead(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"path\",\"param\":\"notification_id\",\"value\":\"1\",\"msg\":\"notification_id must be a hexadecimal 24-symbols string\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestMarkAllNotificationsRead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	usr := model.User{ID: "1"}
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest("POST", "/api/notifications/read", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().MarkAllNotificationsRead(usr).Return(nil)
+				handler.markAllNotificationsRead(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\": \"success\"}")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("POST", "/api/notifications/read", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().MarkAllNotificationsRead(usr).Return(errors.New("internal error"))
+				handler.markAllNotificationsRead(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"internal error\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+// subscribeWith returns a closed subscription holding the payloads, the stream writes them and ends
+func subscribeWith(payloads ...[]byte) *broker.Subscription {
+	events := broker.NewMemoryBroker()
+	sub, _ := events.Subscribe("test")
+	for _, payload := range payloads {
+		_ = events.Publish("test", payload)
+	}
+	sub.Close()
+	return sub
+}
+
+func TestGetPostEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	postID := "111111111111111111111111"
+	comment := model.NewComment("222222222222222222222222", postID, "body", model.Author{ID: "1", Username: "van"})
+	added, _ := json.Marshal(model.NewCommentEvent(model.EventCommentAdded, comment))
+	deleted, _ := json.Marshal(model.NewDeletionEvent(model.EventCommentDeleted, postID, comment.ID))
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(resp *http.Response, body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest("GET", "/api/post/"+postID+"/events", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().SubscribePost(postID).Return(subscribeWith(added, deleted), nil)
+				r = mux.SetURLVars(r, map[string]string{"post_id": postID})
+				handler.getPostEvents(w, r)
+				return w.Result()
+			},
+			check: func(resp *http.Response, body []byte) bool {
+				data := []byte("event: comment_added\ndata: " + string(added) + "\n\nevent: comment_deleted\ndata: " + string(deleted) + "\n\n")
+				return resp.Header.Get("Content-Type") == "text/event-stream" && reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/post/"+postID+"/events", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().SubscribePost(postID).Return(nil, customerr.PostNotFoundByID{PostID: postID})
+				r = mux.SetURLVars(r, map[string]string{"post_id": postID})
+				handler.getPostEvents(w, r)
+				return w.Result()
+			},
+			check: func(resp *http.Response, body []byte) bool {
+				data := []byte("{\"message\":\"post not found\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/post/1/events", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				r = mux.SetURLVars(r, map[string]string{"post_id": "1"})
+				handler.getPostEvents(w, r)
+				return w.Result()
+			},
+			check: func(resp *http.Response, body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"path\",\"param\":\"post_id\",\"value\":\"1\",\"msg\":\"post_id must be a hexadecimal 24-symbols string\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(resp, body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestGetCommunityEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	post := model.NewTextPost("111111111111111111111111", model.TextPostInput{Category: "music", Title: "title", Type: "text", Text: "text"}, model.Author{ID: "1", Username: "van"})
+	created, _ := json.Marshal(model.NewPostEvent(model.EventPostCreated, post))
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest("GET", "/api/communities/music/events", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().SubscribeCommunity("music").Return(subscribeWith(created), nil)
+				r = mux.SetURLVars(r, map[string]string{"community": "music"})
+				handler.getCommunityEvents(w, r)
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("event: post_created\ndata: " + string(created) + "\n\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/communities/nothing/events", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().SubscribeCommunity("nothing").Return(nil, customerr.CommunityNotFoundByName{Name: "nothing"})
+				r = mux.SetURLVars(r, map[string]string{"community": "nothing"})
+				handler.getCommunityEvents(w, r)
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"community not found\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+// TestConcurrentVotes runs the votes through the real service, every vote has to be kept
+func TestConcurrentVotes(t *testing.T) {
+	postID := "111111111111111111111111"
+	users := slicerepo.NewUsersRepo()
+	_ = users.AddUser(model.User{ID: "0", Credential: model.Credential{Username: "author"}})
+	posts := slicerepo.NewPostsRepo()
+	_ = posts.AddPost(model.NewTextPost(postID, model.TextPostInput{Category: "music", Title: "title", Type: "text", Text: "text"}, model.Author{ID: "0", Username: "author"}))
+
+	notifications := slicerepo.NewNotificationsRepo()
+
+	appService := service.NewService(service.Repositories{
+		Users:         users,
+		Posts:         posts,
+		Comments:      slicerepo.NewCommentsRepo(),
+		Votes:         slicerepo.NewVotesRepo(),
+		Notifications: notifications,
+		Communities:   slicerepo.NewCommunitiesRepo(),
+		ModActions:    slicerepo.NewModActionsRepo(),
+		Bans:          slicerepo.NewBansRepo(),
+		LoginAttempts: lockout.NewMemoryStore(),
+	}, service.NewArgon2idHasher(), broker.NewMemoryBroker(), nil)
+	sessions := opaquesession.NewManager(token.NewSigner("love", time.Hour), cookie.NewManager(cookie.NewMapStorage()))
+	handler := NewHandler(sessions, newRefresher(), appService, nil)
+
+	votes := []struct {
+		action string
+		vote   func(w http.ResponseWriter, r *http.Request)
+	}{
+		{"upvote", handler.upvotePost},
+		{"downvote", handler.downvotePost},
+		{"unvote", handler.unvotePost},
+		{"upvote", handler.upvotePost},
+	}
+
+	voters := 80
+	wg := &sync.WaitGroup{}
+	for i := 0; i < voters; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			vote := votes[i%len(votes)]
+			usr := model.User{ID: strconv.Itoa(i + 1)}
+
+			// every user upvotes first, so the unvotes have something to drop
+			for _, v := range []func(w http.ResponseWriter, r *http.Request){handler.upvotePost, vote.vote} {
+				r := httptest.NewRequest("GET", "/api/post/"+postID+"/"+vote.action, nil)
+				r = mux.SetURLVars(r, map[string]string{"post_id": postID})
+				w := httptest.NewRecorder()
+				v(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
+				if w.Code != http.StatusOK {
+					t.Errorf("[%d] unexpected status: %d", i, w.Code)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	post, err := posts.GetPostByID(postID)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if post.Ups != 40 || post.Downs != 20 {
+		t.Errorf("expected 40 ups and 20 downs, got: %d and %d", post.Ups, post.Downs)
+	}
+	if post.Score != 20 {
+		t.Errorf("expected score 20, got: %d", post.Score)
+	}
+	if post.UpvotePercentage != 66 {
+		t.Errorf("expected upvote percentage 66, got: %d", post.UpvotePercentage)
+	}
+
+	author, _ := users.GetUserByID("0")
+	if author.Karma.Post != post.Score {
+		t.Errorf("expected post karma %d, got: %d", post.Score, author.Karma.Post)
+	}
+
+	// the score may cross a milestone several times, the author is told about every milestone once
+	inbox, _ := notifications.GetNotifications("0", model.NotificationsQuery{}, model.NotificationCursor{})
+	milestones := make(map[int]int)
+	for _, notification := range inbox {
+		milestones[notification.Score]++
+	}
+	for milestone, count := range milestones {
+		if count != 1 {
+			t.Errorf("expected a single notification of milestone %d, got: %d", milestone, count)
+		}
+	}
+	if milestones[10] != 1 {
+		t.Errorf("expected a notification of milestone 10, got: %+v", inbox)
+	}
+}
+
+func TestEditComment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	vars := map[string]string{"post_id": "111111111111111111111111", "comment_id": "222222222222222222222222"}
+	edited := model.Post{ID: "111111111111111111111111", Comments: []model.Comment{{ID: "222222222222222222222222", Body: "edited", Edited: "2022-01-01T00:00:00.000Z"}}}
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest("PATCH", "/api/post/111111111111111111111111/222222222222222222222222", bytes.NewReader([]byte("{\"comment\": \"edited\"}"))),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().EditComment("111111111111111111111111", "222222222222222222222222", "edited", model.User{ID: "1"}).Return(edited, nil)
+				r = mux.SetURLVars(r, vars)
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.editComment(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data, _ := json.Marshal(edited)
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("PATCH", "/api/post/111111111111111111111111/222222222222222222222222", bytes.NewReader([]byte("{\"comment\": \"\"}"))),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				r = mux.SetURLVars(r, vars)
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.editComment(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"body\",\"param\":\"comment\",\"value\":\"\",\"msg\":\"comment must be a non-empty string\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("PATCH", "/api/post/111111111111111111111111/222222222222222222222222", bytes.NewReader([]byte("{"))),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				r = mux.SetURLVars(r, vars)
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.editComment(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"bad request\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("PATCH", "/api/post/111111111111111111111111/222222222222222222222222", bytes.NewReader([]byte("{\"comment\": \"edited\"}"))),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().EditComment("111111111111111111111111", "222222222222222222222222", "edited", model.User{ID: "2"}).
+					Return(model.Post{}, customerr.NotOwner{})
+				r = mux.SetURLVars(r, vars)
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "2"})
+				handler.editComment(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"user not own this resource\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestUpvoteComment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	vars := map[string]string{"post_id": "111111111111111111111111", "comment_id": "222222222222222222222222"}
+	voted := model.Post{ID: "111111111111111111111111", Comments: []model.Comment{{ID: "222222222222222222222222", Voting: model.Voting{Score: 1}}}}
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest("GET", "/api/post/111111111111111111111111/222222222222222222222222/upvote", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().UpvoteComment("111111111111111111111111", "222222222222222222222222", model.User{ID: "1"}).Return(voted, nil)
+				r = mux.SetURLVars(r, vars)
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.upvoteComment(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data, _ := json.Marshal(voted)
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/post/111111111111111111111111/1/upvote", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111", "comment_id": "1"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.upvoteComment(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"path\",\"param\":\"comment_id\",\"value\":\"1\",\"msg\":\"comment_id must be a hexadecimal 24-symbols string\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/post/111111111111111111111111/222222222222222222222222/upvote", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().UpvoteComment("111111111111111111111111", "222222222222222222222222", model.User{ID: "1"}).
+					Return(model.Post{}, customerr.CommentNotFoundByID{PostID: "111111111111111111111111", CommentID: "222222222222222222222222"})
+				r = mux.SetURLVars(r, vars)
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.upvoteComment(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"comment not found\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestDownvoteComment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	vars := map[string]string{"post_id": "111111111111111111111111", "comment_id": "222222222222222222222222"}
+	voted := model.Post{ID: "111111111111111111111111", Comments: []model.Comment{{ID: "222222222222222222222222", Voting: model.Voting{Score: -1}}}}
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest("GET", "/api/post/111111111111111111111111/222222222222222222222222/downvote", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().DownvoteComment("111111111111111111111111", "222222222222222222222222", model.User{ID: "1"}).Return(voted, nil)
+				r = mux.SetURLVars(r, vars)
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.downvoteComment(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data, _ := json.Marshal(voted)
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/post/111111111111111111111111/1/downvote", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111", "comment_id": "1"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.downvoteComment(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"path\",\"param\":\"comment_id\",\"value\":\"1\",\"msg\":\"comment_id must be a hexadecimal 24-symbols string\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/post/111111111111111111111111/222222222222222222222222/downvote", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().DownvoteComment("111111111111111111111111", "222222222222222222222222", model.User{ID: "1"}).
+					Return(model.Post{}, customerr.CommentNotFoundByID{PostID: "111111111111111111111111", CommentID: "222222222222222222222222"})
+				r = mux.SetURLVars(r, vars)
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.downvoteComment(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"comment not found\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestUnvoteComment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	vars := map[string]string{"post_id": "111111111111111111111111", "comment_id": "222222222222222222222222"}
+	voted := model.Post{ID: "111111111111111111111111", Comments: []model.Comment{{ID: "222222222222222222222222", Voting: model.Voting{Score: 0}}}}
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest("GET", "/api/post/111111111111111111111111/222222222222222222222222/unvote", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().UnvoteComment("111111111111111111111111", "222222222222222222222222", model.User{ID: "1"}).Return(voted, nil)
+				r = mux.SetURLVars(r, vars)
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.unvoteComment(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data, _ := json.Marshal(voted)
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/post/111111111111111111111111/1/unvote", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111", "comment_id": "1"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.unvoteComment(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"path\",\"param\":\"comment_id\",\"value\":\"1\",\"msg\":\"comment_id must be a hexadecimal 24-symbols string\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/post/111111111111111111111111/222222222222222222222222/unvote", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().UnvoteComment("111111111111111111111111", "222222222222222222222222", model.User{ID: "1"}).
+					Return(model.Post{}, customerr.CommentNotFoundByID{PostID: "111111111111111111111111", CommentID: "222222222222222222222222"})
+				r = mux.SetURLVars(r, vars)
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.unvoteComment(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"comment not found\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestAdminMiddleware(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	next := handler.adminMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("{\"message\": \"success\"}"))
+	}))
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest("PUT", "/api/admin/user/111111111111111111111111/role", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1", Role: model.RoleAdmin})
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\": \"success\"}")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("PUT", "/api/admin/user/111111111111111111111111/role", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1", Role: model.RoleModerator})
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"permission denied\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("PUT", "/api/admin/user/111111111111111111111111/role", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				next.ServeHTTP(w, r)
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"user unauthorized\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestSetUserRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	admin := model.User{ID: "1", Role: model.RoleAdmin}
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest("PUT", "/api/admin/user/111111111111111111111111/role", strings.NewReader("{\"role\":\"moderator\"}")),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().
+					SetUserRole("111111111111111111111111", model.RoleModerator, admin).
+					Return(model.User{ID: "111111111111111111111111", Credential: model.Credential{Username: "van", Password: "hash"}, Role: model.RoleModerator}, nil)
+				r = mux.SetURLVars(r, map[string]string{"user_id": "111111111111111111111111"})
+				ctx := context.WithValue(r.Context(), "user", admin)
+				handler.setUserRole(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"id\":\"111111111111111111111111\",\"username\":\"van\",\"role\":\"moderator\"}")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("PUT", "/api/admin/user/1/role", strings.NewReader("{\"role\":\"moderator\"}")),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				r = mux.SetURLVars(r, map[string]string{"user_id": "1"})
+				ctx := context.WithValue(r.Context(), "user", admin)
+				handler.setUserRole(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"path\",\"param\":\"user_id\",\"value\":\"1\",\"msg\":\"user_id must be a hexadecimal 24-symbols string\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("PUT", "/api/admin/user/111111111111111111111111/role", strings.NewReader("invalid json")),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				r = mux.SetURLVars(r, map[string]string{"user_id": "111111111111111111111111"})
+				ctx := context.WithValue(r.Context(), "user", admin)
+				handler.setUserRole(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"bad request\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("PUT", "/api/admin/user/111111111111111111111111/role", strings.NewReader("{\"role\":\"king\"}")),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				r = mux.SetURLVars(r, map[string]string{"user_id": "111111111111111111111111"})
+				ctx := context.WithValue(r.Context(), "user", admin)
+				handler.setUserRole(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"body\",\"param\":\"role\",\"value\":\"king\",\"msg\":\"role must be a user, a moderator or an admin\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("PUT", "/api/admin/user/111111111111111111111111/role", strings.NewReader("{\"role\":\"admin\"}")),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().
+					SetUserRole("111111111111111111111111", model.RoleAdmin, admin).
+					Return(model.User{}, customerr.UserNotFoundByID{UserID: "111111111111111111111111"})
+				r = mux.SetURLVars(r, map[string]string{"user_id": "111111111111111111111111"})
+				ctx := context.WithValue(r.Context(), "user", admin)
+				handler.setUserRole(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"user not found\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestGetCommunities(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest("GET", "/api/communities", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().GetCommunities().Return([]model.Community{{Name: "funny"}, {Name: "music"}}, nil)
+				handler.getCommunities(w, r)
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data, _ := json.Marshal([]model.Community{{Name: "funny"}, {Name: "music"}})
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/communities", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().GetCommunities().Return(nil, errors.New("internal error"))
+				handler.getCommunities(w, r)
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"internal error\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestGetCommunity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest("GET", "/api/communities/funny", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().GetCommunity("funny").Return(model.Community{Name: "funny", Description: "Funny"}, nil)
+				r = mux.SetURLVars(r, map[string]string{"community": "funny"})
+				handler.getCommunity(w, r)
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data, _ := json.Marshal(model.Community{Name: "funny", Description: "Funny"})
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/communities/Bad%20Name", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				r = mux.SetURLVars(r, map[string]string{"community": "Bad Name"})
+				handler.getCommunity(w, r)
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"path\",\"param\":\"community\",\"value\":\"Bad Name\",\"msg\":\"community must be 3-21 lowercase letters, digits or underscores\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/communities/cats", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().GetCommunity("cats").Return(model.Community{}, customerr.CommunityNotFoundByName{Name: "cats"})
+				r = mux.SetURLVars(r, map[string]string{"community": "cats"})
+				handler.getCommunity(w, r)
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"community not found\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestCreateCommunity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest("POST", "/api/communities", strings.NewReader("{\"name\":\"cats\",\"description\":\"meow\"}")),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().
+					CreateCommunity(model.CommunityInput{Name: "cats", Description: "meow"}, model.User{ID: "1"}).
+					Return(model.Community{Name: "cats", Description: "meow", Creator: model.Author{ID: "1"}}, nil)
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.createCommunity(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data, _ := json.Marshal(model.Community{Name: "cats", Description: "meow", Creator: model.Author{ID: "1"}})
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("POST", "/api/communities", strings.NewReader("invalid json")),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.createCommunity(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"bad request\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("POST", "/api/communities", strings.NewReader("{\"name\":\"c\"}")),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.createCommunity(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"body\",\"param\":\"name\",\"value\":\"c\",\"msg\":\"name must be 3-21 lowercase letters, digits or underscores\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("POST", "/api/communities", strings.NewReader("{\"name\":\"funny\"}")),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().
+					CreateCommunity(model.CommunityInput{Name: "funny"}, model.User{ID: "1"}).
+					Return(model.Community{}, customerr.CommunityAlreadyExists{Name: "funny"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.createCommunity(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"body\",\"param\":\"name\",\"value\":\"funny\",\"msg\":\"already exists\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestUpdateCommunity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest("PATCH", "/api/communities/cats", strings.NewReader("{\"description\":\"purr\"}")),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().
+					UpdateCommunity("cats", "purr", model.User{ID: "1"}).
+					Return(model.Community{Name: "cats", Description: "purr"}, nil)
+				r = mux.SetURLVars(r, map[string]string{"community": "cats"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.updateCommunity(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data, _ := json.Marshal(model.Community{Name: "cats", Description: "purr"})
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("PATCH", "/api/communities/cats", strings.NewReader("{}")),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				r = mux.SetURLVars(r, map[string]string{"community": "cats"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.updateCommunity(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"body\",\"param\":\"description\",\"value\":\"\",\"msg\":\"field is required\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("PATCH", "/api/communities/funny", strings.NewReader("{\"description\":\"purr\"}")),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().
+					UpdateCommunity("funny", "purr", model.User{ID: "1", Credential: model.Credential{Username: "van"}}).
+					Return(model.Community{}, customerr.NotOwner{Username: "van"})
+				r = mux.SetURLVars(r, map[string]string{"community": "funny"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1", Credential: model.Credential{Username: "van"}})
+				handler.updateCommunity(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"user not own this resource\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestDeleteCommunity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest("DELETE", "/api/communities/cats", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().DeleteCommunity("cats", model.User{ID: "1"}).Return(nil)
+				r = mux.SetURLVars(r, map[string]string{"community": "cats"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.deleteCommunity(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\": \"success\"}")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("DELETE", "/api/communities/funny", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().DeleteCommunity("funny", model.User{ID: "1"}).Return(customerr.CommunityNotEmpty{Name: "funny"})
+				r = mux.SetURLVars(r, map[string]string{"community": "funny"})
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
+				handler.deleteCommunity(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"path\",\"param\":\"community\",\"value\":\"funny\",\"msg\":\"community still has posts\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestSendMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	usr := model.User{ID: "1", Credential: model.Credential{Username: "van"}}
+	message := model.Message{
+		ID:        "3",
+		Sender:    model.Author{ID: "1", Username: "van"},
+		Recipient: model.Author{ID: "2", Username: "ivan"},
+		Body:      "hi",
+		Created:   "2022-01-01T00:00:00.000Z",
+	}
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest("POST", "/api/messages/ivan", strings.NewReader("{\"body\":\"hi\"}")),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().SendMessage("ivan", "hi", usr).Return(message, nil)
+				r = mux.SetURLVars(r, map[string]string{"username": "ivan"})
+				handler.sendMessage(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"id\":\"3\",\"sender\":{\"id\":\"1\",\"username\":\"van\"},\"recipient\":{\"id\":\"2\",\"username\":\"ivan\"},\"body\":\"hi\",\"read\":false,\"created\":\"2022-01-01T00:00:00.000Z\"}")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("POST", "/api/messages/ivan", strings.NewReader("{\"body\":\"hi\"}")),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().SendMessage("ivan", "hi", usr).Return(model.Message{}, customerr.UserBlocked{Username: "ivan"})
+				r = mux.SetURLVars(r, map[string]string{"username": "ivan"})
+				handler.sendMessage(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"messages with this user are blocked\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("POST", "/api/messages/ivan", strings.NewReader("{\"body\":\"hi\"}")),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().SendMessage("ivan", "hi", usr).Return(model.Message{}, customerr.RateLimited{Action: "messages", RetryAfter: model.MessageRateWindow})
+				r = mux.SetURLVars(r, map[string]string{"username": "ivan"})
+				handler.sendMessage(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
+				resp := w.Result()
+				if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") != "60" {
+					t.Errorf("unexpected status %d and Retry-After %q", resp.StatusCode, resp.Header.Get("Retry-After"))
+				}
+				return resp
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"too many requests\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("POST", "/api/messages/van", strings.NewReader("{\"body\":\"hi\"}")),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().SendMessage("van", "hi", usr).Return(model.Message{}, customerr.TargetIsSelf{Username: "van"})
+				r = mux.SetURLVars(r, map[string]string{"username": "van"})
+				handler.sendMessage(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"path\",\"param\":\"username\",\"value\":\"van\",\"msg\":\"user can't be yourself\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("POST", "/api/messages/ivan", strings.NewReader("{\"body\":\"\"}")),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				r = mux.SetURLVars(r, map[string]string{"username": "ivan"})
+				handler.sendMessage(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"body\",\"param\":\"body\",\"value\":\"\",\"msg\":\"body must be a non-empty string of at most 10000 symbols\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("POST", "/api/messages/ivan", strings.NewReader("invalid json")),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				r = mux.SetURLVars(r, map[string]string{"username": "ivan"})
+				handler.sendMessage(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"bad request\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestGetConversations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	usr := model.User{ID: "1", Credential: model.Credential{Username: "van"}}
+	message := model.Message{
+		ID:        "3",
+		Sender:    model.Author{ID: "2", Username: "ivan"},
+		Recipient: model.Author{ID: "1", Username: "van"},
+		Body:      "hi",
+		Created:   "2022-01-01T00:00:00.000Z",
+	}
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest("GET", "/api/messages", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().GetConversations(usr).Return([]model.Conversation{model.NewConversation("1", message, 1)}, nil)
+				handler.getConversations(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("[{\"with\":{\"id\":\"2\",\"username\":\"ivan\"},\"last_message\":{\"id\":\"3\",\"sender\":{\"id\":\"2\",\"username\":\"ivan\"},\"recipient\":{\"id\":\"1\",\"username\":\"van\"},\"body\":\"hi\",\"read\":false,\"created\":\"2022-01-01T00:00:00.000Z\"},\"unread\":1}]")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/messages", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().GetConversations(usr).Return(nil, errors.New("internal error"))
+				handler.getConversations(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"internal error\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestGetThread(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	usr := model.User{ID: "1", Credential: model.Credential{Username: "van"}}
+	message := model.Message{
+		ID:        "3",
+		Sender:    model.Author{ID: "2", Username: "ivan"},
+		Recipient: model.Author{ID: "1", Username: "van"},
+		Body:      "hi",
+		Read:      true,
+		Created:   "2022-01-01T00:00:00.000Z",
+	}
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest("GET", "/api/messages/ivan", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().GetThread("ivan", usr).Return([]model.Message{message}, nil)
+				r = mux.SetURLVars(r, map[string]string{"username": "ivan"})
+				handler.getThread(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("[{\"id\":\"3\",\"sender\":{\"id\":\"2\",\"username\":\"ivan\"},\"recipient\":{\"id\":\"1\",\"username\":\"van\"},\"body\":\"hi\",\"read\":true,\"created\":\"2022-01-01T00:00:00.000Z\"}]")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/messages/petr", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().GetThread("petr", usr).Return(nil, customerr.UserNotFoundByUsername{Username: "petr"})
+				r = mux.SetURLVars(r, map[string]string{"username": "petr"})
+				handler.getThread(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"user not found\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestMarkThreadRead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	usr := model.User{ID: "1", Credential: model.Credential{Username: "van"}}
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest("POST", "/api/messages/ivan/read", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().MarkThreadRead("ivan", usr).Return(nil)
+				r = mux.SetURLVars(r, map[string]string{"username": "ivan"})
+				handler.markThreadRead(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\": \"success\"}")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("POST", "/api/messages/ivan/read", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().MarkThreadRead("ivan", usr).Return(errors.New("internal error"))
+				r = mux.SetURLVars(r, map[string]string{"username": "ivan"})
+				handler.markThreadRead(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"internal error\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestBlockUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	usr := model.User{ID: "1", Credential: model.Credential{Username: "van"}}
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest("POST", "/api/user/ivan/block", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().BlockUser("ivan", usr).Return(nil)
+				r = mux.SetURLVars(r, map[string]string{"username": "ivan"})
+				handler.blockUser(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\": \"success\"}")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("POST", "/api/user/van/block", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().BlockUser("van", usr).Return(customerr.TargetIsSelf{Username: "van"})
+				r = mux.SetURLVars(r, map[string]string{"username": "van"})
+				handler.blockUser(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"path\",\"param\":\"username\",\"value\":\"van\",\"msg\":\"user can't be yourself\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("DELETE", "/api/user/ivan/block", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().UnblockUser("ivan", usr).Return(nil)
+				r = mux.SetURLVars(r, map[string]string{"username": "ivan"})
+				handler.unblockUser(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\": \"success\"}")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/user/me/blocks", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				blocks := []model.Block{{UserID: "1", Blocked: model.Author{ID: "2", Username: "ivan"}, Created: "2022-01-01T00:00:00.000Z"}}
+				service.EXPECT().GetBlocks(usr).Return(blocks, nil)
+				handler.getBlocks(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("[{\"user\":{\"id\":\"2\",\"username\":\"ivan\"},\"created\":\"2022-01-01T00:00:00.000Z\"}]")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestModeratorMiddleware(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	next := handler.moderatorMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("{\"message\": \"success\"}"))
+	}))
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest("GET", "/api/reports", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1", Role: model.RoleModerator})
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\": \"success\"}")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/reports", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1", Role: model.RoleAdmin})
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\": \"success\"}")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/reports", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1", Role: model.RoleUser})
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"permission denied\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestReportPost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	usr := model.User{ID: "1", Credential: model.Credential{Username: "van"}}
+	postID := "111111111111111111111111"
+	report := model.Report{
+		ID:        "3",
+		PostID:    postID,
+		Community: "news",
+		Reporter:  model.Author{ID: "1", Username: "van"},
+		Reason:    model.ReportReasonSpam,
+		Text:      "ads",
+		Status:    model.ReportStatusOpen,
+		Created:   "2022-01-01T00:00:00.000Z",
+	}
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest("POST", "/api/post/"+postID+"/report", strings.NewReader("{\"reason\":\"spam\",\"text\":\"ads\"}")),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().ReportPost(postID, "spam", "ads", usr).Return(report, nil)
+				r = mux.SetURLVars(r, map[string]string{"post_id": postID})
+				handler.reportPost(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"id\":\"3\",\"post_id\":\"" + postID + "\",\"community\":\"news\",\"reporter\":{\"id\":\"1\",\"username\":\"van\"},\"reason\":\"spam\",\"text\":\"ads\",\"status\":\"open\",\"created\":\"2022-01-01T00:00:00.000Z\"}")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("POST", "/api/post/"+postID+"/report", strings.NewReader("{\"reason\":\"boring\"}")),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				r = mux.SetURLVars(r, map[string]string{"post_id": postID})
+				handler.reportPost(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"body\",\"param\":\"reason\",\"value\":\"boring\",\"msg\":\"reason must be a spam, a harassment, a hate, a misinformation or an other\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("POST", "/api/post/"+postID+"/report", strings.NewReader("{\"reason\":\"spam\"}")),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().ReportPost(postID, "spam", "", usr).Return(model.Report{}, customerr.PostNotFoundByID{PostID: postID})
+				r = mux.SetURLVars(r, map[string]string{"post_id": postID})
+				handler.reportPost(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"post not found\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestReportComment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	usr := model.User{ID: "1", Credential: model.Credential{Username: "van"}}
+	postID := "111111111111111111111111"
+	commentID := "222222222222222222222222"
+	report := model.Report{
+		ID:        "3",
+		PostID:    postID,
+		CommentID: commentID,
+		Community: "news",
+		Reporter:  model.Author{ID: "1", Username: "van"},
+		Reason:    model.ReportReasonHarassment,
+		Status:    model.ReportStatusOpen,
+		Created:   "2022-01-01T00:00:00.000Z",
+	}
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest("POST", "/api/post/"+postID+"/"+commentID+"/report", strings.NewReader("{\"reason\":\"harassment\"}")),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().ReportComment(postID, commentID, "harassment", "", usr).Return(report, nil)
+				r = mux.SetURLVars(r, map[string]string{"post_id": postID, "comment_id": commentID})
+				handler.reportComment(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"id\":\"3\",\"post_id\":\"" + postID + "\",\"comment_id\":\"" + commentID + "\",\"community\":\"news\",\"reporter\":{\"id\":\"1\",\"username\":\"van\"},\"reason\":\"harassment\",\"status\":\"open\",\"created\":\"2022-01-01T00:00:00.000Z\"}")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("POST", "/api/post/"+postID+"/1/report", strings.NewReader("{\"reason\":\"harassment\"}")),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				r = mux.SetURLVars(r, map[string]string{"post_id": postID, "comment_id": "1"})
+				handler.reportComment(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"path\",\"param\":\"comment_id\",\"value\":\"1\",\"msg\":\"comment_id must be a hexadecimal 24-symbols string\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("POST", "/api/post/"+postID+"/"+commentID+"/report", strings.NewReader("invalid json")),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				r = mux.SetURLVars(r, map[string]string{"post_id": postID, "comment_id": commentID})
+				handler.reportComment(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"bad request\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestGetReportQueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	moderator := model.User{ID: "1", Credential: model.Credential{Username: "admin"}, Role: model.RoleModerator}
+	item := model.ReportedItem{
+		PostID:       "2",
+		Community:    "news",
+		Count:        1,
+		LastReported: "2022-01-01T00:00:00.000Z",
+		Reports: []model.Report{{
+			ID:        "3",
+			PostID:    "2",
+			Community: "news",
+			Reporter:  model.Author{ID: "4", Username: "van"},
+			Reason:    model.ReportReasonSpam,
+			Status:    model.ReportStatusOpen,
+			Created:   "2022-01-01T00:00:00.000Z",
+		}},
+	}
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest("GET", "/api/reports?community=news&limit=10", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().GetReportQueue(model.ReportsQuery{Community: "news", Limit: 10}).Return([]model.ReportedItem{item}, nil)
+				handler.getReportQueue(w, r.WithContext(context.WithValue(r.Context(), "user", moderator)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("[{\"post_id\":\"2\",\"community\":\"news\",\"count\":1,\"last_reported\":\"2022-01-01T00:00:00.000Z\",\"reports\":[{\"id\":\"3\",\"post_id\":\"2\",\"community\":\"news\",\"reporter\":{\"id\":\"4\",\"username\":\"van\"},\"reason\":\"spam\",\"status\":\"open\",\"created\":\"2022-01-01T00:00:00.000Z\"}]}]")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/reports?limit=1000", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				handler.getReportQueue(w, r.WithContext(context.WithValue(r.Context(), "user", moderator)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"query\",\"param\":\"limit\",\"value\":\"1000\",\"msg\":\"limit must be an integer from 1 to 100\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestResolveReports(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	moderator := model.User{ID: "1", Credential: model.Credential{Username: "admin"}, Role: model.RoleModerator}
+	postID := "111111111111111111111111"
+	commentID := "222222222222222222222222"
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
+		{
+			request: httptest.NewRequest("POST", "/api/reports/"+postID, strings.NewReader("{\"action\":\"dismiss\"}")),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				resolution := model.ReportResolution{
+					PostID:    postID,
+					Status:    model.ReportStatusDismissed,
+					Resolved:  2,
+					Moderator: model.Author{ID: "1", Username: "admin"},
+					Created:   "2022-01-01T00:00:00.000Z",
+				}
+				service.EXPECT().ResolveReports(postID, "", "dismiss", "", moderator).Return(resolution, nil)
+				r = mux.SetURLVars(r, map[string]string{"post_id": postID})
+				handler.resolveReports(w, r.WithContext(context.WithValue(r.Context(), "user", moderator)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"post_id\":\"" + postID + "\",\"status\":\"dismissed\",\"resolved\":2,\"moderator\":{\"id\":\"1\",\"username\":\"admin\"},\"created\":\"2022-01-01T00:00:00.000Z\"}")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("POST", "/api/reports/"+postID+"/"+commentID, strings.NewReader("{\"action\":\"remove\",\"reason\":\"spam\"}")),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().ResolveReports(postID, commentID, "remove", "spam", moderator).Return(model.ReportResolution{}, customerr.ReportsNotFound{PostID: postID, CommentID: commentID})
+				r = mux.SetURLVars(r, map[string]string{"post_id": postID, "comment_id": commentID})
+				handler.resolveReports(w, r.WithContext(context.WithValue(r.Context(), "user", moderator)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"reports not found\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("POST", "/api/reports/"+postID, strings.NewReader("{\"action\":\"ban\"}")),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				r = mux.SetURLVars(r, map[string]string{"post_id": postID})
+				handler.resolveReports(w, r.WithContext(context.WithValue(r.Context(), "user", moderator)))
 				return w.Result()
 			},
 			check: func(body []byte) bool {
-				data, _ := json.Marshal(model.Post{ID: "111111111111111111111111"})
+				data := []byte("{\"errors\":[{\"location\":\"body\",\"param\":\"action\",\"value\":\"ban\",\"msg\":\"action must be an approve, a remove or a dismiss\"}]}\n")
 				return reflect.DeepEqual(data, body)
 			},
 		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !item.check(body) {
+			t.Errorf("[%d] unexpected body: %s", i, string(body))
+		}
+	}
+}
+
+func TestGetModLog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	handler := initHandler(ctrl, service)
+
+	moderator := model.User{ID: "1", Credential: model.Credential{Username: "admin"}, Role: model.RoleModerator}
+	page := model.ModLogPage{
+		Actions: []model.ModAction{{
+			ID:         "3",
+			Moderator:  model.Author{ID: "1", Username: "admin"},
+			Action:     model.ModActionRemovePost,
+			TargetUser: model.Author{ID: "4", Username: "van"},
+			Community:  "news",
+			PostID:     "2",
+			Details:    "spam",
+			Created:    "2022-01-01T00:00:00.000Z",
+		}},
+	}
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(body []byte) bool
+	}{
 		{
-			request: httptest.NewRequest(
-				"DELETE",
-				"/api/post/111111111111111111111111/1",
-				nil,
-			),
-			writer: httptest.NewRecorder(),
+			request: httptest.NewRequest("GET", "/api/modlog?community=news&moderator=admin&action=remove_post&limit=10", nil),
+			writer:  httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
-				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111", "comment_id": "1"})
-				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
-				handler.deleteComment(w, r.WithContext(ctx))
+				query := model.ModLogQuery{Community: "news", Moderator: "admin", Action: model.ModActionRemovePost, Limit: 10}
+				service.EXPECT().GetModLog(query).Return(page, nil)
+				handler.getModLog(w, r.WithContext(context.WithValue(r.Context(), "user", moderator)))
 				return w.Result()
 			},
 			check: func(body []byte) bool {
-				data := []byte("{\"errors\":[{\"location\":\"path\",\"param\":\"comment_id\",\"value\":\"1\",\"msg\":\"comment_id must be a hexadecimal 24-symbols string\"}]}\n")
+				data := []byte("{\"actions\":[{\"id\":\"3\",\"moderator\":{\"id\":\"1\",\"username\":\"admin\"},\"action\":\"remove_post\",\"targetUser\":{\"id\":\"4\",\"username\":\"van\"},\"community\":\"news\",\"postId\":\"2\",\"details\":\"spam\",\"created\":\"2022-01-01T00:00:00.000Z\"}],\"next_cursor\":\"\"}")
 				return reflect.DeepEqual(data, body)
 			},
 		},
 		{
-			request: httptest.NewRequest(
-				"DELETE",
-				"/api/post/111111111111111111111111/111111111111111111111111",
-				nil,
-			),
-			writer: httptest.NewRecorder(),
+			request: httptest.NewRequest("GET", "/api/modlog?action=edit_post", nil),
+			writer:  httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
-				service.EXPECT().DeleteComment(
-					"111111111111111111111111",
-					"111111111111111111111111",
-					model.User{ID: "1"},
-				).Return(model.Post{}, customerr.CommentNotFoundByID{PostID: "111111111111111111111111", CommentID: "111111111111111111111111"})
-				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111", "comment_id": "111111111111111111111111"})
-				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
-				handler.deleteComment(w, r.WithContext(ctx))
+				handler.getModLog(w, r.WithContext(context.WithValue(r.Context(), "user", moderator)))
 				return w.Result()
 			},
 			check: func(body []byte) bool {
-				data := []byte("{\"message\":\"comment not found\"}\n")
+				data := []byte("{\"errors\":[{\"location\":\"query\",\"param\":\"action\",\"value\":\"edit_post\",\"msg\":\"action must be a moderator action type\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/modlog?cursor=garbage", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				handler.getModLog(w, r.WithContext(context.WithValue(r.Context(), "user", moderator)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"query\",\"param\":\"cursor\",\"value\":\"garbage\",\"msg\":\"cursor must be a value of next_cursor\"}]}\n")
 				return reflect.DeepEqual(data, body)
 			},
 		},
@@ -870,12 +4429,23 @@ func TestDeleteComment(t *testing.T) {
 	}
 }
 
-func TestUpvotePost(t *testing.T) {
+func TestBanUser(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	service := mock.NewMockappService(ctrl)
 	handler := initHandler(ctrl, service)
 
+	moderator := model.User{ID: "1", Credential: model.Credential{Username: "admin"}, Role: model.RoleModerator}
+	ban := model.Ban{
+		ID:        "3",
+		Community: "news",
+		User:      model.Author{ID: "2", Username: "van"},
+		Moderator: model.Author{ID: "1", Username: "admin"},
+		Reason:    "spam",
+		Expires:   "2022-01-08T00:00:00.000Z",
+		Created:   "2022-01-01T00:00:00.000Z",
+	}
+
 	cases := []struct {
 		request *http.Request
 		writer  *httptest.ResponseRecorder
@@ -883,52 +4453,101 @@ func TestUpvotePost(t *testing.T) {
 		check   func(body []byte) bool
 	}{
 		{
-			request: httptest.NewRequest("GET", "/api/post/111111111111111111111111/upvote", nil),
+			request: httptest.NewRequest("POST", "/api/communities/news/bans", strings.NewReader("{\"username\":\"van\",\"reason\":\"spam\",\"days\":\"7\"}")),
 			writer:  httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
-				service.EXPECT().UpvotePost(
-					"111111111111111111111111",
-					model.User{ID: "1"},
-				).Return(model.Post{ID: "111111111111111111111111"}, nil)
-				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
-				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
-				handler.upvotePost(w, r.WithContext(ctx))
+				service.EXPECT().BanUser("news", "van", model.BanInput{Reason: "spam", Days: 7}, moderator).Return(ban, nil)
+				r = mux.SetURLVars(r, map[string]string{"community": "news"})
+				handler.banUser(w, r.WithContext(context.WithValue(r.Context(), "user", moderator)))
 				return w.Result()
 			},
 			check: func(body []byte) bool {
-				data, _ := json.Marshal(model.Post{ID: "111111111111111111111111"})
+				data := []byte("{\"id\":\"3\",\"community\":\"news\",\"user\":{\"id\":\"2\",\"username\":\"van\"},\"moderator\":{\"id\":\"1\",\"username\":\"admin\"},\"reason\":\"spam\",\"expires\":\"2022-01-08T00:00:00.000Z\",\"created\":\"2022-01-01T00:00:00.000Z\"}")
 				return reflect.DeepEqual(data, body)
 			},
 		},
 		{
-			request: httptest.NewRequest("GET", "/api/post/1/upvote", nil),
+			request: httptest.NewRequest("POST", "/api/communities/news/bans", strings.NewReader("{\"username\":\"van\"}")),
 			writer:  httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
-				r = mux.SetURLVars(r, map[string]string{"post_id": "1"})
-				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
-				handler.upvotePost(w, r.WithContext(ctx))
+				service.EXPECT().BanUser("news", "van", model.BanInput{}, moderator).Return(model.Ban{}, customerr.PermissionDenied{Username: "admin"})
+				r = mux.SetURLVars(r, map[string]string{"community": "news"})
+				handler.banUser(w, r.WithContext(context.WithValue(r.Context(), "user", moderator)))
 				return w.Result()
 			},
 			check: func(body []byte) bool {
-				data := []byte("{\"errors\":[{\"location\":\"path\",\"param\":\"post_id\",\"value\":\"1\",\"msg\":\"post_id must be a hexadecimal 24-symbols string\"}]}\n")
+				data := []byte("{\"message\":\"permission denied\"}\n")
 				return reflect.DeepEqual(data, body)
 			},
 		},
 		{
-			request: httptest.NewRequest("GET", "/api/post/111111111111111111111111/upvote", nil),
+			request: httptest.NewRequest("POST", "/api/communities/news/bans", strings.NewReader("{\"username\":\"van\",\"days\":\"0\"}")),
 			writer:  httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
-				service.EXPECT().UpvotePost(
-					"111111111111111111111111",
-					model.User{ID: "1"},
-				).Return(model.Post{}, customerr.PostNotFoundByID{PostID: "111111111111111111111111"})
+				r = mux.SetURLVars(r, map[string]string{"community": "news"})
+				handler.banUser(w, r.WithContext(context.WithValue(r.Context(), "user", moderator)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"errors\":[{\"location\":\"body\",\"param\":\"days\",\"value\":\"0\",\"msg\":\"days must be an integer from 1 to 3650, leave it out for a permanent ban\"}]}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/communities/news/bans", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().GetBans("news").Return([]model.Ban{ban}, nil)
+				r = mux.SetURLVars(r, map[string]string{"community": "news"})
+				handler.getBans(w, r.WithContext(context.WithValue(r.Context(), "user", moderator)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("[{\"id\":\"3\",\"community\":\"news\",\"user\":{\"id\":\"2\",\"username\":\"van\"},\"moderator\":{\"id\":\"1\",\"username\":\"admin\"},\"reason\":\"spam\",\"expires\":\"2022-01-08T00:00:00.000Z\",\"created\":\"2022-01-01T00:00:00.000Z\"}]")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("DELETE", "/api/communities/news/bans/van", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().UnbanUser("news", "van", moderator).Return(nil)
+				r = mux.SetURLVars(r, map[string]string{"community": "news", "username": "van"})
+				handler.unbanUser(w, r.WithContext(context.WithValue(r.Context(), "user", moderator)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\": \"success\"}")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("DELETE", "/api/communities/news/bans/ivan", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().UnbanUser("news", "ivan", moderator).Return(customerr.BanNotFound{Community: "news", UserID: "4"})
+				r = mux.SetURLVars(r, map[string]string{"community": "news", "username": "ivan"})
+				handler.unbanUser(w, r.WithContext(context.WithValue(r.Context(), "user", moderator)))
+				return w.Result()
+			},
+			check: func(body []byte) bool {
+				data := []byte("{\"message\":\"ban not found\"}\n")
+				return reflect.DeepEqual(data, body)
+			},
+		},
+		{
+			request: httptest.NewRequest("POST", "/api/post/111111111111111111111111", strings.NewReader("{\"comment\":\"hi\"}")),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				usr := model.User{ID: "2", Credential: model.Credential{Username: "van"}}
+				banned := customerr.Banned{Username: "van", Community: "news", Expires: "2022-01-08T00:00:00.000Z"}
+				service.EXPECT().AddComment("111111111111111111111111", "hi", "", usr).Return(model.Post{}, banned)
 				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
-				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
-				handler.upvotePost(w, r.WithContext(ctx))
+				handler.createComment(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
 				return w.Result()
 			},
 			check: func(body []byte) bool {
-				data := []byte("{\"message\":\"post not found\"}\n")
+				data := []byte("{\"message\":\"user is banned from the community\",\"expires\":\"2022-01-08T00:00:00.000Z\"}\n")
 				return reflect.DeepEqual(data, body)
 			},
 		},
@@ -943,12 +4562,15 @@ func TestUpvotePost(t *testing.T) {
 	}
 }
 
-func TestDownvotePost(t *testing.T) {
+func TestSuspendUser(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	service := mock.NewMockappService(ctrl)
 	handler := initHandler(ctrl, service)
 
+	admin := model.User{ID: "1", Credential: model.Credential{Username: "admin"}, Role: model.RoleAdmin}
+	userID := "222222222222222222222222"
+
 	cases := []struct {
 		request *http.Request
 		writer  *httptest.ResponseRecorder
@@ -956,52 +4578,44 @@ func TestDownvotePost(t *testing.T) {
 		check   func(body []byte) bool
 	}{
 		{
-			request: httptest.NewRequest("GET", "/api/post/111111111111111111111111/downvote", nil),
+			request: httptest.NewRequest("PUT", "/api/admin/user/"+userID+"/suspension", strings.NewReader("{\"reason\":\"spam\"}")),
 			writer:  httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
-				service.EXPECT().DownvotePost(
-					"111111111111111111111111",
-					model.User{ID: "1"},
-				).Return(model.Post{ID: "111111111111111111111111"}, nil)
-				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
-				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
-				handler.downvotePost(w, r.WithContext(ctx))
+				suspended := model.User{ID: userID, Credential: model.Credential{Username: "van"}, Suspension: &model.Suspension{Reason: "spam", Created: "2022-01-01T00:00:00.000Z"}}
+				service.EXPECT().SuspendUser(userID, model.BanInput{Reason: "spam"}, admin).Return(suspended, nil)
+				r = mux.SetURLVars(r, map[string]string{"user_id": userID})
+				handler.suspendUser(w, r.WithContext(context.WithValue(r.Context(), "user", admin)))
 				return w.Result()
 			},
 			check: func(body []byte) bool {
-				data, _ := json.Marshal(model.Post{ID: "111111111111111111111111"})
+				data := []byte("{\"id\":\"222222222222222222222222\",\"username\":\"van\",\"suspension\":{\"reason\":\"spam\",\"created\":\"2022-01-01T00:00:00.000Z\"}}")
 				return reflect.DeepEqual(data, body)
 			},
 		},
 		{
-			request: httptest.NewRequest("GET", "/api/post/1/downvote", nil),
+			request: httptest.NewRequest("PUT", "/api/admin/user/"+userID+"/suspension", strings.NewReader("{\"days\":\"week\"}")),
 			writer:  httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
-				r = mux.SetURLVars(r, map[string]string{"post_id": "1"})
-				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
-				handler.downvotePost(w, r.WithContext(ctx))
+				r = mux.SetURLVars(r, map[string]string{"user_id": userID})
+				handler.suspendUser(w, r.WithContext(context.WithValue(r.Context(), "user", admin)))
 				return w.Result()
 			},
 			check: func(body []byte) bool {
-				data := []byte("{\"errors\":[{\"location\":\"path\",\"param\":\"post_id\",\"value\":\"1\",\"msg\":\"post_id must be a hexadecimal 24-symbols string\"}]}\n")
+				data := []byte("{\"errors\":[{\"location\":\"body\",\"param\":\"days\",\"value\":\"week\",\"msg\":\"days must be an integer from 1 to 3650, leave it out for a permanent ban\"}]}\n")
 				return reflect.DeepEqual(data, body)
 			},
 		},
 		{
-			request: httptest.NewRequest("GET", "/api/post/111111111111111111111111/downvote", nil),
+			request: httptest.NewRequest("DELETE", "/api/admin/user/"+userID+"/suspension", nil),
 			writer:  httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
-				service.EXPECT().DownvotePost(
-					"111111111111111111111111",
-					model.User{ID: "1"},
-				).Return(model.Post{}, customerr.PostNotFoundByID{PostID: "111111111111111111111111"})
-				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
-				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
-				handler.downvotePost(w, r.WithContext(ctx))
+				service.EXPECT().UnsuspendUser(userID, admin).Return(model.User{ID: userID, Credential: model.Credential{Username: "van"}}, nil)
+				r = mux.SetURLVars(r, map[string]string{"user_id": userID})
+				handler.unsuspendUser(w, r.WithContext(context.WithValue(r.Context(), "user", admin)))
 				return w.Result()
 			},
 			check: func(body []byte) bool {
-				data := []byte("{\"message\":\"post not found\"}\n")
+				data := []byte("{\"id\":\"222222222222222222222222\",\"username\":\"van\",\"suspension\":null}")
 				return reflect.DeepEqual(data, body)
 			},
 		},
@@ -1016,12 +4630,127 @@ func TestDownvotePost(t *testing.T) {
 	}
 }
 
-func TestUnvotePost(t *testing.T) {
+func TestRateLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	service := mock.NewMockappService(ctrl)
+	sessions := opaquesession.NewManager(token.NewSigner("love", time.Hour), cookie.NewManager(cookie.NewMapStorage()))
+	limiter := NewRateLimiter(ratelimit.NewMemoryStore(), RateLimits{
+		rateLimitRegister: {Requests: 1, Window: time.Hour},
+		rateLimitVotes:    {Requests: 2, Window: time.Minute},
+	})
+	handler := NewHandler(sessions, newRefresher(), service, limiter)
+	router := handler.CreateRouter()
+
+	usr := model.User{ID: "1", Credential: model.Credential{Username: "van"}}
+	postID := "111111111111111111111111"
+	upvote := handler.limitByUser(rateLimitVotes, handler.upvotePost)
+
+	cases := []struct {
+		request *http.Request
+		writer  *httptest.ResponseRecorder
+		run     func(w *httptest.ResponseRecorder, r *http.Request) *http.Response
+		check   func(resp *http.Response) bool
+	}{
+		{
+			request: httptest.NewRequest("POST", "/api/register", strings.NewReader("{\"username\":\"van\",\"password\":\"password\"}")),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().RegisterUser(model.Credential{Username: "van", Password: "password"}).Return(usr, nil)
+				router.ServeHTTP(w, r)
+				return w.Result()
+			},
+			check: func(resp *http.Response) bool {
+				return resp.StatusCode != http.StatusTooManyRequests
+			},
+		},
+		{
+			request: httptest.NewRequest("POST", "/api/register", strings.NewReader("{\"username\":\"ivan\",\"password\":\"password\"}")),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				router.ServeHTTP(w, r)
+				return w.Result()
+			},
+			check: func(resp *http.Response) bool {
+				body, _ := ioutil.ReadAll(resp.Body)
+				return resp.StatusCode == http.StatusTooManyRequests &&
+					resp.Header.Get("Retry-After") == "3600" &&
+					string(body) == "{\"message\":\"too many requests\"}\n"
+			},
+		},
+		{
+			request: httptest.NewRequest("POST", "/api/register", strings.NewReader("{\"username\":\"ivan\",\"password\":\"password\"}")),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().RegisterUser(model.Credential{Username: "ivan", Password: "password"}).Return(model.User{ID: "2"}, nil)
+				r.RemoteAddr = "192.0.2.2:1234"
+				router.ServeHTTP(w, r)
+				return w.Result()
+			},
+			check: func(resp *http.Response) bool {
+				return resp.StatusCode != http.StatusTooManyRequests
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/post/"+postID+"/upvote", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				service.EXPECT().UpvotePost(postID, usr).Return(model.Post{ID: postID}, nil).Times(2)
+				r = mux.SetURLVars(r, map[string]string{"post_id": postID})
+				r = r.WithContext(context.WithValue(r.Context(), "user", usr))
+				upvote(httptest.NewRecorder(), r)
+				upvote(w, r)
+				return w.Result()
+			},
+			check: func(resp *http.Response) bool {
+				return resp.StatusCode == http.StatusOK
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/post/"+postID+"/upvote", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				r = mux.SetURLVars(r, map[string]string{"post_id": postID})
+				upvote(w, r.WithContext(context.WithValue(r.Context(), "user", usr)))
+				return w.Result()
+			},
+			check: func(resp *http.Response) bool {
+				return resp.StatusCode == http.StatusTooManyRequests && resp.Header.Get("Retry-After") == "60"
+			},
+		},
+		{
+			request: httptest.NewRequest("GET", "/api/post/"+postID+"/upvote", nil),
+			writer:  httptest.NewRecorder(),
+			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
+				other := model.User{ID: "2", Credential: model.Credential{Username: "ivan"}}
+				service.EXPECT().UpvotePost(postID, other).Return(model.Post{ID: postID}, nil)
+				r = mux.SetURLVars(r, map[string]string{"post_id": postID})
+				upvote(w, r.WithContext(context.WithValue(r.Context(), "user", other)))
+				return w.Result()
+			},
+			check: func(resp *http.Response) bool {
+				return resp.StatusCode == http.StatusOK
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resp := item.run(item.writer, item.request)
+		if !item.check(resp) {
+			t.Errorf("[%d] unexpected response: %d %v", i, resp.StatusCode, resp.Header)
+		}
+	}
+}
+
+func TestUnlockUser(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	service := mock.NewMockappService(ctrl)
 	handler := initHandler(ctrl, service)
 
+	admin := model.User{ID: "1", Credential: model.Credential{Username: "admin"}, Role: model.RoleAdmin}
+	userID := "222222222222222222222222"
+
 	cases := []struct {
 		request *http.Request
 		writer  *httptest.ResponseRecorder
@@ -1029,52 +4758,43 @@ func TestUnvotePost(t *testing.T) {
 		check   func(body []byte) bool
 	}{
 		{
-			request: httptest.NewRequest("GET", "/api/post/111111111111111111111111/unvote", nil),
+			request: httptest.NewRequest("DELETE", "/api/admin/user/"+userID+"/lockout", nil),
 			writer:  httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
-				service.EXPECT().UnvotePost(
-					"111111111111111111111111",
-					model.User{ID: "1"},
-				).Return(model.Post{ID: "111111111111111111111111"}, nil)
-				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
-				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
-				handler.unvotePost(w, r.WithContext(ctx))
+				service.EXPECT().UnlockUser(userID, admin).Return(nil)
+				r = mux.SetURLVars(r, map[string]string{"user_id": userID})
+				handler.unlockUser(w, r.WithContext(context.WithValue(r.Context(), "user", admin)))
 				return w.Result()
 			},
 			check: func(body []byte) bool {
-				data, _ := json.Marshal(model.Post{ID: "111111111111111111111111"})
+				data := []byte("{\"message\": \"success\"}")
 				return reflect.DeepEqual(data, body)
 			},
 		},
 		{
-			request: httptest.NewRequest("GET", "/api/post/1/unvote", nil),
+			request: httptest.NewRequest("DELETE", "/api/admin/user/2/lockout", nil),
 			writer:  httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
-				r = mux.SetURLVars(r, map[string]string{"post_id": "1"})
-				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
-				handler.unvotePost(w, r.WithContext(ctx))
+				r = mux.SetURLVars(r, map[string]string{"user_id": "2"})
+				handler.unlockUser(w, r.WithContext(context.WithValue(r.Context(), "user", admin)))
 				return w.Result()
 			},
 			check: func(body []byte) bool {
-				data := []byte("{\"errors\":[{\"location\":\"path\",\"param\":\"post_id\",\"value\":\"1\",\"msg\":\"post_id must be a hexadecimal 24-symbols string\"}]}\n")
+				data := []byte("{\"errors\":[{\"location\":\"path\",\"param\":\"user_id\",\"value\":\"2\",\"msg\":\"user_id must be a hexadecimal 24-symbols string\"}]}\n")
 				return reflect.DeepEqual(data, body)
 			},
 		},
 		{
-			request: httptest.NewRequest("GET", "/api/post/111111111111111111111111/unvote", nil),
+			request: httptest.NewRequest("DELETE", "/api/admin/user/"+userID+"/lockout", nil),
 			writer:  httptest.NewRecorder(),
 			run: func(w *httptest.ResponseRecorder, r *http.Request) *http.Response {
-				service.EXPECT().UnvotePost(
-					"111111111111111111111111",
-					model.User{ID: "1"},
-				).Return(model.Post{}, customerr.PostNotFoundByID{PostID: "111111111111111111111111"})
-				r = mux.SetURLVars(r, map[string]string{"post_id": "111111111111111111111111"})
-				ctx := context.WithValue(r.Context(), "user", model.User{ID: "1"})
-				handler.unvotePost(w, r.WithContext(ctx))
+				service.EXPECT().UnlockUser(userID, admin).Return(customerr.UserNotFoundByID{UserID: userID})
+				r = mux.SetURLVars(r, map[string]string{"user_id": userID})
+				handler.unlockUser(w, r.WithContext(context.WithValue(r.Context(), "user", admin)))
 				return w.Result()
 			},
 			check: func(body []byte) bool {
-				data := []byte("{\"message\":\"post not found\"}\n")
+				data := []byte("{\"message\":\"user not found\"}\n")
 				return reflect.DeepEqual(data, body)
 			},
 		},
@@ -1088,3 +4808,60 @@ func TestUnvotePost(t *testing.T) {
 		}
 	}
 }
+
+// TestLoginLockout runs the real service, the username gets locked after the failures and an admin unlocks it
+func TestLoginLockout(t *testing.T) {
+	hasher := service.NewArgon2idHasher()
+	hash, err := hasher.Hash("password")
+	if err != nil {
+		t.Fatalf("cant hash the password: %s", err)
+	}
+
+	userID := "222222222222222222222222"
+	users := slicerepo.NewUsersRepo()
+	_ = users.AddUser(model.User{ID: userID, Credential: model.Credential{Username: "van", Password: hash}})
+	modActions := slicerepo.NewModActionsRepo()
+
+	appService := service.NewService(service.Repositories{
+		Users:         users,
+		ModActions:    modActions,
+		LoginAttempts: lockout.NewMemoryStore(),
+	}, hasher, broker.NewMemoryBroker(), nil)
+	sessions := opaquesession.NewManager(token.NewSigner("love", time.Hour), cookie.NewManager(cookie.NewMapStorage()))
+	handler := NewHandler(sessions, newRefresher(), appService, nil)
+
+	login := func(password string) *http.Response {
+		body := fmt.Sprintf("{\"username\":\"van\",\"password\":\"%s\"}", password)
+		w := httptest.NewRecorder()
+		handler.signIn(w, httptest.NewRequest("POST", "/api/login", strings.NewReader(body)))
+		return w.Result()
+	}
+
+	for i := 0; i < model.MaxLoginFailures; i++ {
+		if resp := login("wrong"); resp.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("[%d] expected a wrong credential, got %d", i, resp.StatusCode)
+		}
+	}
+
+	resp := login("password")
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") != "60" {
+		t.Fatalf("expected a lockout for a minute, got %d and Retry-After %q", resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+
+	page, err := appService.GetModLog(model.ModLogQuery{Action: model.ModActionLockAccount, Limit: 10})
+	if err != nil || len(page.Actions) != 1 || page.Actions[0].TargetUser.ID != userID {
+		t.Errorf("expected a lockout in the modlog, got %+v, %v", page.Actions, err)
+	}
+
+	admin := model.User{ID: "1", Credential: model.Credential{Username: "admin"}, Role: model.RoleAdmin}
+	w := httptest.NewRecorder()
+	r := mux.SetURLVars(httptest.NewRequest("DELETE", "/api/admin/user/"+userID+"/lockout", nil), map[string]string{"user_id": userID})
+	handler.unlockUser(w, r.WithContext(context.WithValue(r.Context(), "user", admin)))
+	if w.Result().StatusCode != http.StatusOK {
+		t.Fatalf("expected the unlock to succeed, got %d", w.Result().StatusCode)
+	}
+
+	if resp = login("password"); resp.StatusCode == http.StatusTooManyRequests {
+		t.Errorf("expected the login to pass after the unlock")
+	}
+}
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"net/http"
+	"redditclone/internal/model"
+	"redditclone/internal/model/customerr"
+)
+
+func (h *Handler) getConversations(w http.ResponseWriter, r *http.Request) {
+	usr := r.Context().Value("user").(model.User)
+
+	conversations, err := h.service.GetConversations(usr)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp, err := json.Marshal(conversations)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if _, err = w.Write(resp); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) getThread(w http.ResponseWriter, r *http.Request) {
+	usr := r.Context().Value("user").(model.User)
+	vars := mux.Vars(r)
+	username := vars["username"]
+
+	if errs := h.validator.ValidatePathValue("username", username); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	messages, err := h.service.GetThread(username, usr)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp, err := json.Marshal(messages)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if _, err = w.Write(resp); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) sendMessage(w http.ResponseWriter, r *http.Request) {
+	usr := r.Context().Value("user").(model.User)
+	vars := mux.Vars(r)
+	username := vars["username"]
+
+	if errs := h.validator.ValidatePathValue("username", username); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	var input map[string]string
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		h.handleError(w, customerr.RequestNotParsed{Message: err.Error()})
+		return
+	}
+
+	if errs := h.validator.ValidateBody("Message", input); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	message, err := h.service.SendMessage(username, input["body"], usr)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp, err := json.Marshal(message)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if _, err = w.Write(resp); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (h *Handler) markThreadRead(w http.ResponseWriter, r *http.Request) {
+	usr := r.Context().Value("user").(model.User)
+	vars := mux.Vars(r)
+	username := vars["username"]
+
+	if errs := h.validator.ValidatePathValue("username", username); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	if err := h.service.MarkThreadRead(username, usr); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp := []byte(fmt.Sprintf("{\"message\": \"success\"}"))
+	if _, err := w.Write(resp); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) getBlocks(w http.ResponseWriter, r *http.Request) {
+	usr := r.Context().Value("user").(model.User)
+
+	blocks, err := h.service.GetBlocks(usr)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp, err := json.Marshal(blocks)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if _, err = w.Write(resp); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) blockUser(w http.ResponseWriter, r *http.Request) {
+	usr := r.Context().Value("user").(model.User)
+	vars := mux.Vars(r)
+	username := vars["username"]
+
+	if errs := h.validator.ValidatePathValue("username", username); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	if err := h.service.BlockUser(username, usr); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp := []byte(fmt.Sprintf("{\"message\": \"success\"}"))
+	if _, err := w.Write(resp); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) unblockUser(w http.ResponseWriter, r *http.Request) {
+	usr := r.Context().Value("user").(model.User)
+	vars := mux.Vars(r)
+	username := vars["username"]
+
+	if errs := h.validator.ValidatePathValue("username", username); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	if err := h.service.UnblockUser(username, usr); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp := []byte(fmt.Sprintf("{\"message\": \"success\"}"))
+	if _, err := w.Write(resp); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
@@ -2,13 +2,11 @@ package handler
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"github.com/sirupsen/logrus"
 	"net/http"
+	"redditclone/internal/model"
 	"redditclone/internal/model/customerr"
-	"redditclone/pkg/token"
-	"strings"
 )
 
 type loggedWriter struct {
@@ -31,6 +29,13 @@ func (w *loggedWriter) Header() http.Header {
 	return w.responseWriter.Header()
 }
 
+// Flush lets the event streams through the access log
+func (w *loggedWriter) Flush() {
+	if flusher, ok := w.responseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
 func (h *Handler) accessLogMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		loggedWriter := &loggedWriter{responseWriter: w}
@@ -40,51 +45,57 @@ func (h *Handler) accessLogMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-func (h *Handler) getToken(r *http.Request) (string, error) {
-	header := r.Header.Get(authorizationHeader)
-	if header == "" {
-		return "", errors.New("empty auth header")
+// requestUser resolves the user of the token the request carries
+func (h *Handler) requestUser(r *http.Request) (model.User, error) {
+	t, err := h.sessions.GetTokenFromRequest(r)
+	if err != nil {
+		return model.User{}, customerr.Unauthorized{Message: err.Error()}
 	}
 
-	headerParts := strings.Split(header, " ")
-	if len(headerParts) != 2 {
-		return "", errors.New("invalid auth header")
+	authUser, err := h.sessions.GetUserByToken(t)
+	if err != nil {
+		return model.User{}, customerr.Unauthorized{Message: err.Error()}
 	}
 
-	return headerParts[1], nil
-}
-
-func (h *Handler) getAuthUserFromJWT(t string) (token.AuthUser, error) {
-	return h.signer.ParseToken(t)
-}
-
-func (h *Handler) getAuthUserFromCookie(t string) (token.AuthUser, error) {
-	serializedUser, err := h.sessions.GetCookie(t)
+	usr, err := h.service.GetUserByID(authUser.ID)
 	if err != nil {
-		return token.AuthUser{}, err
+		if _, ok := err.(customerr.UserNotFoundByID); ok {
+			return model.User{}, customerr.Unauthorized{Message: err.Error()}
+		}
+		return model.User{}, err
 	}
-	var authUser token.AuthUser
-	err = json.Unmarshal(serializedUser, &authUser)
-	return authUser, err
+
+	return usr, nil
 }
 
+// authorizeMiddleware leaves a suspended user only the routes for viewers
 func (h *Handler) authorizeMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		t, err := h.getToken(r)
+		usr, err := h.requestUser(r)
 		if err != nil {
-			h.handleError(w, customerr.Unauthorized{Message: err.Error()})
+			h.handleError(w, err)
 			return
 		}
 
-		authUser, err := h.getAuthUserFromCookie(t)
-		if err != nil {
-			h.handleError(w, customerr.Unauthorized{Message: err.Error()})
+		if usr.IsSuspended() {
+			h.handleError(w, customerr.Banned{Username: usr.Username, Expires: usr.Suspension.Expires})
+			return
 		}
 
-		usr, err := h.service.GetUserByID(authUser.ID)
+		ctx := context.WithValue(r.Context(), "user", usr)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// identifyMiddleware puts the user into the context when the request carries a valid token,
+// the other requests pass as anonymous
+func (h *Handler) identifyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		usr, err := h.requestUser(r)
 		if err != nil {
-			if _, ok := err.(customerr.UserNotFoundByID); ok {
-				h.handleError(w, customerr.Unauthorized{Message: err.Error()})
+			if _, ok := err.(customerr.Unauthorized); ok {
+				next.ServeHTTP(w, r)
+				return
 			}
 			h.handleError(w, err)
 			return
@@ -95,6 +106,41 @@ func (h *Handler) authorizeMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// adminMiddleware must run after authorizeMiddleware, it relies on the user put into the context
+func (h *Handler) adminMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		usr, ok := r.Context().Value("user").(model.User)
+		if !ok {
+			h.handleError(w, customerr.Unauthorized{Message: "user not found in context"})
+			return
+		}
+
+		if !usr.IsAdmin() {
+			h.handleError(w, customerr.PermissionDenied{Username: usr.Username})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (h *Handler) moderatorMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		usr, ok := r.Context().Value("user").(model.User)
+		if !ok {
+			h.handleError(w, customerr.Unauthorized{Message: "user not found in context"})
+			return
+		}
+
+		if !usr.IsModerator() {
+			h.handleError(w, customerr.PermissionDenied{Username: usr.Username})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
 func (h *Handler) recoverPanicMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
@@ -6,6 +6,8 @@ package mock_handler
 
 import (
 	model "redditclone/internal/model"
+	broker "redditclone/pkg/broker"
+	token "redditclone/pkg/token"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -35,18 +37,18 @@ func (m *MockauthService) EXPECT() *MockauthServiceMockRecorder {
 }
 
 // LoginUser mocks base method.
-func (m *MockauthService) LoginUser(cred model.Credential) (model.User, error) {
+func (m *MockauthService) LoginUser(cred model.Credential, ip string) (model.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LoginUser", cred)
+	ret := m.ctrl.Call(m, "LoginUser", cred, ip)
 	ret0, _ := ret[0].(model.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // LoginUser indicates an expected call of LoginUser.
-func (mr *MockauthServiceMockRecorder) LoginUser(cred interface{}) *gomock.Call {
+func (mr *MockauthServiceMockRecorder) LoginUser(cred, ip interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginUser", reflect.TypeOf((*MockauthService)(nil).LoginUser), cred)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginUser", reflect.TypeOf((*MockauthService)(nil).LoginUser), cred, ip)
 }
 
 // RegisterUser mocks base method.
@@ -88,18 +90,18 @@ func (m *MockpostsService) EXPECT() *MockpostsServiceMockRecorder {
 }
 
 // AddComment mocks base method.
-func (m *MockpostsService) AddComment(postID, commentText string, usr model.User) (model.Post, error) {
+func (m *MockpostsService) AddComment(postID, commentText, parentID string, usr model.User) (model.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddComment", postID, commentText, usr)
+	ret := m.ctrl.Call(m, "AddComment", postID, commentText, parentID, usr)
 	ret0, _ := ret[0].(model.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // AddComment indicates an expected call of AddComment.
-func (mr *MockpostsServiceMockRecorder) AddComment(postID, commentText, usr interface{}) *gomock.Call {
+func (mr *MockpostsServiceMockRecorder) AddComment(postID, commentText, parentID, usr interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddComment", reflect.TypeOf((*MockpostsService)(nil).AddComment), postID, commentText, usr)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddComment", reflect.TypeOf((*MockpostsService)(nil).AddComment), postID, commentText, parentID, usr)
 }
 
 // CreateTextPost mocks base method.
@@ -133,32 +135,47 @@ func (mr *MockpostsServiceMockRecorder) CreateURLPost(input, usr interface{}) *g
 }
 
 // DeleteComment mocks base method.
-func (m *MockpostsService) DeleteComment(postID, commentID string, usr model.User) (model.Post, error) {
+func (m *MockpostsService) DeleteComment(postID, commentID, reason string, usr model.User) (model.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteComment", postID, commentID, usr)
+	ret := m.ctrl.Call(m, "DeleteComment", postID, commentID, reason, usr)
 	ret0, _ := ret[0].(model.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DeleteComment indicates an expected call of DeleteComment.
-func (mr *MockpostsServiceMockRecorder) DeleteComment(postID, commentID, usr interface{}) *gomock.Call {
+func (mr *MockpostsServiceMockRecorder) DeleteComment(postID, commentID, reason, usr interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteComment", reflect.TypeOf((*MockpostsService)(nil).DeleteComment), postID, commentID, usr)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteComment", reflect.TypeOf((*MockpostsService)(nil).DeleteComment), postID, commentID, reason, usr)
 }
 
 // DeletePost mocks base method.
-func (m *MockpostsService) DeletePost(postID string, usr model.User) error {
+func (m *MockpostsService) DeletePost(postID, reason string, usr model.User) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeletePost", postID, usr)
+	ret := m.ctrl.Call(m, "DeletePost", postID, reason, usr)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeletePost indicates an expected call of DeletePost.
-func (mr *MockpostsServiceMockRecorder) DeletePost(postID, usr interface{}) *gomock.Call {
+func (mr *MockpostsServiceMockRecorder) DeletePost(postID, reason, usr interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePost", reflect.TypeOf((*MockpostsService)(nil).DeletePost), postID, usr)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePost", reflect.TypeOf((*MockpostsService)(nil).DeletePost), postID, reason, usr)
+}
+
+// DownvoteComment mocks base method.
+func (m *MockpostsService) DownvoteComment(postID, commentID string, usr model.User) (model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownvoteComment", postID, commentID, usr)
+	ret0, _ := ret[0].(model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DownvoteComment indicates an expected call of DownvoteComment.
+func (mr *MockpostsServiceMockRecorder) DownvoteComment(postID, commentID, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownvoteComment", reflect.TypeOf((*MockpostsService)(nil).DownvoteComment), postID, commentID, usr)
 }
 
 // DownvotePost mocks base method.
@@ -176,6 +193,36 @@ func (mr *MockpostsServiceMockRecorder) DownvotePost(postID, usr interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownvotePost", reflect.TypeOf((*MockpostsService)(nil).DownvotePost), postID, usr)
 }
 
+// EditComment mocks base method.
+func (m *MockpostsService) EditComment(postID, commentID, commentText string, usr model.User) (model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditComment", postID, commentID, commentText, usr)
+	ret0, _ := ret[0].(model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EditComment indicates an expected call of EditComment.
+func (mr *MockpostsServiceMockRecorder) EditComment(postID, commentID, commentText, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditComment", reflect.TypeOf((*MockpostsService)(nil).EditComment), postID, commentID, commentText, usr)
+}
+
+// FillUserVotes mocks base method.
+func (m *MockpostsService) FillUserVotes(posts []model.Post, usr model.User) ([]model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FillUserVotes", posts, usr)
+	ret0, _ := ret[0].([]model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FillUserVotes indicates an expected call of FillUserVotes.
+func (mr *MockpostsServiceMockRecorder) FillUserVotes(posts, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FillUserVotes", reflect.TypeOf((*MockpostsService)(nil).FillUserVotes), posts, usr)
+}
+
 // GetAllPosts mocks base method.
 func (m *MockpostsService) GetAllPosts() ([]model.Post, error) {
 	m.ctrl.T.Helper()
@@ -206,6 +253,36 @@ func (mr *MockpostsServiceMockRecorder) GetPostByID(postID interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostByID", reflect.TypeOf((*MockpostsService)(nil).GetPostByID), postID)
 }
 
+// GetPostRevisions mocks base method.
+func (m *MockpostsService) GetPostRevisions(postID string) ([]model.PostRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostRevisions", postID)
+	ret0, _ := ret[0].([]model.PostRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostRevisions indicates an expected call of GetPostRevisions.
+func (mr *MockpostsServiceMockRecorder) GetPostRevisions(postID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostRevisions", reflect.TypeOf((*MockpostsService)(nil).GetPostRevisions), postID)
+}
+
+// GetPosts mocks base method.
+func (m *MockpostsService) GetPosts(query model.PostsQuery) (model.PostsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPosts", query)
+	ret0, _ := ret[0].(model.PostsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPosts indicates an expected call of GetPosts.
+func (mr *MockpostsServiceMockRecorder) GetPosts(query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPosts", reflect.TypeOf((*MockpostsService)(nil).GetPosts), query)
+}
+
 // GetPostsByAuthor mocks base method.
 func (m *MockpostsService) GetPostsByAuthor(username string) ([]model.Post, error) {
 	m.ctrl.T.Helper()
@@ -236,248 +313,263 @@ func (mr *MockpostsServiceMockRecorder) GetPostsByCategory(category interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostsByCategory", reflect.TypeOf((*MockpostsService)(nil).GetPostsByCategory), category)
 }
 
-// UnvotePost mocks base method.
-func (m *MockpostsService) UnvotePost(postID string, usr model.User) (model.Post, error) {
+// GetVotesByUser mocks base method.
+func (m *MockpostsService) GetVotesByUser(usr model.User) ([]model.Vote, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UnvotePost", postID, usr)
-	ret0, _ := ret[0].(model.Post)
+	ret := m.ctrl.Call(m, "GetVotesByUser", usr)
+	ret0, _ := ret[0].([]model.Vote)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UnvotePost indicates an expected call of UnvotePost.
-func (mr *MockpostsServiceMockRecorder) UnvotePost(postID, usr interface{}) *gomock.Call {
+// GetVotesByUser indicates an expected call of GetVotesByUser.
+func (mr *MockpostsServiceMockRecorder) GetVotesByUser(usr interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnvotePost", reflect.TypeOf((*MockpostsService)(nil).UnvotePost), postID, usr)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVotesByUser", reflect.TypeOf((*MockpostsService)(nil).GetVotesByUser), usr)
 }
 
-// UpvotePost mocks base method.
-func (m *MockpostsService) UpvotePost(postID string, usr model.User) (model.Post, error) {
+// ListPosts mocks base method.
+func (m *MockpostsService) ListPosts(query model.PostsQuery) ([]model.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpvotePost", postID, usr)
-	ret0, _ := ret[0].(model.Post)
+	ret := m.ctrl.Call(m, "ListPosts", query)
+	ret0, _ := ret[0].([]model.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpvotePost indicates an expected call of UpvotePost.
-func (mr *MockpostsServiceMockRecorder) UpvotePost(postID, usr interface{}) *gomock.Call {
+// ListPosts indicates an expected call of ListPosts.
+func (mr *MockpostsServiceMockRecorder) ListPosts(query interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpvotePost", reflect.TypeOf((*MockpostsService)(nil).UpvotePost), postID, usr)
-}
-
-// MockusersService is a mock of usersService interface.
-type MockusersService struct {
-	ctrl     *gomock.Controller
-	recorder *MockusersServiceMockRecorder
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPosts", reflect.TypeOf((*MockpostsService)(nil).ListPosts), query)
 }
 
-// MockusersServiceMockRecorder is the mock recorder for MockusersService.
-type MockusersServiceMockRecorder struct {
-	mock *MockusersService
-}
-
-// NewMockusersService creates a new mock instance.
-func NewMockusersService(ctrl *gomock.Controller) *MockusersService {
-	mock := &MockusersService{ctrl: ctrl}
-	mock.recorder = &MockusersServiceMockRecorder{mock}
-	return mock
-}
-
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockusersService) EXPECT() *MockusersServiceMockRecorder {
-	return m.recorder
-}
-
-// GetUserByID mocks base method.
-func (m *MockusersService) GetUserByID(userID string) (model.User, error) {
+// UnvoteComment mocks base method.
+func (m *MockpostsService) UnvoteComment(postID, commentID string, usr model.User) (model.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserByID", userID)
-	ret0, _ := ret[0].(model.User)
+	ret := m.ctrl.Call(m, "UnvoteComment", postID, commentID, usr)
+	ret0, _ := ret[0].(model.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserByID indicates an expected call of GetUserByID.
-func (mr *MockusersServiceMockRecorder) GetUserByID(userID interface{}) *gomock.Call {
+// UnvoteComment indicates an expected call of UnvoteComment.
+func (mr *MockpostsServiceMockRecorder) UnvoteComment(postID, commentID, usr interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockusersService)(nil).GetUserByID), userID)
-}
-
-// MockappService is a mock of appService interface.
-type MockappService struct {
-	ctrl     *gomock.Controller
-	recorder *MockappServiceMockRecorder
-}
-
-// MockappServiceMockRecorder is the mock recorder for MockappService.
-type MockappServiceMockRecorder struct {
-	mock *MockappService
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnvoteComment", reflect.TypeOf((*MockpostsService)(nil).UnvoteComment), postID, commentID, usr)
 }
 
-// NewMockappService creates a new mock instance.
-func NewMockappService(ctrl *gomock.Controller) *MockappService {
-	mock := &MockappService{ctrl: ctrl}
-	mock.recorder = &MockappServiceMockRecorder{mock}
-	return mock
+// UnvotePost mocks base method.
+func (m *MockpostsService) UnvotePost(postID string, usr model.User) (model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnvotePost", postID, usr)
+	ret0, _ := ret[0].(model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockappService) EXPECT() *MockappServiceMockRecorder {
-	return m.recorder
+// UnvotePost indicates an expected call of UnvotePost.
+func (mr *MockpostsServiceMockRecorder) UnvotePost(postID, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnvotePost", reflect.TypeOf((*MockpostsService)(nil).UnvotePost), postID, usr)
 }
 
-// AddComment mocks base method.
-func (m *MockappService) AddComment(postID, commentText string, usr model.User) (model.Post, error) {
+// UpdatePost mocks base method.
+func (m *MockpostsService) UpdatePost(postID string, input model.PostUpdateInput, usr model.User) (model.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddComment", postID, commentText, usr)
+	ret := m.ctrl.Call(m, "UpdatePost", postID, input, usr)
 	ret0, _ := ret[0].(model.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// AddComment indicates an expected call of AddComment.
-func (mr *MockappServiceMockRecorder) AddComment(postID, commentText, usr interface{}) *gomock.Call {
+// UpdatePost indicates an expected call of UpdatePost.
+func (mr *MockpostsServiceMockRecorder) UpdatePost(postID, input, usr interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddComment", reflect.TypeOf((*MockappService)(nil).AddComment), postID, commentText, usr)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePost", reflect.TypeOf((*MockpostsService)(nil).UpdatePost), postID, input, usr)
 }
 
-// CreateTextPost mocks base method.
-func (m *MockappService) CreateTextPost(input model.TextPostInput, usr model.User) (model.Post, error) {
+// UpvoteComment mocks base method.
+func (m *MockpostsService) UpvoteComment(postID, commentID string, usr model.User) (model.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTextPost", input, usr)
+	ret := m.ctrl.Call(m, "UpvoteComment", postID, commentID, usr)
 	ret0, _ := ret[0].(model.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateTextPost indicates an expected call of CreateTextPost.
-func (mr *MockappServiceMockRecorder) CreateTextPost(input, usr interface{}) *gomock.Call {
+// UpvoteComment indicates an expected call of UpvoteComment.
+func (mr *MockpostsServiceMockRecorder) UpvoteComment(postID, commentID, usr interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTextPost", reflect.TypeOf((*MockappService)(nil).CreateTextPost), input, usr)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpvoteComment", reflect.TypeOf((*MockpostsService)(nil).UpvoteComment), postID, commentID, usr)
 }
 
-// CreateURLPost mocks base method.
-func (m *MockappService) CreateURLPost(input model.URLPostInput, usr model.User) (model.Post, error) {
+// UpvotePost mocks base method.
+func (m *MockpostsService) UpvotePost(postID string, usr model.User) (model.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateURLPost", input, usr)
+	ret := m.ctrl.Call(m, "UpvotePost", postID, usr)
 	ret0, _ := ret[0].(model.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateURLPost indicates an expected call of CreateURLPost.
-func (mr *MockappServiceMockRecorder) CreateURLPost(input, usr interface{}) *gomock.Call {
+// UpvotePost indicates an expected call of UpvotePost.
+func (mr *MockpostsServiceMockRecorder) UpvotePost(postID, usr interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateURLPost", reflect.TypeOf((*MockappService)(nil).CreateURLPost), input, usr)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpvotePost", reflect.TypeOf((*MockpostsService)(nil).UpvotePost), postID, usr)
 }
 
-// DeleteComment mocks base method.
-func (m *MockappService) DeleteComment(postID, commentID string, usr model.User) (model.Post, error) {
+// MockcommunitiesService is a mock of communitiesService interface.
+type MockcommunitiesService struct {
+	ctrl     *gomock.Controller
+	recorder *MockcommunitiesServiceMockRecorder
+}
+
+// MockcommunitiesServiceMockRecorder is the mock recorder for MockcommunitiesService.
+type MockcommunitiesServiceMockRecorder struct {
+	mock *MockcommunitiesService
+}
+
+// NewMockcommunitiesService creates a new mock instance.
+func NewMockcommunitiesService(ctrl *gomock.Controller) *MockcommunitiesService {
+	mock := &MockcommunitiesService{ctrl: ctrl}
+	mock.recorder = &MockcommunitiesServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockcommunitiesService) EXPECT() *MockcommunitiesServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateCommunity mocks base method.
+func (m *MockcommunitiesService) CreateCommunity(input model.CommunityInput, usr model.User) (model.Community, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteComment", postID, commentID, usr)
-	ret0, _ := ret[0].(model.Post)
+	ret := m.ctrl.Call(m, "CreateCommunity", input, usr)
+	ret0, _ := ret[0].(model.Community)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// DeleteComment indicates an expected call of DeleteComment.
-func (mr *MockappServiceMockRecorder) DeleteComment(postID, commentID, usr interface{}) *gomock.Call {
+// CreateCommunity indicates an expected call of CreateCommunity.
+func (mr *MockcommunitiesServiceMockRecorder) CreateCommunity(input, usr interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteComment", reflect.TypeOf((*MockappService)(nil).DeleteComment), postID, commentID, usr)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCommunity", reflect.TypeOf((*MockcommunitiesService)(nil).CreateCommunity), input, usr)
 }
 
-// DeletePost mocks base method.
-func (m *MockappService) DeletePost(postID string, usr model.User) error {
+// DeleteCommunity mocks base method.
+func (m *MockcommunitiesService) DeleteCommunity(name string, usr model.User) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeletePost", postID, usr)
+	ret := m.ctrl.Call(m, "DeleteCommunity", name, usr)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeletePost indicates an expected call of DeletePost.
-func (mr *MockappServiceMockRecorder) DeletePost(postID, usr interface{}) *gomock.Call {
+// DeleteCommunity indicates an expected call of DeleteCommunity.
+func (mr *MockcommunitiesServiceMockRecorder) DeleteCommunity(name, usr interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePost", reflect.TypeOf((*MockappService)(nil).DeletePost), postID, usr)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCommunity", reflect.TypeOf((*MockcommunitiesService)(nil).DeleteCommunity), name, usr)
 }
 
-// DownvotePost mocks base method.
-func (m *MockappService) DownvotePost(postID string, usr model.User) (model.Post, error) {
+// GetCommunities mocks base method.
+func (m *MockcommunitiesService) GetCommunities() ([]model.Community, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DownvotePost", postID, usr)
-	ret0, _ := ret[0].(model.Post)
+	ret := m.ctrl.Call(m, "GetCommunities")
+	ret0, _ := ret[0].([]model.Community)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// DownvotePost indicates an expected call of DownvotePost.
-func (mr *MockappServiceMockRecorder) DownvotePost(postID, usr interface{}) *gomock.Call {
+// GetCommunities indicates an expected call of GetCommunities.
+func (mr *MockcommunitiesServiceMockRecorder) GetCommunities() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownvotePost", reflect.TypeOf((*MockappService)(nil).DownvotePost), postID, usr)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommunities", reflect.TypeOf((*MockcommunitiesService)(nil).GetCommunities))
 }
 
-// GetAllPosts mocks base method.
-func (m *MockappService) GetAllPosts() ([]model.Post, error) {
+// GetCommunity mocks base method.
+func (m *MockcommunitiesService) GetCommunity(name string) (model.Community, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllPosts")
-	ret0, _ := ret[0].([]model.Post)
+	ret := m.ctrl.Call(m, "GetCommunity", name)
+	ret0, _ := ret[0].(model.Community)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAllPosts indicates an expected call of GetAllPosts.
-func (mr *MockappServiceMockRecorder) GetAllPosts() *gomock.Call {
+// GetCommunity indicates an expected call of GetCommunity.
+func (mr *MockcommunitiesServiceMockRecorder) GetCommunity(name interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllPosts", reflect.TypeOf((*MockappService)(nil).GetAllPosts))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommunity", reflect.TypeOf((*MockcommunitiesService)(nil).GetCommunity), name)
 }
 
-// GetPostByID mocks base method.
-func (m *MockappService) GetPostByID(postID string) (model.Post, error) {
+// UpdateCommunity mocks base method.
+func (m *MockcommunitiesService) UpdateCommunity(name, description string, usr model.User) (model.Community, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPostByID", postID)
-	ret0, _ := ret[0].(model.Post)
+	ret := m.ctrl.Call(m, "UpdateCommunity", name, description, usr)
+	ret0, _ := ret[0].(model.Community)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetPostByID indicates an expected call of GetPostByID.
-func (mr *MockappServiceMockRecorder) GetPostByID(postID interface{}) *gomock.Call {
+// UpdateCommunity indicates an expected call of UpdateCommunity.
+func (mr *MockcommunitiesServiceMockRecorder) UpdateCommunity(name, description, usr interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostByID", reflect.TypeOf((*MockappService)(nil).GetPostByID), postID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCommunity", reflect.TypeOf((*MockcommunitiesService)(nil).UpdateCommunity), name, description, usr)
 }
 
-// GetPostsByAuthor mocks base method.
-func (m *MockappService) GetPostsByAuthor(username string) ([]model.Post, error) {
+// MockusersService is a mock of usersService interface.
+type MockusersService struct {
+	ctrl     *gomock.Controller
+	recorder *MockusersServiceMockRecorder
+}
+
+// MockusersServiceMockRecorder is the mock recorder for MockusersService.
+type MockusersServiceMockRecorder struct {
+	mock *MockusersService
+}
+
+// NewMockusersService creates a new mock instance.
+func NewMockusersService(ctrl *gomock.Controller) *MockusersService {
+	mock := &MockusersService{ctrl: ctrl}
+	mock.recorder = &MockusersServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockusersService) EXPECT() *MockusersServiceMockRecorder {
+	return m.recorder
+}
+
+// GetModLog mocks base method.
+func (m *MockusersService) GetModLog(query model.ModLogQuery) (model.ModLogPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPostsByAuthor", username)
-	ret0, _ := ret[0].([]model.Post)
+	ret := m.ctrl.Call(m, "GetModLog", query)
+	ret0, _ := ret[0].(model.ModLogPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetPostsByAuthor indicates an expected call of GetPostsByAuthor.
-func (mr *MockappServiceMockRecorder) GetPostsByAuthor(username interface{}) *gomock.Call {
+// GetModLog indicates an expected call of GetModLog.
+func (mr *MockusersServiceMockRecorder) GetModLog(query interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostsByAuthor", reflect.TypeOf((*MockappService)(nil).GetPostsByAuthor), username)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModLog", reflect.TypeOf((*MockusersService)(nil).GetModLog), query)
 }
 
-// GetPostsByCategory mocks base method.
-func (m *MockappService) GetPostsByCategory(category string) ([]model.Post, error) {
+// GetProfile mocks base method.
+func (m *MockusersService) GetProfile(username string) (model.Profile, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPostsByCategory", category)
-	ret0, _ := ret[0].([]model.Post)
+	ret := m.ctrl.Call(m, "GetProfile", username)
+	ret0, _ := ret[0].(model.Profile)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetPostsByCategory indicates an expected call of GetPostsByCategory.
-func (mr *MockappServiceMockRecorder) GetPostsByCategory(category interface{}) *gomock.Call {
+// GetProfile indicates an expected call of GetProfile.
+func (mr *MockusersServiceMockRecorder) GetProfile(username interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostsByCategory", reflect.TypeOf((*MockappService)(nil).GetPostsByCategory), category)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfile", reflect.TypeOf((*MockusersService)(nil).GetProfile), username)
 }
 
 // GetUserByID mocks base method.
-func (m *MockappService) GetUserByID(userID string) (model.User, error) {
+func (m *MockusersService) GetUserByID(userID string) (model.User, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetUserByID", userID)
 	ret0, _ := ret[0].(model.User)
@@ -486,67 +578,1436 @@ func (m *MockappService) GetUserByID(userID string) (model.User, error) {
 }
 
 // GetUserByID indicates an expected call of GetUserByID.
-func (mr *MockappServiceMockRecorder) GetUserByID(userID interface{}) *gomock.Call {
+func (mr *MockusersServiceMockRecorder) GetUserByID(userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockappService)(nil).GetUserByID), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockusersService)(nil).GetUserByID), userID)
 }
 
-// LoginUser mocks base method.
-func (m *MockappService) LoginUser(cred model.Credential) (model.User, error) {
+// SetUserRole mocks base method.
+func (m *MockusersService) SetUserRole(userID, role string, admin model.User) (model.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LoginUser", cred)
+	ret := m.ctrl.Call(m, "SetUserRole", userID, role, admin)
 	ret0, _ := ret[0].(model.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// LoginUser indicates an expected call of LoginUser.
-func (mr *MockappServiceMockRecorder) LoginUser(cred interface{}) *gomock.Call {
+// SetUserRole indicates an expected call of SetUserRole.
+func (mr *MockusersServiceMockRecorder) SetUserRole(userID, role, admin interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginUser", reflect.TypeOf((*MockappService)(nil).LoginUser), cred)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserRole", reflect.TypeOf((*MockusersService)(nil).SetUserRole), userID, role, admin)
 }
 
-// RegisterUser mocks base method.
-func (m *MockappService) RegisterUser(cred model.Credential) (model.User, error) {
+// SuspendUser mocks base method.
+func (m *MockusersService) SuspendUser(userID string, input model.BanInput, admin model.User) (model.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RegisterUser", cred)
+	ret := m.ctrl.Call(m, "SuspendUser", userID, input, admin)
 	ret0, _ := ret[0].(model.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// RegisterUser indicates an expected call of RegisterUser.
-func (mr *MockappServiceMockRecorder) RegisterUser(cred interface{}) *gomock.Call {
+// SuspendUser indicates an expected call of SuspendUser.
+func (mr *MockusersServiceMockRecorder) SuspendUser(userID, input, admin interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterUser", reflect.TypeOf((*MockappService)(nil).RegisterUser), cred)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuspendUser", reflect.TypeOf((*MockusersService)(nil).SuspendUser), userID, input, admin)
 }
 
-// UnvotePost mocks base method.
-func (m *MockappService) UnvotePost(postID string, usr model.User) (model.Post, error) {
+// UnlockUser mocks base method.
+func (m *MockusersService) UnlockUser(userID string, admin model.User) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UnvotePost", postID, usr)
-	ret0, _ := ret[0].(model.Post)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "UnlockUser", userID, admin)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// UnvotePost indicates an expected call of UnvotePost.
-func (mr *MockappServiceMockRecorder) UnvotePost(postID, usr interface{}) *gomock.Call {
+// UnlockUser indicates an expected call of UnlockUser.
+func (mr *MockusersServiceMockRecorder) UnlockUser(userID, admin interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnvotePost", reflect.TypeOf((*MockappService)(nil).UnvotePost), postID, usr)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockUser", reflect.TypeOf((*MockusersService)(nil).UnlockUser), userID, admin)
 }
 
-// UpvotePost mocks base method.
-func (m *MockappService) UpvotePost(postID string, usr model.User) (model.Post, error) {
+// UnsuspendUser mocks base method.
+func (m *MockusersService) UnsuspendUser(userID string, admin model.User) (model.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpvotePost", postID, usr)
-	ret0, _ := ret[0].(model.Post)
+	ret := m.ctrl.Call(m, "UnsuspendUser", userID, admin)
+	ret0, _ := ret[0].(model.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpvotePost indicates an expected call of UpvotePost.
-func (mr *MockappServiceMockRecorder) UpvotePost(postID, usr interface{}) *gomock.Call {
+// UnsuspendUser indicates an expected call of UnsuspendUser.
+func (mr *MockusersServiceMockRecorder) UnsuspendUser(userID, admin interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpvotePost", reflect.TypeOf((*MockappService)(nil).UpvotePost), postID, usr)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsuspendUser", reflect.TypeOf((*MockusersService)(nil).UnsuspendUser), userID, admin)
+}
+
+// MocksearchService is a mock of searchService interface.
+type MocksearchService struct {
+	ctrl     *gomock.Controller
+	recorder *MocksearchServiceMockRecorder
+}
+
+// MocksearchServiceMockRecorder is the mock recorder for MocksearchService.
+type MocksearchServiceMockRecorder struct {
+	mock *MocksearchService
+}
+
+// NewMocksearchService creates a new mock instance.
+func NewMocksearchService(ctrl *gomock.Controller) *MocksearchService {
+	mock := &MocksearchService{ctrl: ctrl}
+	mock.recorder = &MocksearchServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocksearchService) EXPECT() *MocksearchServiceMockRecorder {
+	return m.recorder
+}
+
+// Search mocks base method.
+func (m *MocksearchService) Search(query model.SearchQuery) (model.SearchPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", query)
+	ret0, _ := ret[0].(model.SearchPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MocksearchServiceMockRecorder) Search(query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MocksearchService)(nil).Search), query)
+}
+
+// MockeventsService is a mock of eventsService interface.
+type MockeventsService struct {
+	ctrl     *gomock.Controller
+	recorder *MockeventsServiceMockRecorder
+}
+
+// MockeventsServiceMockRecorder is the mock recorder for MockeventsService.
+type MockeventsServiceMockRecorder struct {
+	mock *MockeventsService
+}
+
+// NewMockeventsService creates a new mock instance.
+func NewMockeventsService(ctrl *gomock.Controller) *MockeventsService {
+	mock := &MockeventsService{ctrl: ctrl}
+	mock.recorder = &MockeventsServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockeventsService) EXPECT() *MockeventsServiceMockRecorder {
+	return m.recorder
+}
+
+// SubscribeCommunity mocks base method.
+func (m *MockeventsService) SubscribeCommunity(name string) (*broker.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeCommunity", name)
+	ret0, _ := ret[0].(*broker.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeCommunity indicates an expected call of SubscribeCommunity.
+func (mr *MockeventsServiceMockRecorder) SubscribeCommunity(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeCommunity", reflect.TypeOf((*MockeventsService)(nil).SubscribeCommunity), name)
+}
+
+// SubscribePost mocks base method.
+func (m *MockeventsService) SubscribePost(postID string) (*broker.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribePost", postID)
+	ret0, _ := ret[0].(*broker.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribePost indicates an expected call of SubscribePost.
+func (mr *MockeventsServiceMockRecorder) SubscribePost(postID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribePost", reflect.TypeOf((*MockeventsService)(nil).SubscribePost), postID)
+}
+
+// MocknotificationsService is a mock of notificationsService interface.
+type MocknotificationsService struct {
+	ctrl     *gomock.Controller
+	recorder *MocknotificationsServiceMockRecorder
+}
+
+// MocknotificationsServiceMockRecorder is the mock recorder for MocknotificationsService.
+type MocknotificationsServiceMockRecorder struct {
+	mock *MocknotificationsService
+}
+
+// NewMocknotificationsService creates a new mock instance.
+func NewMocknotificationsService(ctrl *gomock.Controller) *MocknotificationsService {
+	mock := &MocknotificationsService{ctrl: ctrl}
+	mock.recorder = &MocknotificationsServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocknotificationsService) EXPECT() *MocknotificationsServiceMockRecorder {
+	return m.recorder
+}
+
+// GetNotifications mocks base method.
+func (m *MocknotificationsService) GetNotifications(query model.NotificationsQuery, usr model.User) (model.NotificationsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotifications", query, usr)
+	ret0, _ := ret[0].(model.NotificationsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotifications indicates an expected call of GetNotifications.
+func (mr *MocknotificationsServiceMockRecorder) GetNotifications(query, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotifications", reflect.TypeOf((*MocknotificationsService)(nil).GetNotifications), query, usr)
+}
+
+// MarkAllNotificationsRead mocks base method.
+func (m *MocknotificationsService) MarkAllNotificationsRead(usr model.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAllNotificationsRead", usr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkAllNotificationsRead indicates an expected call of MarkAllNotificationsRead.
+func (mr *MocknotificationsServiceMockRecorder) MarkAllNotificationsRead(usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllNotificationsRead", reflect.TypeOf((*MocknotificationsService)(nil).MarkAllNotificationsRead), usr)
+}
+
+// MarkNotificationRead mocks base method.
+func (m *MocknotificationsService) MarkNotificationRead(notificationID string, usr model.User) (model.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationRead", notificationID, usr)
+	ret0, _ := ret[0].(model.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkNotificationRead indicates an expected call of MarkNotificationRead.
+func (mr *MocknotificationsServiceMockRecorder) MarkNotificationRead(notificationID, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationRead", reflect.TypeOf((*MocknotificationsService)(nil).MarkNotificationRead), notificationID, usr)
+}
+
+// MockmessagesService is a mock of messagesService interface.
+type MockmessagesService struct {
+	ctrl     *gomock.Controller
+	recorder *MockmessagesServiceMockRecorder
+}
+
+// MockmessagesServiceMockRecorder is the mock recorder for MockmessagesService.
+type MockmessagesServiceMockRecorder struct {
+	mock *MockmessagesService
+}
+
+// NewMockmessagesService creates a new mock instance.
+func NewMockmessagesService(ctrl *gomock.Controller) *MockmessagesService {
+	mock := &MockmessagesService{ctrl: ctrl}
+	mock.recorder = &MockmessagesServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockmessagesService) EXPECT() *MockmessagesServiceMockRecorder {
+	return m.recorder
+}
+
+// BlockUser mocks base method.
+func (m *MockmessagesService) BlockUser(username string, usr model.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockUser", username, usr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlockUser indicates an expected call of BlockUser.
+func (mr *MockmessagesServiceMockRecorder) BlockUser(username, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockUser", reflect.TypeOf((*MockmessagesService)(nil).BlockUser), username, usr)
+}
+
+// GetBlocks mocks base method.
+func (m *MockmessagesService) GetBlocks(usr model.User) ([]model.Block, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlocks", usr)
+	ret0, _ := ret[0].([]model.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlocks indicates an expected call of GetBlocks.
+func (mr *MockmessagesServiceMockRecorder) GetBlocks(usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlocks", reflect.TypeOf((*MockmessagesService)(nil).GetBlocks), usr)
+}
+
+// GetConversations mocks base method.
+func (m *MockmessagesService) GetConversations(usr model.User) ([]model.Conversation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConversations", usr)
+	ret0, _ := ret[0].([]model.Conversation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConversations indicates an expected call of GetConversations.
+func (mr *MockmessagesServiceMockRecorder) GetConversations(usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConversations", reflect.TypeOf((*MockmessagesService)(nil).GetConversations), usr)
+}
+
+// GetThread mocks base method.
+func (m *MockmessagesService) GetThread(username string, usr model.User) ([]model.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetThread", username, usr)
+	ret0, _ := ret[0].([]model.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetThread indicates an expected call of GetThread.
+func (mr *MockmessagesServiceMockRecorder) GetThread(username, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetThread", reflect.TypeOf((*MockmessagesService)(nil).GetThread), username, usr)
+}
+
+// MarkThreadRead mocks base method.
+func (m *MockmessagesService) MarkThreadRead(username string, usr model.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkThreadRead", username, usr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkThreadRead indicates an expected call of MarkThreadRead.
+func (mr *MockmessagesServiceMockRecorder) MarkThreadRead(username, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkThreadRead", reflect.TypeOf((*MockmessagesService)(nil).MarkThreadRead), username, usr)
+}
+
+// SendMessage mocks base method.
+func (m *MockmessagesService) SendMessage(username, body string, usr model.User) (model.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMessage", username, body, usr)
+	ret0, _ := ret[0].(model.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendMessage indicates an expected call of SendMessage.
+func (mr *MockmessagesServiceMockRecorder) SendMessage(username, body, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMessage", reflect.TypeOf((*MockmessagesService)(nil).SendMessage), username, body, usr)
+}
+
+// UnblockUser mocks base method.
+func (m *MockmessagesService) UnblockUser(username string, usr model.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnblockUser", username, usr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnblockUser indicates an expected call of UnblockUser.
+func (mr *MockmessagesServiceMockRecorder) UnblockUser(username, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnblockUser", reflect.TypeOf((*MockmessagesService)(nil).UnblockUser), username, usr)
+}
+
+// MockbansService is a mock of bansService interface.
+type MockbansService struct {
+	ctrl     *gomock.Controller
+	recorder *MockbansServiceMockRecorder
+}
+
+// MockbansServiceMockRecorder is the mock recorder for MockbansService.
+type MockbansServiceMockRecorder struct {
+	mock *MockbansService
+}
+
+// NewMockbansService creates a new mock instance.
+func NewMockbansService(ctrl *gomock.Controller) *MockbansService {
+	mock := &MockbansService{ctrl: ctrl}
+	mock.recorder = &MockbansServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockbansService) EXPECT() *MockbansServiceMockRecorder {
+	return m.recorder
+}
+
+// BanUser mocks base method.
+func (m *MockbansService) BanUser(community, username string, input model.BanInput, moderator model.User) (model.Ban, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BanUser", community, username, input, moderator)
+	ret0, _ := ret[0].(model.Ban)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BanUser indicates an expected call of BanUser.
+func (mr *MockbansServiceMockRecorder) BanUser(community, username, input, moderator interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BanUser", reflect.TypeOf((*MockbansService)(nil).BanUser), community, username, input, moderator)
+}
+
+// GetBans mocks base method.
+func (m *MockbansService) GetBans(community string) ([]model.Ban, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBans", community)
+	ret0, _ := ret[0].([]model.Ban)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBans indicates an expected call of GetBans.
+func (mr *MockbansServiceMockRecorder) GetBans(community interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBans", reflect.TypeOf((*MockbansService)(nil).GetBans), community)
+}
+
+// UnbanUser mocks base method.
+func (m *MockbansService) UnbanUser(community, username string, moderator model.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnbanUser", community, username, moderator)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnbanUser indicates an expected call of UnbanUser.
+func (mr *MockbansServiceMockRecorder) UnbanUser(community, username, moderator interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnbanUser", reflect.TypeOf((*MockbansService)(nil).UnbanUser), community, username, moderator)
+}
+
+// MockreportsService is a mock of reportsService interface.
+type MockreportsService struct {
+	ctrl     *gomock.Controller
+	recorder *MockreportsServiceMockRecorder
+}
+
+// MockreportsServiceMockRecorder is the mock recorder for MockreportsService.
+type MockreportsServiceMockRecorder struct {
+	mock *MockreportsService
+}
+
+// NewMockreportsService creates a new mock instance.
+func NewMockreportsService(ctrl *gomock.Controller) *MockreportsService {
+	mock := &MockreportsService{ctrl: ctrl}
+	mock.recorder = &MockreportsServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockreportsService) EXPECT() *MockreportsServiceMockRecorder {
+	return m.recorder
+}
+
+// GetReportQueue mocks base method.
+func (m *MockreportsService) GetReportQueue(query model.ReportsQuery) ([]model.ReportedItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportQueue", query)
+	ret0, _ := ret[0].([]model.ReportedItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReportQueue indicates an expected call of GetReportQueue.
+func (mr *MockreportsServiceMockRecorder) GetReportQueue(query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportQueue", reflect.TypeOf((*MockreportsService)(nil).GetReportQueue), query)
+}
+
+// ReportComment mocks base method.
+func (m *MockreportsService) ReportComment(postID, commentID, reason, text string, usr model.User) (model.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportComment", postID, commentID, reason, text, usr)
+	ret0, _ := ret[0].(model.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReportComment indicates an expected call of ReportComment.
+func (mr *MockreportsServiceMockRecorder) ReportComment(postID, commentID, reason, text, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportComment", reflect.TypeOf((*MockreportsService)(nil).ReportComment), postID, commentID, reason, text, usr)
+}
+
+// ReportPost mocks base method.
+func (m *MockreportsService) ReportPost(postID, reason, text string, usr model.User) (model.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportPost", postID, reason, text, usr)
+	ret0, _ := ret[0].(model.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReportPost indicates an expected call of ReportPost.
+func (mr *MockreportsServiceMockRecorder) ReportPost(postID, reason, text, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportPost", reflect.TypeOf((*MockreportsService)(nil).ReportPost), postID, reason, text, usr)
+}
+
+// ResolveReports mocks base method.
+func (m *MockreportsService) ResolveReports(postID, commentID, action, reason string, moderator model.User) (model.ReportResolution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveReports", postID, commentID, action, reason, moderator)
+	ret0, _ := ret[0].(model.ReportResolution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveReports indicates an expected call of ResolveReports.
+func (mr *MockreportsServiceMockRecorder) ResolveReports(postID, commentID, action, reason, moderator interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveReports", reflect.TypeOf((*MockreportsService)(nil).ResolveReports), postID, commentID, action, reason, moderator)
+}
+
+// MockappService is a mock of appService interface.
+type MockappService struct {
+	ctrl     *gomock.Controller
+	recorder *MockappServiceMockRecorder
+}
+
+// MockappServiceMockRecorder is the mock recorder for MockappService.
+type MockappServiceMockRecorder struct {
+	mock *MockappService
+}
+
+// NewMockappService creates a new mock instance.
+func NewMockappService(ctrl *gomock.Controller) *MockappService {
+	mock := &MockappService{ctrl: ctrl}
+	mock.recorder = &MockappServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockappService) EXPECT() *MockappServiceMockRecorder {
+	return m.recorder
+}
+
+// AddComment mocks base method.
+func (m *MockappService) AddComment(postID, commentText, parentID string, usr model.User) (model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddComment", postID, commentText, parentID, usr)
+	ret0, _ := ret[0].(model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddComment indicates an expected call of AddComment.
+func (mr *MockappServiceMockRecorder) AddComment(postID, commentText, parentID, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddComment", reflect.TypeOf((*MockappService)(nil).AddComment), postID, commentText, parentID, usr)
+}
+
+// BanUser mocks base method.
+func (m *MockappService) BanUser(community, username string, input model.BanInput, moderator model.User) (model.Ban, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BanUser", community, username, input, moderator)
+	ret0, _ := ret[0].(model.Ban)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BanUser indicates an expected call of BanUser.
+func (mr *MockappServiceMockRecorder) BanUser(community, username, input, moderator interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BanUser", reflect.TypeOf((*MockappService)(nil).BanUser), community, username, input, moderator)
+}
+
+// BlockUser mocks base method.
+func (m *MockappService) BlockUser(username string, usr model.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockUser", username, usr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlockUser indicates an expected call of BlockUser.
+func (mr *MockappServiceMockRecorder) BlockUser(username, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockUser", reflect.TypeOf((*MockappService)(nil).BlockUser), username, usr)
+}
+
+// CreateCommunity mocks base method.
+func (m *MockappService) CreateCommunity(input model.CommunityInput, usr model.User) (model.Community, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCommunity", input, usr)
+	ret0, _ := ret[0].(model.Community)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCommunity indicates an expected call of CreateCommunity.
+func (mr *MockappServiceMockRecorder) CreateCommunity(input, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCommunity", reflect.TypeOf((*MockappService)(nil).CreateCommunity), input, usr)
+}
+
+// CreateTextPost mocks base method.
+func (m *MockappService) CreateTextPost(input model.TextPostInput, usr model.User) (model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTextPost", input, usr)
+	ret0, _ := ret[0].(model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTextPost indicates an expected call of CreateTextPost.
+func (mr *MockappServiceMockRecorder) CreateTextPost(input, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTextPost", reflect.TypeOf((*MockappService)(nil).CreateTextPost), input, usr)
+}
+
+// CreateURLPost mocks base method.
+func (m *MockappService) CreateURLPost(input model.URLPostInput, usr model.User) (model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateURLPost", input, usr)
+	ret0, _ := ret[0].(model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateURLPost indicates an expected call of CreateURLPost.
+func (mr *MockappServiceMockRecorder) CreateURLPost(input, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateURLPost", reflect.TypeOf((*MockappService)(nil).CreateURLPost), input, usr)
+}
+
+// DeleteComment mocks base method.
+func (m *MockappService) DeleteComment(postID, commentID, reason string, usr model.User) (model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteComment", postID, commentID, reason, usr)
+	ret0, _ := ret[0].(model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteComment indicates an expected call of DeleteComment.
+func (mr *MockappServiceMockRecorder) DeleteComment(postID, commentID, reason, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteComment", reflect.TypeOf((*MockappService)(nil).DeleteComment), postID, commentID, reason, usr)
+}
+
+// DeleteCommunity mocks base method.
+func (m *MockappService) DeleteCommunity(name string, usr model.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCommunity", name, usr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCommunity indicates an expected call of DeleteCommunity.
+func (mr *MockappServiceMockRecorder) DeleteCommunity(name, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCommunity", reflect.TypeOf((*MockappService)(nil).DeleteCommunity), name, usr)
+}
+
+// DeletePost mocks base method.
+func (m *MockappService) DeletePost(postID, reason string, usr model.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePost", postID, reason, usr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePost indicates an expected call of DeletePost.
+func (mr *MockappServiceMockRecorder) DeletePost(postID, reason, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePost", reflect.TypeOf((*MockappService)(nil).DeletePost), postID, reason, usr)
+}
+
+// DownvoteComment mocks base method.
+func (m *MockappService) DownvoteComment(postID, commentID string, usr model.User) (model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownvoteComment", postID, commentID, usr)
+	ret0, _ := ret[0].(model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DownvoteComment indicates an expected call of DownvoteComment.
+func (mr *MockappServiceMockRecorder) DownvoteComment(postID, commentID, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownvoteComment", reflect.TypeOf((*MockappService)(nil).DownvoteComment), postID, commentID, usr)
+}
+
+// DownvotePost mocks base method.
+func (m *MockappService) DownvotePost(postID string, usr model.User) (model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownvotePost", postID, usr)
+	ret0, _ := ret[0].(model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DownvotePost indicates an expected call of DownvotePost.
+func (mr *MockappServiceMockRecorder) DownvotePost(postID, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownvotePost", reflect.TypeOf((*MockappService)(nil).DownvotePost), postID, usr)
+}
+
+// EditComment mocks base method.
+func (m *MockappService) EditComment(postID, commentID, commentText string, usr model.User) (model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditComment", postID, commentID, commentText, usr)
+	ret0, _ := ret[0].(model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EditComment indicates an expected call of EditComment.
+func (mr *MockappServiceMockRecorder) EditComment(postID, commentID, commentText, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditComment", reflect.TypeOf((*MockappService)(nil).EditComment), postID, commentID, commentText, usr)
+}
+
+// FillUserVotes mocks base method.
+func (m *MockappService) FillUserVotes(posts []model.Post, usr model.User) ([]model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FillUserVotes", posts, usr)
+	ret0, _ := ret[0].([]model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FillUserVotes indicates an expected call of FillUserVotes.
+func (mr *MockappServiceMockRecorder) FillUserVotes(posts, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FillUserVotes", reflect.TypeOf((*MockappService)(nil).FillUserVotes), posts, usr)
+}
+
+// GetAllPosts mocks base method.
+func (m *MockappService) GetAllPosts() ([]model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllPosts")
+	ret0, _ := ret[0].([]model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllPosts indicates an expected call of GetAllPosts.
+func (mr *MockappServiceMockRecorder) GetAllPosts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllPosts", reflect.TypeOf((*MockappService)(nil).GetAllPosts))
+}
+
+// GetBans mocks base method.
+func (m *MockappService) GetBans(community string) ([]model.Ban, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBans", community)
+	ret0, _ := ret[0].([]model.Ban)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBans indicates an expected call of GetBans.
+func (mr *MockappServiceMockRecorder) GetBans(community interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBans", reflect.TypeOf((*MockappService)(nil).GetBans), community)
+}
+
+// GetBlocks mocks base method.
+func (m *MockappService) GetBlocks(usr model.User) ([]model.Block, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlocks", usr)
+	ret0, _ := ret[0].([]model.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlocks indicates an expected call of GetBlocks.
+func (mr *MockappServiceMockRecorder) GetBlocks(usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlocks", reflect.TypeOf((*MockappService)(nil).GetBlocks), usr)
+}
+
+// GetCommunities mocks base method.
+func (m *MockappService) GetCommunities() ([]model.Community, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommunities")
+	ret0, _ := ret[0].([]model.Community)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCommunities indicates an expected call of GetCommunities.
+func (mr *MockappServiceMockRecorder) GetCommunities() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommunities", reflect.TypeOf((*MockappService)(nil).GetCommunities))
+}
+
+// GetCommunity mocks base method.
+func (m *MockappService) GetCommunity(name string) (model.Community, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommunity", name)
+	ret0, _ := ret[0].(model.Community)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCommunity indicates an expected call of GetCommunity.
+func (mr *MockappServiceMockRecorder) GetCommunity(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommunity", reflect.TypeOf((*MockappService)(nil).GetCommunity), name)
+}
+
+// GetConversations mocks base method.
+func (m *MockappService) GetConversations(usr model.User) ([]model.Conversation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConversations", usr)
+	ret0, _ := ret[0].([]model.Conversation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConversations indicates an expected call of GetConversations.
+func (mr *MockappServiceMockRecorder) GetConversations(usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConversations", reflect.TypeOf((*MockappService)(nil).GetConversations), usr)
+}
+
+// GetModLog mocks base method.
+func (m *MockappService) GetModLog(query model.ModLogQuery) (model.ModLogPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetModLog", query)
+	ret0, _ := ret[0].(model.ModLogPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetModLog indicates an expected call of GetModLog.
+func (mr *MockappServiceMockRecorder) GetModLog(query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModLog", reflect.TypeOf((*MockappService)(nil).GetModLog), query)
+}
+
+// GetNotifications mocks base method.
+func (m *MockappService) GetNotifications(query model.NotificationsQuery, usr model.User) (model.NotificationsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotifications", query, usr)
+	ret0, _ := ret[0].(model.NotificationsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotifications indicates an expected call of GetNotifications.
+func (mr *MockappServiceMockRecorder) GetNotifications(query, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotifications", reflect.TypeOf((*MockappService)(nil).GetNotifications), query, usr)
+}
+
+// GetPostByID mocks base method.
+func (m *MockappService) GetPostByID(postID string) (model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostByID", postID)
+	ret0, _ := ret[0].(model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostByID indicates an expected call of GetPostByID.
+func (mr *MockappServiceMockRecorder) GetPostByID(postID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostByID", reflect.TypeOf((*MockappService)(nil).GetPostByID), postID)
+}
+
+// GetPostRevisions mocks base method.
+func (m *MockappService) GetPostRevisions(postID string) ([]model.PostRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostRevisions", postID)
+	ret0, _ := ret[0].([]model.PostRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostRevisions indicates an expected call of GetPostRevisions.
+func (mr *MockappServiceMockRecorder) GetPostRevisions(postID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostRevisions", reflect.TypeOf((*MockappService)(nil).GetPostRevisions), postID)
+}
+
+// GetPosts mocks base method.
+func (m *MockappService) GetPosts(query model.PostsQuery) (model.PostsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPosts", query)
+	ret0, _ := ret[0].(model.PostsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPosts indicates an expected call of GetPosts.
+func (mr *MockappServiceMockRecorder) GetPosts(query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPosts", reflect.TypeOf((*MockappService)(nil).GetPosts), query)
+}
+
+// GetPostsByAuthor mocks base method.
+func (m *MockappService) GetPostsByAuthor(username string) ([]model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostsByAuthor", username)
+	ret0, _ := ret[0].([]model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostsByAuthor indicates an expected call of GetPostsByAuthor.
+func (mr *MockappServiceMockRecorder) GetPostsByAuthor(username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostsByAuthor", reflect.TypeOf((*MockappService)(nil).GetPostsByAuthor), username)
+}
+
+// GetPostsByCategory mocks base method.
+func (m *MockappService) GetPostsByCategory(category string) ([]model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPostsByCategory", category)
+	ret0, _ := ret[0].([]model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPostsByCategory indicates an expected call of GetPostsByCategory.
+func (mr *MockappServiceMockRecorder) GetPostsByCategory(category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPostsByCategory", reflect.TypeOf((*MockappService)(nil).GetPostsByCategory), category)
+}
+
+// GetProfile mocks base method.
+func (m *MockappService) GetProfile(username string) (model.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProfile", username)
+	ret0, _ := ret[0].(model.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProfile indicates an expected call of GetProfile.
+func (mr *MockappServiceMockRecorder) GetProfile(username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfile", reflect.TypeOf((*MockappService)(nil).GetProfile), username)
+}
+
+// GetReportQueue mocks base method.
+func (m *MockappService) GetReportQueue(query model.ReportsQuery) ([]model.ReportedItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportQueue", query)
+	ret0, _ := ret[0].([]model.ReportedItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReportQueue indicates an expected call of GetReportQueue.
+func (mr *MockappServiceMockRecorder) GetReportQueue(query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportQueue", reflect.TypeOf((*MockappService)(nil).GetReportQueue), query)
+}
+
+// GetThread mocks base method.
+func (m *MockappService) GetThread(username string, usr model.User) ([]model.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetThread", username, usr)
+	ret0, _ := ret[0].([]model.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetThread indicates an expected call of GetThread.
+func (mr *MockappServiceMockRecorder) GetThread(username, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetThread", reflect.TypeOf((*MockappService)(nil).GetThread), username, usr)
+}
+
+// GetUserByID mocks base method.
+func (m *MockappService) GetUserByID(userID string) (model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByID", userID)
+	ret0, _ := ret[0].(model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockappServiceMockRecorder) GetUserByID(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockappService)(nil).GetUserByID), userID)
+}
+
+// GetVotesByUser mocks base method.
+func (m *MockappService) GetVotesByUser(usr model.User) ([]model.Vote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVotesByUser", usr)
+	ret0, _ := ret[0].([]model.Vote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVotesByUser indicates an expected call of GetVotesByUser.
+func (mr *MockappServiceMockRecorder) GetVotesByUser(usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVotesByUser", reflect.TypeOf((*MockappService)(nil).GetVotesByUser), usr)
+}
+
+// ListPosts mocks base method.
+func (m *MockappService) ListPosts(query model.PostsQuery) ([]model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPosts", query)
+	ret0, _ := ret[0].([]model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPosts indicates an expected call of ListPosts.
+func (mr *MockappServiceMockRecorder) ListPosts(query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPosts", reflect.TypeOf((*MockappService)(nil).ListPosts), query)
+}
+
+// LoginUser mocks base method.
+func (m *MockappService) LoginUser(cred model.Credential, ip string) (model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginUser", cred, ip)
+	ret0, _ := ret[0].(model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoginUser indicates an expected call of LoginUser.
+func (mr *MockappServiceMockRecorder) LoginUser(cred, ip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginUser", reflect.TypeOf((*MockappService)(nil).LoginUser), cred, ip)
+}
+
+// MarkAllNotificationsRead mocks base method.
+func (m *MockappService) MarkAllNotificationsRead(usr model.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAllNotificationsRead", usr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkAllNotificationsRead indicates an expected call of MarkAllNotificationsRead.
+func (mr *MockappServiceMockRecorder) MarkAllNotificationsRead(usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllNotificationsRead", reflect.TypeOf((*MockappService)(nil).MarkAllNotificationsRead), usr)
+}
+
+// MarkNotificationRead mocks base method.
+func (m *MockappService) MarkNotificationRead(notificationID string, usr model.User) (model.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationRead", notificationID, usr)
+	ret0, _ := ret[0].(model.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkNotificationRead indicates an expected call of MarkNotificationRead.
+func (mr *MockappServiceMockRecorder) MarkNotificationRead(notificationID, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationRead", reflect.TypeOf((*MockappService)(nil).MarkNotificationRead), notificationID, usr)
+}
+
+// MarkThreadRead mocks base method.
+func (m *MockappService) MarkThreadRead(username string, usr model.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkThreadRead", username, usr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkThreadRead indicates an expected call of MarkThreadRead.
+func (mr *MockappServiceMockRecorder) MarkThreadRead(username, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkThreadRead", reflect.TypeOf((*MockappService)(nil).MarkThreadRead), username, usr)
+}
+
+// RegisterUser mocks base method.
+func (m *MockappService) RegisterUser(cred model.Credential) (model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterUser", cred)
+	ret0, _ := ret[0].(model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterUser indicates an expected call of RegisterUser.
+func (mr *MockappServiceMockRecorder) RegisterUser(cred interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterUser", reflect.TypeOf((*MockappService)(nil).RegisterUser), cred)
+}
+
+// ReportComment mocks base method.
+func (m *MockappService) ReportComment(postID, commentID, reason, text string, usr model.User) (model.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportComment", postID, commentID, reason, text, usr)
+	ret0, _ := ret[0].(model.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReportComment indicates an expected call of ReportComment.
+func (mr *MockappServiceMockRecorder) ReportComment(postID, commentID, reason, text, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportComment", reflect.TypeOf((*MockappService)(nil).ReportComment), postID, commentID, reason, text, usr)
+}
+
+// ReportPost mocks base method.
+func (m *MockappService) ReportPost(postID, reason, text string, usr model.User) (model.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportPost", postID, reason, text, usr)
+	ret0, _ := ret[0].(model.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReportPost indicates an expected call of ReportPost.
+func (mr *MockappServiceMockRecorder) ReportPost(postID, reason, text, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportPost", reflect.TypeOf((*MockappService)(nil).ReportPost), postID, reason, text, usr)
+}
+
+// ResolveReports mocks base method.
+func (m *MockappService) ResolveReports(postID, commentID, action, reason string, moderator model.User) (model.ReportResolution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveReports", postID, commentID, action, reason, moderator)
+	ret0, _ := ret[0].(model.ReportResolution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveReports indicates an expected call of ResolveReports.
+func (mr *MockappServiceMockRecorder) ResolveReports(postID, commentID, action, reason, moderator interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveReports", reflect.TypeOf((*MockappService)(nil).ResolveReports), postID, commentID, action, reason, moderator)
+}
+
+// Search mocks base method.
+func (m *MockappService) Search(query model.SearchQuery) (model.SearchPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", query)
+	ret0, _ := ret[0].(model.SearchPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockappServiceMockRecorder) Search(query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockappService)(nil).Search), query)
+}
+
+// SendMessage mocks base method.
+func (m *MockappService) SendMessage(username, body string, usr model.User) (model.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMessage", username, body, usr)
+	ret0, _ := ret[0].(model.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendMessage indicates an expected call of SendMessage.
+func (mr *MockappServiceMockRecorder) SendMessage(username, body, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMessage", reflect.TypeOf((*MockappService)(nil).SendMessage), username, body, usr)
+}
+
+// SetUserRole mocks base method.
+func (m *MockappService) SetUserRole(userID, role string, admin model.User) (model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserRole", userID, role, admin)
+	ret0, _ := ret[0].(model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetUserRole indicates an expected call of SetUserRole.
+func (mr *MockappServiceMockRecorder) SetUserRole(userID, role, admin interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserRole", reflect.TypeOf((*MockappService)(nil).SetUserRole), userID, role, admin)
+}
+
+// SubscribeCommunity mocks base method.
+func (m *MockappService) SubscribeCommunity(name string) (*broker.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeCommunity", name)
+	ret0, _ := ret[0].(*broker.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeCommunity indicates an expected call of SubscribeCommunity.
+func (mr *MockappServiceMockRecorder) SubscribeCommunity(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeCommunity", reflect.TypeOf((*MockappService)(nil).SubscribeCommunity), name)
+}
+
+// SubscribePost mocks base method.
+func (m *MockappService) SubscribePost(postID string) (*broker.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribePost", postID)
+	ret0, _ := ret[0].(*broker.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribePost indicates an expected call of SubscribePost.
+func (mr *MockappServiceMockRecorder) SubscribePost(postID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribePost", reflect.TypeOf((*MockappService)(nil).SubscribePost), postID)
+}
+
+// SuspendUser mocks base method.
+func (m *MockappService) SuspendUser(userID string, input model.BanInput, admin model.User) (model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SuspendUser", userID, input, admin)
+	ret0, _ := ret[0].(model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SuspendUser indicates an expected call of SuspendUser.
+func (mr *MockappServiceMockRecorder) SuspendUser(userID, input, admin interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuspendUser", reflect.TypeOf((*MockappService)(nil).SuspendUser), userID, input, admin)
+}
+
+// UnbanUser mocks base method.
+func (m *MockappService) UnbanUser(community, username string, moderator model.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnbanUser", community, username, moderator)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnbanUser indicates an expected call of UnbanUser.
+func (mr *MockappServiceMockRecorder) UnbanUser(community, username, moderator interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnbanUser", reflect.TypeOf((*MockappService)(nil).UnbanUser), community, username, moderator)
+}
+
+// UnblockUser mocks base method.
+func (m *MockappService) UnblockUser(username string, usr model.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnblockUser", username, usr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnblockUser indicates an expected call of UnblockUser.
+func (mr *MockappServiceMockRecorder) UnblockUser(username, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnblockUser", reflect.TypeOf((*MockappService)(nil).UnblockUser), username, usr)
+}
+
+// UnlockUser mocks base method.
+func (m *MockappService) UnlockUser(userID string, admin model.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockUser", userID, admin)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnlockUser indicates an expected call of UnlockUser.
+func (mr *MockappServiceMockRecorder) UnlockUser(userID, admin interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockUser", reflect.TypeOf((*MockappService)(nil).UnlockUser), userID, admin)
+}
+
+// UnsuspendUser mocks base method.
+func (m *MockappService) UnsuspendUser(userID string, admin model.User) (model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnsuspendUser", userID, admin)
+	ret0, _ := ret[0].(model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnsuspendUser indicates an expected call of UnsuspendUser.
+func (mr *MockappServiceMockRecorder) UnsuspendUser(userID, admin interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsuspendUser", reflect.TypeOf((*MockappService)(nil).UnsuspendUser), userID, admin)
+}
+
+// UnvoteComment mocks base method.
+func (m *MockappService) UnvoteComment(postID, commentID string, usr model.User) (model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnvoteComment", postID, commentID, usr)
+	ret0, _ := ret[0].(model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnvoteComment indicates an expected call of UnvoteComment.
+func (mr *MockappServiceMockRecorder) UnvoteComment(postID, commentID, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnvoteComment", reflect.TypeOf((*MockappService)(nil).UnvoteComment), postID, commentID, usr)
+}
+
+// UnvotePost mocks base method.
+func (m *MockappService) UnvotePost(postID string, usr model.User) (model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnvotePost", postID, usr)
+	ret0, _ := ret[0].(model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnvotePost indicates an expected call of UnvotePost.
+func (mr *MockappServiceMockRecorder) UnvotePost(postID, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnvotePost", reflect.TypeOf((*MockappService)(nil).UnvotePost), postID, usr)
+}
+
+// UpdateCommunity mocks base method.
+func (m *MockappService) UpdateCommunity(name, description string, usr model.User) (model.Community, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCommunity", name, description, usr)
+	ret0, _ := ret[0].(model.Community)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCommunity indicates an expected call of UpdateCommunity.
+func (mr *MockappServiceMockRecorder) UpdateCommunity(name, description, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCommunity", reflect.TypeOf((*MockappService)(nil).UpdateCommunity), name, description, usr)
+}
+
+// UpdatePost mocks base method.
+func (m *MockappService) UpdatePost(postID string, input model.PostUpdateInput, usr model.User) (model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePost", postID, input, usr)
+	ret0, _ := ret[0].(model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePost indicates an expected call of UpdatePost.
+func (mr *MockappServiceMockRecorder) UpdatePost(postID, input, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePost", reflect.TypeOf((*MockappService)(nil).UpdatePost), postID, input, usr)
+}
+
+// UpvoteComment mocks base method.
+func (m *MockappService) UpvoteComment(postID, commentID string, usr model.User) (model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpvoteComment", postID, commentID, usr)
+	ret0, _ := ret[0].(model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpvoteComment indicates an expected call of UpvoteComment.
+func (mr *MockappServiceMockRecorder) UpvoteComment(postID, commentID, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpvoteComment", reflect.TypeOf((*MockappService)(nil).UpvoteComment), postID, commentID, usr)
+}
+
+// UpvotePost mocks base method.
+func (m *MockappService) UpvotePost(postID string, usr model.User) (model.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpvotePost", postID, usr)
+	ret0, _ := ret[0].(model.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpvotePost indicates an expected call of UpvotePost.
+func (mr *MockappServiceMockRecorder) UpvotePost(postID, usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpvotePost", reflect.TypeOf((*MockappService)(nil).UpvotePost), postID, usr)
+}
+
+// MocktokenRefresher is a mock of tokenRefresher interface.
+type MocktokenRefresher struct {
+	ctrl     *gomock.Controller
+	recorder *MocktokenRefresherMockRecorder
+}
+
+// MocktokenRefresherMockRecorder is the mock recorder for MocktokenRefresher.
+type MocktokenRefresherMockRecorder struct {
+	mock *MocktokenRefresher
+}
+
+// NewMocktokenRefresher creates a new mock instance.
+func NewMocktokenRefresher(ctrl *gomock.Controller) *MocktokenRefresher {
+	mock := &MocktokenRefresher{ctrl: ctrl}
+	mock.recorder = &MocktokenRefresherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocktokenRefresher) EXPECT() *MocktokenRefresherMockRecorder {
+	return m.recorder
+}
+
+// Issue mocks base method.
+func (m *MocktokenRefresher) Issue(usr token.AuthUser) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Issue", usr)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Issue indicates an expected call of Issue.
+func (mr *MocktokenRefresherMockRecorder) Issue(usr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Issue", reflect.TypeOf((*MocktokenRefresher)(nil).Issue), usr)
+}
+
+// Revoke mocks base method.
+func (m *MocktokenRefresher) Revoke(refreshToken string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", refreshToken)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MocktokenRefresherMockRecorder) Revoke(refreshToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MocktokenRefresher)(nil).Revoke), refreshToken)
+}
+
+// RevokeUser mocks base method.
+func (m *MocktokenRefresher) RevokeUser(userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUser", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUser indicates an expected call of RevokeUser.
+func (mr *MocktokenRefresherMockRecorder) RevokeUser(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUser", reflect.TypeOf((*MocktokenRefresher)(nil).RevokeUser), userID)
+}
+
+// Rotate mocks base method.
+func (m *MocktokenRefresher) Rotate(refreshToken string) (token.AuthUser, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rotate", refreshToken)
+	ret0, _ := ret[0].(token.AuthUser)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Rotate indicates an expected call of Rotate.
+func (mr *MocktokenRefresherMockRecorder) Rotate(refreshToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rotate", reflect.TypeOf((*MocktokenRefresher)(nil).Rotate), refreshToken)
 }
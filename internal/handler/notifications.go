@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"net/http"
+	"redditclone/internal/model"
+	"strconv"
+)
+
+func (h *Handler) getNotifications(w http.ResponseWriter, r *http.Request) {
+	usr := r.Context().Value("user").(model.User)
+	values := r.URL.Query()
+
+	errs := h.validator.ValidateQueryValue("unread", values.Get("unread"))
+	errs = append(errs, h.validator.ValidateQueryValue("limit", values.Get("limit"))...)
+	errs = append(errs, h.validator.ValidateQueryValueAs("notification_cursor", "cursor", values.Get("cursor"))...)
+	if len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	query := model.NotificationsQuery{Cursor: values.Get("cursor")}
+	query.UnreadOnly, _ = strconv.ParseBool(values.Get("unread"))
+	query.Limit, _ = strconv.Atoi(values.Get("limit"))
+
+	page, err := h.service.GetNotifications(query, usr)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp, err := json.Marshal(page)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if _, err = w.Write(resp); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) markNotificationRead(w http.ResponseWriter, r *http.Request) {
+	usr := r.Context().Value("user").(model.User)
+	vars := mux.Vars(r)
+	notificationID := vars["notification_id"]
+
+	if errs := h.validator.ValidatePathValue("notification_id", notificationID); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	notification, err := h.service.MarkNotificationRead(notificationID, usr)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp, err := json.Marshal(notification)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if _, err = w.Write(resp); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) markAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	usr := r.Context().Value("user").(model.User)
+
+	if err := h.service.MarkAllNotificationsRead(usr); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp := []byte(fmt.Sprintf("{\"message\": \"success\"}"))
+	if _, err := w.Write(resp); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
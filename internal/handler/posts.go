@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"redditclone/internal/model"
 	"redditclone/internal/model/customerr"
+	"redditclone/pkg/httpvalidator"
+	"strconv"
 )
 
 func writePosts(w http.ResponseWriter, posts []model.Post) error {
@@ -35,13 +37,119 @@ func writePost(w http.ResponseWriter, p model.Post) error {
 	return nil
 }
 
+func isPageRequested(r *http.Request) bool {
+	query := r.URL.Query()
+	return query.Has("limit") || query.Has("cursor")
+}
+
+// isQueryRequested keeps the unsorted listings for the clients which don't ask for an order or a page
+func isQueryRequested(r *http.Request) bool {
+	query := r.URL.Query()
+	return isPageRequested(r) || query.Has("sort") || query.Has("t")
+}
+
+// viewPosts shows an identified caller their own votes, anonymous callers get only the counts,
+// the removed content is shown to the moderators only
+func (h *Handler) viewPosts(r *http.Request, posts []model.Post) ([]model.Post, error) {
+	usr, ok := r.Context().Value("user").(model.User)
+	if !ok || !usr.IsModerator() {
+		posts = model.RedactPosts(posts)
+	}
+	if !ok {
+		return posts, nil
+	}
+	return h.service.FillUserVotes(posts, usr)
+}
+
+// getPostsByQuery writes a page envelope when a page is requested and a plain array otherwise
+func (h *Handler) getPostsByQuery(w http.ResponseWriter, r *http.Request, query model.PostsQuery) {
+	values := r.URL.Query()
+	errs := make([]httpvalidator.ValidationError, 0)
+	for _, param := range []string{"sort", "t", "limit", "cursor"} {
+		errs = append(errs, h.validator.ValidateQueryValue(param, values.Get(param))...)
+	}
+	if len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	query.Sort = values.Get("sort")
+	query.Window = values.Get("t")
+
+	var (
+		resp []byte
+		err  error
+	)
+	if isPageRequested(r) {
+		query.Limit, _ = strconv.Atoi(values.Get("limit"))
+		query.Cursor = values.Get("cursor")
+
+		var page model.PostsPage
+		if page, err = h.service.GetPosts(query); err != nil {
+			h.handleError(w, err)
+			return
+		}
+		if page.Posts, err = h.viewPosts(r, page.Posts); err != nil {
+			h.handleError(w, err)
+			return
+		}
+		resp, err = json.Marshal(page)
+	} else {
+		var posts []model.Post
+		if posts, err = h.service.ListPosts(query); err != nil {
+			h.handleError(w, err)
+			return
+		}
+		if posts, err = h.viewPosts(r, posts); err != nil {
+			h.handleError(w, err)
+			return
+		}
+		resp, err = json.Marshal(posts)
+	}
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if _, err = w.Write(resp); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
+
+const (
+	commentsViewFlat = "flat"
+	commentsViewTree = "tree"
+)
+
+// applyCommentsView nests the comments for the tree view, the flat view references parents by ID
+func applyCommentsView(p model.Post, view string) model.Post {
+	if view == commentsViewTree {
+		p.Comments = model.BuildCommentTree(p.Comments)
+	}
+	return p
+}
+
 func (h *Handler) getAllPosts(w http.ResponseWriter, r *http.Request) {
+	if isQueryRequested(r) {
+		h.getPostsByQuery(w, r, model.PostsQuery{})
+		return
+	}
+
 	posts, err := h.service.GetAllPosts()
 	if err != nil {
 		h.handleError(w, err)
 		return
 	}
 
+	if posts, err = h.viewPosts(r, posts); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
 	if err = writePosts(w, posts); err != nil {
 		h.handleError(w, err)
 		return
@@ -60,12 +168,22 @@ func (h *Handler) getPostsByCategory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if isQueryRequested(r) {
+		h.getPostsByQuery(w, r, model.PostsQuery{Category: category})
+		return
+	}
+
 	posts, err := h.service.GetPostsByCategory(category)
 	if err != nil {
 		h.handleError(w, err)
 		return
 	}
 
+	if posts, err = h.viewPosts(r, posts); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
 	if err = writePosts(w, posts); err != nil {
 		h.handleError(w, err)
 		return
@@ -84,12 +202,22 @@ func (h *Handler) getPostsByUsername(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if isQueryRequested(r) {
+		h.getPostsByQuery(w, r, model.PostsQuery{Author: username})
+		return
+	}
+
 	posts, err := h.service.GetPostsByAuthor(username)
 	if err != nil {
 		h.handleError(w, err)
 		return
 	}
 
+	if posts, err = h.viewPosts(r, posts); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
 	if err = writePosts(w, posts); err != nil {
 		h.handleError(w, err)
 		return
@@ -161,7 +289,11 @@ func (h *Handler) getPost(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	postID := vars["post_id"]
 
-	if errs := h.validator.ValidatePathValue("post_id", postID); len(errs) != 0 {
+	view := r.URL.Query().Get("view")
+
+	postIDValidationErrs := h.validator.ValidatePathValue("post_id", postID)
+	viewValidationErrs := h.validator.ValidateQueryValue("view", view)
+	if errs := append(postIDValidationErrs, viewValidationErrs...); len(errs) != 0 {
 		h.handleValidationErrors(w, errs)
 		return
 	}
@@ -172,7 +304,13 @@ func (h *Handler) getPost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err = writePost(w, existedPost); err != nil {
+	posts, err := h.viewPosts(r, []model.Post{existedPost})
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if err = writePost(w, applyCommentsView(posts[0], view)); err != nil {
 		h.handleError(w, err)
 		return
 	}
@@ -181,7 +319,20 @@ func (h *Handler) getPost(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
-func (h *Handler) deletePost(w http.ResponseWriter, r *http.Request) {
+func writeRevisions(w http.ResponseWriter, revisions []model.PostRevision) error {
+	resp, err := json.Marshal(revisions)
+	if err != nil {
+		return err
+	}
+
+	if _, err = w.Write(resp); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (h *Handler) updatePost(w http.ResponseWriter, r *http.Request) {
 	usr := r.Context().Value("user").(model.User)
 
 	vars := mux.Vars(r)
@@ -192,7 +343,80 @@ func (h *Handler) deletePost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.DeletePost(postID, usr); err != nil {
+	var input map[string]string
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		h.handleError(w, customerr.RequestNotParsed{Message: err.Error()})
+		return
+	}
+
+	if input["title"] == "" && input["text"] == "" {
+		h.handleValidationErrors(w, []httpvalidator.ValidationError{{
+			Location: "body",
+			Param:    "text",
+			Value:    input["text"],
+			Message:  "title or text must be a non-empty string",
+		}})
+		return
+	}
+
+	post, err := h.service.UpdatePost(postID, model.PostUpdateInput{
+		Title: input["title"],
+		Text:  input["text"],
+	}, usr)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if err = writePost(w, post); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) getPostRevisions(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	postID := vars["post_id"]
+
+	if errs := h.validator.ValidatePathValue("post_id", postID); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	revisions, err := h.service.GetPostRevisions(postID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if err = writeRevisions(w, revisions); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) deletePost(w http.ResponseWriter, r *http.Request) {
+	usr := r.Context().Value("user").(model.User)
+
+	vars := mux.Vars(r)
+	postID := vars["post_id"]
+
+	reason := r.URL.Query().Get("reason")
+
+	errs := h.validator.ValidatePathValue("post_id", postID)
+	if errs = append(errs, h.validator.ValidateQueryValue("reason", reason)...); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	if err := h.service.DeletePost(postID, reason, usr); err != nil {
 		h.handleError(w, err)
 		return
 	}
@@ -213,7 +437,11 @@ func (h *Handler) createComment(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	postID := vars["post_id"]
 
-	if errs := h.validator.ValidatePathValue("post_id", postID); len(errs) != 0 {
+	view := r.URL.Query().Get("view")
+
+	postIDValidationErrs := h.validator.ValidatePathValue("post_id", postID)
+	viewValidationErrs := h.validator.ValidateQueryValue("view", view)
+	if errs := append(postIDValidationErrs, viewValidationErrs...); len(errs) != 0 {
 		h.handleValidationErrors(w, errs)
 		return
 	}
@@ -231,14 +459,15 @@ func (h *Handler) createComment(w http.ResponseWriter, r *http.Request) {
 	}
 
 	comment := input["comment"]
+	parentID := input["parent_id"]
 
-	existedPost, err := h.service.AddComment(postID, comment, usr)
+	existedPost, err := h.service.AddComment(postID, comment, parentID, usr)
 	if err != nil {
 		h.handleError(w, err)
 		return
 	}
 
-	if err = writePost(w, existedPost); err != nil {
+	if err = writePost(w, applyCommentsView(existedPost, view)); err != nil {
 		h.handleError(w, err)
 		return
 	}
@@ -254,20 +483,26 @@ func (h *Handler) deleteComment(w http.ResponseWriter, r *http.Request) {
 	postID := vars["post_id"]
 	commentID := vars["comment_id"]
 
+	view := r.URL.Query().Get("view")
+	reason := r.URL.Query().Get("reason")
+
 	postIDValidationErrs := h.validator.ValidatePathValue("post_id", postID)
 	commentIDValidationErrs := h.validator.ValidatePathValue("comment_id", commentID)
-	if errs := append(postIDValidationErrs, commentIDValidationErrs...); len(errs) != 0 {
+	viewValidationErrs := h.validator.ValidateQueryValue("view", view)
+	errs := append(postIDValidationErrs, commentIDValidationErrs...)
+	errs = append(errs, viewValidationErrs...)
+	if errs = append(errs, h.validator.ValidateQueryValue("reason", reason)...); len(errs) != 0 {
 		h.handleValidationErrors(w, errs)
 		return
 	}
 
-	existedPost, err := h.service.DeleteComment(postID, commentID, usr)
+	existedPost, err := h.service.DeleteComment(postID, commentID, reason, usr)
 	if err != nil {
 		h.handleError(w, err)
 		return
 	}
 
-	if err = writePost(w, existedPost); err != nil {
+	if err = writePost(w, applyCommentsView(existedPost, view)); err != nil {
 		h.handleError(w, err)
 		return
 	}
@@ -353,3 +588,171 @@ func (h *Handler) unvotePost(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 }
+
+func (h *Handler) editComment(w http.ResponseWriter, r *http.Request) {
+	usr := r.Context().Value("user").(model.User)
+
+	vars := mux.Vars(r)
+	postID := vars["post_id"]
+	commentID := vars["comment_id"]
+
+	view := r.URL.Query().Get("view")
+
+	postIDValidationErrs := h.validator.ValidatePathValue("post_id", postID)
+	commentIDValidationErrs := h.validator.ValidatePathValue("comment_id", commentID)
+	viewValidationErrs := h.validator.ValidateQueryValue("view", view)
+	errs := append(postIDValidationErrs, commentIDValidationErrs...)
+	if errs = append(errs, viewValidationErrs...); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	var input map[string]string
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		h.handleError(w, customerr.RequestNotParsed{Message: err.Error()})
+		return
+	}
+
+	if errs := h.validator.ValidateBody("CommentUpdate", input); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	existedPost, err := h.service.EditComment(postID, commentID, input["comment"], usr)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if err = writePost(w, applyCommentsView(existedPost, view)); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) upvoteComment(w http.ResponseWriter, r *http.Request) {
+	usr := r.Context().Value("user").(model.User)
+
+	vars := mux.Vars(r)
+	postID := vars["post_id"]
+	commentID := vars["comment_id"]
+
+	view := r.URL.Query().Get("view")
+
+	postIDValidationErrs := h.validator.ValidatePathValue("post_id", postID)
+	commentIDValidationErrs := h.validator.ValidatePathValue("comment_id", commentID)
+	viewValidationErrs := h.validator.ValidateQueryValue("view", view)
+	errs := append(postIDValidationErrs, commentIDValidationErrs...)
+	if errs = append(errs, viewValidationErrs...); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	existedPost, err := h.service.UpvoteComment(postID, commentID, usr)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if err = writePost(w, applyCommentsView(existedPost, view)); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) downvoteComment(w http.ResponseWriter, r *http.Request) {
+	usr := r.Context().Value("user").(model.User)
+
+	vars := mux.Vars(r)
+	postID := vars["post_id"]
+	commentID := vars["comment_id"]
+
+	view := r.URL.Query().Get("view")
+
+	postIDValidationErrs := h.validator.ValidatePathValue("post_id", postID)
+	commentIDValidationErrs := h.validator.ValidatePathValue("comment_id", commentID)
+	viewValidationErrs := h.validator.ValidateQueryValue("view", view)
+	errs := append(postIDValidationErrs, commentIDValidationErrs...)
+	if errs = append(errs, viewValidationErrs...); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	existedPost, err := h.service.DownvoteComment(postID, commentID, usr)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if err = writePost(w, applyCommentsView(existedPost, view)); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) unvoteComment(w http.ResponseWriter, r *http.Request) {
+	usr := r.Context().Value("user").(model.User)
+
+	vars := mux.Vars(r)
+	postID := vars["post_id"]
+	commentID := vars["comment_id"]
+
+	view := r.URL.Query().Get("view")
+
+	postIDValidationErrs := h.validator.ValidatePathValue("post_id", postID)
+	commentIDValidationErrs := h.validator.ValidatePathValue("comment_id", commentID)
+	viewValidationErrs := h.validator.ValidateQueryValue("view", view)
+	errs := append(postIDValidationErrs, commentIDValidationErrs...)
+	if errs = append(errs, viewValidationErrs...); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	existedPost, err := h.service.UnvoteComment(postID, commentID, usr)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if err = writePost(w, applyCommentsView(existedPost, view)); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) getUserVotes(w http.ResponseWriter, r *http.Request) {
+	usr := r.Context().Value("user").(model.User)
+
+	votes, err := h.service.GetVotesByUser(usr)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp, err := json.Marshal(votes)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if _, err = w.Write(resp); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"github.com/sirupsen/logrus"
+	"net"
+	"net/http"
+	"redditclone/internal/model"
+	"redditclone/internal/model/customerr"
+	"redditclone/pkg/ratelimit"
+	"time"
+)
+
+// the names of the route budgets, the votes on posts and comments share one budget
+const (
+	rateLimitRegister = "register"
+	rateLimitLogin    = "login"
+	rateLimitPosts    = "posts"
+	rateLimitComments = "comments"
+	rateLimitVotes    = "votes"
+)
+
+type RateLimitStore interface {
+	Allow(key string, limit ratelimit.Limit) (bool, time.Duration, error)
+}
+
+// RateLimits are the budgets by the route name, a route without a budget is not limited
+type RateLimits map[string]ratelimit.Limit
+
+type rateLimiter struct {
+	store  RateLimitStore
+	limits RateLimits
+}
+
+func NewRateLimiter(store RateLimitStore, limits RateLimits) *rateLimiter {
+	return &rateLimiter{store: store, limits: limits}
+}
+
+// allow lets the request through when the store fails, the limits are not worth an outage
+func (l *rateLimiter) allow(name string, key string) error {
+	if l == nil {
+		return nil
+	}
+
+	limit, ok := l.limits[name]
+	if !ok || limit.Requests <= 0 {
+		return nil
+	}
+
+	allowed, retryAfter, err := l.store.Allow(name+":"+key, limit)
+	if err != nil {
+		logrus.Errorln(err)
+		return nil
+	}
+
+	if !allowed {
+		return customerr.RateLimited{Action: name, RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// clientIP is the address of the connection, the forwarding headers are not trusted
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// limitByIP throttles the routes for anonymous clients, such as register and login
+func (h *Handler) limitByIP(name string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := h.limiter.allow(name, "ip:"+clientIP(r)); err != nil {
+			h.handleError(w, err)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// limitByUser spends the budget of the logged in user, it wraps only the routes of routerForAuthorized
+func (h *Handler) limitByUser(name string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		usr, ok := r.Context().Value("user").(model.User)
+		if !ok {
+			h.handleError(w, customerr.Unauthorized{Message: "user not found in context"})
+			return
+		}
+
+		if err := h.limiter.allow(name, "user:"+usr.ID); err != nil {
+			h.handleError(w, err)
+			return
+		}
+		next(w, r)
+	}
+}
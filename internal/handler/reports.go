@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"net/http"
+	"redditclone/internal/model"
+	"redditclone/internal/model/customerr"
+	"strconv"
+)
+
+// decodeReport parses and validates the body of a report
+func (h *Handler) decodeReport(w http.ResponseWriter, r *http.Request) (map[string]string, bool) {
+	var input map[string]string
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		h.handleError(w, customerr.RequestNotParsed{Message: err.Error()})
+		return nil, false
+	}
+
+	if errs := h.validator.ValidateBody("Report", input); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return nil, false
+	}
+
+	return input, true
+}
+
+func writeReport(w http.ResponseWriter, report model.Report) error {
+	resp, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(resp)
+	return err
+}
+
+func (h *Handler) reportPost(w http.ResponseWriter, r *http.Request) {
+	usr := r.Context().Value("user").(model.User)
+	vars := mux.Vars(r)
+	postID := vars["post_id"]
+
+	if errs := h.validator.ValidatePathValue("post_id", postID); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	input, ok := h.decodeReport(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.service.ReportPost(postID, input["reason"], input["text"], usr)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if err = writeReport(w, report); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (h *Handler) reportComment(w http.ResponseWriter, r *http.Request) {
+	usr := r.Context().Value("user").(model.User)
+	vars := mux.Vars(r)
+	postID := vars["post_id"]
+	commentID := vars["comment_id"]
+
+	errs := h.validator.ValidatePathValue("post_id", postID)
+	errs = append(errs, h.validator.ValidatePathValue("comment_id", commentID)...)
+	if len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	input, ok := h.decodeReport(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.service.ReportComment(postID, commentID, input["reason"], input["text"], usr)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if err = writeReport(w, report); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (h *Handler) getReportQueue(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+
+	errs := h.validator.ValidateQueryValueAs("community_filter", "community", values.Get("community"))
+	errs = append(errs, h.validator.ValidateQueryValue("limit", values.Get("limit"))...)
+	if len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	query := model.ReportsQuery{Community: values.Get("community")}
+	query.Limit, _ = strconv.Atoi(values.Get("limit"))
+
+	items, err := h.service.GetReportQueue(query)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp, err := json.Marshal(items)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if _, err = w.Write(resp); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
+
+// resolveReports serves both the post and the comment queue entries, comment_id is empty for a post
+func (h *Handler) resolveReports(w http.ResponseWriter, r *http.Request) {
+	moderator := r.Context().Value("user").(model.User)
+	vars := mux.Vars(r)
+	postID := vars["post_id"]
+	commentID := vars["comment_id"]
+
+	errs := h.validator.ValidatePathValue("post_id", postID)
+	if commentID != "" {
+		errs = append(errs, h.validator.ValidatePathValue("comment_id", commentID)...)
+	}
+	if len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	var input map[string]string
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		h.handleError(w, customerr.RequestNotParsed{Message: err.Error()})
+		return
+	}
+
+	if errs := h.validator.ValidateBody("ReportResolution", input); len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	resolution, err := h.service.ResolveReports(postID, commentID, input["action"], input["reason"], moderator)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp, err := json.Marshal(resolution)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if _, err = w.Write(resp); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
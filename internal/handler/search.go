@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"redditclone/internal/model"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseSearchTime accepts a date or an RFC 3339 time and converts it to the stored format,
+// an empty value leaves the bound open
+func parseSearchTime(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		if t, err = time.Parse(time.RFC3339, value); err != nil {
+			return "", err
+		}
+	}
+
+	return t.UTC().Format("2006-01-02T15:04:05.000Z"), nil
+}
+
+func (h *Handler) search(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+
+	errs := h.validator.ValidateQueryValue("q", values.Get("q"))
+	errs = append(errs, h.validator.ValidateQueryValueAs("community_filter", "community", values.Get("community"))...)
+	errs = append(errs, h.validator.ValidateQueryValue("from", values.Get("from"))...)
+	errs = append(errs, h.validator.ValidateQueryValue("to", values.Get("to"))...)
+	errs = append(errs, h.validator.ValidateQueryValueAs("search_sort", "sort", values.Get("sort"))...)
+	errs = append(errs, h.validator.ValidateQueryValue("limit", values.Get("limit"))...)
+	errs = append(errs, h.validator.ValidateQueryValueAs("search_cursor", "cursor", values.Get("cursor"))...)
+	if len(errs) != 0 {
+		h.handleValidationErrors(w, errs)
+		return
+	}
+
+	query := model.SearchQuery{
+		Text:      strings.TrimSpace(values.Get("q")),
+		Community: values.Get("community"),
+		Author:    values.Get("author"),
+		Sort:      values.Get("sort"),
+		Cursor:    values.Get("cursor"),
+	}
+	query.From, _ = parseSearchTime(values.Get("from"))
+	query.To, _ = parseSearchTime(values.Get("to"))
+	query.Limit, _ = strconv.Atoi(values.Get("limit"))
+
+	page, err := h.service.Search(query)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	resp, err := json.Marshal(page)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if _, err = w.Write(resp); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+}
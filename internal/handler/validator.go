@@ -1,14 +1,34 @@
 package handler
 
 import (
+	"github.com/sirupsen/logrus"
+	"redditclone/internal/model"
+	"redditclone/internal/model/customerr"
+	"redditclone/pkg/cursor"
 	"redditclone/pkg/hexid"
 	"redditclone/pkg/httpvalidator"
+	"regexp"
+	"strconv"
+	"strings"
 )
 
 var (
-	categories = [...]string{"music", "funny", "videos", "programming", "news", "fashion"}
+	communityNamePattern = regexp.MustCompile(`^[a-z0-9_]{3,21}$`)
 )
 
+const (
+	maxCommunityDescriptionLen = 500
+)
+
+// communityExists checks a category against the stored communities, a storage failure fails the validation
+func (h *Handler) communityExists(name string) bool {
+	_, err := h.service.GetCommunity(name)
+	if _, ok := err.(customerr.CommunityNotFoundByName); !ok && err != nil {
+		logrus.Errorln(err)
+	}
+	return err == nil
+}
+
 func (h *Handler) initValidator() {
 	postInputTmpl := httpvalidator.RequestBody{
 		Fields: httpvalidator.Fields{
@@ -16,15 +36,8 @@ func (h *Handler) initValidator() {
 				Required: true,
 				Rules: []httpvalidator.Rule{
 					{
-						Description: "category must has specific type",
-						Validate: func(category string) bool {
-							for _, existedCategory := range categories {
-								if existedCategory == category {
-									return true
-								}
-							}
-							return false
-						},
+						Description: "category must be an existing community",
+						Validate:    h.communityExists,
 					},
 				},
 			},
@@ -112,6 +125,86 @@ func (h *Handler) initValidator() {
 		},
 	}
 
+	refreshTokenTmpl := httpvalidator.RequestBody{
+		Fields: httpvalidator.Fields{
+			"refresh_token": httpvalidator.BodyField{
+				Required: true,
+				Rules: []httpvalidator.Rule{
+					{
+						Description: "refresh_token must be a non-empty string",
+						Validate: func(refreshToken string) bool {
+							return len(refreshToken) > 0
+						},
+					},
+				},
+			},
+		},
+	}
+
+	roleTmpl := httpvalidator.RequestBody{
+		Fields: httpvalidator.Fields{
+			"role": httpvalidator.BodyField{
+				Required: true,
+				Rules: []httpvalidator.Rule{
+					{
+						Description: "role must be a user, a moderator or an admin",
+						Validate: func(role string) bool {
+							for _, existedRole := range model.Roles {
+								if existedRole == role {
+									return true
+								}
+							}
+							return false
+						},
+					},
+				},
+			},
+		},
+	}
+
+	communityTmpl := httpvalidator.RequestBody{
+		Fields: httpvalidator.Fields{
+			"name": httpvalidator.BodyField{
+				Required: true,
+				Rules: []httpvalidator.Rule{
+					{
+						Description: "name must be 3-21 lowercase letters, digits or underscores",
+						Validate: func(name string) bool {
+							return communityNamePattern.MatchString(name)
+						},
+					},
+				},
+			},
+			"description": httpvalidator.BodyField{
+				Required: false,
+				Rules: []httpvalidator.Rule{
+					{
+						Description: "description must be at most 500 symbols",
+						Validate: func(description string) bool {
+							return len(description) <= maxCommunityDescriptionLen
+						},
+					},
+				},
+			},
+		},
+	}
+
+	communityUpdateTmpl := httpvalidator.RequestBody{
+		Fields: httpvalidator.Fields{
+			"description": httpvalidator.BodyField{
+				Required: true,
+				Rules: []httpvalidator.Rule{
+					{
+						Description: "description must be at most 500 symbols",
+						Validate: func(description string) bool {
+							return len(description) <= maxCommunityDescriptionLen
+						},
+					},
+				},
+			},
+		},
+	}
+
 	commentTmpl := httpvalidator.RequestBody{
 		Fields: httpvalidator.Fields{
 			"comment": httpvalidator.BodyField{
@@ -125,6 +218,162 @@ func (h *Handler) initValidator() {
 					},
 				},
 			},
+			"parent_id": httpvalidator.BodyField{
+				Required: false,
+				Rules: []httpvalidator.Rule{
+					{
+						Description: "parent_id must be a hexadecimal 24-symbols string",
+						Validate: func(id string) bool {
+							return id == "" || hexid.Validate(id)
+						},
+					},
+				},
+			},
+		},
+	}
+
+	commentUpdateTmpl := httpvalidator.RequestBody{
+		Fields: httpvalidator.Fields{
+			"comment": httpvalidator.BodyField{
+				Required: true,
+				Rules: []httpvalidator.Rule{
+					{
+						Description: "comment must be a non-empty string",
+						Validate: func(comment string) bool {
+							return len(comment) > 0
+						},
+					},
+				},
+			},
+		},
+	}
+
+	messageTmpl := httpvalidator.RequestBody{
+		Fields: httpvalidator.Fields{
+			"body": httpvalidator.BodyField{
+				Required: true,
+				Rules: []httpvalidator.Rule{
+					{
+						Description: "body must be a non-empty string of at most 10000 symbols",
+						Validate: func(body string) bool {
+							return len(body) > 0 && len(body) <= model.MaxMessageLen
+						},
+					},
+				},
+			},
+		},
+	}
+
+	reportTmpl := httpvalidator.RequestBody{
+		Fields: httpvalidator.Fields{
+			"reason": httpvalidator.BodyField{
+				Required: true,
+				Rules: []httpvalidator.Rule{
+					{
+						Description: "reason must be a spam, a harassment, a hate, a misinformation or an other",
+						Validate: func(reason string) bool {
+							for _, existedReason := range model.ReportReasons {
+								if existedReason == reason {
+									return true
+								}
+							}
+							return false
+						},
+					},
+				},
+			},
+			"text": httpvalidator.BodyField{
+				Required: false,
+				Rules: []httpvalidator.Rule{
+					{
+						Description: "text must be at most 500 symbols",
+						Validate: func(text string) bool {
+							return len(text) <= model.MaxReportTextLen
+						},
+					},
+				},
+			},
+		},
+	}
+
+	reportResolutionTmpl := httpvalidator.RequestBody{
+		Fields: httpvalidator.Fields{
+			"action": httpvalidator.BodyField{
+				Required: true,
+				Rules: []httpvalidator.Rule{
+					{
+						Description: "action must be an approve, a remove or a dismiss",
+						Validate: func(action string) bool {
+							_, ok := model.ReportResolutions[action]
+							return ok
+						},
+					},
+				},
+			},
+			"reason": httpvalidator.BodyField{
+				Required: false,
+				Rules: []httpvalidator.Rule{
+					{
+						Description: "reason must be at most 255 symbols",
+						Validate: func(reason string) bool {
+							return len(reason) <= model.MaxRemovalReasonLen
+						},
+					},
+				},
+			},
+		},
+	}
+
+	banReasonField := httpvalidator.BodyField{
+		Required: false,
+		Rules: []httpvalidator.Rule{
+			{
+				Description: "reason must be at most 200 symbols",
+				Validate: func(reason string) bool {
+					return len(reason) <= model.MaxBanReasonLen
+				},
+			},
+		},
+	}
+
+	banDaysField := httpvalidator.BodyField{
+		Required: false,
+		Rules: []httpvalidator.Rule{
+			{
+				Description: "days must be an integer from 1 to 3650, leave it out for a permanent ban",
+				Validate: func(days string) bool {
+					if days == "" {
+						return true
+					}
+					n, err := strconv.Atoi(days)
+					return err == nil && n >= 1 && n <= model.MaxBanDays
+				},
+			},
+		},
+	}
+
+	banTmpl := httpvalidator.RequestBody{
+		Fields: httpvalidator.Fields{
+			"username": httpvalidator.BodyField{
+				Required: true,
+				Rules: []httpvalidator.Rule{
+					{
+						Description: "username must be a non-empty string",
+						Validate: func(username string) bool {
+							return len(username) > 0
+						},
+					},
+				},
+			},
+			"reason": banReasonField,
+			"days":   banDaysField,
+		},
+	}
+
+	suspensionTmpl := httpvalidator.RequestBody{
+		Fields: httpvalidator.Fields{
+			"reason": banReasonField,
+			"days":   banDaysField,
 		},
 	}
 
@@ -132,7 +381,17 @@ func (h *Handler) initValidator() {
 	h.validator.AddBodyTemplate("TextPostInput", textPostInputTmpl)
 	h.validator.AddBodyTemplate("URLPostInput", urlPostInputTmpl)
 	h.validator.AddBodyTemplate("Credential", credentialTmpl)
+	h.validator.AddBodyTemplate("RefreshToken", refreshTokenTmpl)
+	h.validator.AddBodyTemplate("Role", roleTmpl)
+	h.validator.AddBodyTemplate("Community", communityTmpl)
+	h.validator.AddBodyTemplate("CommunityUpdate", communityUpdateTmpl)
 	h.validator.AddBodyTemplate("Comment", commentTmpl)
+	h.validator.AddBodyTemplate("CommentUpdate", commentUpdateTmpl)
+	h.validator.AddBodyTemplate("Message", messageTmpl)
+	h.validator.AddBodyTemplate("Report", reportTmpl)
+	h.validator.AddBodyTemplate("ReportResolution", reportResolutionTmpl)
+	h.validator.AddBodyTemplate("Ban", banTmpl)
+	h.validator.AddBodyTemplate("Suspension", suspensionTmpl)
 
 	userIDValueRules := []httpvalidator.Rule{
 		{
@@ -161,16 +420,27 @@ func (h *Handler) initValidator() {
 		},
 	}
 
+	notificationIDValueRules := []httpvalidator.Rule{
+		{
+			Description: "notification_id must be a hexadecimal 24-symbols string",
+			Validate: func(id string) bool {
+				return hexid.Validate(id)
+			},
+		},
+	}
+
 	categoryRules := []httpvalidator.Rule{
 		{
-			Description: "category must has specific type",
-			Validate: func(category string) bool {
-				for _, existedCategory := range categories {
-					if existedCategory == category {
-						return true
-					}
-				}
-				return false
+			Description: "category must be an existing community",
+			Validate:    h.communityExists,
+		},
+	}
+
+	communityRules := []httpvalidator.Rule{
+		{
+			Description: "community must be 3-21 lowercase letters, digits or underscores",
+			Validate: func(name string) bool {
+				return communityNamePattern.MatchString(name)
 			},
 		},
 	}
@@ -187,6 +457,195 @@ func (h *Handler) initValidator() {
 	h.validator.AddPathValueTemplate("user_id", userIDValueRules)
 	h.validator.AddPathValueTemplate("post_id", postIDValueRules)
 	h.validator.AddPathValueTemplate("comment_id", commentIDValueRules)
+	h.validator.AddPathValueTemplate("notification_id", notificationIDValueRules)
 	h.validator.AddPathValueTemplate("category", categoryRules)
+	h.validator.AddPathValueTemplate("community", communityRules)
 	h.validator.AddPathValueTemplate("username", usernameRules)
+
+	viewRules := []httpvalidator.Rule{
+		{
+			Description: "view must be a flat or a tree",
+			Validate: func(view string) bool {
+				return view == "" || view == commentsViewFlat || view == commentsViewTree
+			},
+		},
+	}
+
+	limitRules := []httpvalidator.Rule{
+		{
+			Description: "limit must be an integer from 1 to 100",
+			Validate: func(limit string) bool {
+				if limit == "" {
+					return true
+				}
+				n, err := strconv.Atoi(limit)
+				return err == nil && n >= 1 && n <= model.MaxPageLimit
+			},
+		},
+	}
+
+	cursorRules := []httpvalidator.Rule{
+		{
+			Description: "cursor must be a value of next_cursor",
+			Validate: func(encoded string) bool {
+				var position model.PostCursor
+				return encoded == "" || cursor.Decode(encoded, &position) == nil
+			},
+		},
+	}
+
+	sortRules := []httpvalidator.Rule{
+		{
+			Description: "sort must be a hot, a new, a top, a rising or a controversial",
+			Validate: func(sort string) bool {
+				if sort == "" {
+					return true
+				}
+				for _, existedSort := range model.Sorts {
+					if existedSort == sort {
+						return true
+					}
+				}
+				return false
+			},
+		},
+	}
+
+	windowRules := []httpvalidator.Rule{
+		{
+			Description: "t must be an hour, a day, a week, a month, a year or all",
+			Validate: func(window string) bool {
+				_, ok := model.TopWindows[window]
+				return window == "" || ok
+			},
+		},
+	}
+
+	searchTextRules := []httpvalidator.Rule{
+		{
+			Description: "q must be a non-empty string",
+			Validate: func(text string) bool {
+				return len(strings.TrimSpace(text)) > 0
+			},
+		},
+	}
+
+	communityFilterRules := []httpvalidator.Rule{
+		{
+			Description: "community must be 3-21 lowercase letters, digits or underscores",
+			Validate: func(name string) bool {
+				return name == "" || communityNamePattern.MatchString(name)
+			},
+		},
+	}
+
+	searchTimeRules := []httpvalidator.Rule{
+		{
+			Description: "time must be a date or an RFC 3339 time",
+			Validate: func(value string) bool {
+				_, err := parseSearchTime(value)
+				return err == nil
+			},
+		},
+	}
+
+	searchSortRules := []httpvalidator.Rule{
+		{
+			Description: "sort must be a relevance or a new",
+			Validate: func(sort string) bool {
+				if sort == "" {
+					return true
+				}
+				for _, existedSort := range model.SearchSorts {
+					if existedSort == sort {
+						return true
+					}
+				}
+				return false
+			},
+		},
+	}
+
+	searchCursorRules := []httpvalidator.Rule{
+		{
+			Description: "cursor must be a value of next_cursor",
+			Validate: func(encoded string) bool {
+				var position model.SearchCursor
+				return encoded == "" || cursor.Decode(encoded, &position) == nil
+			},
+		},
+	}
+
+	unreadRules := []httpvalidator.Rule{
+		{
+			Description: "unread must be a true or a false",
+			Validate: func(unread string) bool {
+				_, err := strconv.ParseBool(unread)
+				return unread == "" || err == nil
+			},
+		},
+	}
+
+	reasonRules := []httpvalidator.Rule{
+		{
+			Description: "reason must be at most 255 symbols",
+			Validate: func(reason string) bool {
+				return len(reason) <= model.MaxRemovalReasonLen
+			},
+		},
+	}
+
+	modActionRules := []httpvalidator.Rule{
+		{
+			Description: "action must be a moderator action type",
+			Validate: func(action string) bool {
+				if action == "" {
+					return true
+				}
+				for _, existedAction := range model.ModActions {
+					if existedAction == action {
+						return true
+					}
+				}
+				return false
+			},
+		},
+	}
+
+	modLogCursorRules := []httpvalidator.Rule{
+		{
+			Description: "cursor must be a value of next_cursor",
+			Validate: func(encoded string) bool {
+				var position model.ModActionCursor
+				return encoded == "" || cursor.Decode(encoded, &position) == nil
+			},
+		},
+	}
+
+	notificationCursorRules := []httpvalidator.Rule{
+		{
+			Description: "cursor must be a value of next_cursor",
+			Validate: func(encoded string) bool {
+				var position model.NotificationCursor
+				return encoded == "" || cursor.Decode(encoded, &position) == nil
+			},
+		},
+	}
+
+	h.validator.AddQueryValueTemplate("view", viewRules)
+	h.validator.AddQueryValueTemplate("sort", sortRules)
+	h.validator.AddQueryValueTemplate("t", windowRules)
+	h.validator.AddQueryValueTemplate("limit", limitRules)
+	h.validator.AddQueryValueTemplate("cursor", cursorRules)
+	h.validator.AddQueryValueTemplate("q", searchTextRules)
+	h.validator.AddQueryValueTemplate("community_filter", communityFilterRules)
+	h.validator.AddQueryValueTemplate("from", searchTimeRules)
+	h.validator.AddQueryValueTemplate("to", searchTimeRules)
+	h.validator.AddQueryValueTemplate("search_sort", searchSortRules)
+	h.validator.AddQueryValueTemplate("search_cursor", searchCursorRules)
+	h.validator.AddQueryValueTemplate("unread", unreadRules)
+	h.validator.AddQueryValueTemplate("notification_cursor", notificationCursorRules)
+	h.validator.AddQueryValueTemplate("reason", reasonRules)
+	h.validator.AddQueryValueTemplate("mod_action", modActionRules)
+	h.validator.AddQueryValueTemplate("modlog_cursor", modLogCursorRules)
 }
@@ -0,0 +1,101 @@
+package model
+
+import "time"
+
+const (
+	AutomodActionRemove = "remove"
+	AutomodActionFlag   = "flag"
+	AutomodActionLock   = "lock"
+	AutomodActionReply  = "reply"
+)
+
+var AutomodActions = [...]string{AutomodActionRemove, AutomodActionFlag, AutomodActionLock, AutomodActionReply}
+
+const (
+	AutomodTargetAny     = "any"
+	AutomodTargetPost    = "post"
+	AutomodTargetComment = "comment"
+)
+
+// AutomodUsername signs the removals, the reports and the replies of the automoderator,
+// it is not a registered user so it has no ID
+const AutomodUsername = "AutoModerator"
+
+var Automoderator = User{Credential: Credential{Username: AutomodUsername}, Role: RoleModerator}
+
+// AutomodAuthorCondition matches the authors below the given account age or karma
+type AutomodAuthorCondition struct {
+	AccountAgeBelow string `yaml:"account_age_below"`
+	KarmaBelow      *int   `yaml:"karma_below"`
+}
+
+// AutomodRule matches when all of its conditions match, the regexes use the Go syntax.
+// A rule without Reports runs on creation and on edits, a rule with Reports runs on every report
+// once the open reports of the content reach that count.
+type AutomodRule struct {
+	Name    string                  `yaml:"name"`
+	Target  string                  `yaml:"target"`
+	Title   string                  `yaml:"title"`
+	Body    string                  `yaml:"body"`
+	Domains []string                `yaml:"domains"`
+	Author  *AutomodAuthorCondition `yaml:"author"`
+	Reports int                     `yaml:"reports"`
+	Action  string                  `yaml:"action"`
+	Reason  string                  `yaml:"reason"`
+	Reply   string                  `yaml:"reply"`
+	DryRun  bool                    `yaml:"dry_run"`
+}
+
+// AutomodRules is the rule file, DryRun turns every rule into a dry run
+type AutomodRules struct {
+	DryRun bool          `yaml:"dry_run"`
+	Rules  []AutomodRule `yaml:"rules"`
+}
+
+// AutomodSubject is a post or a comment being checked, Title and URL are empty for a comment.
+// Reports is the count of the open reports when a report triggers the check, it is zero on creation and on edits.
+// Edited is set when an edit triggers the check, the reply rules skip the edits not to reply again.
+type AutomodSubject struct {
+	Target    string
+	Title     string
+	Body      string
+	URL       string
+	Community string
+	Author    User
+	Reports   int
+	Edited    bool
+}
+
+func NewPostSubject(post Post, author User) AutomodSubject {
+	return AutomodSubject{
+		Target:    AutomodTargetPost,
+		Title:     post.Title,
+		Body:      post.Text,
+		URL:       post.URL,
+		Community: post.Category,
+		Author:    author,
+	}
+}
+
+func NewCommentSubject(post Post, comment Comment, author User) AutomodSubject {
+	return AutomodSubject{
+		Target:    AutomodTargetComment,
+		Body:      comment.Body,
+		Community: post.Category,
+		Author:    author,
+	}
+}
+
+// AccountAge is unknown for the users registered before the creation time was kept
+func (s AutomodSubject) AccountAge(now time.Time) (time.Duration, bool) {
+	created, err := time.Parse("2006-01-02T15:04:05.000Z", s.Author.Created)
+	if err != nil {
+		return 0, false
+	}
+	return now.Sub(created), true
+}
+
+type AutomodMatch struct {
+	Rule   AutomodRule
+	DryRun bool
+}
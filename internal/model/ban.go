@@ -0,0 +1,74 @@
+package model
+
+import "time"
+
+const (
+	MaxBanReasonLen = 200
+	MaxBanDays      = 3650
+)
+
+// BanInput takes the ban length in days, zero days makes the ban permanent
+type BanInput struct {
+	Reason string
+	Days   int
+}
+
+// Ban keeps the user from posting, commenting and voting in one community, an empty Expires is permanent
+type Ban struct {
+	ID        string `json:"id"`
+	Community string `json:"community"`
+	User      Author `json:"user"`
+	Moderator Author `json:"moderator"`
+	Reason    string `json:"reason,omitempty"`
+	Expires   string `json:"expires,omitempty"`
+	Created   string `json:"created"`
+}
+
+func NewBan(banID string, community string, user, moderator Author, input BanInput) Ban {
+	now := time.Now().UTC()
+	return Ban{
+		ID:        banID,
+		Community: community,
+		User:      user,
+		Moderator: moderator,
+		Reason:    input.Reason,
+		Expires:   banExpires(now, input.Days),
+		Created:   now.Format("2006-01-02T15:04:05.000Z"),
+	}
+}
+
+func (b Ban) IsActive() bool {
+	return banActive(b.Expires)
+}
+
+// Suspension is the site-wide ban kept on the user record, it leaves the user only reading
+type Suspension struct {
+	Reason  string `json:"reason,omitempty"`
+	Expires string `json:"expires,omitempty"`
+	Created string `json:"created"`
+}
+
+func NewSuspension(input BanInput) *Suspension {
+	now := time.Now().UTC()
+	return &Suspension{
+		Reason:  input.Reason,
+		Expires: banExpires(now, input.Days),
+		Created: now.Format("2006-01-02T15:04:05.000Z"),
+	}
+}
+
+func (s *Suspension) IsActive() bool {
+	return s != nil && banActive(s.Expires)
+}
+
+func banExpires(now time.Time, days int) string {
+	if days <= 0 {
+		return ""
+	}
+	return now.AddDate(0, 0, days).Format("2006-01-02T15:04:05.000Z")
+}
+
+// banActive compares the times as strings, the format keeps them in order
+func banActive(expires string) bool {
+	return expires == "" || expires > time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+}
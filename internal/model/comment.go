@@ -2,18 +2,95 @@ package model
 
 import "time"
 
+const (
+	MaxCommentDepth           = 8
+	DeletedCommentPlaceholder = "[deleted]"
+)
+
 type Comment struct {
-	ID      string `json:"id" bson:"id"`
-	Created string `json:"created" bson:"created"`
-	Author  Author `json:"author" bson:"author"`
-	Body    string `json:"body" bson:"body"`
+	ID       string    `json:"id" bson:"id"`
+	PostID   string    `json:"post_id" bson:"postId"`
+	ParentID string    `json:"parent_id,omitempty" bson:"parentId"`
+	Depth    int       `json:"depth" bson:"depth"`
+	Created  string    `json:"created" bson:"created"`
+	Author   Author    `json:"author" bson:"author"`
+	Body     string    `json:"body" bson:"body"`
+	Edited   string    `json:"edited,omitempty" bson:"edited,omitempty"`
+	Deleted  bool      `json:"deleted,omitempty" bson:"deleted"`
+	Removed  *Removal  `json:"removed,omitempty" bson:"removed,omitempty"`
+	Replies  []Comment `json:"replies,omitempty" bson:"-"`
+
+	Voting `bson:",inline"`
 }
 
-func NewComment(commentID string, text string, author Author) Comment {
+func NewComment(commentID string, postID string, text string, author Author) Comment {
 	return Comment{
 		ID:      commentID,
+		PostID:  postID,
 		Created: time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
 		Author:  author,
 		Body:    text,
+		Voting:  NewVoting(),
 	}
 }
+
+func NewReply(commentID string, text string, author Author, parent Comment) Comment {
+	reply := NewComment(commentID, parent.PostID, text, author)
+	reply.ParentID = parent.ID
+	reply.Depth = parent.Depth + 1
+	return reply
+}
+
+// Deleted comments keep their place in the thread, only the author and the body are dropped
+func (c *Comment) MarkDeleted() *Comment {
+	c.Author = Author{Username: DeletedCommentPlaceholder}
+	c.Body = DeletedCommentPlaceholder
+	c.Deleted = true
+	return c
+}
+
+func (c *Comment) Edit(text string) *Comment {
+	c.Body = text
+	c.Edited = time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+	return c
+}
+
+func HasReplies(comments []Comment, commentID string) bool {
+	for _, c := range comments {
+		if c.ParentID == commentID {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildCommentTree nests the flat list by parent IDs keeping the original order,
+// a comment with a missing parent becomes a root
+func BuildCommentTree(comments []Comment) []Comment {
+	known := make(map[string]bool, len(comments))
+	for _, c := range comments {
+		known[c.ID] = true
+	}
+
+	children := make(map[string][]Comment)
+	roots := make([]Comment, 0)
+	for _, c := range comments {
+		if c.ParentID == "" || !known[c.ParentID] {
+			roots = append(roots, c)
+			continue
+		}
+		children[c.ParentID] = append(children[c.ParentID], c)
+	}
+
+	var attach func(nodes []Comment) []Comment
+	attach = func(nodes []Comment) []Comment {
+		for i := range nodes {
+			if replies, ok := children[nodes[i].ID]; ok {
+				nodes[i].Replies = attach(replies)
+			}
+		}
+		return nodes
+	}
+
+	return attach(roots)
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+type CommunityInput struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type Community struct {
+	Name        string `json:"name" bson:"name"`
+	Description string `json:"description" bson:"description"`
+	Creator     Author `json:"creator" bson:"creator"`
+	Created     string `json:"created" bson:"created"`
+}
+
+func NewCommunity(input CommunityInput, creator Author) Community {
+	return Community{
+		Name:        input.Name,
+		Description: input.Description,
+		Creator:     creator,
+		Created:     time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
+	}
+}
@@ -2,6 +2,7 @@ package customerr
 
 import (
 	"fmt"
+	"time"
 )
 
 type UserAlreadyExists struct {
@@ -53,6 +54,39 @@ func (e CommentNotFoundByID) Error() string {
 	return fmt.Sprintf("comment not found by ID: %s in post with ID: %s", e.CommentID, e.PostID)
 }
 
+type ReportsNotFound struct {
+	PostID    string
+	CommentID string
+}
+
+func (e ReportsNotFound) Error() string {
+	return fmt.Sprintf("open reports not found for post %s comment %s", e.PostID, e.CommentID)
+}
+
+type CommunityAlreadyExists struct {
+	Name string
+}
+
+func (e CommunityAlreadyExists) Error() string {
+	return fmt.Sprintf("community already exists: %s", e.Name)
+}
+
+type CommunityNotFoundByName struct {
+	Name string
+}
+
+func (e CommunityNotFoundByName) Error() string {
+	return fmt.Sprintf("community not found by name: %s", e.Name)
+}
+
+type CommunityNotEmpty struct {
+	Name string
+}
+
+func (e CommunityNotEmpty) Error() string {
+	return fmt.Sprintf("community still has posts: %s", e.Name)
+}
+
 type NotOwner struct {
 	Username string
 }
@@ -76,3 +110,133 @@ type Unauthorized struct {
 func (e Unauthorized) Error() string {
 	return fmt.Sprintf("user unauthorized: %s", e.Message)
 }
+
+type PermissionDenied struct {
+	Username string
+}
+
+func (e PermissionDenied) Error() string {
+	return fmt.Sprintf("user %s has no permission for this action", e.Username)
+}
+
+type CommentTooDeep struct {
+	ParentID string
+	MaxDepth int
+}
+
+func (e CommentTooDeep) Error() string {
+	return fmt.Sprintf("reply to comment %s exceeds max depth %d", e.ParentID, e.MaxDepth)
+}
+
+type InvalidCursor struct {
+	Cursor string
+}
+
+func (e InvalidCursor) Error() string {
+	return fmt.Sprintf("cursor is invalid: %s", e.Cursor)
+}
+
+type PostNotEditable struct {
+	PostID string
+}
+
+func (e PostNotEditable) Error() string {
+	return fmt.Sprintf("post is not editable: %s", e.PostID)
+}
+
+// PostChanged means a conditional write found the post changed by another request since it was read
+type PostChanged struct {
+	PostID string
+}
+
+func (e PostChanged) Error() string {
+	return fmt.Sprintf("post %s was changed by another request", e.PostID)
+}
+
+// CommentChanged means a conditional write found the comment already deleted or removed by another request
+type CommentChanged struct {
+	PostID    string
+	CommentID string
+}
+
+func (e CommentChanged) Error() string {
+	return fmt.Sprintf("comment %s of post %s was changed by another request", e.CommentID, e.PostID)
+}
+
+type NotificationNotFoundByID struct {
+	NotificationID string
+}
+
+func (e NotificationNotFoundByID) Error() string {
+	return fmt.Sprintf("notification not found by ID: %s", e.NotificationID)
+}
+
+type TargetIsSelf struct {
+	Username string
+}
+
+func (e TargetIsSelf) Error() string {
+	return fmt.Sprintf("user %s targets themselves", e.Username)
+}
+
+type UserBlocked struct {
+	Username string
+}
+
+func (e UserBlocked) Error() string {
+	return fmt.Sprintf("messages with user %s are blocked", e.Username)
+}
+
+type RateLimited struct {
+	Action     string
+	RetryAfter time.Duration
+}
+
+func (e RateLimited) Error() string {
+	return fmt.Sprintf("rate limit of %s exceeded, retry after %s", e.Action, e.RetryAfter)
+}
+
+// Banned is a community ban, or a site-wide suspension when Community is empty, an empty Expires is permanent
+type Banned struct {
+	Username  string
+	Community string
+	Expires   string
+}
+
+func (e Banned) Error() string {
+	if e.Community == "" {
+		return fmt.Sprintf("user %s is suspended", e.Username)
+	}
+	return fmt.Sprintf("user %s is banned from community %s", e.Username, e.Community)
+}
+
+type BanNotFound struct {
+	Community string
+	UserID    string
+}
+
+func (e BanNotFound) Error() string {
+	return fmt.Sprintf("ban of user %s in community %s not found", e.UserID, e.Community)
+}
+
+type PostLocked struct {
+	PostID string
+}
+
+func (e PostLocked) Error() string {
+	return fmt.Sprintf("post is locked: %s", e.PostID)
+}
+
+// LoginLocked is a lockout of the username, or of the address when Username is empty
+type LoginLocked struct {
+	Username   string
+	IP         string
+	RetryAfter time.Duration
+}
+
+func (e LoginLocked) Error() string {
+	if e.Username == "" {
+		return fmt.Sprintf("logins from %s are locked, retry after %s", e.IP, e.RetryAfter)
+	}
+	return fmt.Sprintf("logins of user %s are locked, retry after %s", e.Username, e.RetryAfter)
+}
@@ -0,0 +1,71 @@
+package model
+
+import "time"
+
+const (
+	EventPostCreated    = "post_created"
+	EventPostEdited     = "post_edited"
+	EventPostVoted      = "post_voted"
+	EventPostDeleted    = "post_deleted"
+	EventCommentAdded   = "comment_added"
+	EventCommentEdited  = "comment_edited"
+	EventCommentVoted   = "comment_voted"
+	EventCommentDeleted = "comment_deleted"
+)
+
+// Event tells the subscribers of a post or of a community what has changed,
+// the post and the comment are sent without the votes of the acting user
+type Event struct {
+	Type      string   `json:"type"`
+	PostID    string   `json:"post_id"`
+	CommentID string   `json:"comment_id,omitempty"`
+	Community string   `json:"community,omitempty"`
+	Post      *Post    `json:"post,omitempty"`
+	Comment   *Comment `json:"comment,omitempty"`
+	Created   string   `json:"created"`
+}
+
+func PostTopic(postID string) string {
+	return "post:" + postID
+}
+
+func CommunityTopic(community string) string {
+	return "community:" + community
+}
+
+func newEvent(eventType string, postID string) Event {
+	return Event{
+		Type:    eventType,
+		PostID:  postID,
+		Created: time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
+	}
+}
+
+// NewPostEvent carries the post without its comments, they have their own events.
+// The events reach anonymous subscribers, so the removed content is always redacted.
+func NewPostEvent(eventType string, post Post) Event {
+	event := newEvent(eventType, post.ID)
+	event.Community = post.Category
+	post.Comments = nil
+	post = post.Redacted()
+	post.SetUserVote(Vote{})
+	event.Post = &post
+	return event
+}
+
+func NewCommentEvent(eventType string, comment Comment) Event {
+	event := newEvent(eventType, comment.PostID)
+	event.CommentID = comment.ID
+	comment.Replies = nil
+	comment = comment.Redacted()
+	comment.SetUserVote(Vote{})
+	event.Comment = &comment
+	return event
+}
+
+// NewDeletionEvent names only the deleted post or comment
+func NewDeletionEvent(eventType string, postID, commentID string) Event {
+	event := newEvent(eventType, postID)
+	event.CommentID = commentID
+	return event
+}
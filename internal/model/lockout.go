@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+const (
+	// MaxLoginFailures locks the username after that many failed logins, every next failure doubles the lock
+	MaxLoginFailures = 5
+	// MaxLoginFailuresPerIP is higher, many users can share one address
+	MaxLoginFailuresPerIP = 20
+	LoginLockout          = time.Minute
+	MaxLoginLockout       = time.Hour
+	// LoginFailuresTTL is how long the failures are counted after the last one
+	LoginFailuresTTL = 24 * time.Hour
+)
+
+// SystemUsername signs the actions nobody has taken by hand, such as the login lockouts
+const SystemUsername = "system"
+
+var System = User{Credential: Credential{Username: SystemUsername}}
+
+// LoginLockoutFor is zero below the failure limit, then it grows twice with every failure up to MaxLoginLockout
+func LoginLockoutFor(failures int, maxFailures int) time.Duration {
+	if failures < maxFailures {
+		return 0
+	}
+
+	lockout := LoginLockout
+	for i := maxFailures; i < failures && lockout < MaxLoginLockout; i++ {
+		lockout *= 2
+	}
+	if lockout > MaxLoginLockout {
+		lockout = MaxLoginLockout
+	}
+	return lockout
+}
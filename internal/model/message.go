@@ -0,0 +1,61 @@
+package model
+
+import "time"
+
+const (
+	MaxMessageLen = 10000
+
+	// MessageRateLimit is how many messages a user can send within MessageRateWindow
+	MessageRateLimit  = 10
+	MessageRateWindow = time.Minute
+)
+
+type Message struct {
+	ID        string `json:"id"`
+	Sender    Author `json:"sender"`
+	Recipient Author `json:"recipient"`
+	Body      string `json:"body"`
+	Read      bool   `json:"read"`
+	Created   string `json:"created"`
+}
+
+func NewMessage(messageID string, sender, recipient Author, body string) Message {
+	return Message{
+		ID:        messageID,
+		Sender:    sender,
+		Recipient: recipient,
+		Body:      body,
+		Created:   time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
+	}
+}
+
+// Conversation is the user's side of the messages with another user, Unread counts the received ones
+type Conversation struct {
+	With        Author  `json:"with"`
+	LastMessage Message `json:"last_message"`
+	Unread      int     `json:"unread"`
+}
+
+// NewConversation takes the other side from the last message
+func NewConversation(userID string, lastMessage Message, unread int) Conversation {
+	with := lastMessage.Recipient
+	if lastMessage.Recipient.ID == userID {
+		with = lastMessage.Sender
+	}
+	return Conversation{With: with, LastMessage: lastMessage, Unread: unread}
+}
+
+// Block stops the messages between the user and the blocked one in both directions
+type Block struct {
+	UserID  string `json:"-"`
+	Blocked Author `json:"user"`
+	Created string `json:"created"`
+}
+
+func NewBlock(userID string, blocked Author) Block {
+	return Block{
+		UserID:  userID,
+		Blocked: blocked,
+		Created: time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
+	}
+}
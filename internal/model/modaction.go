@@ -0,0 +1,67 @@
+package model
+
+import "time"
+
+const (
+	ModActionRemovePost    = "remove_post"
+	ModActionRemoveComment = "remove_comment"
+	ModActionSetRole       = "set_role"
+	ModActionApproveReport = "approve_report"
+	ModActionDismissReport = "dismiss_report"
+	ModActionBanUser       = "ban_user"
+	ModActionUnbanUser     = "unban_user"
+	ModActionSuspendUser   = "suspend_user"
+	ModActionUnsuspendUser = "unsuspend_user"
+	ModActionLockPost      = "lock_post"
+	ModActionLockAccount   = "lock_account"
+	ModActionUnlockAccount = "unlock_account"
+)
+
+var ModActions = [...]string{ModActionRemovePost, ModActionRemoveComment, ModActionSetRole, ModActionApproveReport, ModActionDismissReport,
+	ModActionBanUser, ModActionUnbanUser, ModActionSuspendUser, ModActionUnsuspendUser, ModActionLockPost, ModActionLockAccount, ModActionUnlockAccount}
+
+type ModAction struct {
+	ID         string `json:"id" bson:"id"`
+	Moderator  Author `json:"moderator" bson:"moderator"`
+	Action     string `json:"action" bson:"action"`
+	TargetUser Author `json:"targetUser" bson:"targetUser"`
+	Community  string `json:"community,omitempty" bson:"community"`
+	PostID     string `json:"postId,omitempty" bson:"postId"`
+	CommentID  string `json:"commentId,omitempty" bson:"commentId"`
+	Details    string `json:"details,omitempty" bson:"details"`
+	Created    string `json:"created" bson:"created"`
+}
+
+func NewModAction(actionID string, action string, moderator Author, targetUser Author) ModAction {
+	return ModAction{
+		ID:         actionID,
+		Moderator:  moderator,
+		Action:     action,
+		TargetUser: targetUser,
+		Created:    time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
+	}
+}
+
+// ModLogQuery filters the modlog, Moderator is a username
+type ModLogQuery struct {
+	Community string
+	Moderator string
+	Action    string
+	Limit     int
+	Cursor    string
+}
+
+// ModActionCursor points at the last action of a page, the newest actions go first
+type ModActionCursor struct {
+	Created string `json:"created"`
+	ID      string `json:"id"`
+}
+
+func (c ModActionCursor) IsZero() bool {
+	return c == ModActionCursor{}
+}
+
+type ModLogPage struct {
+	Actions    []ModAction `json:"actions"`
+	NextCursor string      `json:"next_cursor"`
+}
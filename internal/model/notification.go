@@ -0,0 +1,115 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+const (
+	NotificationPostComment    = "post_comment"
+	NotificationCommentReply   = "comment_reply"
+	NotificationMention        = "mention"
+	NotificationScoreMilestone = "score_milestone"
+)
+
+// MaxMentions caps the users a single comment can notify by mentions
+const MaxMentions = 10
+
+// ScoreMilestones are the scores of a post or a comment its author is told about
+var ScoreMilestones = []int{10, 50, 100, 500, 1000, 5000, 10000}
+
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([\w-]+)`)
+
+// Notification is an inbox record of the recipient, Key is set for the notifications sent only once,
+// the ID is given by the service on storing. Body is not stored, it is the text of the comment
+// filled on reading while the comment is neither deleted nor removed.
+type Notification struct {
+	ID        string  `json:"id" bson:"id"`
+	UserID    string  `json:"-" bson:"userId"`
+	Type      string  `json:"type" bson:"type"`
+	Actor     *Author `json:"actor,omitempty" bson:"actor,omitempty"`
+	PostID    string  `json:"post_id" bson:"postId"`
+	CommentID string  `json:"comment_id,omitempty" bson:"commentId,omitempty"`
+	Body      string  `json:"body,omitempty" bson:"body,omitempty"`
+	Score     int     `json:"score,omitempty" bson:"score,omitempty"`
+	Key       string  `json:"-" bson:"key,omitempty"`
+	Read      bool    `json:"read" bson:"read"`
+	Created   string  `json:"created" bson:"created"`
+}
+
+// NewCommentNotification tells the recipient about a comment, a reply or a mention, it keeps only the ids of the comment
+func NewCommentNotification(notificationType string, userID string, comment Comment) Notification {
+	actor := comment.Author
+	return Notification{
+		UserID:    userID,
+		Type:      notificationType,
+		Actor:     &actor,
+		PostID:    comment.PostID,
+		CommentID: comment.ID,
+		Created:   time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
+	}
+}
+
+// NewMilestoneNotification is keyed by the target and the milestone, so a score crossing it again is not told twice
+func NewMilestoneNotification(userID string, postID, commentID string, milestone int) Notification {
+	return Notification{
+		UserID:    userID,
+		Type:      NotificationScoreMilestone,
+		PostID:    postID,
+		CommentID: commentID,
+		Score:     milestone,
+		Key:       fmt.Sprintf("milestone:%s:%s:%d", postID, commentID, milestone),
+		Created:   time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
+	}
+}
+
+// ReachedMilestones returns the milestones the score has risen to
+func ReachedMilestones(previous, current int) []int {
+	reached := make([]int, 0)
+	for _, milestone := range ScoreMilestones {
+		if previous < milestone && current >= milestone {
+			reached = append(reached, milestone)
+		}
+	}
+	return reached
+}
+
+// Mentions returns the distinct usernames mentioned with @ in the text
+func Mentions(text string) []string {
+	usernames := make([]string, 0)
+	seen := make(map[string]struct{})
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		if _, ok := seen[match[1]]; ok {
+			continue
+		}
+		seen[match[1]] = struct{}{}
+		usernames = append(usernames, match[1])
+		if len(usernames) == MaxMentions {
+			break
+		}
+	}
+	return usernames
+}
+
+type NotificationsQuery struct {
+	UnreadOnly bool
+	Limit      int
+	Cursor     string
+}
+
+// NotificationCursor points at the last notification of a page, the newest notifications go first
+type NotificationCursor struct {
+	Created string `json:"created"`
+	ID      string `json:"id"`
+}
+
+func (c NotificationCursor) IsZero() bool {
+	return c == NotificationCursor{}
+}
+
+type NotificationsPage struct {
+	Notifications []Notification `json:"notifications"`
+	Unread        int            `json:"unread"`
+	NextCursor    string         `json:"next_cursor"`
+}
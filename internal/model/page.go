@@ -0,0 +1,91 @@
+package model
+
+const (
+	DefaultPageLimit = 25
+	MaxPageLimit     = 100
+)
+
+// PostsQuery selects a listing, Since is set by the service from the sort window
+type PostsQuery struct {
+	Category string
+	Author   string
+	Sort     string
+	Window   string
+	Since    string
+	Limit    int
+	Cursor   string
+}
+
+// PostCursor points at the last post of a page, pages go from the highest rank to the lowest,
+// new is ranked by the creation time and the other sorts by Rank
+type PostCursor struct {
+	Sort    string  `json:"sort"`
+	Created string  `json:"created,omitempty"`
+	Rank    float64 `json:"rank,omitempty"`
+	ID      string  `json:"id"`
+}
+
+// PostRank is the stored value a sort mode orders by
+func PostRank(post Post, sort string) float64 {
+	switch sort {
+	case SortHot, SortRising:
+		return post.Ranks.Hot
+	case SortTop:
+		return float64(post.Score)
+	case SortControversial:
+		return post.Ranks.Controversial
+	default:
+		return 0
+	}
+}
+
+func NewPostCursor(post Post, sort string) PostCursor {
+	if sort == SortNew {
+		return PostCursor{Sort: sort, Created: post.Created, ID: post.ID}
+	}
+	return PostCursor{Sort: sort, Rank: PostRank(post, sort), ID: post.ID}
+}
+
+func (c PostCursor) IsZero() bool {
+	return c == PostCursor{}
+}
+
+// PostPrecedes reports whether the first post goes before the second one in the sort mode
+func PostPrecedes(a, b Post, sort string) bool {
+	if sort == SortNew {
+		if a.Created != b.Created {
+			return a.Created > b.Created
+		}
+		return a.ID > b.ID
+	}
+
+	rankA, rankB := PostRank(a, sort), PostRank(b, sort)
+	if rankA != rankB {
+		return rankA > rankB
+	}
+	return a.ID > b.ID
+}
+
+// Precedes reports whether the post goes after the cursor
+func (c PostCursor) Precedes(post Post) bool {
+	if c.IsZero() {
+		return true
+	}
+	if c.Sort == SortNew {
+		if post.Created != c.Created {
+			return post.Created < c.Created
+		}
+		return post.ID < c.ID
+	}
+
+	rank := PostRank(post, c.Sort)
+	if rank != c.Rank {
+		return rank < c.Rank
+	}
+	return post.ID < c.ID
+}
+
+type PostsPage struct {
+	Posts      []Post `json:"posts"`
+	NextCursor string `json:"next_cursor"`
+}
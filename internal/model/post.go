@@ -2,11 +2,6 @@ package model
 
 import "time"
 
-type Vote struct {
-	UserID string `json:"user" bson:"user"`
-	Vote   int    `json:"vote" bson:"vote"`
-}
-
 type TextPostInput struct {
 	Category string `json:"category"`
 	Title    string `json:"title"`
@@ -21,112 +16,95 @@ type URLPostInput struct {
 	URL      string `json:"url"`
 }
 
+type PostUpdateInput struct {
+	Title string `json:"title"`
+	Text  string `json:"text"`
+}
+
+// PostRevision keeps a replaced version of a post, Created is the time the version was written
+type PostRevision struct {
+	Title   string `json:"title" bson:"title"`
+	Text    string `json:"text" bson:"text"`
+	Created string `json:"created" bson:"created"`
+}
+
 type Post struct {
-	ID               string    `json:"id" bson:"id"`
-	Score            int       `json:"score" bson:"score"`
-	Views            int       `json:"views" bson:"views"`
-	Type             string    `json:"type" bson:"type"`
-	Title            string    `json:"title" bson:"title"`
-	Author           Author    `json:"author" bson:"author"`
-	Category         string    `json:"category" bson:"category"`
-	Text             string    `json:"text,omitempty" bson:"text"`
-	URL              string    `json:"url,omitempty" bson:"url"`
-	Votes            []Vote    `json:"votes" bson:"votes"`
-	Comments         []Comment `json:"comments" bson:"comments"`
-	Created          string    `json:"created" bson:"created"`
-	UpvotePercentage int       `json:"upvotePercentage" bson:"upvotePercentage"`
+	ID           string    `json:"id" bson:"id"`
+	Views        int       `json:"views" bson:"views"`
+	Type         string    `json:"type" bson:"type"`
+	Title        string    `json:"title" bson:"title"`
+	Author       Author    `json:"author" bson:"author"`
+	Category     string    `json:"category" bson:"category"`
+	Text         string    `json:"text,omitempty" bson:"text"`
+	URL          string    `json:"url,omitempty" bson:"url"`
+	Comments     []Comment `json:"comments,omitempty" bson:"-"`
+	CommentCount int       `json:"commentCount" bson:"commentCount"`
+	Created      string    `json:"created" bson:"created"`
+	Edited       string    `json:"edited,omitempty" bson:"edited,omitempty"`
+	Removed      *Removal  `json:"removed,omitempty" bson:"removed,omitempty"`
+	Locked       bool      `json:"locked,omitempty" bson:"locked,omitempty"`
+	Ranks        PostRanks `json:"-" bson:",inline"`
+
+	Voting `bson:",inline"`
 }
 
 func NewTextPost(postID string, input TextPostInput, author Author) Post {
-	return Post{
-		Score:            0,
-		Views:            0,
-		Type:             input.Type,
-		Title:            input.Title,
-		Author:           author,
-		Category:         input.Category,
-		Text:             input.Text,
-		Votes:            make([]Vote, 0),
-		Comments:         make([]Comment, 0),
-		Created:          time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
-		UpvotePercentage: 0,
-		ID:               postID,
+	post := Post{
+		Voting:       NewVoting(),
+		Views:        0,
+		Type:         input.Type,
+		Title:        input.Title,
+		Author:       author,
+		Category:     input.Category,
+		Text:         input.Text,
+		CommentCount: 0,
+		Created:      time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
+		ID:           postID,
 	}
+	post.RecalculateRanks()
+	return post
 }
 
 func NewURLPost(postID string, input URLPostInput, author Author) Post {
-	return Post{
-		Score:            0,
-		Views:            0,
-		Type:             input.Type,
-		Title:            input.Title,
-		Author:           author,
-		Category:         input.Category,
-		URL:              input.URL,
-		Votes:            make([]Vote, 0),
-		Comments:         make([]Comment, 0),
-		Created:          time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
-		UpvotePercentage: 0,
-		ID:               postID,
+	post := Post{
+		Voting:       NewVoting(),
+		Views:        0,
+		Type:         input.Type,
+		Title:        input.Title,
+		Author:       author,
+		Category:     input.Category,
+		URL:          input.URL,
+		CommentCount: 0,
+		Created:      time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
+		ID:           postID,
 	}
+	post.RecalculateRanks()
+	return post
 }
 
-func (p *Post) Upvote(userID string) *Post {
-	found := false
-	for i, vote := range p.Votes {
-		if vote.UserID == userID {
-			p.Votes[i].Vote = 1
-			p.Score += 2
-			found = true
-			break
-		}
-	}
-	if !found {
-		p.Votes = append(p.Votes, Vote{UserID: userID, Vote: 1})
-		p.Score += 1
+func (p *Post) RecalculateRanks() *Post {
+	created, _ := time.Parse("2006-01-02T15:04:05.000Z", p.Created)
+	p.Ranks = PostRanks{
+		Hot:           HotRank(p.Ups, p.Downs, created),
+		Controversial: ControversialRank(p.Ups, p.Downs),
 	}
 	return p
 }
 
-func (p *Post) Downvote(userID string) *Post {
-	found := false
-	for i, vote := range p.Votes {
-		if vote.UserID == userID {
-			p.Votes[i].Vote = -1
-			p.Score -= 2
-			found = true
-			break
-		}
-	}
-	if !found {
-		p.Votes = append(p.Votes, Vote{UserID: userID, Vote: -1})
-		p.Score -= 1
+// Edit replaces the title and the text, the empty ones are kept, and returns the replaced version
+func (p *Post) Edit(input PostUpdateInput) PostRevision {
+	revision := PostRevision{Title: p.Title, Text: p.Text, Created: p.Created}
+	if p.Edited != "" {
+		revision.Created = p.Edited
 	}
-	return p
-}
-
-func (p *Post) Unvote(userID string) *Post {
-	for i, vote := range p.Votes {
-		if vote.UserID == userID {
-			p.Votes = append(p.Votes[:i], p.Votes[i+1:]...)
-			p.Score -= vote.Vote
-			break
-		}
-	}
-	return p
-}
 
-func (p *Post) RecalculatePercentage() *Post {
-	upvotes := 0
-	for _, vote := range p.Votes {
-		if vote.Vote == 1 {
-			upvotes++
-		}
+	if input.Title != "" {
+		p.Title = input.Title
 	}
-	if len(p.Votes) == 0 {
-		p.UpvotePercentage = 0
-	} else {
-		p.UpvotePercentage = 100 * upvotes / len(p.Votes)
+	if input.Text != "" {
+		p.Text = input.Text
 	}
-	return p
+	p.Edited = time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+
+	return revision
 }
@@ -0,0 +1,50 @@
+package model
+
+const (
+	ActivityPost    = "post"
+	ActivityComment = "comment"
+)
+
+const RecentActivityLimit = 10
+
+// Activity is a post or a comment in the recent activity of a profile
+type Activity struct {
+	Type      string `json:"type"`
+	PostID    string `json:"postId"`
+	CommentID string `json:"commentId,omitempty"`
+	Title     string `json:"title,omitempty"`
+	Body      string `json:"body,omitempty"`
+	Score     int    `json:"score"`
+	Created   string `json:"created"`
+}
+
+func NewPostActivity(post Post) Activity {
+	return Activity{
+		Type:    ActivityPost,
+		PostID:  post.ID,
+		Title:   post.Title,
+		Score:   post.Score,
+		Created: post.Created,
+	}
+}
+
+func NewCommentActivity(comment Comment) Activity {
+	return Activity{
+		Type:      ActivityComment,
+		PostID:    comment.PostID,
+		CommentID: comment.ID,
+		Body:      comment.Body,
+		Score:     comment.Score,
+		Created:   comment.Created,
+	}
+}
+
+type Profile struct {
+	ID             string     `json:"id"`
+	Username       string     `json:"username"`
+	Created        string     `json:"created"`
+	Karma          Karma      `json:"karma"`
+	PostCount      int        `json:"postCount"`
+	CommentCount   int        `json:"commentCount"`
+	RecentActivity []Activity `json:"recentActivity"`
+}
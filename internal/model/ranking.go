@@ -0,0 +1,80 @@
+package model
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	SortHot           = "hot"
+	SortNew           = "new"
+	SortTop           = "top"
+	SortRising        = "rising"
+	SortControversial = "controversial"
+)
+
+var Sorts = []string{SortHot, SortNew, SortTop, SortRising, SortControversial}
+
+const (
+	TopWindowHour  = "hour"
+	TopWindowDay   = "day"
+	TopWindowWeek  = "week"
+	TopWindowMonth = "month"
+	TopWindowYear  = "year"
+	TopWindowAll   = "all"
+)
+
+var TopWindows = map[string]time.Duration{
+	TopWindowHour:  time.Hour,
+	TopWindowDay:   24 * time.Hour,
+	TopWindowWeek:  7 * 24 * time.Hour,
+	TopWindowMonth: 30 * 24 * time.Hour,
+	TopWindowYear:  365 * 24 * time.Hour,
+	TopWindowAll:   0,
+}
+
+// RisingWindow limits rising to the fresh posts, they are ranked by hot
+const RisingWindow = 6 * time.Hour
+
+const (
+	HotEpoch = 1134028003
+	HotDecay = 45000
+)
+
+// PostRanks are stored along with the post so the feeds can be sorted by an index
+type PostRanks struct {
+	Hot           float64 `bson:"hot"`
+	Controversial float64 `bson:"controversial"`
+}
+
+// HotRank grows with the order of the score, every 12.5 hours of age weigh as much as 10 times the score
+func HotRank(ups, downs int, created time.Time) float64 {
+	score := ups - downs
+	order := math.Log10(math.Max(math.Abs(float64(score)), 1))
+
+	sign := 0.0
+	if score > 0 {
+		sign = 1
+	} else if score < 0 {
+		sign = -1
+	}
+
+	seconds := float64(created.Unix() - HotEpoch)
+
+	return sign*order + seconds/HotDecay
+}
+
+// ControversialRank is high for posts with many votes split evenly
+func ControversialRank(ups, downs int) float64 {
+	if ups <= 0 || downs <= 0 {
+		return 0
+	}
+
+	magnitude := float64(ups + downs)
+	balance := float64(downs) / float64(ups)
+	if ups < downs {
+		balance = float64(ups) / float64(downs)
+	}
+
+	return math.Pow(magnitude, balance)
+}
@@ -0,0 +1,62 @@
+package model
+
+import "time"
+
+const (
+	RemovedContentPlaceholder = "[removed]"
+	MaxRemovalReasonLen       = 255
+)
+
+// Removal marks the content taken down by a moderator, the content is kept for the moderators
+type Removal struct {
+	Moderator *Author `json:"moderator,omitempty" bson:"moderator"`
+	Reason    string  `json:"reason,omitempty" bson:"reason"`
+	Created   string  `json:"created" bson:"created"`
+}
+
+func NewRemoval(moderator Author, reason string) *Removal {
+	return &Removal{
+		Moderator: &moderator,
+		Reason:    reason,
+		Created:   time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
+	}
+}
+
+// redacted keeps only the time of the removal
+func (r *Removal) redacted() *Removal {
+	return &Removal{Created: r.Created}
+}
+
+// Redacted hides the removed content and who removed it, the comments of the post included
+func (c Comment) Redacted() Comment {
+	if c.Removed != nil {
+		c.Body = RemovedContentPlaceholder
+		c.Removed = c.Removed.redacted()
+	}
+	return c
+}
+
+func (p Post) Redacted() Post {
+	if p.Removed != nil {
+		p.Title = RemovedContentPlaceholder
+		p.Text = RemovedContentPlaceholder
+		p.URL = ""
+		p.Removed = p.Removed.redacted()
+	}
+	if p.Comments != nil {
+		comments := make([]Comment, len(p.Comments))
+		for i, comment := range p.Comments {
+			comments[i] = comment.Redacted()
+		}
+		p.Comments = comments
+	}
+	return p
+}
+
+func RedactPosts(posts []Post) []Post {
+	redacted := make([]Post, len(posts))
+	for i, post := range posts {
+		redacted[i] = post.Redacted()
+	}
+	return redacted
+}
@@ -0,0 +1,86 @@
+package model
+
+import "time"
+
+const (
+	ReportReasonSpam           = "spam"
+	ReportReasonHarassment     = "harassment"
+	ReportReasonHate           = "hate"
+	ReportReasonMisinformation = "misinformation"
+	ReportReasonOther          = "other"
+
+	MaxReportTextLen = 500
+)
+
+var ReportReasons = [...]string{ReportReasonSpam, ReportReasonHarassment, ReportReasonHate, ReportReasonMisinformation, ReportReasonOther}
+
+const (
+	ReportStatusOpen      = "open"
+	ReportStatusApproved  = "approved"
+	ReportStatusRemoved   = "removed"
+	ReportStatusDismissed = "dismissed"
+)
+
+// ReportResolutions are the actions a moderator takes on the reported item, named as in the request body
+var ReportResolutions = map[string]string{
+	"approve": ReportStatusApproved,
+	"remove":  ReportStatusRemoved,
+	"dismiss": ReportStatusDismissed,
+}
+
+// Report is a single user's flag on a post or a comment, a user has at most one open report per item
+type Report struct {
+	ID         string  `json:"id" bson:"id"`
+	PostID     string  `json:"post_id" bson:"postId"`
+	CommentID  string  `json:"comment_id,omitempty" bson:"commentId"`
+	Community  string  `json:"community" bson:"community"`
+	Reporter   Author  `json:"reporter" bson:"reporter"`
+	Reason     string  `json:"reason" bson:"reason"`
+	Text       string  `json:"text,omitempty" bson:"text"`
+	Status     string  `json:"status" bson:"status"`
+	ResolvedBy *Author `json:"resolved_by,omitempty" bson:"resolvedBy,omitempty"`
+	Resolved   string  `json:"resolved,omitempty" bson:"resolved,omitempty"`
+	Created    string  `json:"created" bson:"created"`
+}
+
+func NewReport(reportID string, post Post, commentID string, reporter Author, reason string, text string) Report {
+	return Report{
+		ID:        reportID,
+		PostID:    post.ID,
+		CommentID: commentID,
+		Community: post.Category,
+		Reporter:  reporter,
+		Reason:    reason,
+		Text:      text,
+		Status:    ReportStatusOpen,
+		Created:   time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
+	}
+}
+
+type ReportsQuery struct {
+	Community string
+	Limit     int
+}
+
+// ReportedItem is an entry of the moderator queue, the open reports of a post or a comment,
+// the content is filled by the service
+type ReportedItem struct {
+	PostID       string   `json:"post_id" bson:"postId"`
+	CommentID    string   `json:"comment_id,omitempty" bson:"commentId"`
+	Community    string   `json:"community" bson:"community"`
+	Count        int      `json:"count" bson:"count"`
+	LastReported string   `json:"last_reported" bson:"lastReported"`
+	Reports      []Report `json:"reports" bson:"reports"`
+	Post         *Post    `json:"post,omitempty" bson:"-"`
+	Comment      *Comment `json:"comment,omitempty" bson:"-"`
+}
+
+// ReportResolution is the result of a moderator action on the reported item
+type ReportResolution struct {
+	PostID    string `json:"post_id"`
+	CommentID string `json:"comment_id,omitempty"`
+	Status    string `json:"status"`
+	Resolved  int    `json:"resolved"`
+	Moderator Author `json:"moderator"`
+	Created   string `json:"created"`
+}
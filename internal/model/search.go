@@ -0,0 +1,92 @@
+package model
+
+const (
+	SearchSortRelevance = "relevance"
+	SearchSortNew       = "new"
+)
+
+var SearchSorts = []string{SearchSortRelevance, SearchSortNew}
+
+const (
+	SearchResultPost    = "post"
+	SearchResultComment = "comment"
+)
+
+// SearchQuery looks the text up in the posts and the comments, From and To bound the creation time
+type SearchQuery struct {
+	Text      string
+	Community string
+	Author    string
+	From      string
+	To        string
+	Sort      string
+	Limit     int
+	Cursor    string
+}
+
+// SearchCursor counts the posts and the comments already returned,
+// both kinds are searched apart and merged page by page
+type SearchCursor struct {
+	Sort     string `json:"sort"`
+	Posts    int    `json:"posts"`
+	Comments int    `json:"comments"`
+}
+
+type SearchResult struct {
+	Type      string   `json:"type"`
+	Relevance float64  `json:"relevance"`
+	Post      *Post    `json:"post,omitempty"`
+	Comment   *Comment `json:"comment,omitempty"`
+}
+
+func NewPostResult(post Post, relevance float64) SearchResult {
+	return SearchResult{Type: SearchResultPost, Relevance: relevance, Post: &post}
+}
+
+func NewCommentResult(comment Comment, relevance float64) SearchResult {
+	return SearchResult{Type: SearchResultComment, Relevance: relevance, Comment: &comment}
+}
+
+func (r SearchResult) Created() string {
+	if r.Post != nil {
+		return r.Post.Created
+	}
+	return r.Comment.Created
+}
+
+func (r SearchResult) ID() string {
+	if r.Post != nil {
+		return r.Post.ID
+	}
+	return r.Comment.ID
+}
+
+// SearchPrecedes reports whether the first result goes before the second one in the sort mode
+func SearchPrecedes(a, b SearchResult, sort string) bool {
+	if sort != SearchSortNew && a.Relevance != b.Relevance {
+		return a.Relevance > b.Relevance
+	}
+	if a.Created() != b.Created() {
+		return a.Created() > b.Created()
+	}
+	return a.ID() > b.ID()
+}
+
+// Matches applies the filters of the query except the text and the community
+func (q SearchQuery) Matches(author Author, created string) bool {
+	if q.Author != "" && author.Username != q.Author {
+		return false
+	}
+	if q.From != "" && created < q.From {
+		return false
+	}
+	if q.To != "" && created >= q.To {
+		return false
+	}
+	return true
+}
+
+type SearchPage struct {
+	Results    []SearchResult `json:"results"`
+	NextCursor string         `json:"next_cursor"`
+}
@@ -1,11 +1,43 @@
 package model
 
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+var Roles = [...]string{RoleUser, RoleModerator, RoleAdmin}
+
 type Credential struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
 }
 
+// Karma sums the scores of the user's posts and comments, it is updated on every vote
+type Karma struct {
+	Post    int `json:"post"`
+	Comment int `json:"comment"`
+}
+
 type User struct {
 	ID string `json:"id"`
 	Credential
+	Role    string `json:"role"`
+	Created string `json:"created"`
+	Karma   Karma  `json:"karma"`
+	// Suspension is kept after it expires until it is lifted or replaced
+	Suspension *Suspension `json:"suspension,omitempty"`
+}
+
+func (u User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
+
+// IsModerator is true for admins as well, they have every moderator permission
+func (u User) IsModerator() bool {
+	return u.Role == RoleModerator || u.Role == RoleAdmin
+}
+
+func (u User) IsSuspended() bool {
+	return u.Suspension.IsActive()
 }
@@ -0,0 +1,84 @@
+package model
+
+const (
+	VoteUp   = 1
+	VoteDown = -1
+	VoteNone = 0
+)
+
+// Vote is stored apart from the voted post or comment, CommentID is empty for a post vote
+type Vote struct {
+	UserID    string `json:"user" bson:"user"`
+	PostID    string `json:"postId" bson:"postId"`
+	CommentID string `json:"commentId,omitempty" bson:"commentId"`
+	Vote      int    `json:"vote" bson:"vote"`
+	Created   string `json:"created" bson:"created"`
+}
+
+func (v Vote) IsPostVote() bool {
+	return v.CommentID == ""
+}
+
+// Voting is the score of anything users vote on, posts and comments embed it.
+// Only the counts are stored, Votes is filled with the vote of the caller for the clients looking their vote up there.
+type Voting struct {
+	Score            int    `json:"score" bson:"score"`
+	Ups              int    `json:"ups" bson:"ups"`
+	Downs            int    `json:"downs" bson:"downs"`
+	UpvotePercentage int    `json:"upvotePercentage" bson:"upvotePercentage"`
+	Votes            []Vote `json:"votes" bson:"-"`
+}
+
+func NewVoting() Voting {
+	return Voting{
+		Score:            0,
+		Ups:              0,
+		Downs:            0,
+		UpvotePercentage: 0,
+		Votes:            make([]Vote, 0),
+	}
+}
+
+// VoteDelta is the change of the ups and the downs when a user replaces the previous vote
+func VoteDelta(previous, value int) (int, int) {
+	ups, downs := 0, 0
+	switch previous {
+	case VoteUp:
+		ups--
+	case VoteDown:
+		downs--
+	}
+	switch value {
+	case VoteUp:
+		ups++
+	case VoteDown:
+		downs++
+	}
+	return ups, downs
+}
+
+// AddCounts applies a VoteDelta, the stores apply the same rule atomically
+func (v *Voting) AddCounts(ups, downs int) *Voting {
+	v.Ups += ups
+	v.Downs += downs
+	return v.Recalculate()
+}
+
+func (v *Voting) Recalculate() *Voting {
+	v.Score = v.Ups - v.Downs
+	if v.Ups+v.Downs == 0 {
+		v.UpvotePercentage = 0
+	} else {
+		v.UpvotePercentage = 100 * v.Ups / (v.Ups + v.Downs)
+	}
+	return v
+}
+
+// SetUserVote shows the caller their own vote, VoteNone leaves no vote
+func (v *Voting) SetUserVote(vote Vote) *Voting {
+	v.Votes = make([]Vote, 0, 1)
+	if vote.Vote != VoteNone {
+		v.Votes = append(v.Votes, vote)
+	}
+	return v
+}
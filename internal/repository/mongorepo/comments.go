@@ -0,0 +1,195 @@
+package mongorepo
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"redditclone/internal/model"
+	"redditclone/internal/model/customerr"
+)
+
+type commentsRepo struct {
+	comments *mongo.Collection
+}
+
+func NewCommentsRepo(collection *mongo.Collection) *commentsRepo {
+	return &commentsRepo{comments: collection}
+}
+
+func (r *commentsRepo) CreateIndexes() error {
+	_, err := r.comments.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "postId", Value: 1}, {Key: "created", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "author.username", Value: 1}, {Key: "created", Value: -1}},
+		},
+	})
+	return err
+}
+
+func (r *commentsRepo) AddComment(comment model.Comment) error {
+	_, err := r.comments.InsertOne(context.TODO(), comment)
+	return err
+}
+
+func (r *commentsRepo) GetCommentByID(postID, commentID string) (model.Comment, error) {
+	var comment model.Comment
+	filter := bson.M{"postId": postID, "id": commentID}
+	err := r.comments.FindOne(context.TODO(), filter).Decode(&comment)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return model.Comment{}, customerr.CommentNotFoundByID{PostID: postID, CommentID: commentID}
+		}
+		return model.Comment{}, err
+	}
+	return comment, nil
+}
+
+func (r *commentsRepo) GetCommentsByPost(postID string) ([]model.Comment, error) {
+	comments := make([]model.Comment, 0)
+	filter := bson.M{"postId": postID}
+	opt := options.Find().SetSort(bson.D{{Key: "created", Value: 1}})
+	cursor, err := r.comments.Find(context.TODO(), filter, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	for cursor.Next(context.TODO()) {
+		var comment model.Comment
+		err = cursor.Decode(&comment)
+		if err != nil {
+			return nil, err
+		}
+
+		comments = append(comments, comment)
+	}
+
+	return comments, nil
+}
+
+// GetCommentsByAuthor returns the latest comments of the user, the deleted ones have no author
+func (r *commentsRepo) GetCommentsByAuthor(username string, limit int) ([]model.Comment, error) {
+	comments := make([]model.Comment, 0)
+	filter := bson.M{"author.username": username}
+	opt := options.Find().
+		SetSort(bson.D{{Key: "created", Value: -1}}).
+		SetLimit(int64(limit))
+	cursor, err := r.comments.Find(context.TODO(), filter, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	for cursor.Next(context.TODO()) {
+		var comment model.Comment
+		err = cursor.Decode(&comment)
+		if err != nil {
+			return nil, err
+		}
+
+		comments = append(comments, comment)
+	}
+
+	return comments, nil
+}
+
+func (r *commentsRepo) CountCommentsByAuthor(username string) (int, error) {
+	filter := bson.M{"author.username": username}
+	count, err := r.comments.CountDocuments(context.TODO(), filter)
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+func (r *commentsRepo) DeleteComment(postID, commentID string) error {
+	filter := bson.M{"postId": postID, "id": commentID}
+	res, err := r.comments.DeleteOne(context.TODO(), filter)
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return customerr.CommentNotFoundByID{PostID: postID, CommentID: commentID}
+	}
+	return nil
+}
+
+// MarkCommentDeleted matches only a comment which is not deleted yet, so one of concurrent deletions wins
+func (r *commentsRepo) MarkCommentDeleted(postID, commentID string) error {
+	filter := bson.M{"postId": postID, "id": commentID, "deleted": bson.M{"$ne": true}}
+	update := bson.M{"$set": bson.M{
+		"author":  model.Author{Username: model.DeletedCommentPlaceholder},
+		"body":    model.DeletedCommentPlaceholder,
+		"deleted": true,
+	}}
+	res, err := r.comments.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return customerr.CommentNotFoundByID{PostID: postID, CommentID: commentID}
+	}
+	return nil
+}
+
+// RemoveComment gives CommentChanged for a comment already removed by another request
+func (r *commentsRepo) RemoveComment(postID, commentID string, removal *model.Removal) error {
+	filter := bson.M{"postId": postID, "id": commentID, "removed": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"removed": removal}}
+	res, err := r.comments.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount != 0 {
+		return nil
+	}
+
+	count, err := r.comments.CountDocuments(context.TODO(), bson.M{"postId": postID, "id": commentID})
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return customerr.CommentNotFoundByID{PostID: postID, CommentID: commentID}
+	}
+	return customerr.CommentChanged{PostID: postID, CommentID: commentID}
+}
+
+func (r *commentsRepo) EditComment(comment model.Comment) error {
+	filter := bson.M{"postId": comment.PostID, "id": comment.ID}
+	update := bson.M{"$set": bson.M{"body": comment.Body, "edited": comment.Edited}}
+	res, err := r.comments.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return customerr.CommentNotFoundByID{PostID: comment.PostID, CommentID: comment.ID}
+	}
+	return nil
+}
+
+func (r *commentsRepo) UpdateCommentVoteCounts(postID, commentID string, ups, downs int) (model.Comment, error) {
+	var comment model.Comment
+	pipeline := countsPipeline(ups, downs)
+
+	filter := bson.M{"postId": postID, "id": commentID}
+	opt := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := r.comments.FindOneAndUpdate(context.TODO(), filter, pipeline, opt).Decode(&comment)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return model.Comment{}, customerr.CommentNotFoundByID{PostID: postID, CommentID: commentID}
+		}
+		return model.Comment{}, err
+	}
+	return comment, nil
+}
+
+func (r *commentsRepo) DeletePostComments(postID string) error {
+	filter := bson.M{"postId": postID}
+	_, err := r.comments.DeleteMany(context.TODO(), filter)
+	return err
+}
@@ -0,0 +1,633 @@
+package mongorepo
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"redditclone/internal/model"
+	"redditclone/internal/model/customerr"
+	"reflect"
+	"testing"
+)
+
+func marshalComment(comment model.Comment) bson.D {
+	bsonData, _ := bson.Marshal(comment)
+
+	var bsonD bson.D
+	_ = bson.Unmarshal(bsonData, &bsonD)
+
+	return bsonD
+}
+
+func TestAddComment(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	cases := []struct {
+		expectedErr error
+		run         func() error
+	}{
+		{
+			expectedErr: nil,
+			run: func() error {
+				var err error
+				mt.Run("success", func(mt *mtest.T) {
+					repo := NewCommentsRepo(mt.Coll)
+					mt.AddMockResponses(mtest.CreateSuccessResponse())
+					err = repo.AddComment(model.Comment{ID: "1", PostID: "1"})
+				})
+				return err
+			},
+		},
+		{
+			expectedErr: mongo.CommandError{Message: "command failed"},
+			run: func() error {
+				var err error
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewCommentsRepo(mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					err = repo.AddComment(model.Comment{ID: "1", PostID: "1"})
+				})
+				return err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+	}
+}
+
+func TestGetCommentByID(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	cases := []struct {
+		expectedComment model.Comment
+		expectedErr     error
+		run             func(comment model.Comment) (model.Comment, error)
+	}{
+		{
+			expectedComment: model.Comment{ID: "1", PostID: "1"},
+			expectedErr:     nil,
+			run: func(comment model.Comment) (model.Comment, error) {
+				var err error
+				mt.Run("success", func(mt *mtest.T) {
+					repo := NewCommentsRepo(mt.Coll)
+					mt.AddMockResponses(
+						mtest.CreateCursorResponse(1, "redditclone.comments", mtest.FirstBatch, marshalComment(comment)),
+					)
+					comment, err = repo.GetCommentByID("1", "1")
+				})
+				return comment, err
+			},
+		},
+		{
+			expectedComment: model.Comment{},
+			expectedErr:     mongo.CommandError{Message: "command failed"},
+			run: func(comment model.Comment) (model.Comment, error) {
+				var err error
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewCommentsRepo(mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					comment, err = repo.GetCommentByID("1", "1")
+				})
+				return comment, err
+			},
+		},
+		{
+			expectedComment: model.Comment{},
+			expectedErr:     customerr.CommentNotFoundByID{PostID: "1", CommentID: "1"},
+			run: func(comment model.Comment) (model.Comment, error) {
+				var err error
+				mt.Run("comment not found", func(mt *mtest.T) {
+					repo := NewCommentsRepo(mt.Coll)
+					mt.AddMockResponses(
+						mtest.CreateCursorResponse(1, "redditclone.comments", mtest.FirstBatch),
+						mtest.CreateCursorResponse(0, "redditclone.comments", mtest.NextBatch),
+					)
+					comment, err = repo.GetCommentByID("1", "1")
+				})
+				return comment, err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		comment, err := item.run(item.expectedComment)
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+		if !reflect.DeepEqual(item.expectedComment, comment) {
+			t.Errorf("[%d] expected comment: %+v, got: %+v", i, item.expectedComment, comment)
+		}
+	}
+}
+
+func TestGetCommentsByPost(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	cases := []struct {
+		expectedComments []model.Comment
+		expectedErr      error
+		run              func(comments []model.Comment) ([]model.Comment, error)
+	}{
+		{
+			expectedComments: []model.Comment{
+				{ID: "1", PostID: "1"},
+				{ID: "2", PostID: "1", ParentID: "1", Depth: 1},
+			},
+			expectedErr: nil,
+			run: func(comments []model.Comment) ([]model.Comment, error) {
+				var err error
+				mt.Run("success", func(mt *mtest.T) {
+					repo := NewCommentsRepo(mt.Coll)
+					mt.AddMockResponses(
+						mtest.CreateCursorResponse(1, "redditclone.comments", mtest.FirstBatch, marshalComment(comments[0])),
+						mtest.CreateCursorResponse(1, "redditclone.comments", mtest.NextBatch, marshalComment(comments[1])),
+						mtest.CreateCursorResponse(0, "redditclone.comments", mtest.NextBatch),
+					)
+					comments, err = repo.GetCommentsByPost("1")
+				})
+				return comments, err
+			},
+		},
+		{
+			expectedComments: nil,
+			expectedErr:      mongo.CommandError{Message: "command failed"},
+			run: func(comments []model.Comment) ([]model.Comment, error) {
+				var err error
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewCommentsRepo(mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					comments, err = repo.GetCommentsByPost("1")
+				})
+				return comments, err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		comments, err := item.run(item.expectedComments)
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+		if !reflect.DeepEqual(item.expectedComments, comments) {
+			t.Errorf("[%d] expected comments: %+v, got: %+v", i, item.expectedComments, comments)
+		}
+	}
+}
+
+func TestDeleteComment(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	cases := []struct {
+		expectedErr error
+		run         func() error
+	}{
+		{
+			expectedErr: nil,
+			run: func() error {
+				var err error
+				mt.Run("success", func(mt *mtest.T) {
+					repo := NewCommentsRepo(mt.Coll)
+					mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}))
+					err = repo.DeleteComment("1", "1")
+				})
+				return err
+			},
+		},
+		{
+			expectedErr: customerr.CommentNotFoundByID{PostID: "1", CommentID: "1"},
+			run: func() error {
+				var err error
+				mt.Run("comment not found", func(mt *mtest.T) {
+					repo := NewCommentsRepo(mt.Coll)
+					mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}))
+					err = repo.DeleteComment("1", "1")
+				})
+				return err
+			},
+		},
+		{
+			expectedErr: mongo.CommandError{Message: "command failed"},
+			run: func() error {
+				var err error
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewCommentsRepo(mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					err = repo.DeleteComment("1", "1")
+				})
+				return err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+	}
+}
+
+func TestMarkCommentDeleted(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	cases := []struct {
+		expectedErr error
+		run         func() error
+	}{
+		{
+			expectedErr: nil,
+			run: func() error {
+				var err error
+				mt.Run("success", func(mt *mtest.T) {
+					repo := NewCommentsRepo(mt.Coll)
+					mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}))
+					err = repo.MarkCommentDeleted("1", "1")
+				})
+				return err
+			},
+		},
+		{
+			expectedErr: customerr.CommentNotFoundByID{PostID: "1", CommentID: "1"},
+			run: func() error {
+				var err error
+				mt.Run("comment not found", func(mt *mtest.T) {
+					repo := NewCommentsRepo(mt.Coll)
+					mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}))
+					err = repo.MarkCommentDeleted("1", "1")
+				})
+				return err
+			},
+		},
+		{
+			expectedErr: mongo.CommandError{Message: "command failed"},
+			run: func() error {
+				var err error
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewCommentsRepo(mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					err = repo.MarkCommentDeleted("1", "1")
+				})
+				return err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+	}
+}
+
+func TestRemoveComment(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	cases := []struct {
+		expectedErr error
+		run         func() error
+	}{
+		{
+			expectedErr: nil,
+			run: func() error {
+				var err error
+				mt.Run("success", func(mt *mtest.T) {
+					repo := NewCommentsRepo(mt.Coll)
+					mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}))
+					err = repo.RemoveComment("1", "1", model.NewRemoval(model.Author{ID: "2", Username: "admin"}, "spam"))
+				})
+				return err
+			},
+		},
+		{
+			expectedErr: customerr.CommentNotFoundByID{PostID: "1", CommentID: "1"},
+			run: func() error {
+				var err error
+				mt.Run("comment not found", func(mt *mtest.T) {
+					repo := NewCommentsRepo(mt.Coll)
+					mt.AddMockResponses(
+						mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}),
+						mtest.CreateCursorResponse(0, "redditclone.comments", mtest.FirstBatch),
+					)
+					err = repo.RemoveComment("1", "1", model.NewRemoval(model.Author{ID: "2", Username: "admin"}, "spam"))
+				})
+				return err
+			},
+		},
+		{
+			expectedErr: customerr.CommentChanged{PostID: "1", CommentID: "1"},
+			run: func() error {
+				var err error
+				mt.Run("comment already removed", func(mt *mtest.T) {
+					repo := NewCommentsRepo(mt.Coll)
+					mt.AddMockResponses(
+						mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}),
+						mtest.CreateCursorResponse(0, "redditclone.comments", mtest.FirstBatch, bson.D{{Key: "n", Value: 1}}),
+					)
+					err = repo.RemoveComment("1", "1", model.NewRemoval(model.Author{ID: "2", Username: "admin"}, "spam"))
+				})
+				return err
+			},
+		},
+		{
+			expectedErr: mongo.CommandError{Message: "command failed"},
+			run: func() error {
+				var err error
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewCommentsRepo(mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					err = repo.RemoveComment("1", "1", model.NewRemoval(model.Author{ID: "2", Username: "admin"}, "spam"))
+				})
+				return err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+	}
+}
+
+func TestDeletePostComments(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	cases := []struct {
+		expectedErr error
+		run         func() error
+	}{
+		{
+			expectedErr: nil,
+			run: func() error {
+				var err error
+				mt.Run("success", func(mt *mtest.T) {
+					repo := NewCommentsRepo(mt.Coll)
+					mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 2}))
+					err = repo.DeletePostComments("1")
+				})
+				return err
+			},
+		},
+		{
+			expectedErr: mongo.CommandError{Message: "command failed"},
+			run: func() error {
+				var err error
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewCommentsRepo(mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					err = repo.DeletePostComments("1")
+				})
+				return err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+	}
+}
+
+func TestEditComment(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	cases := []struct {
+		expectedErr error
+		run         func() error
+	}{
+		{
+			expectedErr: nil,
+			run: func() error {
+				var err error
+				mt.Run("success", func(mt *mtest.T) {
+					repo := NewCommentsRepo(mt.Coll)
+					mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}))
+					err = repo.EditComment(model.Comment{ID: "1", PostID: "1", Body: "edited"})
+				})
+				return err
+			},
+		},
+		{
+			expectedErr: customerr.CommentNotFoundByID{PostID: "1", CommentID: "1"},
+			run: func() error {
+				var err error
+				mt.Run("comment not found", func(mt *mtest.T) {
+					repo := NewCommentsRepo(mt.Coll)
+					mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}))
+					err = repo.EditComment(model.Comment{ID: "1", PostID: "1", Body: "edited"})
+				})
+				return err
+			},
+		},
+		{
+			expectedErr: mongo.CommandError{Message: "command failed"},
+			run: func() error {
+				var err error
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewCommentsRepo(mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					err = repo.EditComment(model.Comment{ID: "1", PostID: "1", Body: "edited"})
+				})
+				return err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+	}
+}
+
+func TestUpdateCommentVoteCounts(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	voted := model.Comment{ID: "1", PostID: "1", Voting: model.Voting{Score: 1, Ups: 1, UpvotePercentage: 100}}
+
+	cases := []struct {
+		expectedComment model.Comment
+		expectedErr     error
+		run             func(comment model.Comment) (model.Comment, error)
+	}{
+		{
+			expectedComment: voted,
+			expectedErr:     nil,
+			run: func(comment model.Comment) (model.Comment, error) {
+				var err error
+				mt.Run("success", func(mt *mtest.T) {
+					repo := NewCommentsRepo(mt.Coll)
+					mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: marshalComment(comment)}))
+					comment, err = repo.UpdateCommentVoteCounts("1", "1", 1, 0)
+				})
+				return comment, err
+			},
+		},
+		{
+			expectedComment: model.Comment{},
+			expectedErr:     customerr.CommentNotFoundByID{PostID: "1", CommentID: "1"},
+			run: func(comment model.Comment) (model.Comment, error) {
+				var err error
+				mt.Run("comment not found", func(mt *mtest.T) {
+					repo := NewCommentsRepo(mt.Coll)
+					mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil}))
+					comment, err = repo.UpdateCommentVoteCounts("1", "1", 1, 0)
+				})
+				return comment, err
+			},
+		},
+		{
+			expectedComment: model.Comment{},
+			expectedErr:     mongo.CommandError{Message: "command failed"},
+			run: func(comment model.Comment) (model.Comment, error) {
+				var err error
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewCommentsRepo(mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					comment, err = repo.UpdateCommentVoteCounts("1", "1", 1, 0)
+				})
+				return comment, err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		comment, err := item.run(item.expectedComment)
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+		if !reflect.DeepEqual(item.expectedComment, comment) {
+			t.Errorf("[%d] expected comment: %+v, got: %+v", i, item.expectedComment, comment)
+		}
+	}
+}
+
+func TestGetCommentsByAuthor(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	cases := []struct {
+		expectedComments []model.Comment
+		expectedErr      error
+		run              func(comments []model.Comment) ([]model.Comment, error)
+	}{
+		{
+			expectedComments: []model.Comment{
+				{ID: "2", PostID: "1", Author: model.Author{ID: "1", Username: "van"}},
+				{ID: "1", PostID: "2", Author: model.Author{ID: "1", Username: "van"}},
+			},
+			expectedErr: nil,
+			run: func(comments []model.Comment) ([]model.Comment, error) {
+				var err error
+				mt.Run("success", func(mt *mtest.T) {
+					repo := NewCommentsRepo(mt.Coll)
+					mt.AddMockResponses(
+						mtest.CreateCursorResponse(1, "redditclone.comments", mtest.FirstBatch, marshalComment(comments[0])),
+						mtest.CreateCursorResponse(1, "redditclone.comments", mtest.NextBatch, marshalComment(comments[1])),
+						mtest.CreateCursorResponse(0, "redditclone.comments", mtest.NextBatch),
+					)
+					comments, err = repo.GetCommentsByAuthor("van", 10)
+				})
+				return comments, err
+			},
+		},
+		{
+			expectedComments: nil,
+			expectedErr:      mongo.CommandError{Message: "command failed"},
+			run: func(comments []model.Comment) ([]model.Comment, error) {
+				var err error
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewCommentsRepo(mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					comments, err = repo.GetCommentsByAuthor("van", 10)
+				})
+				return comments, err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		comments, err := item.run(item.expectedComments)
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+		if !reflect.DeepEqual(item.expectedComments, comments) {
+			t.Errorf("[%d] expected comments: %+v, got: %+v", i, item.expectedComments, comments)
+		}
+	}
+}
+
+func TestCountCommentsByAuthor(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	cases := []struct {
+		expectedCount int
+		expectedErr   error
+		run           func() (int, error)
+	}{
+		{
+			expectedCount: 3,
+			expectedErr:   nil,
+			run: func() (int, error) {
+				var (
+					count int
+					err   error
+				)
+				mt.Run("success", func(mt *mtest.T) {
+					repo := NewCommentsRepo(mt.Coll)
+					mt.AddMockResponses(
+						mtest.CreateCursorResponse(0, "redditclone.comments", mtest.FirstBatch, bson.D{{Key: "n", Value: 3}}),
+					)
+					count, err = repo.CountCommentsByAuthor("van")
+				})
+				return count, err
+			},
+		},
+		{
+			expectedCount: 0,
+			expectedErr:   mongo.CommandError{Message: "command failed"},
+			run: func() (int, error) {
+				var (
+					count int
+					err   error
+				)
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewCommentsRepo(mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					count, err = repo.CountCommentsByAuthor("van")
+				})
+				return count, err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		count, err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+		if item.expectedCount != count {
+			t.Errorf("[%d] expected count: %d, got: %d", i, item.expectedCount, count)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package mongorepo
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"redditclone/internal/model"
+	"redditclone/internal/model/customerr"
+)
+
+type communitiesRepo struct {
+	communities *mongo.Collection
+}
+
+func NewCommunitiesRepo(collection *mongo.Collection) *communitiesRepo {
+	return &communitiesRepo{communities: collection}
+}
+
+func (r *communitiesRepo) CreateIndexes() error {
+	_, err := r.communities.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+func (r *communitiesRepo) AddCommunity(community model.Community) error {
+	_, err := r.communities.InsertOne(context.TODO(), community)
+	if mongo.IsDuplicateKeyError(err) {
+		return customerr.CommunityAlreadyExists{Name: community.Name}
+	}
+	return err
+}
+
+func (r *communitiesRepo) GetCommunities() ([]model.Community, error) {
+	communities := make([]model.Community, 0)
+	filter := bson.M{}
+	opt := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := r.communities.Find(context.TODO(), filter, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	for cursor.Next(context.TODO()) {
+		var community model.Community
+		err = cursor.Decode(&community)
+		if err != nil {
+			return nil, err
+		}
+
+		communities = append(communities, community)
+	}
+
+	return communities, nil
+}
+
+func (r *communitiesRepo) GetCommunityByName(name string) (model.Community, error) {
+	var community model.Community
+	filter := bson.M{"name": name}
+	err := r.communities.FindOne(context.TODO(), filter).Decode(&community)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return model.Community{}, customerr.CommunityNotFoundByName{Name: name}
+		}
+		return model.Community{}, err
+	}
+	return community, nil
+}
+
+func (r *communitiesRepo) UpdateCommunity(name string, description string) (model.Community, error) {
+	var community model.Community
+	filter := bson.M{"name": name}
+	update := bson.M{"$set": bson.M{"description": description}}
+	opt := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := r.communities.FindOneAndUpdate(context.TODO(), filter, update, opt).Decode(&community)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return model.Community{}, customerr.CommunityNotFoundByName{Name: name}
+		}
+		return model.Community{}, err
+	}
+	return community, nil
+}
+
+func (r *communitiesRepo) DeleteCommunity(name string) error {
+	filter := bson.M{"name": name}
+	res, err := r.communities.DeleteOne(context.TODO(), filter)
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return customerr.CommunityNotFoundByName{Name: name}
+	}
+	return nil
+}
@@ -0,0 +1,310 @@
+package mongorepo
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"redditclone/internal/model"
+	"redditclone/internal/model/customerr"
+	"reflect"
+	"testing"
+)
+
+func marshalCommunity(community model.Community) bson.D {
+	bsonData, _ := bson.Marshal(community)
+
+	var bsonD bson.D
+	_ = bson.Unmarshal(bsonData, &bsonD)
+
+	return bsonD
+}
+
+func TestAddCommunity(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	cases := []struct {
+		expectedErr error
+		run         func() error
+	}{
+		{
+			expectedErr: nil,
+			run: func() error {
+				var err error
+				mt.Run("success", func(mt *mtest.T) {
+					repo := NewCommunitiesRepo(mt.Coll)
+					mt.AddMockResponses(mtest.CreateSuccessResponse())
+					err = repo.AddCommunity(model.Community{Name: "funny"})
+				})
+				return err
+			},
+		},
+		{
+			expectedErr: customerr.CommunityAlreadyExists{Name: "funny"},
+			run: func() error {
+				var err error
+				mt.Run("duplicate", func(mt *mtest.T) {
+					repo := NewCommunitiesRepo(mt.Coll)
+					mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+						Index:   0,
+						Code:    11000,
+						Message: "duplicate key error",
+					}))
+					err = repo.AddCommunity(model.Community{Name: "funny"})
+				})
+				return err
+			},
+		},
+		{
+			expectedErr: mongo.CommandError{Message: "command failed"},
+			run: func() error {
+				var err error
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewCommunitiesRepo(mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					err = repo.AddCommunity(model.Community{Name: "funny"})
+				})
+				return err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+	}
+}
+
+func TestGetCommunities(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	cases := []struct {
+		expectedCommunities []model.Community
+		expectedErr         error
+		run                 func([]model.Community) ([]model.Community, error)
+	}{
+		{
+			expectedCommunities: []model.Community{{Name: "funny"}, {Name: "music"}},
+			expectedErr:         nil,
+			run: func(expectedCommunities []model.Community) ([]model.Community, error) {
+				var communities []model.Community
+				var err error
+				mt.Run("success", func(mt *mtest.T) {
+					repo := NewCommunitiesRepo(mt.Coll)
+					docs := make([]bson.D, 0)
+					for _, community := range expectedCommunities {
+						docs = append(docs, marshalCommunity(community))
+					}
+					mt.AddMockResponses(
+						mtest.CreateCursorResponse(1, "redditclone.communities", mtest.FirstBatch, docs...),
+						mtest.CreateCursorResponse(0, "redditclone.communities", mtest.NextBatch),
+					)
+					communities, err = repo.GetCommunities()
+				})
+				return communities, err
+			},
+		},
+		{
+			expectedCommunities: nil,
+			expectedErr:         mongo.CommandError{Message: "command failed"},
+			run: func(expectedCommunities []model.Community) ([]model.Community, error) {
+				var communities []model.Community
+				var err error
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewCommunitiesRepo(mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					communities, err = repo.GetCommunities()
+				})
+				return communities, err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		communities, err := item.run(item.expectedCommunities)
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+		if !reflect.DeepEqual(item.expectedCommunities, communities) {
+			t.Errorf("[%d] expected communities: %+v, got: %+v", i, item.expectedCommunities, communities)
+		}
+	}
+}
+
+func TestGetCommunityByName(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	cases := []struct {
+		expectedCommunity model.Community
+		expectedErr       error
+		run               func(model.Community) (model.Community, error)
+	}{
+		{
+			expectedCommunity: model.Community{Name: "funny", Description: "Funny"},
+			expectedErr:       nil,
+			run: func(expectedCommunity model.Community) (model.Community, error) {
+				var community model.Community
+				var err error
+				mt.Run("success", func(mt *mtest.T) {
+					repo := NewCommunitiesRepo(mt.Coll)
+					mt.AddMockResponses(
+						mtest.CreateCursorResponse(1, "redditclone.communities", mtest.FirstBatch, marshalCommunity(expectedCommunity)),
+					)
+					community, err = repo.GetCommunityByName("funny")
+				})
+				return community, err
+			},
+		},
+		{
+			expectedCommunity: model.Community{},
+			expectedErr:       customerr.CommunityNotFoundByName{Name: "cats"},
+			run: func(expectedCommunity model.Community) (model.Community, error) {
+				var community model.Community
+				var err error
+				mt.Run("community not found", func(mt *mtest.T) {
+					repo := NewCommunitiesRepo(mt.Coll)
+					mt.AddMockResponses(
+						mtest.CreateCursorResponse(1, "redditclone.communities", mtest.FirstBatch),
+						mtest.CreateCursorResponse(0, "redditclone.communities", mtest.NextBatch),
+					)
+					community, err = repo.GetCommunityByName("cats")
+				})
+				return community, err
+			},
+		},
+		{
+			expectedCommunity: model.Community{},
+			expectedErr:       mongo.CommandError{Message: "command failed"},
+			run: func(expectedCommunity model.Community) (model.Community, error) {
+				var community model.Community
+				var err error
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewCommunitiesRepo(mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					community, err = repo.GetCommunityByName("funny")
+				})
+				return community, err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		community, err := item.run(item.expectedCommunity)
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+		if !reflect.DeepEqual(item.expectedCommunity, community) {
+			t.Errorf("[%d] expected community: %+v, got: %+v", i, item.expectedCommunity, community)
+		}
+	}
+}
+
+func TestUpdateCommunity(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	cases := []struct {
+		expectedCommunity model.Community
+		expectedErr       error
+		run               func(model.Community) (model.Community, error)
+	}{
+		{
+			expectedCommunity: model.Community{Name: "cats", Description: "purr"},
+			expectedErr:       nil,
+			run: func(expectedCommunity model.Community) (model.Community, error) {
+				var community model.Community
+				var err error
+				mt.Run("success", func(mt *mtest.T) {
+					repo := NewCommunitiesRepo(mt.Coll)
+					mt.AddMockResponses(
+						mtest.CreateSuccessResponse(bson.E{Key: "value", Value: marshalCommunity(expectedCommunity)}),
+					)
+					community, err = repo.UpdateCommunity("cats", "purr")
+				})
+				return community, err
+			},
+		},
+		{
+			expectedCommunity: model.Community{},
+			expectedErr:       customerr.CommunityNotFoundByName{Name: "cats"},
+			run: func(expectedCommunity model.Community) (model.Community, error) {
+				var community model.Community
+				var err error
+				mt.Run("community not found", func(mt *mtest.T) {
+					repo := NewCommunitiesRepo(mt.Coll)
+					mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil}))
+					community, err = repo.UpdateCommunity("cats", "purr")
+				})
+				return community, err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		community, err := item.run(item.expectedCommunity)
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+		if !reflect.DeepEqual(item.expectedCommunity, community) {
+			t.Errorf("[%d] expected community: %+v, got: %+v", i, item.expectedCommunity, community)
+		}
+	}
+}
+
+func TestDeleteCommunity(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	cases := []struct {
+		expectedErr error
+		run         func() error
+	}{
+		{
+			expectedErr: nil,
+			run: func() error {
+				var err error
+				mt.Run("success", func(mt *mtest.T) {
+					repo := NewCommunitiesRepo(mt.Coll)
+					mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}))
+					err = repo.DeleteCommunity("cats")
+				})
+				return err
+			},
+		},
+		{
+			expectedErr: customerr.CommunityNotFoundByName{Name: "cats"},
+			run: func() error {
+				var err error
+				mt.Run("community not found", func(mt *mtest.T) {
+					repo := NewCommunitiesRepo(mt.Coll)
+					mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}))
+					err = repo.DeleteCommunity("cats")
+				})
+				return err
+			},
+		},
+		{
+			expectedErr: mongo.CommandError{Message: "command failed"},
+			run: func() error {
+				var err error
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewCommunitiesRepo(mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					err = repo.DeleteCommunity("cats")
+				})
+				return err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package mongorepo
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"redditclone/internal/model"
+	"redditclone/internal/model/customerr"
+)
+
+type notificationsRepo struct {
+	notifications *mongo.Collection
+}
+
+func NewNotificationsRepo(collection *mongo.Collection) *notificationsRepo {
+	return &notificationsRepo{notifications: collection}
+}
+
+// CreateIndexes serves the inbox pages and keeps the keyed notifications unique
+func (r *notificationsRepo) CreateIndexes() error {
+	_, err := r.notifications.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "userId", Value: 1}, {Key: "created", Value: -1}, {Key: "id", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "userId", Value: 1}, {Key: "read", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "key", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"key": bson.M{"$exists": true}}),
+		},
+	})
+	return err
+}
+
+// AddNotification skips a keyed notification which was already stored
+func (r *notificationsRepo) AddNotification(notification model.Notification) error {
+	_, err := r.notifications.InsertOne(context.TODO(), notification)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
+
+func (r *notificationsRepo) GetNotifications(userID string, query model.NotificationsQuery, after model.NotificationCursor) ([]model.Notification, error) {
+	notifications := make([]model.Notification, 0)
+	filter := bson.M{"userId": userID}
+	if query.UnreadOnly {
+		filter["read"] = false
+	}
+	if !after.IsZero() {
+		filter["$or"] = bson.A{
+			bson.M{"created": bson.M{"$lt": after.Created}},
+			bson.M{"created": after.Created, "id": bson.M{"$lt": after.ID}},
+		}
+	}
+
+	opt := options.Find().
+		SetSort(bson.D{{Key: "created", Value: -1}, {Key: "id", Value: -1}}).
+		SetLimit(int64(query.Limit))
+	cursor, err := r.notifications.Find(context.TODO(), filter, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	for cursor.Next(context.TODO()) {
+		var notification model.Notification
+		err = cursor.Decode(&notification)
+		if err != nil {
+			return nil, err
+		}
+
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, nil
+}
+
+func (r *notificationsRepo) CountUnread(userID string) (int, error) {
+	filter := bson.M{"userId": userID, "read": false}
+	count, err := r.notifications.CountDocuments(context.TODO(), filter)
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// MarkRead finds the notification among the user's ones, so a stranger gets it not found
+func (r *notificationsRepo) MarkRead(userID, notificationID string) (model.Notification, error) {
+	var notification model.Notification
+
+	filter := bson.M{"userId": userID, "id": notificationID}
+	update := bson.M{"$set": bson.M{"read": true}}
+	opt := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := r.notifications.FindOneAndUpdate(context.TODO(), filter, update, opt).Decode(&notification)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return model.Notification{}, customerr.NotificationNotFoundByID{NotificationID: notificationID}
+		}
+		return model.Notification{}, err
+	}
+	return notification, nil
+}
+
+func (r *notificationsRepo) MarkAllRead(userID string) error {
+	filter := bson.M{"userId": userID, "read": false}
+	update := bson.M{"$set": bson.M{"read": true}}
+	_, err := r.notifications.UpdateMany(context.TODO(), filter, update)
+	return err
+}
@@ -0,0 +1,299 @@
+package mongorepo
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"redditclone/internal/model"
+	"redditclone/internal/model/customerr"
+	"reflect"
+	"testing"
+)
+
+func marshalNotification(notification model.Notification) bson.D {
+	bsonData, _ := bson.Marshal(notification)
+
+	var bsonD bson.D
+	_ = bson.Unmarshal(bsonData, &bsonD)
+
+	return bsonD
+}
+
+func TestAddNotification(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	notification := model.NewMilestoneNotification("1", "1", "", 10)
+
+	cases := []struct {
+		expectedErr error
+		run         func() error
+	}{
+		{
+			expectedErr: nil,
+			run: func() error {
+				var err error
+				mt.Run("success", func(mt *mtest.T) {
+					repo := NewNotificationsRepo(mt.Coll)
+					mt.AddMockResponses(mtest.CreateSuccessResponse())
+					err = repo.AddNotification(notification)
+				})
+				return err
+			},
+		},
+		{
+			expectedErr: nil,
+			run: func() error {
+				var err error
+				mt.Run("already notified", func(mt *mtest.T) {
+					repo := NewNotificationsRepo(mt.Coll)
+					mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+						Index:   0,
+						Code:    11000,
+						Message: "duplicate key error",
+					}))
+					err = repo.AddNotification(notification)
+				})
+				return err
+			},
+		},
+		{
+			expectedErr: mongo.CommandError{Message: "command failed"},
+			run: func() error {
+				var err error
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewNotificationsRepo(mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					err = repo.AddNotification(notification)
+				})
+				return err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+	}
+}
+
+func TestGetNotifications(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	query := model.NotificationsQuery{UnreadOnly: true, Limit: 3}
+	after := model.NotificationCursor{Created: "2022-01-03T00:00:00.000Z", ID: "3"}
+
+	cases := []struct {
+		expectedNotifications []model.Notification
+		expectedErr           error
+		run                   func(notifications []model.Notification) ([]model.Notification, error)
+	}{
+		{
+			expectedNotifications: []model.Notification{
+				{ID: "2", UserID: "1", Type: model.NotificationPostComment, Actor: &model.Author{ID: "2", Username: "van"}, PostID: "1", CommentID: "1", Body: "body", Created: "2022-01-02T00:00:00.000Z"},
+				{ID: "1", UserID: "1", Type: model.NotificationScoreMilestone, PostID: "1", Score: 10, Created: "2022-01-01T00:00:00.000Z"},
+			},
+			expectedErr: nil,
+			run: func(notifications []model.Notification) ([]model.Notification, error) {
+				var err error
+				mt.Run("success", func(mt *mtest.T) {
+					repo := NewNotificationsRepo(mt.Coll)
+					mt.AddMockResponses(
+						mtest.CreateCursorResponse(1, "redditclone.notifications", mtest.FirstBatch, marshalNotification(notifications[0])),
+						mtest.CreateCursorResponse(1, "redditclone.notifications", mtest.NextBatch, marshalNotification(notifications[1])),
+						mtest.CreateCursorResponse(0, "redditclone.notifications", mtest.NextBatch),
+					)
+					notifications, err = repo.GetNotifications("1", query, after)
+				})
+				return notifications, err
+			},
+		},
+		{
+			expectedNotifications: nil,
+			expectedErr:           mongo.CommandError{Message: "command failed"},
+			run: func(notifications []model.Notification) ([]model.Notification, error) {
+				var err error
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewNotificationsRepo(mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					notifications, err = repo.GetNotifications("1", query, after)
+				})
+				return notifications, err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		notifications, err := item.run(item.expectedNotifications)
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+		if !reflect.DeepEqual(item.expectedNotifications, notifications) {
+			t.Errorf("[%d] expected notifications: %+v, got: %+v", i, item.expectedNotifications, notifications)
+		}
+	}
+}
+
+func TestCountUnread(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	cases := []struct {
+		expectedCount int
+		expectedErr   error
+		run           func() (int, error)
+	}{
+		{
+			expectedCount: 3,
+			expectedErr:   nil,
+			run: func() (int, error) {
+				var (
+					count int
+					err   error
+				)
+				mt.Run("success", func(mt *mtest.T) {
+					repo := NewNotificationsRepo(mt.Coll)
+					mt.AddMockResponses(
+						mtest.CreateCursorResponse(0, "redditclone.notifications", mtest.FirstBatch, bson.D{{Key: "n", Value: 3}}),
+					)
+					count, err = repo.CountUnread("1")
+				})
+				return count, err
+			},
+		},
+		{
+			expectedCount: 0,
+			expectedErr:   mongo.CommandError{Message: "command failed"},
+			run: func() (int, error) {
+				var (
+					count int
+					err   error
+				)
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewNotificationsRepo(mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					count, err = repo.CountUnread("1")
+				})
+				return count, err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		count, err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+		if item.expectedCount != count {
+			t.Errorf("[%d] expected count: %d, got: %d", i, item.expectedCount, count)
+		}
+	}
+}
+
+func TestMarkRead(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	read := model.Notification{ID: "1", UserID: "1", Type: model.NotificationScoreMilestone, PostID: "1", Score: 10, Read: true}
+
+	cases := []struct {
+		expectedNotification model.Notification
+		expectedErr          error
+		run                  func(notification model.Notification) (model.Notification, error)
+	}{
+		{
+			expectedNotification: read,
+			expectedErr:          nil,
+			run: func(notification model.Notification) (model.Notification, error) {
+				var err error
+				mt.Run("success", func(mt *mtest.T) {
+					repo := NewNotificationsRepo(mt.Coll)
+					mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: marshalNotification(notification)}))
+					notification, err = repo.MarkRead("1", "1")
+				})
+				return notification, err
+			},
+		},
+		{
+			expectedNotification: model.Notification{},
+			expectedErr:          customerr.NotificationNotFoundByID{NotificationID: "1"},
+			run: func(notification model.Notification) (model.Notification, error) {
+				var err error
+				mt.Run("notification not found", func(mt *mtest.T) {
+					repo := NewNotificationsRepo(mt.Coll)
+					mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil}))
+					notification, err = repo.MarkRead("2", "1")
+				})
+				return notification, err
+			},
+		},
+		{
+			expectedNotification: model.Notification{},
+			expectedErr:          mongo.CommandError{Message: "command failed"},
+			run: func(notification model.Notification) (model.Notification, error) {
+				var err error
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewNotificationsRepo(mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					notification, err = repo.MarkRead("1", "1")
+				})
+				return notification, err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		notification, err := item.run(item.expectedNotification)
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+		if !reflect.DeepEqual(item.expectedNotification, notification) {
+			t.Errorf("[%d] expected notification: %+v, got: %+v", i, item.expectedNotification, notification)
+		}
+	}
+}
+
+func TestMarkAllRead(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	cases := []struct {
+		expectedErr error
+		run         func() error
+	}{
+		{
+			expectedErr: nil,
+			run: func() error {
+				var err error
+				mt.Run("success", func(mt *mtest.T) {
+					repo := NewNotificationsRepo(mt.Coll)
+					mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 2}, bson.E{Key: "nModified", Value: 2}))
+					err = repo.MarkAllRead("1")
+				})
+				return err
+			},
+		},
+		{
+			expectedErr: mongo.CommandError{Message: "command failed"},
+			run: func() error {
+				var err error
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewNotificationsRepo(mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					err = repo.MarkAllRead("1")
+				})
+				return err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+	}
+}
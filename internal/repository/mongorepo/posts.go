@@ -17,6 +17,41 @@ func NewPostsRepo(collection *mongo.Collection) *postsRepo {
 	return &postsRepo{posts: collection}
 }
 
+// sortFields maps the sort modes to the stored fields they order by
+var sortFields = map[string]string{
+	model.SortNew:           "created",
+	model.SortHot:           "hot",
+	model.SortRising:        "hot",
+	model.SortTop:           "score",
+	model.SortControversial: "controversial",
+}
+
+// CreateIndexes covers the listings, each sort field is indexed for the whole feed and for a community
+func (r *postsRepo) CreateIndexes() error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "author.username", Value: 1}, {Key: "created", Value: -1}, {Key: "id", Value: -1}},
+		},
+	}
+	for _, field := range []string{"created", "hot", "score", "controversial"} {
+		indexes = append(indexes,
+			mongo.IndexModel{
+				Keys: bson.D{{Key: field, Value: -1}, {Key: "id", Value: -1}},
+			},
+			mongo.IndexModel{
+				Keys: bson.D{{Key: "category", Value: 1}, {Key: field, Value: -1}, {Key: "id", Value: -1}},
+			},
+		)
+	}
+
+	_, err := r.posts.Indexes().CreateMany(context.TODO(), indexes)
+	return err
+}
+
 func (r *postsRepo) GetAllPosts() ([]model.Post, error) {
 	posts := make([]model.Post, 0)
 	filter := bson.M{}
@@ -80,6 +115,62 @@ func (r *postsRepo) GetPostsByAuthor(username string) ([]model.Post, error) {
 	return posts, nil
 }
 
+func (r *postsRepo) CountPostsByAuthor(username string) (int, error) {
+	filter := bson.M{"author.username": username}
+	count, err := r.posts.CountDocuments(context.TODO(), filter)
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+func (r *postsRepo) GetPostsPage(query model.PostsQuery, after model.PostCursor) ([]model.Post, error) {
+	posts := make([]model.Post, 0)
+	filter := bson.M{}
+	if query.Category != "" {
+		filter["category"] = query.Category
+	}
+	if query.Author != "" {
+		filter["author.username"] = query.Author
+	}
+	if query.Since != "" {
+		filter["created"] = bson.M{"$gte": query.Since}
+	}
+
+	field := sortFields[query.Sort]
+	if !after.IsZero() {
+		var position interface{} = after.Rank
+		if query.Sort == model.SortNew {
+			position = after.Created
+		}
+		filter["$or"] = bson.A{
+			bson.M{field: bson.M{"$lt": position}},
+			bson.M{field: position, "id": bson.M{"$lt": after.ID}},
+		}
+	}
+
+	opt := options.Find().
+		SetSort(bson.D{{Key: field, Value: -1}, {Key: "id", Value: -1}}).
+		SetLimit(int64(query.Limit)).
+		SetProjection(bson.M{"revisions": 0})
+	cursor, err := r.posts.Find(context.TODO(), filter, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	for cursor.Next(context.TODO()) {
+		var post model.Post
+		err = cursor.Decode(&post)
+		if err != nil {
+			return nil, err
+		}
+
+		posts = append(posts, post)
+	}
+
+	return posts, nil
+}
+
 func (r *postsRepo) AddPost(post model.Post) error {
 	_, err := r.posts.InsertOne(context.TODO(), post)
 	if err != nil {
@@ -116,67 +207,132 @@ func (r *postsRepo) GetPostByIDAndUpdateViews(postID string) (model.Post, error)
 	return post, nil
 }
 
+// notRemoved limits the writes to the posts no moderator has removed
+var notRemoved = bson.M{"$exists": false}
+
+// changedOrMissing tells a post changed by another request from a missing one after a conditional write matched nothing
+func (r *postsRepo) changedOrMissing(postID string) error {
+	count, err := r.posts.CountDocuments(context.TODO(), bson.M{"id": postID})
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return customerr.PostNotFoundByID{PostID: postID}
+	}
+	return customerr.PostChanged{PostID: postID}
+}
+
+// DeletePost leaves a removed post in place, it stays for the modlog
 func (r *postsRepo) DeletePost(postID string) error {
-	filter := bson.M{"id": postID}
-	_, err := r.posts.DeleteOne(context.TODO(), filter)
-	return err
+	filter := bson.M{"id": postID, "removed": notRemoved}
+	res, err := r.posts.DeleteOne(context.TODO(), filter)
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return customerr.PostNotFoundByID{PostID: postID}
+	}
+	return nil
 }
 
-func (r *postsRepo) AddComment(postID string, comment model.Comment) (model.Post, error) {
-	var post model.Post
-	filter := bson.M{"id": postID}
-	update := bson.M{"$push": bson.M{"comments": comment}}
-	opt := options.FindOneAndUpdate().SetReturnDocument(options.After)
-	err := r.posts.FindOneAndUpdate(context.TODO(), filter, update, opt).Decode(&post)
+// RemovePost keeps the post with the removal set, the content stays for the moderators.
+// A post already removed by another request gives PostChanged.
+func (r *postsRepo) RemovePost(postID string, removal *model.Removal) error {
+	filter := bson.M{"id": postID, "removed": notRemoved}
+	update := bson.M{"$set": bson.M{"removed": removal}}
+	res, err := r.posts.UpdateOne(context.TODO(), filter, update)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return model.Post{}, customerr.PostNotFoundByID{PostID: postID}
-		}
-		return model.Post{}, err
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return r.changedOrMissing(postID)
 	}
+	return nil
+}
 
-	return post, nil
+// LockPost keeps the post open for reading only, no new comments are added.
+// A post already locked by another request gives PostChanged.
+func (r *postsRepo) LockPost(postID string) error {
+	filter := bson.M{"id": postID, "locked": bson.M{"$ne": true}}
+	update := bson.M{"$set": bson.M{"locked": true}}
+	res, err := r.posts.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return r.changedOrMissing(postID)
+	}
+	return nil
 }
 
-func (r *postsRepo) GetCommentByID(postID, commentID string) (model.Comment, error) {
-	var post model.Post
+// EditPost writes only over the version the edit was made from, edited is its edit time or empty for the original
+func (r *postsRepo) EditPost(post model.Post, edited string, revision model.PostRevision) error {
+	filter := bson.M{"id": post.ID, "removed": notRemoved, "edited": edited}
+	if edited == "" {
+		filter["edited"] = bson.M{"$exists": false}
+	}
+	update := bson.M{
+		"$set":  bson.M{"title": post.Title, "text": post.Text, "edited": post.Edited},
+		"$push": bson.M{"revisions": revision},
+	}
+	res, err := r.posts.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return r.changedOrMissing(post.ID)
+	}
+	return nil
+}
+
+// GetPostRevisions reads only the revisions, they are stored in the post document but left out of model.Post
+func (r *postsRepo) GetPostRevisions(postID string) ([]model.PostRevision, error) {
+	var stored struct {
+		Revisions []model.PostRevision `bson:"revisions"`
+	}
 	filter := bson.M{"id": postID}
-	err := r.posts.FindOne(context.TODO(), filter).Decode(&post)
+	opt := options.FindOne().SetProjection(bson.M{"revisions": 1})
+	err := r.posts.FindOne(context.TODO(), filter, opt).Decode(&stored)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return model.Comment{}, customerr.PostNotFoundByID{PostID: postID}
+			return nil, customerr.PostNotFoundByID{PostID: postID}
 		}
-		return model.Comment{}, err
+		return nil, err
 	}
-
-	for _, c := range post.Comments {
-		if c.ID == commentID {
-			return c, nil
-		}
+	if stored.Revisions == nil {
+		stored.Revisions = make([]model.PostRevision, 0)
 	}
+	return stored.Revisions, nil
+}
 
-	return model.Comment{}, customerr.CommentNotFoundByID{PostID: postID, CommentID: commentID}
+func (r *postsRepo) UpdateCommentCount(postID string, delta int) error {
+	filter := bson.M{"id": postID}
+	update := bson.M{"$inc": bson.M{"commentCount": delta}}
+	res, err := r.posts.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return customerr.PostNotFoundByID{PostID: postID}
+	}
+	return nil
 }
 
-func (r *postsRepo) DeleteComment(postID, commentID string) (model.Post, error) {
+// UpdateVoteCounts applies the counts in a single update pipeline, so concurrent votes never overwrite each other
+func (r *postsRepo) UpdateVoteCounts(postID string, ups, downs int) (model.Post, error) {
 	var post model.Post
+	pipeline := append(countsPipeline(ups, downs), ranksPipeline()...)
+
 	filter := bson.M{"id": postID}
-	update := bson.M{"$pull": bson.M{"comments": bson.M{"id": commentID}}}
-	opt := options.FindOneAndUpdate().SetReturnDocument(options.After)
-	err := r.posts.FindOneAndUpdate(context.TODO(), filter, update, opt).Decode(&post)
+	opt := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetProjection(bson.M{"revisions": 0})
+	err := r.posts.FindOneAndUpdate(context.TODO(), filter, pipeline, opt).Decode(&post)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return model.Post{}, customerr.PostNotFoundByID{PostID: postID}
 		}
 		return model.Post{}, err
 	}
-
 	return post, nil
 }
-
-func (r *postsRepo) UpdateVotes(postID string, score int, upvotePercentage int, votes []model.Vote) error {
-	filter := bson.M{"id": postID}
-	update := bson.M{"$set": bson.M{"score": score, "votes": votes, "upvotePercentage": upvotePercentage}}
-	_, err := r.posts.UpdateOne(context.TODO(), filter, update)
-	return err
-}
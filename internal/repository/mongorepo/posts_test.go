@@ -216,6 +216,104 @@ func TestPostsByAuthor(t *testing.T) {
 	}
 }
 
+func TestGetPostsPage(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	cases := []struct {
+		expectedPosts []model.Post
+		expectedErr   error
+		run           func([]model.Post) ([]model.Post, error)
+	}{
+		{
+			expectedPosts: []model.Post{{ID: "3", Category: "funny"}, {ID: "2", Category: "funny"}},
+			expectedErr:   nil,
+			run: func(expectedPosts []model.Post) ([]model.Post, error) {
+				var posts []model.Post
+				var err error
+				mt.Run("first page", func(mt *mtest.T) {
+					repo := NewPostsRepo(mt.Coll)
+					docs := marshalPosts(expectedPosts)
+					mt.AddMockResponses(
+						mtest.CreateCursorResponse(1, "redditclone.posts", mtest.FirstBatch, docs...),
+						mtest.CreateCursorResponse(0, "redditclone.posts", mtest.NextBatch),
+					)
+					posts, err = repo.GetPostsPage(model.PostsQuery{Category: "funny", Sort: model.SortNew, Limit: 2}, model.PostCursor{})
+				})
+				return posts, err
+			},
+		},
+		{
+			expectedPosts: []model.Post{{ID: "1", Author: model.Author{Username: "user"}}},
+			expectedErr:   nil,
+			run: func(expectedPosts []model.Post) ([]model.Post, error) {
+				var posts []model.Post
+				var err error
+				mt.Run("next page", func(mt *mtest.T) {
+					repo := NewPostsRepo(mt.Coll)
+					docs := marshalPosts(expectedPosts)
+					mt.AddMockResponses(
+						mtest.CreateCursorResponse(1, "redditclone.posts", mtest.FirstBatch, docs...),
+						mtest.CreateCursorResponse(0, "redditclone.posts", mtest.NextBatch),
+					)
+					after := model.PostCursor{Sort: model.SortNew, Created: "2022-01-01T00:00:00.000Z", ID: "2"}
+					posts, err = repo.GetPostsPage(model.PostsQuery{Author: "user", Sort: model.SortNew, Limit: 2}, after)
+				})
+				return posts, err
+			},
+		},
+		{
+			expectedPosts: []model.Post{{ID: "4", Voting: model.Voting{Score: 10}, Ranks: model.PostRanks{Hot: 1500.5}}},
+			expectedErr:   nil,
+			run: func(expectedPosts []model.Post) ([]model.Post, error) {
+				var posts []model.Post
+				var err error
+				mt.Run("top of the week", func(mt *mtest.T) {
+					repo := NewPostsRepo(mt.Coll)
+					docs := marshalPosts(expectedPosts)
+					mt.AddMockResponses(
+						mtest.CreateCursorResponse(1, "redditclone.posts", mtest.FirstBatch, docs...),
+						mtest.CreateCursorResponse(0, "redditclone.posts", mtest.NextBatch),
+					)
+					query := model.PostsQuery{Sort: model.SortTop, Since: "2022-01-01T00:00:00.000Z", Limit: 2}
+					posts, err = repo.GetPostsPage(query, model.PostCursor{Sort: model.SortTop, Rank: 11, ID: "5"})
+				})
+				return posts, err
+			},
+		},
+		{
+			expectedPosts: make([]model.Post, 0),
+			expectedErr:   mongo.CommandError{Message: "command failed"},
+			run: func(expectedPosts []model.Post) ([]model.Post, error) {
+				var posts []model.Post
+				var err error
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewPostsRepo(mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					posts, err = repo.GetPostsPage(model.PostsQuery{Sort: model.SortHot, Limit: 2}, model.PostCursor{})
+				})
+				return posts, err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		posts, err := item.run(item.expectedPosts)
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+		if len(posts) != len(item.expectedPosts) && err == nil {
+			t.Errorf("[%d] expected %d posts, got: %d", i, len(item.expectedPosts), len(posts))
+			continue
+		}
+		for j, expectedPost := range item.expectedPosts {
+			if !reflect.DeepEqual(expectedPost, posts[j]) {
+				t.Errorf("[%d:%d] expected post: %+v, got: %+v", i, j, expectedPost, posts[j])
+			}
+		}
+	}
+}
+
 func TestAddPost(t *testing.T) {
 	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
 	defer mt.Close()
@@ -411,9 +509,19 @@ func TestDeletePost(t *testing.T) {
 				var err error
 				mt.Run("success", func(mt *mtest.T) {
 					repo := NewPostsRepo(mt.Coll)
-					mt.AddMockResponses(
-						mtest.CreateSuccessResponse(),
-					)
+					mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}))
+					err = repo.DeletePost("1")
+				})
+				return err
+			},
+		},
+		{
+			expectedErr: customerr.PostNotFoundByID{PostID: "1"},
+			run: func() error {
+				var err error
+				mt.Run("post not found or removed", func(mt *mtest.T) {
+					repo := NewPostsRepo(mt.Coll)
+					mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}))
 					err = repo.DeletePost("1")
 				})
 				return err
@@ -441,115 +549,266 @@ func TestDeletePost(t *testing.T) {
 	}
 }
 
-func TestAddComment(t *testing.T) {
+func TestEditPost(t *testing.T) {
 	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
 	defer mt.Close()
 
+	post := model.Post{ID: "1", Title: "new title", Text: "text", Edited: "2022-01-02T00:00:00.000Z"}
+	revision := model.PostRevision{Title: "title", Text: "text", Created: "2022-01-01T00:00:00.000Z"}
+
 	cases := []struct {
-		expectedPost model.Post
-		expectedErr  error
-		run          func(post model.Post) (model.Post, error)
+		expectedErr error
+		run         func() error
 	}{
 		{
-			expectedPost: model.Post{ID: "1", Comments: []model.Comment{{ID: "1"}}},
-			expectedErr:  nil,
-			run: func(post model.Post) (model.Post, error) {
+			expectedErr: nil,
+			run: func() error {
 				var err error
 				mt.Run("success", func(mt *mtest.T) {
 					repo := NewPostsRepo(mt.Coll)
-					doc := marshalPost(post)
+					mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}))
+					err = repo.EditPost(post, "", revision)
+				})
+				return err
+			},
+		},
+		{
+			expectedErr: customerr.PostNotFoundByID{PostID: "1"},
+			run: func() error {
+				var err error
+				mt.Run("post not found", func(mt *mtest.T) {
+					repo := NewPostsRepo(mt.Coll)
 					mt.AddMockResponses(
-						mtest.CreateSuccessResponse(bson.E{Key: "value", Value: doc}),
+						mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}),
+						mtest.CreateCursorResponse(0, "redditclone.posts", mtest.FirstBatch),
 					)
-					post, err = repo.AddComment("1", model.Comment{ID: "1"})
+					err = repo.EditPost(post, "", revision)
 				})
-				return post, err
+				return err
 			},
 		},
 		{
-			expectedPost: model.Post{},
-			expectedErr:  mongo.CommandError{Message: "command failed"},
-			run: func(post model.Post) (model.Post, error) {
+			expectedErr: customerr.PostChanged{PostID: "1"},
+			run: func() error {
+				var err error
+				mt.Run("post changed", func(mt *mtest.T) {
+					repo := NewPostsRepo(mt.Coll)
+					mt.AddMockResponses(
+						mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}),
+						mtest.CreateCursorResponse(0, "redditclone.posts", mtest.FirstBatch, bson.D{{Key: "n", Value: 1}}),
+					)
+					err = repo.EditPost(post, "", revision)
+				})
+				return err
+			},
+		},
+		{
+			expectedErr: mongo.CommandError{Message: "command failed"},
+			run: func() error {
 				var err error
 				mt.Run("command failed", func(mt *mtest.T) {
 					repo := NewPostsRepo(mt.Coll)
-					mt.AddMockResponses(bson.D{{"ok", 0}})
-					post, err = repo.AddComment("1", model.Comment{ID: "1"})
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					err = repo.EditPost(post, "", revision)
 				})
-				return post, err
+				return err
 			},
 		},
+	}
+
+	for i, item := range cases {
+		err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+	}
+}
+
+func TestRemovePost(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	cases := []struct {
+		expectedErr error
+		run         func() error
+	}{
 		{
-			expectedPost: model.Post{},
-			expectedErr:  customerr.PostNotFoundByID{PostID: "1"},
-			run: func(post model.Post) (model.Post, error) {
+			expectedErr: nil,
+			run: func() error {
+				var err error
+				mt.Run("success", func(mt *mtest.T) {
+					repo := NewPostsRepo(mt.Coll)
+					mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}))
+					err = repo.RemovePost("1", model.NewRemoval(model.Author{ID: "2", Username: "admin"}, "spam"))
+				})
+				return err
+			},
+		},
+		{
+			expectedErr: customerr.PostNotFoundByID{PostID: "1"},
+			run: func() error {
 				var err error
 				mt.Run("post not found", func(mt *mtest.T) {
 					repo := NewPostsRepo(mt.Coll)
 					mt.AddMockResponses(
-						mtest.CreateCursorResponse(1, "redditclone.posts", mtest.FirstBatch),
-						mtest.CreateCursorResponse(0, "redditclone.posts", mtest.NextBatch),
+						mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}),
+						mtest.CreateCursorResponse(0, "redditclone.posts", mtest.FirstBatch),
 					)
-					post, err = repo.AddComment("1", model.Comment{ID: "1"})
+					err = repo.RemovePost("1", model.NewRemoval(model.Author{ID: "2", Username: "admin"}, "spam"))
 				})
-				return post, err
+				return err
+			},
+		},
+		{
+			expectedErr: customerr.PostChanged{PostID: "1"},
+			run: func() error {
+				var err error
+				mt.Run("post changed", func(mt *mtest.T) {
+					repo := NewPostsRepo(mt.Coll)
+					mt.AddMockResponses(
+						mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}),
+						mtest.CreateCursorResponse(0, "redditclone.posts", mtest.FirstBatch, bson.D{{Key: "n", Value: 1}}),
+					)
+					err = repo.RemovePost("1", model.NewRemoval(model.Author{ID: "2", Username: "admin"}, "spam"))
+				})
+				return err
+			},
+		},
+		{
+			expectedErr: mongo.CommandError{Message: "command failed"},
+			run: func() error {
+				var err error
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewPostsRepo(mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					err = repo.RemovePost("1", model.NewRemoval(model.Author{ID: "2", Username: "admin"}, "spam"))
+				})
+				return err
 			},
 		},
 	}
 
 	for i, item := range cases {
-		post, err := item.run(item.expectedPost)
+		err := item.run()
 		if !compareErrorsMsg(item.expectedErr, err) {
 			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
 		}
-		if !reflect.DeepEqual(item.expectedPost, post) {
-			t.Errorf("[%d] expected post: %+v, got: %+v", i, item.expectedPost, post)
-		}
 	}
 }
 
-func TestGetCommentByID(t *testing.T) {
+func TestLockPost(t *testing.T) {
 	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
 	defer mt.Close()
 
 	cases := []struct {
-		expectedComment model.Comment
-		expectedErr     error
-		run             func(comment model.Comment) (model.Comment, error)
+		expectedErr error
+		run         func() error
 	}{
 		{
-			expectedComment: model.Comment{ID: "1"},
-			expectedErr:     nil,
-			run: func(comment model.Comment) (model.Comment, error) {
+			expectedErr: nil,
+			run: func() error {
 				var err error
 				mt.Run("success", func(mt *mtest.T) {
 					repo := NewPostsRepo(mt.Coll)
-					doc := marshalPost(model.Post{ID: "1", Comments: []model.Comment{{ID: "1"}}})
+					mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}))
+					err = repo.LockPost("1")
+				})
+				return err
+			},
+		},
+		{
+			expectedErr: customerr.PostNotFoundByID{PostID: "1"},
+			run: func() error {
+				var err error
+				mt.Run("post not found", func(mt *mtest.T) {
+					repo := NewPostsRepo(mt.Coll)
 					mt.AddMockResponses(
-						mtest.CreateCursorResponse(1, "redditclone.posts", mtest.FirstBatch, doc),
+						mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}),
+						mtest.CreateCursorResponse(0, "redditclone.posts", mtest.FirstBatch),
 					)
-					comment, err = repo.GetCommentByID("1", "1")
+					err = repo.LockPost("1")
 				})
-				return comment, err
+				return err
 			},
 		},
 		{
-			expectedComment: model.Comment{},
-			expectedErr:     mongo.CommandError{Message: "command failed"},
-			run: func(comment model.Comment) (model.Comment, error) {
+			expectedErr: customerr.PostChanged{PostID: "1"},
+			run: func() error {
+				var err error
+				mt.Run("post changed", func(mt *mtest.T) {
+					repo := NewPostsRepo(mt.Coll)
+					mt.AddMockResponses(
+						mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}),
+						mtest.CreateCursorResponse(0, "redditclone.posts", mtest.FirstBatch, bson.D{{Key: "n", Value: 1}}),
+					)
+					err = repo.LockPost("1")
+				})
+				return err
+			},
+		},
+		{
+			expectedErr: mongo.CommandError{Message: "command failed"},
+			run: func() error {
 				var err error
 				mt.Run("command failed", func(mt *mtest.T) {
 					repo := NewPostsRepo(mt.Coll)
-					mt.AddMockResponses(bson.D{{"ok", 0}})
-					comment, err = repo.GetCommentByID("1", "1")
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					err = repo.LockPost("1")
+				})
+				return err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+	}
+}
+
+func TestGetPostRevisions(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	cases := []struct {
+		expectedRevisions []model.PostRevision
+		expectedErr       error
+		run               func(revisions []model.PostRevision) ([]model.PostRevision, error)
+	}{
+		{
+			expectedRevisions: []model.PostRevision{{Title: "title", Text: "text", Created: "2022-01-01T00:00:00.000Z"}},
+			expectedErr:       nil,
+			run: func(revisions []model.PostRevision) ([]model.PostRevision, error) {
+				var err error
+				mt.Run("success", func(mt *mtest.T) {
+					repo := NewPostsRepo(mt.Coll)
+					doc := bson.D{{Key: "revisions", Value: revisions}}
+					mt.AddMockResponses(mtest.CreateCursorResponse(1, "redditclone.posts", mtest.FirstBatch, doc))
+					revisions, err = repo.GetPostRevisions("1")
+				})
+				return revisions, err
+			},
+		},
+		{
+			expectedRevisions: []model.PostRevision{},
+			expectedErr:       nil,
+			run: func(revisions []model.PostRevision) ([]model.PostRevision, error) {
+				var err error
+				mt.Run("never edited", func(mt *mtest.T) {
+					repo := NewPostsRepo(mt.Coll)
+					doc := bson.D{{Key: "id", Value: "1"}}
+					mt.AddMockResponses(mtest.CreateCursorResponse(1, "redditclone.posts", mtest.FirstBatch, doc))
+					revisions, err = repo.GetPostRevisions("1")
 				})
-				return comment, err
+				return revisions, err
 			},
 		},
 		{
-			expectedComment: model.Comment{},
-			expectedErr:     customerr.PostNotFoundByID{PostID: "1"},
-			run: func(comment model.Comment) (model.Comment, error) {
+			expectedRevisions: nil,
+			expectedErr:       customerr.PostNotFoundByID{PostID: "1"},
+			run: func(revisions []model.PostRevision) ([]model.PostRevision, error) {
 				var err error
 				mt.Run("post not found", func(mt *mtest.T) {
 					repo := NewPostsRepo(mt.Coll)
@@ -557,51 +816,104 @@ func TestGetCommentByID(t *testing.T) {
 						mtest.CreateCursorResponse(1, "redditclone.posts", mtest.FirstBatch),
 						mtest.CreateCursorResponse(0, "redditclone.posts", mtest.NextBatch),
 					)
-					comment, err = repo.GetCommentByID("1", "1")
+					revisions, err = repo.GetPostRevisions("1")
 				})
-				return comment, err
+				return revisions, err
 			},
 		},
 		{
-			expectedComment: model.Comment{},
-			expectedErr:     customerr.CommentNotFoundByID{PostID: "1", CommentID: "1"},
-			run: func(comment model.Comment) (model.Comment, error) {
+			expectedRevisions: nil,
+			expectedErr:       mongo.CommandError{Message: "command failed"},
+			run: func(revisions []model.PostRevision) ([]model.PostRevision, error) {
 				var err error
-				mt.Run("comment not found", func(mt *mtest.T) {
+				mt.Run("command failed", func(mt *mtest.T) {
 					repo := NewPostsRepo(mt.Coll)
-					doc := marshalPost(model.Post{ID: "1"})
-					mt.AddMockResponses(
-						mtest.CreateCursorResponse(1, "redditclone.posts", mtest.FirstBatch, doc),
-					)
-					comment, err = repo.GetCommentByID("1", "1")
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					revisions, err = repo.GetPostRevisions("1")
 				})
-				return comment, err
+				return revisions, err
 			},
 		},
 	}
 
 	for i, item := range cases {
-		comment, err := item.run(item.expectedComment)
+		revisions, err := item.run(item.expectedRevisions)
 		if !compareErrorsMsg(item.expectedErr, err) {
 			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
 		}
-		if !reflect.DeepEqual(item.expectedComment, comment) {
-			t.Errorf("[%d] expected post: %+v, got: %+v", i, item.expectedComment, comment)
+		if !reflect.DeepEqual(item.expectedRevisions, revisions) {
+			t.Errorf("[%d] expected revisions: %+v, got: %+v", i, item.expectedRevisions, revisions)
 		}
 	}
 }
 
-func TestDeleteComment(t *testing.T) {
+func TestUpdateCommentCount(t *testing.T) {
 	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
 	defer mt.Close()
 
+	cases := []struct {
+		expectedErr error
+		run         func() error
+	}{
+		{
+			expectedErr: nil,
+			run: func() error {
+				var err error
+				mt.Run("success", func(mt *mtest.T) {
+					repo := NewPostsRepo(mt.Coll)
+					mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}))
+					err = repo.UpdateCommentCount("1", 1)
+				})
+				return err
+			},
+		},
+		{
+			expectedErr: customerr.PostNotFoundByID{PostID: "1"},
+			run: func() error {
+				var err error
+				mt.Run("post not found", func(mt *mtest.T) {
+					repo := NewPostsRepo(mt.Coll)
+					mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}))
+					err = repo.UpdateCommentCount("1", 1)
+				})
+				return err
+			},
+		},
+		{
+			expectedErr: mongo.CommandError{Message: "command failed"},
+			run: func() error {
+				var err error
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewPostsRepo(mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					err = repo.UpdateCommentCount("1", 1)
+				})
+				return err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+	}
+}
+
+func TestUpdateVoteCounts(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	voted := model.Post{ID: "1", Voting: model.Voting{Score: 1, Ups: 1, UpvotePercentage: 100}}
+
 	cases := []struct {
 		expectedPost model.Post
 		expectedErr  error
 		run          func(post model.Post) (model.Post, error)
 	}{
 		{
-			expectedPost: model.Post{ID: "1"},
+			expectedPost: voted,
 			expectedErr:  nil,
 			run: func(post model.Post) (model.Post, error) {
 				var err error
@@ -611,36 +923,35 @@ func TestDeleteComment(t *testing.T) {
 					mt.AddMockResponses(
 						mtest.CreateSuccessResponse(bson.E{Key: "value", Value: doc}),
 					)
-					post, err = repo.DeleteComment("1", "1")
+					post, err = repo.UpdateVoteCounts("1", 1, 0)
 				})
 				return post, err
 			},
 		},
 		{
 			expectedPost: model.Post{},
-			expectedErr:  mongo.CommandError{Message: "command failed"},
+			expectedErr:  customerr.PostNotFoundByID{PostID: "1"},
 			run: func(post model.Post) (model.Post, error) {
 				var err error
-				mt.Run("command failed", func(mt *mtest.T) {
+				mt.Run("post not found", func(mt *mtest.T) {
 					repo := NewPostsRepo(mt.Coll)
-					mt.AddMockResponses(bson.D{{"ok", 0}})
-					post, err = repo.DeleteComment("1", "1")
+					mt.AddMockResponses(
+						mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil}),
+					)
+					post, err = repo.UpdateVoteCounts("1", 1, 0)
 				})
 				return post, err
 			},
 		},
 		{
 			expectedPost: model.Post{},
-			expectedErr:  customerr.PostNotFoundByID{PostID: "1"},
+			expectedErr:  mongo.CommandError{Message: "command failed"},
 			run: func(post model.Post) (model.Post, error) {
 				var err error
-				mt.Run("post not found", func(mt *mtest.T) {
+				mt.Run("command failed", func(mt *mtest.T) {
 					repo := NewPostsRepo(mt.Coll)
-					mt.AddMockResponses(
-						mtest.CreateCursorResponse(1, "redditclone.posts", mtest.FirstBatch),
-						mtest.CreateCursorResponse(0, "redditclone.posts", mtest.NextBatch),
-					)
-					post, err = repo.DeleteComment("1", "1")
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					post, err = repo.UpdateVoteCounts("1", 1, 0)
 				})
 				return post, err
 			},
@@ -658,46 +969,58 @@ func TestDeleteComment(t *testing.T) {
 	}
 }
 
-func TestUpdateVotes(t *testing.T) {
+func TestCountPostsByAuthor(t *testing.T) {
 	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
 	defer mt.Close()
 
 	cases := []struct {
-		expectedErr error
-		run         func() error
+		expectedCount int
+		expectedErr   error
+		run           func() (int, error)
 	}{
 		{
-			expectedErr: nil,
-			run: func() error {
-				var err error
+			expectedCount: 2,
+			expectedErr:   nil,
+			run: func() (int, error) {
+				var (
+					count int
+					err   error
+				)
 				mt.Run("success", func(mt *mtest.T) {
 					repo := NewPostsRepo(mt.Coll)
 					mt.AddMockResponses(
-						mtest.CreateSuccessResponse(),
+						mtest.CreateCursorResponse(0, "redditclone.posts", mtest.FirstBatch, bson.D{{Key: "n", Value: 2}}),
 					)
-					err = repo.UpdateVotes("1", 1, 100, []model.Vote{})
+					count, err = repo.CountPostsByAuthor("van")
 				})
-				return err
+				return count, err
 			},
 		},
 		{
-			expectedErr: mongo.CommandError{Message: "command failed"},
-			run: func() error {
-				var err error
+			expectedCount: 0,
+			expectedErr:   mongo.CommandError{Message: "command failed"},
+			run: func() (int, error) {
+				var (
+					count int
+					err   error
+				)
 				mt.Run("command failed", func(mt *mtest.T) {
 					repo := NewPostsRepo(mt.Coll)
-					mt.AddMockResponses(bson.D{{"ok", 0}})
-					err = repo.UpdateVotes("1", 1, 100, []model.Vote{})
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					count, err = repo.CountPostsByAuthor("van")
 				})
-				return err
+				return count, err
 			},
 		},
 	}
 
 	for i, item := range cases {
-		err := item.run()
+		count, err := item.run()
 		if !compareErrorsMsg(item.expectedErr, err) {
 			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
 		}
+		if item.expectedCount != count {
+			t.Errorf("[%d] expected count: %d, got: %d", i, item.expectedCount, count)
+		}
 	}
 }
@@ -0,0 +1,116 @@
+package mongorepo
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"redditclone/internal/model"
+)
+
+type reportsRepo struct {
+	reports *mongo.Collection
+}
+
+func NewReportsRepo(collection *mongo.Collection) *reportsRepo {
+	return &reportsRepo{reports: collection}
+}
+
+// CreateIndexes keeps one open report of a user per item and serves the queue
+func (r *reportsRepo) CreateIndexes() error {
+	_, err := r.reports.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "postId", Value: 1}, {Key: "commentId", Value: 1}, {Key: "reporter.id", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"status": model.ReportStatusOpen}),
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "community", Value: 1}},
+		},
+	})
+	return err
+}
+
+// AddReport returns the open report of the user on the item, the given one is stored only when there is none
+func (r *reportsRepo) AddReport(report model.Report) (model.Report, error) {
+	filter := bson.M{
+		"postId":      report.PostID,
+		"commentId":   report.CommentID,
+		"reporter.id": report.Reporter.ID,
+		"status":      model.ReportStatusOpen,
+	}
+	update := bson.M{"$setOnInsert": report}
+	opt := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var stored model.Report
+	err := r.reports.FindOneAndUpdate(context.TODO(), filter, update, opt).Decode(&stored)
+	if mongo.IsDuplicateKeyError(err) {
+		// a concurrent report of the same user was inserted first
+		err = r.reports.FindOne(context.TODO(), filter).Decode(&stored)
+	}
+	if err != nil {
+		return model.Report{}, err
+	}
+	return stored, nil
+}
+
+func (r *reportsRepo) GetReportQueue(query model.ReportsQuery) ([]model.ReportedItem, error) {
+	match := bson.M{"status": model.ReportStatusOpen}
+	if query.Community != "" {
+		match["community"] = query.Community
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$sort", Value: bson.D{{Key: "created", Value: 1}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{{Key: "postId", Value: "$postId"}, {Key: "commentId", Value: "$commentId"}}},
+			{Key: "postId", Value: bson.M{"$first": "$postId"}},
+			{Key: "commentId", Value: bson.M{"$first": "$commentId"}},
+			{Key: "community", Value: bson.M{"$first": "$community"}},
+			{Key: "count", Value: bson.M{"$sum": 1}},
+			{Key: "lastReported", Value: bson.M{"$max": "$created"}},
+			{Key: "reports", Value: bson.M{"$push": "$$ROOT"}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "lastReported", Value: -1}}}},
+		{{Key: "$limit", Value: query.Limit}},
+	}
+
+	cursor, err := r.reports.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]model.ReportedItem, 0)
+	for cursor.Next(context.TODO()) {
+		var item model.ReportedItem
+		err = cursor.Decode(&item)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (r *reportsRepo) CountOpenReports(postID, commentID string) (int, error) {
+	filter := bson.M{"postId": postID, "commentId": commentID, "status": model.ReportStatusOpen}
+	count, err := r.reports.CountDocuments(context.TODO(), filter)
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+func (r *reportsRepo) ResolveReports(postID, commentID string, status string, moderator model.Author, resolved string) (int, error) {
+	filter := bson.M{"postId": postID, "commentId": commentID, "status": model.ReportStatusOpen}
+	update := bson.M{"$set": bson.M{"status": status, "resolvedBy": moderator, "resolved": resolved}}
+	result, err := r.reports.UpdateMany(context.TODO(), filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return int(result.ModifiedCount), nil
+}
@@ -0,0 +1,264 @@
+package mongorepo
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"redditclone/internal/model"
+	"reflect"
+	"testing"
+)
+
+func marshalReport(report model.Report) bson.D {
+	bsonData, _ := bson.Marshal(report)
+
+	var bsonD bson.D
+	_ = bson.Unmarshal(bsonData, &bsonD)
+
+	return bsonD
+}
+
+func TestAddReport(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	report := model.Report{
+		ID:        "1",
+		PostID:    "2",
+		Community: "news",
+		Reporter:  model.Author{ID: "3", Username: "ivan"},
+		Reason:    model.ReportReasonSpam,
+		Status:    model.ReportStatusOpen,
+		Created:   "2022-01-01T00:00:00.000Z",
+	}
+	existing := report
+	existing.ID = "4"
+	existing.Reason = model.ReportReasonOther
+
+	cases := []struct {
+		expectedReport model.Report
+		expectedErr    error
+		run            func() (model.Report, error)
+	}{
+		{
+			expectedReport: report,
+			expectedErr:    nil,
+			run: func() (model.Report, error) {
+				var (
+					stored model.Report
+					err    error
+				)
+				mt.Run("added", func(mt *mtest.T) {
+					repo := NewReportsRepo(mt.Coll)
+					mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: marshalReport(report)}))
+					stored, err = repo.AddReport(report)
+				})
+				return stored, err
+			},
+		},
+		{
+			expectedReport: existing,
+			expectedErr:    nil,
+			run: func() (model.Report, error) {
+				var (
+					stored model.Report
+					err    error
+				)
+				mt.Run("collapsed", func(mt *mtest.T) {
+					repo := NewReportsRepo(mt.Coll)
+					mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: marshalReport(existing)}))
+					stored, err = repo.AddReport(report)
+				})
+				return stored, err
+			},
+		},
+		{
+			expectedReport: existing,
+			expectedErr:    nil,
+			run: func() (model.Report, error) {
+				var (
+					stored model.Report
+					err    error
+				)
+				mt.Run("concurrent report", func(mt *mtest.T) {
+					repo := NewReportsRepo(mt.Coll)
+					mt.AddMockResponses(
+						mtest.CreateWriteErrorsResponse(mtest.WriteError{
+							Index:   0,
+							Code:    11000,
+							Message: "duplicate key error",
+						}),
+						mtest.CreateCursorResponse(0, "redditclone.reports", mtest.FirstBatch, marshalReport(existing)),
+					)
+					stored, err = repo.AddReport(report)
+				})
+				return stored, err
+			},
+		},
+		{
+			expectedReport: model.Report{},
+			expectedErr:    mongo.CommandError{Message: "command failed"},
+			run: func() (model.Report, error) {
+				var (
+					stored model.Report
+					err    error
+				)
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewReportsRepo(mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					stored, err = repo.AddReport(report)
+				})
+				return stored, err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		report, err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+		if !reflect.DeepEqual(item.expectedReport, report) {
+			t.Errorf("[%d] expected report: %+v, got: %+v", i, item.expectedReport, report)
+		}
+	}
+}
+
+func TestGetReportQueue(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	report := model.Report{
+		ID:        "1",
+		PostID:    "2",
+		CommentID: "5",
+		Community: "news",
+		Reporter:  model.Author{ID: "3", Username: "ivan"},
+		Reason:    model.ReportReasonSpam,
+		Status:    model.ReportStatusOpen,
+		Created:   "2022-01-01T00:00:00.000Z",
+	}
+	item := model.ReportedItem{
+		PostID:       "2",
+		CommentID:    "5",
+		Community:    "news",
+		Count:        1,
+		LastReported: "2022-01-01T00:00:00.000Z",
+		Reports:      []model.Report{report},
+	}
+
+	cases := []struct {
+		expectedItems []model.ReportedItem
+		expectedErr   error
+		run           func() ([]model.ReportedItem, error)
+	}{
+		{
+			expectedItems: []model.ReportedItem{item},
+			expectedErr:   nil,
+			run: func() ([]model.ReportedItem, error) {
+				var (
+					items []model.ReportedItem
+					err   error
+				)
+				mt.Run("success", func(mt *mtest.T) {
+					repo := NewReportsRepo(mt.Coll)
+					mt.AddMockResponses(
+						mtest.CreateCursorResponse(0, "redditclone.reports", mtest.FirstBatch, bson.D{
+							{Key: "_id", Value: bson.D{{Key: "postId", Value: "2"}, {Key: "commentId", Value: "5"}}},
+							{Key: "postId", Value: "2"},
+							{Key: "commentId", Value: "5"},
+							{Key: "community", Value: "news"},
+							{Key: "count", Value: 1},
+							{Key: "lastReported", Value: "2022-01-01T00:00:00.000Z"},
+							{Key: "reports", Value: bson.A{marshalReport(report)}},
+						}),
+					)
+					items, err = repo.GetReportQueue(model.ReportsQuery{Community: "news", Limit: 25})
+				})
+				return items, err
+			},
+		},
+		{
+			expectedItems: nil,
+			expectedErr:   mongo.CommandError{Message: "command failed"},
+			run: func() ([]model.ReportedItem, error) {
+				var (
+					items []model.ReportedItem
+					err   error
+				)
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewReportsRepo(mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					items, err = repo.GetReportQueue(model.ReportsQuery{Limit: 25})
+				})
+				return items, err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		items, err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+		if !reflect.DeepEqual(item.expectedItems, items) {
+			t.Errorf("[%d] expected items: %+v, got: %+v", i, item.expectedItems, items)
+		}
+	}
+}
+
+func TestResolveReports(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	moderator := model.Author{ID: "1", Username: "admin"}
+
+	cases := []struct {
+		expectedResolved int
+		expectedErr      error
+		run              func() (int, error)
+	}{
+		{
+			expectedResolved: 2,
+			expectedErr:      nil,
+			run: func() (int, error) {
+				var (
+					resolved int
+					err      error
+				)
+				mt.Run("success", func(mt *mtest.T) {
+					repo := NewReportsRepo(mt.Coll)
+					mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 2}, bson.E{Key: "nModified", Value: 2}))
+					resolved, err = repo.ResolveReports("2", "", model.ReportStatusDismissed, moderator, "2022-01-01T00:00:00.000Z")
+				})
+				return resolved, err
+			},
+		},
+		{
+			expectedResolved: 0,
+			expectedErr:      mongo.CommandError{Message: "command failed"},
+			run: func() (int, error) {
+				var (
+					resolved int
+					err      error
+				)
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewReportsRepo(mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					resolved, err = repo.ResolveReports("2", "", model.ReportStatusDismissed, moderator, "2022-01-01T00:00:00.000Z")
+				})
+				return resolved, err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		resolved, err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+		if item.expectedResolved != resolved {
+			t.Errorf("[%d] expected resolved: %d, got: %d", i, item.expectedResolved, resolved)
+		}
+	}
+}
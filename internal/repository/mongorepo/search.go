@@ -0,0 +1,157 @@
+package mongorepo
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"redditclone/internal/model"
+)
+
+type searchRepo struct {
+	posts    *mongo.Collection
+	comments *mongo.Collection
+}
+
+func NewSearchRepo(posts, comments *mongo.Collection) *searchRepo {
+	return &searchRepo{posts: posts, comments: comments}
+}
+
+// CreateIndexes creates the text indexes, a collection can have only one of them
+func (r *searchRepo) CreateIndexes() error {
+	_, err := r.posts.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{{Key: "title", Value: "text"}, {Key: "text", Value: "text"}, {Key: "url", Value: "text"}},
+		Options: options.Index().
+			SetName("search").
+			SetWeights(bson.D{{Key: "title", Value: 3}, {Key: "text", Value: 1}, {Key: "url", Value: 1}}),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.comments.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "body", Value: "text"}},
+		Options: options.Index().SetName("search"),
+	})
+	return err
+}
+
+// searchFilter matches the text and the filters shared by the posts and the comments,
+// the removed content is left out
+func searchFilter(query model.SearchQuery) bson.M {
+	filter := bson.M{"$text": bson.M{"$search": query.Text}, "removed": bson.M{"$exists": false}}
+	if query.Author != "" {
+		filter["author.username"] = query.Author
+	}
+
+	created := bson.M{}
+	if query.From != "" {
+		created["$gte"] = query.From
+	}
+	if query.To != "" {
+		created["$lt"] = query.To
+	}
+	if len(created) != 0 {
+		filter["created"] = created
+	}
+
+	return filter
+}
+
+func searchSort(sort string) bson.D {
+	if sort == model.SearchSortNew {
+		return bson.D{{Key: "created", Value: -1}, {Key: "id", Value: -1}}
+	}
+	return bson.D{{Key: "relevance", Value: -1}, {Key: "created", Value: -1}, {Key: "id", Value: -1}}
+}
+
+type foundPost struct {
+	Post      model.Post `bson:",inline"`
+	Relevance float64    `bson:"relevance"`
+}
+
+func (r *searchRepo) SearchPosts(query model.SearchQuery, skip int) ([]model.SearchResult, error) {
+	results := make([]model.SearchResult, 0)
+
+	filter := searchFilter(query)
+	if query.Community != "" {
+		filter["category"] = query.Community
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$set", Value: bson.M{"relevance": bson.M{"$meta": "textScore"}}}},
+		{{Key: "$project", Value: bson.M{"revisions": 0}}},
+		{{Key: "$sort", Value: searchSort(query.Sort)}},
+		{{Key: "$skip", Value: skip}},
+		{{Key: "$limit", Value: query.Limit}},
+	}
+	cursor, err := r.posts.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	for cursor.Next(context.TODO()) {
+		var found foundPost
+		err = cursor.Decode(&found)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, model.NewPostResult(found.Post, found.Relevance))
+	}
+
+	return results, nil
+}
+
+type foundComment struct {
+	Comment   model.Comment `bson:",inline"`
+	Relevance float64       `bson:"relevance"`
+}
+
+// SearchComments looks the community up in the posts, the comments don't keep it
+func (r *searchRepo) SearchComments(query model.SearchQuery, skip int) ([]model.SearchResult, error) {
+	results := make([]model.SearchResult, 0)
+
+	filter := searchFilter(query)
+	filter["deleted"] = false
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$set", Value: bson.M{"relevance": bson.M{"$meta": "textScore"}}}},
+	}
+	if query.Community != "" {
+		pipeline = append(pipeline,
+			bson.D{{Key: "$lookup", Value: bson.M{
+				"from":         r.posts.Name(),
+				"localField":   "postId",
+				"foreignField": "id",
+				"as":           "post",
+			}}},
+			bson.D{{Key: "$match", Value: bson.M{"post.category": query.Community}}},
+			bson.D{{Key: "$unset", Value: "post"}},
+		)
+	}
+	pipeline = append(pipeline,
+		bson.D{{Key: "$sort", Value: searchSort(query.Sort)}},
+		bson.D{{Key: "$skip", Value: skip}},
+		bson.D{{Key: "$limit", Value: query.Limit}},
+	)
+
+	cursor, err := r.comments.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	for cursor.Next(context.TODO()) {
+		var found foundComment
+		err = cursor.Decode(&found)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, model.NewCommentResult(found.Comment, found.Relevance))
+	}
+
+	return results, nil
+}
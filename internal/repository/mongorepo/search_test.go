@@ -0,0 +1,129 @@
+package mongorepo
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"redditclone/internal/model"
+	"reflect"
+	"testing"
+)
+
+func marshalFound(doc bson.D, relevance float64) bson.D {
+	return append(doc, bson.E{Key: "relevance", Value: relevance})
+}
+
+func TestSearchPosts(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	query := model.SearchQuery{Text: "gopher", Community: "programming", Author: "admin", Limit: 2}
+
+	cases := []struct {
+		expectedResults []model.SearchResult
+		expectedErr     error
+		run             func(results []model.SearchResult) ([]model.SearchResult, error)
+	}{
+		{
+			expectedResults: []model.SearchResult{
+				model.NewPostResult(model.Post{ID: "2", Title: "gopher", Category: "programming"}, 3),
+				model.NewPostResult(model.Post{ID: "1", Text: "gopher", Category: "programming"}, 1),
+			},
+			expectedErr: nil,
+			run: func(results []model.SearchResult) ([]model.SearchResult, error) {
+				var err error
+				mt.Run("success", func(mt *mtest.T) {
+					repo := NewSearchRepo(mt.Coll, mt.Coll)
+					mt.AddMockResponses(
+						mtest.CreateCursorResponse(1, "redditclone.posts", mtest.FirstBatch,
+							marshalFound(marshalPost(*results[0].Post), results[0].Relevance)),
+						mtest.CreateCursorResponse(1, "redditclone.posts", mtest.NextBatch,
+							marshalFound(marshalPost(*results[1].Post), results[1].Relevance)),
+						mtest.CreateCursorResponse(0, "redditclone.posts", mtest.NextBatch),
+					)
+					results, err = repo.SearchPosts(query, 0)
+				})
+				return results, err
+			},
+		},
+		{
+			expectedResults: nil,
+			expectedErr:     mongo.CommandError{Message: "command failed"},
+			run: func(results []model.SearchResult) ([]model.SearchResult, error) {
+				var err error
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewSearchRepo(mt.Coll, mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					results, err = repo.SearchPosts(query, 0)
+				})
+				return results, err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		results, err := item.run(item.expectedResults)
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+		if !reflect.DeepEqual(item.expectedResults, results) {
+			t.Errorf("[%d] expected results: %+v, got: %+v", i, item.expectedResults, results)
+		}
+	}
+}
+
+func TestSearchComments(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	query := model.SearchQuery{Text: "gopher", Community: "programming", Sort: model.SearchSortNew, Limit: 2}
+
+	cases := []struct {
+		expectedResults []model.SearchResult
+		expectedErr     error
+		run             func(results []model.SearchResult) ([]model.SearchResult, error)
+	}{
+		{
+			expectedResults: []model.SearchResult{
+				model.NewCommentResult(model.Comment{ID: "1", PostID: "1", Body: "gopher", Created: "2022-01-01T00:00:00.000Z"}, 1.5),
+			},
+			expectedErr: nil,
+			run: func(results []model.SearchResult) ([]model.SearchResult, error) {
+				var err error
+				mt.Run("success", func(mt *mtest.T) {
+					repo := NewSearchRepo(mt.Coll, mt.Coll)
+					mt.AddMockResponses(
+						mtest.CreateCursorResponse(1, "redditclone.comments", mtest.FirstBatch,
+							marshalFound(marshalComment(*results[0].Comment), results[0].Relevance)),
+						mtest.CreateCursorResponse(0, "redditclone.comments", mtest.NextBatch),
+					)
+					results, err = repo.SearchComments(query, 1)
+				})
+				return results, err
+			},
+		},
+		{
+			expectedResults: nil,
+			expectedErr:     mongo.CommandError{Message: "command failed"},
+			run: func(results []model.SearchResult) ([]model.SearchResult, error) {
+				var err error
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewSearchRepo(mt.Coll, mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					results, err = repo.SearchComments(query, 1)
+				})
+				return results, err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		results, err := item.run(item.expectedResults)
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+		if !reflect.DeepEqual(item.expectedResults, results) {
+			t.Errorf("[%d] expected results: %+v, got: %+v", i, item.expectedResults, results)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package mongorepo
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"redditclone/internal/model"
+)
+
+type votesRepo struct {
+	votes *mongo.Collection
+}
+
+func NewVotesRepo(collection *mongo.Collection) *votesRepo {
+	return &votesRepo{votes: collection}
+}
+
+// CreateIndexes keeps a single vote of a user per post or comment
+func (r *votesRepo) CreateIndexes() error {
+	_, err := r.votes.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user", Value: 1}, {Key: "postId", Value: 1}, {Key: "commentId", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user", Value: 1}, {Key: "created", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "postId", Value: 1}},
+		},
+	})
+	return err
+}
+
+// SetVote replaces the vote of the user and returns the replaced value, VoteNone drops the vote
+func (r *votesRepo) SetVote(vote model.Vote) (int, error) {
+	filter := bson.M{"user": vote.UserID, "postId": vote.PostID, "commentId": vote.CommentID}
+
+	var (
+		previous model.Vote
+		err      error
+	)
+	if vote.Vote == model.VoteNone {
+		err = r.votes.FindOneAndDelete(context.TODO(), filter).Decode(&previous)
+	} else {
+		update := bson.M{"$set": bson.M{"vote": vote.Vote, "created": vote.Created}}
+		opt := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.Before)
+		err = r.votes.FindOneAndUpdate(context.TODO(), filter, update, opt).Decode(&previous)
+		// two first votes of the user race on the unique index, the loser retries once and updates the vote of the winner
+		if mongo.IsDuplicateKeyError(err) {
+			err = r.votes.FindOneAndUpdate(context.TODO(), filter, update, opt).Decode(&previous)
+		}
+	}
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return model.VoteNone, nil
+		}
+		return model.VoteNone, err
+	}
+
+	return previous.Vote, nil
+}
+
+func (r *votesRepo) findVotes(filter bson.M, opts ...*options.FindOptions) ([]model.Vote, error) {
+	votes := make([]model.Vote, 0)
+	cursor, err := r.votes.Find(context.TODO(), filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	for cursor.Next(context.TODO()) {
+		var vote model.Vote
+		err = cursor.Decode(&vote)
+		if err != nil {
+			return nil, err
+		}
+
+		votes = append(votes, vote)
+	}
+
+	return votes, nil
+}
+
+// GetUserVotes returns the votes of the user on the posts and on their comments
+func (r *votesRepo) GetUserVotes(userID string, postIDs []string) ([]model.Vote, error) {
+	filter := bson.M{"user": userID, "postId": bson.M{"$in": postIDs}}
+	return r.findVotes(filter)
+}
+
+func (r *votesRepo) GetVotesByUser(userID string) ([]model.Vote, error) {
+	filter := bson.M{"user": userID}
+	opt := options.Find().SetSort(bson.D{{Key: "created", Value: -1}})
+	return r.findVotes(filter, opt)
+}
+
+func (r *votesRepo) DeletePostVotes(postID string) error {
+	filter := bson.M{"postId": postID}
+	_, err := r.votes.DeleteMany(context.TODO(), filter)
+	return err
+}
+
+func (r *votesRepo) DeleteCommentVotes(postID, commentID string) error {
+	filter := bson.M{"postId": postID, "commentId": commentID}
+	_, err := r.votes.DeleteMany(context.TODO(), filter)
+	return err
+}
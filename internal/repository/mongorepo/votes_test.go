@@ -0,0 +1,319 @@
+package mongorepo
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"redditclone/internal/model"
+	"reflect"
+	"testing"
+)
+
+func marshalVote(vote model.Vote) bson.D {
+	bsonData, _ := bson.Marshal(vote)
+
+	var bsonD bson.D
+	_ = bson.Unmarshal(bsonData, &bsonD)
+
+	return bsonD
+}
+
+func TestSetVote(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	cases := []struct {
+		expectedPrevious int
+		expectedErr      error
+		run              func() (int, error)
+	}{
+		{
+			expectedPrevious: model.VoteDown,
+			expectedErr:      nil,
+			run: func() (int, error) {
+				var (
+					previous int
+					err      error
+				)
+				mt.Run("vote replaced", func(mt *mtest.T) {
+					repo := NewVotesRepo(mt.Coll)
+					doc := marshalVote(model.Vote{UserID: "1", PostID: "1", Vote: model.VoteDown})
+					mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: doc}))
+					previous, err = repo.SetVote(model.Vote{UserID: "1", PostID: "1", Vote: model.VoteUp})
+				})
+				return previous, err
+			},
+		},
+		{
+			expectedPrevious: model.VoteUp,
+			expectedErr:      nil,
+			run: func() (int, error) {
+				var (
+					previous int
+					err      error
+				)
+				mt.Run("racing first votes", func(mt *mtest.T) {
+					repo := NewVotesRepo(mt.Coll)
+					doc := marshalVote(model.Vote{UserID: "1", PostID: "1", Vote: model.VoteUp})
+					mt.AddMockResponses(
+						mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 0, Code: 11000, Message: "duplicate key error"}),
+						mtest.CreateSuccessResponse(bson.E{Key: "value", Value: doc}),
+					)
+					previous, err = repo.SetVote(model.Vote{UserID: "1", PostID: "1", Vote: model.VoteDown})
+				})
+				return previous, err
+			},
+		},
+		{
+			expectedPrevious: model.VoteNone,
+			expectedErr:      nil,
+			run: func() (int, error) {
+				var (
+					previous int
+					err      error
+				)
+				mt.Run("first vote", func(mt *mtest.T) {
+					repo := NewVotesRepo(mt.Coll)
+					mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil}))
+					previous, err = repo.SetVote(model.Vote{UserID: "1", PostID: "1", Vote: model.VoteUp})
+				})
+				return previous, err
+			},
+		},
+		{
+			expectedPrevious: model.VoteUp,
+			expectedErr:      nil,
+			run: func() (int, error) {
+				var (
+					previous int
+					err      error
+				)
+				mt.Run("vote dropped", func(mt *mtest.T) {
+					repo := NewVotesRepo(mt.Coll)
+					doc := marshalVote(model.Vote{UserID: "1", PostID: "1", CommentID: "1", Vote: model.VoteUp})
+					mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: doc}))
+					previous, err = repo.SetVote(model.Vote{UserID: "1", PostID: "1", CommentID: "1", Vote: model.VoteNone})
+				})
+				return previous, err
+			},
+		},
+		{
+			expectedPrevious: model.VoteNone,
+			expectedErr:      mongo.CommandError{Message: "command failed"},
+			run: func() (int, error) {
+				var (
+					previous int
+					err      error
+				)
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewVotesRepo(mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					previous, err = repo.SetVote(model.Vote{UserID: "1", PostID: "1", Vote: model.VoteUp})
+				})
+				return previous, err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		previous, err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+		if item.expectedPrevious != previous {
+			t.Errorf("[%d] expected previous vote: %d, got: %d", i, item.expectedPrevious, previous)
+		}
+	}
+}
+
+func TestGetVotesByUser(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	cases := []struct {
+		expectedVotes []model.Vote
+		expectedErr   error
+		run           func(votes []model.Vote) ([]model.Vote, error)
+	}{
+		{
+			expectedVotes: []model.Vote{
+				{UserID: "1", PostID: "1", Vote: model.VoteUp, Created: "2022-01-02T00:00:00.000Z"},
+				{UserID: "1", PostID: "1", CommentID: "1", Vote: model.VoteDown, Created: "2022-01-01T00:00:00.000Z"},
+			},
+			expectedErr: nil,
+			run: func(votes []model.Vote) ([]model.Vote, error) {
+				var err error
+				mt.Run("success", func(mt *mtest.T) {
+					repo := NewVotesRepo(mt.Coll)
+					mt.AddMockResponses(
+						mtest.CreateCursorResponse(1, "redditclone.votes", mtest.FirstBatch, marshalVote(votes[0])),
+						mtest.CreateCursorResponse(1, "redditclone.votes", mtest.NextBatch, marshalVote(votes[1])),
+						mtest.CreateCursorResponse(0, "redditclone.votes", mtest.NextBatch),
+					)
+					votes, err = repo.GetVotesByUser("1")
+				})
+				return votes, err
+			},
+		},
+		{
+			expectedVotes: nil,
+			expectedErr:   mongo.CommandError{Message: "command failed"},
+			run: func(votes []model.Vote) ([]model.Vote, error) {
+				var err error
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewVotesRepo(mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					votes, err = repo.GetVotesByUser("1")
+				})
+				return votes, err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		votes, err := item.run(item.expectedVotes)
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+		if !reflect.DeepEqual(item.expectedVotes, votes) {
+			t.Errorf("[%d] expected votes: %+v, got: %+v", i, item.expectedVotes, votes)
+		}
+	}
+}
+
+func TestGetUserVotes(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	cases := []struct {
+		expectedVotes []model.Vote
+		expectedErr   error
+		run           func(votes []model.Vote) ([]model.Vote, error)
+	}{
+		{
+			expectedVotes: []model.Vote{
+				{UserID: "1", PostID: "2", Vote: model.VoteUp},
+			},
+			expectedErr: nil,
+			run: func(votes []model.Vote) ([]model.Vote, error) {
+				var err error
+				mt.Run("success", func(mt *mtest.T) {
+					repo := NewVotesRepo(mt.Coll)
+					mt.AddMockResponses(
+						mtest.CreateCursorResponse(1, "redditclone.votes", mtest.FirstBatch, marshalVote(votes[0])),
+						mtest.CreateCursorResponse(0, "redditclone.votes", mtest.NextBatch),
+					)
+					votes, err = repo.GetUserVotes("1", []string{"1", "2"})
+				})
+				return votes, err
+			},
+		},
+		{
+			expectedVotes: nil,
+			expectedErr:   mongo.CommandError{Message: "command failed"},
+			run: func(votes []model.Vote) ([]model.Vote, error) {
+				var err error
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewVotesRepo(mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					votes, err = repo.GetUserVotes("1", []string{"1", "2"})
+				})
+				return votes, err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		votes, err := item.run(item.expectedVotes)
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+		if !reflect.DeepEqual(item.expectedVotes, votes) {
+			t.Errorf("[%d] expected votes: %+v, got: %+v", i, item.expectedVotes, votes)
+		}
+	}
+}
+
+func TestDeletePostVotes(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	cases := []struct {
+		expectedErr error
+		run         func() error
+	}{
+		{
+			expectedErr: nil,
+			run: func() error {
+				var err error
+				mt.Run("success", func(mt *mtest.T) {
+					repo := NewVotesRepo(mt.Coll)
+					mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 2}))
+					err = repo.DeletePostVotes("1")
+				})
+				return err
+			},
+		},
+		{
+			expectedErr: mongo.CommandError{Message: "command failed"},
+			run: func() error {
+				var err error
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewVotesRepo(mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					err = repo.DeletePostVotes("1")
+				})
+				return err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+	}
+}
+
+func TestDeleteCommentVotes(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	cases := []struct {
+		expectedErr error
+		run         func() error
+	}{
+		{
+			expectedErr: nil,
+			run: func() error {
+				var err error
+				mt.Run("success", func(mt *mtest.T) {
+					repo := NewVotesRepo(mt.Coll)
+					mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 2}))
+					err = repo.DeleteCommentVotes("1", "2")
+				})
+				return err
+			},
+		},
+		{
+			expectedErr: mongo.CommandError{Message: "command failed"},
+			run: func() error {
+				var err error
+				mt.Run("command failed", func(mt *mtest.T) {
+					repo := NewVotesRepo(mt.Coll)
+					mt.AddMockResponses(bson.D{{Key: "ok", Value: 0}})
+					err = repo.DeleteCommentVotes("1", "2")
+				})
+				return err
+			},
+		},
+	}
+
+	for i, item := range cases {
+		err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+	}
+}
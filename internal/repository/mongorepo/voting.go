@@ -0,0 +1,68 @@
+package mongorepo
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"redditclone/internal/model"
+)
+
+// countsPipeline applies a model.VoteDelta and derives the score and the upvote percentage in one update,
+// the same way model.Voting.AddCounts does
+func countsPipeline(ups, downs int) mongo.Pipeline {
+	total := bson.M{"$add": bson.A{"$ups", "$downs"}}
+
+	return mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"ups":   bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$ups", 0}}, ups}},
+			"downs": bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$downs", 0}}, downs}},
+		}}},
+		{{Key: "$set", Value: bson.M{
+			"score": bson.M{"$subtract": bson.A{"$ups", "$downs"}},
+			"upvotePercentage": bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{total, 0}},
+				0,
+				bson.M{"$toInt": bson.M{"$trunc": bson.M{"$divide": bson.A{
+					bson.M{"$multiply": bson.A{100, "$ups"}},
+					total,
+				}}}},
+			}},
+		}}},
+	}
+}
+
+// ranksPipeline follows model.HotRank and model.ControversialRank, it expects the counts set by countsPipeline
+func ranksPipeline() mongo.Pipeline {
+	sign := bson.M{"$cond": bson.A{
+		bson.M{"$gt": bson.A{"$score", 0}},
+		1,
+		bson.M{"$cond": bson.A{bson.M{"$lt": bson.A{"$score", 0}}, -1, 0}},
+	}}
+	order := bson.M{"$log10": bson.M{"$max": bson.A{bson.M{"$abs": "$score"}, 1}}}
+	seconds := bson.M{"$subtract": bson.A{
+		bson.M{"$floor": bson.M{"$divide": bson.A{
+			bson.M{"$toLong": bson.M{"$dateFromString": bson.M{"dateString": "$created"}}},
+			1000,
+		}}},
+		model.HotEpoch,
+	}}
+
+	balance := bson.M{"$cond": bson.A{
+		bson.M{"$lt": bson.A{"$ups", "$downs"}},
+		bson.M{"$divide": bson.A{"$ups", "$downs"}},
+		bson.M{"$divide": bson.A{"$downs", "$ups"}},
+	}}
+
+	return mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"hot": bson.M{"$add": bson.A{
+				bson.M{"$multiply": bson.A{sign, order}},
+				bson.M{"$divide": bson.A{seconds, model.HotDecay}},
+			}},
+			"controversial": bson.M{"$cond": bson.A{
+				bson.M{"$or": bson.A{bson.M{"$lte": bson.A{"$ups", 0}}, bson.M{"$lte": bson.A{"$downs", 0}}}},
+				0,
+				bson.M{"$pow": bson.A{bson.M{"$add": bson.A{"$ups", "$downs"}}, balance}},
+			}},
+		}}},
+	}
+}
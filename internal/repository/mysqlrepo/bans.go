@@ -0,0 +1,117 @@
+package mysqlrepo
+
+import (
+	"database/sql"
+	"redditclone/internal/model"
+	"redditclone/internal/model/customerr"
+)
+
+type bansRepo struct {
+	db *sql.DB
+}
+
+func NewBansRepo(db *sql.DB) *bansRepo {
+	return &bansRepo{db: db}
+}
+
+// AddBan replaces the previous ban of the user in the community
+func (r *bansRepo) AddBan(ban model.Ban) error {
+	_, err := r.db.Exec(
+		"INSERT INTO community_ban "+
+			"(`id`, `community`, `user_id`, `username`, `moderator_id`, `moderator_username`, `reason`, `expires`, `created`) "+
+			"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) "+
+			"ON DUPLICATE KEY UPDATE id = VALUES(id), moderator_id = VALUES(moderator_id), "+
+			"moderator_username = VALUES(moderator_username), reason = VALUES(reason), "+
+			"expires = VALUES(expires), created = VALUES(created)",
+		ban.ID,
+		ban.Community,
+		ban.User.ID,
+		ban.User.Username,
+		ban.Moderator.ID,
+		ban.Moderator.Username,
+		ban.Reason,
+		ban.Expires,
+		ban.Created,
+	)
+	return err
+}
+
+// rowScanner is either *sql.Row or *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanBan(row rowScanner) (model.Ban, error) {
+	var ban model.Ban
+	err := row.Scan(
+		&ban.ID,
+		&ban.Community,
+		&ban.User.ID,
+		&ban.User.Username,
+		&ban.Moderator.ID,
+		&ban.Moderator.Username,
+		&ban.Reason,
+		&ban.Expires,
+		&ban.Created,
+	)
+	return ban, err
+}
+
+// GetBan returns the ban even if it has expired, the caller checks whether it is active
+func (r *bansRepo) GetBan(community, userID string) (model.Ban, error) {
+	ban, err := scanBan(r.db.QueryRow(
+		"SELECT id, community, user_id, username, moderator_id, moderator_username, reason, expires, created "+
+			"FROM community_ban WHERE community = ? AND user_id = ?",
+		community,
+		userID,
+	))
+	if err == sql.ErrNoRows {
+		return model.Ban{}, customerr.BanNotFound{Community: community, UserID: userID}
+	}
+	return ban, err
+}
+
+// GetActiveBans leaves out the bans expired by now
+func (r *bansRepo) GetActiveBans(community string, now string) ([]model.Ban, error) {
+	rows, err := r.db.Query(
+		"SELECT id, community, user_id, username, moderator_id, moderator_username, reason, expires, created "+
+			"FROM community_ban WHERE community = ? AND (expires = '' OR expires > ?) ORDER BY created DESC",
+		community,
+		now,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	bans := make([]model.Ban, 0)
+	for rows.Next() {
+		ban, err := scanBan(rows)
+		if err != nil {
+			return nil, err
+		}
+		bans = append(bans, ban)
+	}
+
+	return bans, rows.Err()
+}
+
+func (r *bansRepo) DeleteBan(community, userID string) error {
+	res, err := r.db.Exec(
+		"DELETE FROM community_ban WHERE community = ? AND user_id = ?",
+		community,
+		userID,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return customerr.BanNotFound{Community: community, UserID: userID}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package mysqlrepo
+
+import (
+	"database/sql"
+	"errors"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+	"redditclone/internal/model"
+	"redditclone/internal/model/customerr"
+	"reflect"
+	"testing"
+)
+
+var banColumnNames = []string{"id", "community", "user_id", "username", "moderator_id", "moderator_username", "reason", "expires", "created"}
+
+func TestBans(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Errorf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewBansRepo(db)
+
+	ban := model.Ban{
+		ID:        "1",
+		Community: "news",
+		User:      model.Author{ID: "2", Username: "van"},
+		Moderator: model.Author{ID: "3", Username: "admin"},
+		Reason:    "spam",
+		Expires:   "2022-01-08T00:00:00.000Z",
+		Created:   "2022-01-01T00:00:00.000Z",
+	}
+	banRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows(banColumnNames).AddRow(
+			ban.ID, ban.Community, ban.User.ID, ban.User.Username, ban.Moderator.ID, ban.Moderator.Username,
+			ban.Reason, ban.Expires, ban.Created,
+		)
+	}
+
+	cases := []struct {
+		expected    interface{}
+		expectedErr error
+		run         func() (interface{}, error)
+	}{
+		{
+			expected:    nil,
+			expectedErr: nil,
+			run: func() (interface{}, error) {
+				mock.
+					ExpectExec("INSERT INTO community_ban (.+) ON DUPLICATE KEY UPDATE").
+					WithArgs("1", "news", "2", "van", "3", "admin", "spam", "2022-01-08T00:00:00.000Z", "2022-01-01T00:00:00.000Z").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				return nil, repo.AddBan(ban)
+			},
+		},
+		{
+			expected:    ban,
+			expectedErr: nil,
+			run: func() (interface{}, error) {
+				mock.
+					ExpectQuery("SELECT (.+) FROM community_ban WHERE community = \\? AND user_id = \\?").
+					WithArgs("news", "2").
+					WillReturnRows(banRows())
+				return repo.GetBan("news", "2")
+			},
+		},
+		{
+			expected:    model.Ban{},
+			expectedErr: customerr.BanNotFound{Community: "news", UserID: "4"},
+			run: func() (interface{}, error) {
+				mock.
+					ExpectQuery("SELECT (.+) FROM community_ban WHERE community = \\? AND user_id = \\?").
+					WithArgs("news", "4").
+					WillReturnError(sql.ErrNoRows)
+				return repo.GetBan("news", "4")
+			},
+		},
+		{
+			expected:    []model.Ban{ban},
+			expectedErr: nil,
+			run: func() (interface{}, error) {
+				mock.
+					ExpectQuery("SELECT (.+) FROM community_ban WHERE community = \\? AND \\(expires = '' OR expires > \\?\\)").
+					WithArgs("news", "2022-01-02T00:00:00.000Z").
+					WillReturnRows(banRows())
+				return repo.GetActiveBans("news", "2022-01-02T00:00:00.000Z")
+			},
+		},
+		{
+			expected:    []model.Ban(nil),
+			expectedErr: errors.New("bad query"),
+			run: func() (interface{}, error) {
+				mock.
+					ExpectQuery("SELECT (.+) FROM community_ban WHERE").
+					WithArgs("news", "2022-01-02T00:00:00.000Z").
+					WillReturnError(errors.New("bad query"))
+				return repo.GetActiveBans("news", "2022-01-02T00:00:00.000Z")
+			},
+		},
+		{
+			expected:    nil,
+			expectedErr: nil,
+			run: func() (interface{}, error) {
+				mock.
+					ExpectExec("DELETE FROM community_ban WHERE").
+					WithArgs("news", "2").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				return nil, repo.DeleteBan("news", "2")
+			},
+		},
+		{
+			expected:    nil,
+			expectedErr: customerr.BanNotFound{Community: "news", UserID: "4"},
+			run: func() (interface{}, error) {
+				mock.
+					ExpectExec("DELETE FROM community_ban WHERE").
+					WithArgs("news", "4").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				return nil, repo.DeleteBan("news", "4")
+			},
+		},
+	}
+
+	for i, item := range cases {
+		result, err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+		if !reflect.DeepEqual(item.expected, result) {
+			t.Errorf("[%d] expected: %+v, got: %+v", i, item.expected, result)
+		}
+	}
+	if err = mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,220 @@
+package mysqlrepo
+
+import (
+	"database/sql"
+	"redditclone/internal/model"
+)
+
+type messagesRepo struct {
+	db *sql.DB
+}
+
+func NewMessagesRepo(db *sql.DB) *messagesRepo {
+	return &messagesRepo{db: db}
+}
+
+const messageColumns = "m.id, m.sender_id, m.sender_username, m.recipient_id, m.recipient_username, m.body, m.is_read, m.created"
+
+// scanMessage reads the message columns followed by the extra ones
+func scanMessage(rows *sql.Rows, message *model.Message, extra ...interface{}) error {
+	dest := []interface{}{
+		&message.ID,
+		&message.Sender.ID,
+		&message.Sender.Username,
+		&message.Recipient.ID,
+		&message.Recipient.Username,
+		&message.Body,
+		&message.Read,
+		&message.Created,
+	}
+	return rows.Scan(append(dest, extra...)...)
+}
+
+// AddMessage stores the message and moves both sides of the conversation to it in one transaction
+func (r *messagesRepo) AddMessage(message model.Message) (err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	_, err = tx.Exec(
+		"INSERT INTO message (`id`, `sender_id`, `sender_username`, `recipient_id`, `recipient_username`, `body`, `is_read`, `created`) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		message.ID,
+		message.Sender.ID,
+		message.Sender.Username,
+		message.Recipient.ID,
+		message.Recipient.Username,
+		message.Body,
+		message.Read,
+		message.Created,
+	)
+	if err != nil {
+		return err
+	}
+
+	sides := []struct {
+		userID  string
+		otherID string
+		unread  int
+	}{
+		{userID: message.Sender.ID, otherID: message.Recipient.ID, unread: 0},
+		{userID: message.Recipient.ID, otherID: message.Sender.ID, unread: 1},
+	}
+	for _, side := range sides {
+		_, err = tx.Exec(
+			"INSERT INTO conversation (`user_id`, `other_id`, `last_message_id`, `last_created`, `unread`) VALUES (?, ?, ?, ?, ?) "+
+				"ON DUPLICATE KEY UPDATE last_message_id = VALUES(last_message_id), last_created = VALUES(last_created), unread = unread + VALUES(unread)",
+			side.userID,
+			side.otherID,
+			message.ID,
+			message.Created,
+			side.unread,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *messagesRepo) GetConversations(userID string) ([]model.Conversation, error) {
+	rows, err := r.db.Query(
+		"SELECT "+messageColumns+", c.unread FROM conversation c JOIN message m ON m.id = c.last_message_id WHERE c.user_id = ? ORDER BY c.last_created DESC",
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	conversations := make([]model.Conversation, 0)
+	for rows.Next() {
+		var (
+			message model.Message
+			unread  int
+		)
+		if err = scanMessage(rows, &message, &unread); err != nil {
+			return nil, err
+		}
+		conversations = append(conversations, model.NewConversation(userID, message, unread))
+	}
+
+	return conversations, rows.Err()
+}
+
+func (r *messagesRepo) GetThread(userID, otherID string) ([]model.Message, error) {
+	rows, err := r.db.Query(
+		"SELECT "+messageColumns+" FROM message m WHERE (m.sender_id = ? AND m.recipient_id = ?) OR (m.sender_id = ? AND m.recipient_id = ?) ORDER BY m.created, m.id",
+		userID,
+		otherID,
+		otherID,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := make([]model.Message, 0)
+	for rows.Next() {
+		var message model.Message
+		if err = scanMessage(rows, &message); err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, rows.Err()
+}
+
+// MarkThreadRead reads the received messages and resets the unread count of the user's side
+func (r *messagesRepo) MarkThreadRead(userID, otherID string) (err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	_, err = tx.Exec(
+		"UPDATE message SET is_read = TRUE WHERE recipient_id = ? AND sender_id = ? AND is_read = FALSE",
+		userID,
+		otherID,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		"UPDATE conversation SET unread = 0 WHERE user_id = ? AND other_id = ?",
+		userID,
+		otherID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// AddBlock keeps the first block of the user, blocking again changes nothing
+func (r *messagesRepo) AddBlock(block model.Block) error {
+	_, err := r.db.Exec(
+		"INSERT IGNORE INTO user_block (`user_id`, `blocked_id`, `blocked_username`, `created`) VALUES (?, ?, ?, ?)",
+		block.UserID,
+		block.Blocked.ID,
+		block.Blocked.Username,
+		block.Created,
+	)
+	return err
+}
+
+func (r *messagesRepo) DeleteBlock(userID, blockedID string) error {
+	_, err := r.db.Exec(
+		"DELETE FROM user_block WHERE user_id = ? AND blocked_id = ?",
+		userID,
+		blockedID,
+	)
+	return err
+}
+
+func (r *messagesRepo) GetBlocks(userID string) ([]model.Block, error) {
+	rows, err := r.db.Query(
+		"SELECT user_id, blocked_id, blocked_username, created FROM user_block WHERE user_id = ? ORDER BY created DESC",
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	blocks := make([]model.Block, 0)
+	for rows.Next() {
+		var block model.Block
+		if err = rows.Scan(&block.UserID, &block.Blocked.ID, &block.Blocked.Username, &block.Created); err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+	}
+
+	return blocks, rows.Err()
+}
+
+func (r *messagesRepo) IsBlocked(userID, blockedID string) (bool, error) {
+	var count int
+	err := r.db.QueryRow(
+		"SELECT COUNT(*) FROM user_block WHERE user_id = ? AND blocked_id = ?",
+		userID,
+		blockedID,
+	).Scan(&count)
+	return count != 0, err
+}
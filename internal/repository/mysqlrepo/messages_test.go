@@ -0,0 +1,360 @@
+package mysqlrepo
+
+import (
+	"errors"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+	"redditclone/internal/model"
+	"reflect"
+	"testing"
+)
+
+var messageColumnNames = []string{"id", "sender_id", "sender_username", "recipient_id", "recipient_username", "body", "is_read", "created"}
+
+func TestAddMessage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Errorf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewMessagesRepo(db)
+
+	message := model.Message{
+		ID:        "1",
+		Sender:    model.Author{ID: "2", Username: "van"},
+		Recipient: model.Author{ID: "3", Username: "ivan"},
+		Body:      "hi",
+		Created:   "2022-04-10T12:00:00.000Z",
+	}
+
+	cases := []struct {
+		expectedErr error
+		run         func() error
+	}{
+		{
+			expectedErr: nil,
+			run: func() error {
+				mock.ExpectBegin()
+				mock.
+					ExpectExec("INSERT INTO message").
+					WithArgs("1", "2", "van", "3", "ivan", "hi", false, "2022-04-10T12:00:00.000Z").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.
+					ExpectExec("INSERT INTO conversation").
+					WithArgs("2", "3", "1", "2022-04-10T12:00:00.000Z", 0).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.
+					ExpectExec("INSERT INTO conversation").
+					WithArgs("3", "2", "1", "2022-04-10T12:00:00.000Z", 1).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+				return repo.AddMessage(message)
+			},
+		},
+		{
+			expectedErr: errors.New("bad query"),
+			run: func() error {
+				mock.ExpectBegin()
+				mock.
+					ExpectExec("INSERT INTO message").
+					WithArgs("1", "2", "van", "3", "ivan", "hi", false, "2022-04-10T12:00:00.000Z").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.
+					ExpectExec("INSERT INTO conversation").
+					WithArgs("2", "3", "1", "2022-04-10T12:00:00.000Z", 0).
+					WillReturnError(errors.New("bad query"))
+				mock.ExpectRollback()
+				return repo.AddMessage(message)
+			},
+		},
+		{
+			expectedErr: errors.New("bad begin"),
+			run: func() error {
+				mock.ExpectBegin().WillReturnError(errors.New("bad begin"))
+				return repo.AddMessage(message)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("[%d] unfulfilled expectations: %s", i, err)
+		}
+	}
+}
+
+func TestGetConversations(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Errorf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewMessagesRepo(db)
+
+	message := model.Message{
+		ID:        "1",
+		Sender:    model.Author{ID: "2", Username: "van"},
+		Recipient: model.Author{ID: "3", Username: "ivan"},
+		Body:      "hi",
+		Created:   "2022-04-10T12:00:00.000Z",
+	}
+
+	cases := []struct {
+		expectedConversations []model.Conversation
+		expectedErr           error
+		run                   func() ([]model.Conversation, error)
+	}{
+		{
+			expectedConversations: []model.Conversation{{With: message.Sender, LastMessage: message, Unread: 2}},
+			expectedErr:           nil,
+			run: func() ([]model.Conversation, error) {
+				rows := sqlmock.NewRows(append(messageColumnNames, "unread"))
+				rows.AddRow("1", "2", "van", "3", "ivan", "hi", false, "2022-04-10T12:00:00.000Z", 2)
+				mock.
+					ExpectQuery("SELECT (.+) FROM conversation c JOIN message m").
+					WithArgs("3").
+					WillReturnRows(rows)
+				return repo.GetConversations("3")
+			},
+		},
+		{
+			expectedConversations: nil,
+			expectedErr:           errors.New("bad query"),
+			run: func() ([]model.Conversation, error) {
+				mock.
+					ExpectQuery("SELECT (.+) FROM conversation c JOIN message m").
+					WithArgs("3").
+					WillReturnError(errors.New("bad query"))
+				return repo.GetConversations("3")
+			},
+		},
+	}
+
+	for i, item := range cases {
+		conversations, err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+		if !reflect.DeepEqual(item.expectedConversations, conversations) {
+			t.Errorf("[%d] expected conversations: %+v, got: %+v", i, item.expectedConversations, conversations)
+		}
+	}
+}
+
+func TestGetThread(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Errorf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewMessagesRepo(db)
+
+	messages := []model.Message{
+		{
+			ID:        "1",
+			Sender:    model.Author{ID: "2", Username: "van"},
+			Recipient: model.Author{ID: "3", Username: "ivan"},
+			Body:      "hi",
+			Read:      true,
+			Created:   "2022-04-10T12:00:00.000Z",
+		},
+		{
+			ID:        "4",
+			Sender:    model.Author{ID: "3", Username: "ivan"},
+			Recipient: model.Author{ID: "2", Username: "van"},
+			Body:      "hello",
+			Created:   "2022-04-10T12:01:00.000Z",
+		},
+	}
+
+	cases := []struct {
+		expectedMessages []model.Message
+		expectedErr      error
+		run              func() ([]model.Message, error)
+	}{
+		{
+			expectedMessages: messages,
+			expectedErr:      nil,
+			run: func() ([]model.Message, error) {
+				rows := sqlmock.NewRows(messageColumnNames)
+				for _, m := range messages {
+					rows.AddRow(m.ID, m.Sender.ID, m.Sender.Username, m.Recipient.ID, m.Recipient.Username, m.Body, m.Read, m.Created)
+				}
+				mock.
+					ExpectQuery("SELECT (.+) FROM message m WHERE").
+					WithArgs("2", "3", "3", "2").
+					WillReturnRows(rows)
+				return repo.GetThread("2", "3")
+			},
+		},
+		{
+			expectedMessages: nil,
+			expectedErr:      errors.New("bad query"),
+			run: func() ([]model.Message, error) {
+				mock.
+					ExpectQuery("SELECT (.+) FROM message m WHERE").
+					WithArgs("2", "3", "3", "2").
+					WillReturnError(errors.New("bad query"))
+				return repo.GetThread("2", "3")
+			},
+		},
+	}
+
+	for i, item := range cases {
+		messages, err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+		if !reflect.DeepEqual(item.expectedMessages, messages) {
+			t.Errorf("[%d] expected messages: %+v, got: %+v", i, item.expectedMessages, messages)
+		}
+	}
+}
+
+func TestMarkThreadRead(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Errorf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewMessagesRepo(db)
+
+	cases := []struct {
+		expectedErr error
+		run         func() error
+	}{
+		{
+			expectedErr: nil,
+			run: func() error {
+				mock.ExpectBegin()
+				mock.
+					ExpectExec("UPDATE message SET is_read").
+					WithArgs("3", "2").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.
+					ExpectExec("UPDATE conversation SET unread").
+					WithArgs("3", "2").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+				return repo.MarkThreadRead("3", "2")
+			},
+		},
+		{
+			expectedErr: errors.New("bad query"),
+			run: func() error {
+				mock.ExpectBegin()
+				mock.
+					ExpectExec("UPDATE message SET is_read").
+					WithArgs("3", "2").
+					WillReturnError(errors.New("bad query"))
+				mock.ExpectRollback()
+				return repo.MarkThreadRead("3", "2")
+			},
+		},
+	}
+
+	for i, item := range cases {
+		err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("[%d] unfulfilled expectations: %s", i, err)
+		}
+	}
+}
+
+func TestBlocks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Errorf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewMessagesRepo(db)
+
+	block := model.Block{UserID: "2", Blocked: model.Author{ID: "3", Username: "ivan"}, Created: "2022-04-10T12:00:00.000Z"}
+
+	cases := []struct {
+		expected    interface{}
+		expectedErr error
+		run         func() (interface{}, error)
+	}{
+		{
+			expected:    nil,
+			expectedErr: nil,
+			run: func() (interface{}, error) {
+				mock.
+					ExpectExec("INSERT IGNORE INTO user_block").
+					WithArgs("2", "3", "ivan", "2022-04-10T12:00:00.000Z").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				return nil, repo.AddBlock(block)
+			},
+		},
+		{
+			expected:    nil,
+			expectedErr: errors.New("bad query"),
+			run: func() (interface{}, error) {
+				mock.
+					ExpectExec("DELETE FROM user_block").
+					WithArgs("2", "3").
+					WillReturnError(errors.New("bad query"))
+				return nil, repo.DeleteBlock("2", "3")
+			},
+		},
+		{
+			expected:    []model.Block{block},
+			expectedErr: nil,
+			run: func() (interface{}, error) {
+				rows := sqlmock.NewRows([]string{"user_id", "blocked_id", "blocked_username", "created"})
+				rows.AddRow("2", "3", "ivan", "2022-04-10T12:00:00.000Z")
+				mock.
+					ExpectQuery("SELECT user_id, blocked_id, blocked_username, created FROM user_block WHERE").
+					WithArgs("2").
+					WillReturnRows(rows)
+				return repo.GetBlocks("2")
+			},
+		},
+		{
+			expected:    true,
+			expectedErr: nil,
+			run: func() (interface{}, error) {
+				rows := sqlmock.NewRows([]string{"count"}).AddRow(1)
+				mock.
+					ExpectQuery("SELECT COUNT(.+) FROM user_block WHERE").
+					WithArgs("2", "3").
+					WillReturnRows(rows)
+				return repo.IsBlocked("2", "3")
+			},
+		},
+		{
+			expected:    false,
+			expectedErr: nil,
+			run: func() (interface{}, error) {
+				rows := sqlmock.NewRows([]string{"count"}).AddRow(0)
+				mock.
+					ExpectQuery("SELECT COUNT(.+) FROM user_block WHERE").
+					WithArgs("3", "2").
+					WillReturnRows(rows)
+				return repo.IsBlocked("3", "2")
+			},
+		},
+	}
+
+	for i, item := range cases {
+		result, err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+		if !reflect.DeepEqual(item.expected, result) {
+			t.Errorf("[%d] expected: %+v, got: %+v", i, item.expected, result)
+		}
+	}
+}
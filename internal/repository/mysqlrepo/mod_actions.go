@@ -0,0 +1,92 @@
+package mysqlrepo
+
+import (
+	"database/sql"
+	"redditclone/internal/model"
+	"strings"
+)
+
+type modActionsRepo struct {
+	db *sql.DB
+}
+
+func NewModActionsRepo(db *sql.DB) *modActionsRepo {
+	return &modActionsRepo{db: db}
+}
+
+func (r *modActionsRepo) AddModAction(action model.ModAction) error {
+	_, err := r.db.Exec(
+		"INSERT INTO mod_action (`id`, `moderator_id`, `moderator_username`, `action`, `target_user_id`, `target_username`, `community`, `post_id`, `comment_id`, `details`, `created`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		action.ID,
+		action.Moderator.ID,
+		action.Moderator.Username,
+		action.Action,
+		action.TargetUser.ID,
+		action.TargetUser.Username,
+		action.Community,
+		action.PostID,
+		action.CommentID,
+		action.Details,
+		action.Created,
+	)
+	return err
+}
+
+// GetModActions returns the actions from the newest one, the filters left empty are not applied
+func (r *modActionsRepo) GetModActions(query model.ModLogQuery, after model.ModActionCursor) ([]model.ModAction, error) {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+	if query.Community != "" {
+		conditions = append(conditions, "community = ?")
+		args = append(args, query.Community)
+	}
+	if query.Moderator != "" {
+		conditions = append(conditions, "moderator_username = ?")
+		args = append(args, query.Moderator)
+	}
+	if query.Action != "" {
+		conditions = append(conditions, "action = ?")
+		args = append(args, query.Action)
+	}
+	if !after.IsZero() {
+		conditions = append(conditions, "(created < ? OR created = ? AND id < ?)")
+		args = append(args, after.Created, after.Created, after.ID)
+	}
+
+	statement := "SELECT id, moderator_id, moderator_username, action, target_user_id, target_username, community, post_id, comment_id, details, created FROM mod_action"
+	if len(conditions) != 0 {
+		statement += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	statement += " ORDER BY created DESC, id DESC LIMIT ?"
+	args = append(args, query.Limit)
+
+	rows, err := r.db.Query(statement, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	actions := make([]model.ModAction, 0)
+	for rows.Next() {
+		var action model.ModAction
+		err = rows.Scan(
+			&action.ID,
+			&action.Moderator.ID,
+			&action.Moderator.Username,
+			&action.Action,
+			&action.TargetUser.ID,
+			&action.TargetUser.Username,
+			&action.Community,
+			&action.PostID,
+			&action.CommentID,
+			&action.Details,
+			&action.Created,
+		)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, action)
+	}
+
+	return actions, rows.Err()
+}
@@ -0,0 +1,137 @@
+package mysqlrepo
+
+import (
+	"errors"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+	"redditclone/internal/model"
+	"reflect"
+	"testing"
+)
+
+func TestAddModAction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Errorf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewModActionsRepo(db)
+
+	action := model.ModAction{
+		ID:         "1",
+		Moderator:  model.Author{ID: "2", Username: "van"},
+		Action:     model.ModActionRemovePost,
+		TargetUser: model.Author{ID: "3", Username: "ivan"},
+		Community:  "news",
+		PostID:     "4",
+		Created:    "2022-04-10T12:00:00.000Z",
+	}
+
+	cases := []struct {
+		expectedErr error
+		run         func() error
+	}{
+		{
+			expectedErr: nil,
+			run: func() error {
+				mock.
+					ExpectExec("INSERT INTO mod_action").
+					WithArgs("1", "2", "van", model.ModActionRemovePost, "3", "ivan", "news", "4", "", "", "2022-04-10T12:00:00.000Z").
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				return repo.AddModAction(action)
+			},
+		},
+		{
+			expectedErr: errors.New("bad query"),
+			run: func() error {
+				mock.
+					ExpectExec("INSERT INTO mod_action").
+					WithArgs("1", "2", "van", model.ModActionRemovePost, "3", "ivan", "news", "4", "", "", "2022-04-10T12:00:00.000Z").
+					WillReturnError(errors.New("bad query"))
+				return repo.AddModAction(action)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+	}
+}
+
+func TestGetModActions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Errorf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewModActionsRepo(db)
+
+	action := model.ModAction{
+		ID:         "1",
+		Moderator:  model.Author{ID: "2", Username: "van"},
+		Action:     model.ModActionRemovePost,
+		TargetUser: model.Author{ID: "3", Username: "ivan"},
+		Community:  "news",
+		PostID:     "4",
+		Details:    "spam",
+		Created:    "2022-04-10T12:00:00.000Z",
+	}
+	columns := []string{"id", "moderator_id", "moderator_username", "action", "target_user_id", "target_username", "community", "post_id", "comment_id", "details", "created"}
+
+	cases := []struct {
+		expectedActions []model.ModAction
+		expectedErr     error
+		run             func() ([]model.ModAction, error)
+	}{
+		{
+			expectedActions: []model.ModAction{action},
+			expectedErr:     nil,
+			run: func() ([]model.ModAction, error) {
+				rows := sqlmock.NewRows(columns)
+				rows.AddRow("1", "2", "van", model.ModActionRemovePost, "3", "ivan", "news", "4", "", "spam", "2022-04-10T12:00:00.000Z")
+				mock.
+					ExpectQuery("SELECT (.+) FROM mod_action WHERE community = \\? AND moderator_username = \\? AND action = \\? AND \\(created < \\? OR created = \\? AND id < \\?\\) ORDER BY created DESC, id DESC LIMIT \\?").
+					WithArgs("news", "van", model.ModActionRemovePost, "2022-04-11T12:00:00.000Z", "2022-04-11T12:00:00.000Z", "5", 26).
+					WillReturnRows(rows)
+				query := model.ModLogQuery{Community: "news", Moderator: "van", Action: model.ModActionRemovePost, Limit: 26}
+				return repo.GetModActions(query, model.ModActionCursor{Created: "2022-04-11T12:00:00.000Z", ID: "5"})
+			},
+		},
+		{
+			expectedActions: []model.ModAction{},
+			expectedErr:     nil,
+			run: func() ([]model.ModAction, error) {
+				mock.
+					ExpectQuery("SELECT (.+) FROM mod_action ORDER BY created DESC, id DESC LIMIT \\?").
+					WithArgs(26).
+					WillReturnRows(sqlmock.NewRows(columns))
+				return repo.GetModActions(model.ModLogQuery{Limit: 26}, model.ModActionCursor{})
+			},
+		},
+		{
+			expectedActions: nil,
+			expectedErr:     errors.New("bad query"),
+			run: func() ([]model.ModAction, error) {
+				mock.
+					ExpectQuery("SELECT (.+) FROM mod_action").
+					WithArgs("news", 26).
+					WillReturnError(errors.New("bad query"))
+				return repo.GetModActions(model.ModLogQuery{Community: "news", Limit: 26}, model.ModActionCursor{})
+			},
+		},
+	}
+
+	for i, item := range cases {
+		actions, err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+		if !reflect.DeepEqual(item.expectedActions, actions) {
+			t.Errorf("[%d] expected actions: %+v, got: %+v", i, item.expectedActions, actions)
+		}
+	}
+}
@@ -19,10 +19,12 @@ func NewUsersRepo(db *sql.DB) *usersRepo {
 
 func (r *usersRepo) AddUser(user model.User) error {
 	_, err := r.db.Exec(
-		"INSERT INTO user (`id`, `username`, `password`) VALUES (?, ?, ?)",
+		"INSERT INTO user (`id`, `username`, `password`, `role`, `created`) VALUES (?, ?, ?, ?, ?)",
 		user.ID,
 		user.Username,
 		user.Password,
+		user.Role,
+		user.Created,
 	)
 	// Error 1062: Duplicate entry 'van' for key 'user.username'
 	if mysqlErr, ok := err.(*mysql.MySQLError); ok && reflect.DeepEqual(mysqlErr, &mysql.MySQLError{
@@ -34,27 +36,97 @@ func (r *usersRepo) AddUser(user model.User) error {
 	return err
 }
 
-func (r *usersRepo) GetUser(cred model.Credential) (model.User, error) {
-	var user model.User
-	err := r.db.QueryRow(
-		"SELECT id, username, password FROM user WHERE username = ? AND password = ?",
-		cred.Username,
-		cred.Password,
-	).Scan(&user.ID, &user.Username, &user.Password)
+// scanUser leaves Suspension nil for the users never suspended
+func scanUser(row *sql.Row) (model.User, error) {
+	var (
+		user       model.User
+		suspension model.Suspension
+	)
+	err := row.Scan(
+		&user.ID,
+		&user.Username,
+		&user.Password,
+		&user.Role,
+		&user.Created,
+		&user.Karma.Post,
+		&user.Karma.Comment,
+		&suspension.Reason,
+		&suspension.Expires,
+		&suspension.Created,
+	)
+	if err != nil {
+		return model.User{}, err
+	}
+	if suspension.Created != "" {
+		user.Suspension = &suspension
+	}
+	return user, nil
+}
+
+func (r *usersRepo) GetUserByUsername(username string) (model.User, error) {
+	user, err := scanUser(r.db.QueryRow(
+		"SELECT id, username, password, role, created, post_karma, comment_karma, "+
+			"suspension_reason, suspension_expires, suspension_created FROM user WHERE username = ?",
+		username,
+	))
 	if err == sql.ErrNoRows {
-		return model.User{}, customerr.WrongCredential{Username: cred.Username}
+		return model.User{}, customerr.UserNotFoundByUsername{Username: username}
 	}
 	return user, err
 }
 
 func (r *usersRepo) GetUserByID(userID string) (model.User, error) {
-	var user model.User
-	err := r.db.QueryRow(
-		"SELECT id, username, password FROM user WHERE id = ?",
+	user, err := scanUser(r.db.QueryRow(
+		"SELECT id, username, password, role, created, post_karma, comment_karma, "+
+			"suspension_reason, suspension_expires, suspension_created FROM user WHERE id = ?",
 		userID,
-	).Scan(&user.ID, &user.Username, &user.Password)
+	))
 	if err == sql.ErrNoRows {
 		return model.User{}, customerr.UserNotFoundByID{UserID: userID}
 	}
 	return user, err
 }
+
+func (r *usersRepo) UpdatePassword(userID string, password string) error {
+	_, err := r.db.Exec(
+		"UPDATE user SET password = ? WHERE id = ?",
+		password,
+		userID,
+	)
+	return err
+}
+
+func (r *usersRepo) UpdateRole(userID string, role string) error {
+	_, err := r.db.Exec(
+		"UPDATE user SET role = ? WHERE id = ?",
+		role,
+		userID,
+	)
+	return err
+}
+
+// UpdateSuspension lifts the suspension when it is nil
+func (r *usersRepo) UpdateSuspension(userID string, suspension *model.Suspension) error {
+	if suspension == nil {
+		suspension = &model.Suspension{}
+	}
+	_, err := r.db.Exec(
+		"UPDATE user SET suspension_reason = ?, suspension_expires = ?, suspension_created = ? WHERE id = ?",
+		suspension.Reason,
+		suspension.Expires,
+		suspension.Created,
+		userID,
+	)
+	return err
+}
+
+// UpdateKarma adds the deltas in the database, so concurrent votes never overwrite each other
+func (r *usersRepo) UpdateKarma(userID string, karma model.Karma) error {
+	_, err := r.db.Exec(
+		"UPDATE user SET post_karma = post_karma + ?, comment_karma = comment_karma + ? WHERE id = ?",
+		karma.Post,
+		karma.Comment,
+		userID,
+	)
+	return err
+}
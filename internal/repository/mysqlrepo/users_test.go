@@ -16,6 +16,21 @@ func compareErrorsMsg(err1 error, err2 error) bool {
 	return fmt.Sprint(err1) == fmt.Sprint(err2)
 }
 
+func userRows(user model.User) *sqlmock.Rows {
+	var suspension model.Suspension
+	if user.Suspension != nil {
+		suspension = *user.Suspension
+	}
+	rows := sqlmock.NewRows([]string{
+		"id", "username", "password", "role", "created", "post_karma", "comment_karma",
+		"suspension_reason", "suspension_expires", "suspension_created",
+	})
+	return rows.AddRow(
+		user.ID, user.Username, user.Password, user.Role, user.Created, user.Karma.Post, user.Karma.Comment,
+		suspension.Reason, suspension.Expires, suspension.Created,
+	)
+}
+
 func TestAddUser(t *testing.T) {
 	cases := []struct {
 		user        model.User
@@ -23,12 +38,12 @@ func TestAddUser(t *testing.T) {
 		run         func(user model.User, repo *usersRepo, mock sqlmock.Sqlmock) error
 	}{
 		{
-			user:        model.User{ID: "1"},
+			user:        model.User{ID: "1", Role: model.RoleUser, Created: "2022-01-01T00:00:00.000Z"},
 			expectedErr: nil,
 			run: func(user model.User, repo *usersRepo, mock sqlmock.Sqlmock) error {
 				mock.
 					ExpectExec("INSERT INTO user").
-					WithArgs(user.ID, user.Username, user.Password).
+					WithArgs(user.ID, user.Username, user.Password, user.Role, user.Created).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 				return repo.AddUser(user)
 			},
@@ -39,7 +54,7 @@ func TestAddUser(t *testing.T) {
 			run: func(user model.User, repo *usersRepo, mock sqlmock.Sqlmock) error {
 				mock.
 					ExpectExec("INSERT INTO user").
-					WithArgs(user.ID, user.Username, user.Password).
+					WithArgs(user.ID, user.Username, user.Password, user.Role, user.Created).
 					WillReturnError(errors.New("bad query"))
 				return repo.AddUser(user)
 			},
@@ -50,7 +65,7 @@ func TestAddUser(t *testing.T) {
 			run: func(user model.User, repo *usersRepo, mock sqlmock.Sqlmock) error {
 				mock.
 					ExpectExec("INSERT INTO user").
-					WithArgs(user.ID, user.Username, user.Password).
+					WithArgs(user.ID, user.Username, user.Password, user.Role, user.Created).
 					WillReturnError(&mysql.MySQLError{
 						Number:  1062,
 						Message: fmt.Sprintf("Duplicate entry 'ivan' for key 'user.username'"),
@@ -75,7 +90,7 @@ func TestAddUser(t *testing.T) {
 	}
 }
 
-func TestGetUser(t *testing.T) {
+func TestGetUserByUsername(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Errorf("cant create mock: %s", err)
@@ -90,16 +105,15 @@ func TestGetUser(t *testing.T) {
 		run          func(user model.User) (model.User, error)
 	}{
 		{
-			expectedUser: model.User{ID: "1", Credential: model.Credential{Username: "ivan", Password: "qqq"}},
+			expectedUser: model.User{ID: "1", Credential: model.Credential{Username: "ivan", Password: "qqq"}, Role: model.RoleUser, Created: "2022-01-01T00:00:00.000Z", Karma: model.Karma{Post: 3, Comment: -1}},
 			expectedErr:  nil,
 			run: func(user model.User) (model.User, error) {
-				rows := sqlmock.NewRows([]string{"id", "username", "password"})
-				rows.AddRow(user.ID, user.Username, user.Password)
+				rows := userRows(user)
 				mock.
-					ExpectQuery("SELECT id, username, password FROM user WHERE").
-					WithArgs(user.Username, user.Password).
+					ExpectQuery("SELECT id, username, password, role, created, post_karma, comment_karma, suspension_reason, suspension_expires, suspension_created FROM user WHERE").
+					WithArgs(user.Username).
 					WillReturnRows(rows)
-				return repo.GetUser(user.Credential)
+				return repo.GetUserByUsername(user.Username)
 			},
 		},
 		{
@@ -107,22 +121,21 @@ func TestGetUser(t *testing.T) {
 			expectedErr:  errors.New("bad query"),
 			run: func(user model.User) (model.User, error) {
 				mock.
-					ExpectQuery("	SELECT id, username, password FROM user WHERE").
-					WithArgs(user.Username, user.Password).
+					ExpectQuery("SELECT id, username, password, role, created, post_karma, comment_karma, suspension_reason, suspension_expires, suspension_created FROM user WHERE").
+					WithArgs(user.Username).
 					WillReturnError(errors.New("bad query"))
-				return repo.GetUser(user.Credential)
+				return repo.GetUserByUsername(user.Username)
 			},
 		},
 		{
 			expectedUser: model.User{},
-			expectedErr:  customerr.WrongCredential{Username: "ivan"},
+			expectedErr:  customerr.UserNotFoundByUsername{Username: "ivan"},
 			run: func(user model.User) (model.User, error) {
-				user.Username = "ivan"
 				mock.
-					ExpectQuery("SELECT id, username, password FROM user WHERE").
-					WithArgs(user.Username, user.Password).
+					ExpectQuery("SELECT id, username, password, role, created, post_karma, comment_karma, suspension_reason, suspension_expires, suspension_created FROM user WHERE").
+					WithArgs("ivan").
 					WillReturnError(sql.ErrNoRows)
-				return repo.GetUser(user.Credential)
+				return repo.GetUserByUsername("ivan")
 			},
 		},
 	}
@@ -153,24 +166,38 @@ func TestGetUserByID(t *testing.T) {
 		run          func(user model.User) (model.User, error)
 	}{
 		{
-			expectedUser: model.User{ID: "1"},
+			expectedUser: model.User{ID: "1", Role: model.RoleModerator},
 			expectedErr:  nil,
 			run: func(user model.User) (model.User, error) {
-				rows := sqlmock.NewRows([]string{"id", "username", "password"})
-				rows.AddRow(user.ID, user.Username, user.Password)
+				rows := userRows(user)
 				mock.
-					ExpectQuery("SELECT id, username, password FROM user WHERE").
+					ExpectQuery("SELECT id, username, password, role, created, post_karma, comment_karma, suspension_reason, suspension_expires, suspension_created FROM user WHERE").
 					WithArgs(user.ID).
 					WillReturnRows(rows)
 				return repo.GetUserByID(user.ID)
 			},
 		},
+		{
+			expectedUser: model.User{ID: "2", Role: model.RoleUser, Suspension: &model.Suspension{
+				Reason:  "spam",
+				Expires: "2022-01-08T00:00:00.000Z",
+				Created: "2022-01-01T00:00:00.000Z",
+			}},
+			expectedErr: nil,
+			run: func(user model.User) (model.User, error) {
+				mock.
+					ExpectQuery("SELECT id, username, password, role, created, post_karma, comment_karma, suspension_reason, suspension_expires, suspension_created FROM user WHERE").
+					WithArgs(user.ID).
+					WillReturnRows(userRows(user))
+				return repo.GetUserByID(user.ID)
+			},
+		},
 		{
 			expectedUser: model.User{},
 			expectedErr:  errors.New("bad query"),
 			run: func(user model.User) (model.User, error) {
 				mock.
-					ExpectQuery("SELECT id, username, password FROM user WHERE").
+					ExpectQuery("SELECT id, username, password, role, created, post_karma, comment_karma, suspension_reason, suspension_expires, suspension_created FROM user WHERE").
 					WithArgs(user.ID).
 					WillReturnError(errors.New("bad query"))
 				return repo.GetUserByID(user.ID)
@@ -182,7 +209,7 @@ func TestGetUserByID(t *testing.T) {
 			run: func(user model.User) (model.User, error) {
 				user.Username = "ivan"
 				mock.
-					ExpectQuery("SELECT id, username, password FROM user WHERE").
+					ExpectQuery("SELECT id, username, password, role, created, post_karma, comment_karma, suspension_reason, suspension_expires, suspension_created FROM user WHERE").
 					WithArgs("1").
 					WillReturnError(sql.ErrNoRows)
 				return repo.GetUserByID("1")
@@ -200,3 +227,186 @@ func TestGetUserByID(t *testing.T) {
 		}
 	}
 }
+
+func TestUpdatePassword(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Errorf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewUsersRepo(db)
+
+	cases := []struct {
+		expectedErr error
+		run         func() error
+	}{
+		{
+			expectedErr: nil,
+			run: func() error {
+				mock.
+					ExpectExec("UPDATE user SET password").
+					WithArgs("$argon2id$hash", "1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				return repo.UpdatePassword("1", "$argon2id$hash")
+			},
+		},
+		{
+			expectedErr: errors.New("bad query"),
+			run: func() error {
+				mock.
+					ExpectExec("UPDATE user SET password").
+					WithArgs("$argon2id$hash", "1").
+					WillReturnError(errors.New("bad query"))
+				return repo.UpdatePassword("1", "$argon2id$hash")
+			},
+		},
+	}
+
+	for i, item := range cases {
+		err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+	}
+}
+
+func TestUpdateRole(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Errorf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewUsersRepo(db)
+
+	cases := []struct {
+		expectedErr error
+		run         func() error
+	}{
+		{
+			expectedErr: nil,
+			run: func() error {
+				mock.
+					ExpectExec("UPDATE user SET role").
+					WithArgs(model.RoleModerator, "1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				return repo.UpdateRole("1", model.RoleModerator)
+			},
+		},
+		{
+			expectedErr: errors.New("bad query"),
+			run: func() error {
+				mock.
+					ExpectExec("UPDATE user SET role").
+					WithArgs(model.RoleAdmin, "1").
+					WillReturnError(errors.New("bad query"))
+				return repo.UpdateRole("1", model.RoleAdmin)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+	}
+}
+
+func TestUpdateSuspension(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Errorf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewUsersRepo(db)
+	suspension := &model.Suspension{Reason: "spam", Created: "2022-01-01T00:00:00.000Z"}
+
+	cases := []struct {
+		expectedErr error
+		run         func() error
+	}{
+		{
+			expectedErr: nil,
+			run: func() error {
+				mock.
+					ExpectExec("UPDATE user SET suspension_reason").
+					WithArgs("spam", "", "2022-01-01T00:00:00.000Z", "1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				return repo.UpdateSuspension("1", suspension)
+			},
+		},
+		{
+			expectedErr: nil,
+			run: func() error {
+				mock.
+					ExpectExec("UPDATE user SET suspension_reason").
+					WithArgs("", "", "", "1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				return repo.UpdateSuspension("1", nil)
+			},
+		},
+		{
+			expectedErr: errors.New("bad query"),
+			run: func() error {
+				mock.
+					ExpectExec("UPDATE user SET suspension_reason").
+					WithArgs("spam", "", "2022-01-01T00:00:00.000Z", "1").
+					WillReturnError(errors.New("bad query"))
+				return repo.UpdateSuspension("1", suspension)
+			},
+		},
+	}
+
+	for i, item := range cases {
+		err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+	}
+}
+
+func TestUpdateKarma(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Errorf("cant create mock: %s", err)
+	}
+	defer db.Close()
+
+	repo := NewUsersRepo(db)
+
+	cases := []struct {
+		expectedErr error
+		run         func() error
+	}{
+		{
+			expectedErr: nil,
+			run: func() error {
+				mock.
+					ExpectExec("UPDATE user SET post_karma = post_karma").
+					WithArgs(1, 0, "1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				return repo.UpdateKarma("1", model.Karma{Post: 1})
+			},
+		},
+		{
+			expectedErr: errors.New("bad query"),
+			run: func() error {
+				mock.
+					ExpectExec("UPDATE user SET post_karma = post_karma").
+					WithArgs(0, -2, "1").
+					WillReturnError(errors.New("bad query"))
+				return repo.UpdateKarma("1", model.Karma{Comment: -2})
+			},
+		},
+	}
+
+	for i, item := range cases {
+		err := item.run()
+		if !compareErrorsMsg(item.expectedErr, err) {
+			t.Errorf("[%d] expected error: %s, got: %s", i, item.expectedErr, err)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package slicerepo
+
+import (
+	"redditclone/internal/model"
+	"redditclone/internal/model/customerr"
+	"sync"
+)
+
+type bansRepo struct {
+	mutex sync.RWMutex
+	bans  []model.Ban
+}
+
+func NewBansRepo() *bansRepo {
+	return &bansRepo{
+		bans: make([]model.Ban, 0),
+	}
+}
+
+func (r *bansRepo) AddBan(ban model.Ban) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, b := range r.bans {
+		if b.Community == ban.Community && b.User.ID == ban.User.ID {
+			r.bans = append(r.bans[:i], r.bans[i+1:]...)
+			break
+		}
+	}
+	r.bans = append(r.bans, ban)
+
+	return nil
+}
+
+func (r *bansRepo) GetBan(community, userID string) (model.Ban, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, ban := range r.bans {
+		if ban.Community == community && ban.User.ID == userID {
+			return ban, nil
+		}
+	}
+
+	return model.Ban{}, customerr.BanNotFound{Community: community, UserID: userID}
+}
+
+func (r *bansRepo) GetActiveBans(community string, now string) ([]model.Ban, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	bans := make([]model.Ban, 0)
+	for i := len(r.bans) - 1; i >= 0; i-- {
+		ban := r.bans[i]
+		if ban.Community == community && (ban.Expires == "" || ban.Expires > now) {
+			bans = append(bans, ban)
+		}
+	}
+
+	return bans, nil
+}
+
+func (r *bansRepo) DeleteBan(community, userID string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, ban := range r.bans {
+		if ban.Community == community && ban.User.ID == userID {
+			r.bans = append(r.bans[:i], r.bans[i+1:]...)
+			return nil
+		}
+	}
+
+	return customerr.BanNotFound{Community: community, UserID: userID}
+}
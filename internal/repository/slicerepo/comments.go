@@ -0,0 +1,198 @@
+package slicerepo
+
+import (
+	"redditclone/internal/model"
+	"redditclone/internal/model/customerr"
+	"sync"
+)
+
+type commentsRepo struct {
+	mutex    sync.RWMutex
+	comments []model.Comment
+	index    *invertedIndex
+}
+
+func NewCommentsRepo() *commentsRepo {
+	return &commentsRepo{
+		comments: make([]model.Comment, 0),
+		index:    newInvertedIndex(),
+	}
+}
+
+func (r *commentsRepo) AddComment(comment model.Comment) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.comments = append(r.comments, comment)
+	r.index.Add(comment.ID, weightedText{text: comment.Body, weight: 1})
+
+	return nil
+}
+
+func (r *commentsRepo) GetCommentByID(postID, commentID string) (model.Comment, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, comment := range r.comments {
+		if comment.PostID == postID && comment.ID == commentID {
+			return comment, nil
+		}
+	}
+
+	return model.Comment{}, customerr.CommentNotFoundByID{PostID: postID, CommentID: commentID}
+}
+
+func (r *commentsRepo) GetCommentsByPost(postID string) ([]model.Comment, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	comments := make([]model.Comment, 0)
+	for _, comment := range r.comments {
+		if comment.PostID == postID {
+			comments = append(comments, comment)
+		}
+	}
+
+	return comments, nil
+}
+
+func (r *commentsRepo) GetCommentsByAuthor(username string, limit int) ([]model.Comment, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	comments := make([]model.Comment, 0)
+	for i := len(r.comments) - 1; i >= 0 && len(comments) < limit; i-- {
+		if r.comments[i].Author.Username == username {
+			comments = append(comments, r.comments[i])
+		}
+	}
+
+	return comments, nil
+}
+
+func (r *commentsRepo) CountCommentsByAuthor(username string) (int, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	count := 0
+	for _, comment := range r.comments {
+		if comment.Author.Username == username {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *commentsRepo) DeleteComment(postID, commentID string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, comment := range r.comments {
+		if comment.PostID == postID && comment.ID == commentID {
+			r.comments = append(r.comments[:i], r.comments[i+1:]...)
+			r.index.Remove(commentID)
+			return nil
+		}
+	}
+
+	return customerr.CommentNotFoundByID{PostID: postID, CommentID: commentID}
+}
+
+func (r *commentsRepo) MarkCommentDeleted(postID, commentID string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, comment := range r.comments {
+		if comment.PostID == postID && comment.ID == commentID && !comment.Deleted {
+			r.comments[i].MarkDeleted()
+			r.index.Remove(commentID)
+			return nil
+		}
+	}
+
+	return customerr.CommentNotFoundByID{PostID: postID, CommentID: commentID}
+}
+
+func (r *commentsRepo) RemoveComment(postID, commentID string, removal *model.Removal) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, comment := range r.comments {
+		if comment.PostID == postID && comment.ID == commentID {
+			if comment.Removed != nil {
+				return customerr.CommentChanged{PostID: postID, CommentID: commentID}
+			}
+			r.comments[i].Removed = removal
+			r.index.Remove(commentID)
+			return nil
+		}
+	}
+
+	return customerr.CommentNotFoundByID{PostID: postID, CommentID: commentID}
+}
+
+func (r *commentsRepo) EditComment(comment model.Comment) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, existedComment := range r.comments {
+		if existedComment.PostID == comment.PostID && existedComment.ID == comment.ID {
+			r.comments[i].Body = comment.Body
+			r.comments[i].Edited = comment.Edited
+			r.index.Add(comment.ID, weightedText{text: comment.Body, weight: 1})
+			return nil
+		}
+	}
+
+	return customerr.CommentNotFoundByID{PostID: comment.PostID, CommentID: comment.ID}
+}
+
+func (r *commentsRepo) UpdateCommentVoteCounts(postID, commentID string, ups, downs int) (model.Comment, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, comment := range r.comments {
+		if comment.PostID == postID && comment.ID == commentID {
+			r.comments[i].AddCounts(ups, downs)
+			return r.comments[i], nil
+		}
+	}
+
+	return model.Comment{}, customerr.CommentNotFoundByID{PostID: postID, CommentID: commentID}
+}
+
+func (r *commentsRepo) DeletePostComments(postID string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	comments := make([]model.Comment, 0, len(r.comments))
+	for _, comment := range r.comments {
+		if comment.PostID != postID {
+			comments = append(comments, comment)
+		} else {
+			r.index.Remove(comment.ID)
+		}
+	}
+	r.comments = comments
+
+	return nil
+}
+
+// searchComments returns the comments found by the inverted index and the filters, the community is left to the caller
+func (r *commentsRepo) searchComments(query model.SearchQuery) []model.SearchResult {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	scores := r.index.Search(query.Text)
+	results := make([]model.SearchResult, 0, len(scores))
+	for _, comment := range r.comments {
+		relevance, ok := scores[comment.ID]
+		if !ok || !query.Matches(comment.Author, comment.Created) {
+			continue
+		}
+		results = append(results, model.NewCommentResult(comment, relevance))
+	}
+
+	return results
+}
@@ -0,0 +1,88 @@
+package slicerepo
+
+import (
+	"redditclone/internal/model"
+	"redditclone/internal/model/customerr"
+	"sort"
+	"sync"
+)
+
+type communitiesRepo struct {
+	mutex       sync.RWMutex
+	communities []model.Community
+}
+
+func NewCommunitiesRepo() *communitiesRepo {
+	return &communitiesRepo{
+		communities: make([]model.Community, 0),
+	}
+}
+
+func (r *communitiesRepo) AddCommunity(community model.Community) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, existedCommunity := range r.communities {
+		if existedCommunity.Name == community.Name {
+			return customerr.CommunityAlreadyExists{Name: community.Name}
+		}
+	}
+
+	r.communities = append(r.communities, community)
+
+	return nil
+}
+
+func (r *communitiesRepo) GetCommunities() ([]model.Community, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	communities := make([]model.Community, len(r.communities))
+	copy(communities, r.communities)
+	sort.Slice(communities, func(i, j int) bool {
+		return communities[i].Name < communities[j].Name
+	})
+
+	return communities, nil
+}
+
+func (r *communitiesRepo) GetCommunityByName(name string) (model.Community, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, community := range r.communities {
+		if community.Name == name {
+			return community, nil
+		}
+	}
+
+	return model.Community{}, customerr.CommunityNotFoundByName{Name: name}
+}
+
+func (r *communitiesRepo) UpdateCommunity(name string, description string) (model.Community, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, community := range r.communities {
+		if community.Name == name {
+			r.communities[i].Description = description
+			return r.communities[i], nil
+		}
+	}
+
+	return model.Community{}, customerr.CommunityNotFoundByName{Name: name}
+}
+
+func (r *communitiesRepo) DeleteCommunity(name string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, community := range r.communities {
+		if community.Name == name {
+			r.communities = append(r.communities[:i], r.communities[i+1:]...)
+			return nil
+		}
+	}
+
+	return customerr.CommunityNotFoundByName{Name: name}
+}
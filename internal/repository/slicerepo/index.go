@@ -0,0 +1,70 @@
+package slicerepo
+
+import (
+	"strings"
+	"unicode"
+)
+
+// weightedText is a field of a document, the terms of the field count with its weight
+type weightedText struct {
+	text   string
+	weight float64
+}
+
+// invertedIndex maps the terms to the documents with the weighted term frequencies,
+// it is guarded by the mutex of the repository owning it
+type invertedIndex struct {
+	terms map[string]map[string]float64
+	docs  map[string][]string
+}
+
+func newInvertedIndex() *invertedIndex {
+	return &invertedIndex{
+		terms: make(map[string]map[string]float64),
+		docs:  make(map[string][]string),
+	}
+}
+
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Add replaces the indexed fields of the document
+func (i *invertedIndex) Add(docID string, fields ...weightedText) {
+	i.Remove(docID)
+
+	for _, field := range fields {
+		for _, term := range tokenize(field.text) {
+			if i.terms[term] == nil {
+				i.terms[term] = make(map[string]float64)
+			}
+			if _, ok := i.terms[term][docID]; !ok {
+				i.docs[docID] = append(i.docs[docID], term)
+			}
+			i.terms[term][docID] += field.weight
+		}
+	}
+}
+
+func (i *invertedIndex) Remove(docID string) {
+	for _, term := range i.docs[docID] {
+		delete(i.terms[term], docID)
+		if len(i.terms[term]) == 0 {
+			delete(i.terms, term)
+		}
+	}
+	delete(i.docs, docID)
+}
+
+// Search scores the documents containing any of the terms of the text
+func (i *invertedIndex) Search(text string) map[string]float64 {
+	scores := make(map[string]float64)
+	for _, term := range tokenize(text) {
+		for docID, weight := range i.terms[term] {
+			scores[docID] += weight
+		}
+	}
+	return scores
+}
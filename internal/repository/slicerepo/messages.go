@@ -0,0 +1,145 @@
+package slicerepo
+
+import (
+	"redditclone/internal/model"
+	"sort"
+	"sync"
+)
+
+type messagesRepo struct {
+	mutex    sync.RWMutex
+	messages []model.Message
+	blocks   []model.Block
+}
+
+func NewMessagesRepo() *messagesRepo {
+	return &messagesRepo{
+		messages: make([]model.Message, 0),
+		blocks:   make([]model.Block, 0),
+	}
+}
+
+func (r *messagesRepo) AddMessage(message model.Message) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.messages = append(r.messages, message)
+
+	return nil
+}
+
+func (r *messagesRepo) GetConversations(userID string) ([]model.Conversation, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	last := make(map[string]model.Message)
+	unread := make(map[string]int)
+	for _, message := range r.messages {
+		var otherID string
+		switch userID {
+		case message.Sender.ID:
+			otherID = message.Recipient.ID
+		case message.Recipient.ID:
+			otherID = message.Sender.ID
+			if !message.Read {
+				unread[otherID]++
+			}
+		default:
+			continue
+		}
+		last[otherID] = message
+	}
+
+	conversations := make([]model.Conversation, 0, len(last))
+	for otherID, message := range last {
+		conversations = append(conversations, model.NewConversation(userID, message, unread[otherID]))
+	}
+	sort.Slice(conversations, func(i, j int) bool {
+		return conversations[i].LastMessage.Created > conversations[j].LastMessage.Created
+	})
+
+	return conversations, nil
+}
+
+func (r *messagesRepo) GetThread(userID, otherID string) ([]model.Message, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	messages := make([]model.Message, 0)
+	for _, message := range r.messages {
+		if message.Sender.ID == userID && message.Recipient.ID == otherID ||
+			message.Sender.ID == otherID && message.Recipient.ID == userID {
+			messages = append(messages, message)
+		}
+	}
+
+	return messages, nil
+}
+
+func (r *messagesRepo) MarkThreadRead(userID, otherID string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, message := range r.messages {
+		if message.Recipient.ID == userID && message.Sender.ID == otherID {
+			r.messages[i].Read = true
+		}
+	}
+
+	return nil
+}
+
+func (r *messagesRepo) AddBlock(block model.Block) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, b := range r.blocks {
+		if b.UserID == block.UserID && b.Blocked.ID == block.Blocked.ID {
+			return nil
+		}
+	}
+	r.blocks = append(r.blocks, block)
+
+	return nil
+}
+
+func (r *messagesRepo) DeleteBlock(userID, blockedID string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, block := range r.blocks {
+		if block.UserID == userID && block.Blocked.ID == blockedID {
+			r.blocks = append(r.blocks[:i], r.blocks[i+1:]...)
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (r *messagesRepo) GetBlocks(userID string) ([]model.Block, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	blocks := make([]model.Block, 0)
+	for i := len(r.blocks) - 1; i >= 0; i-- {
+		if r.blocks[i].UserID == userID {
+			blocks = append(blocks, r.blocks[i])
+		}
+	}
+
+	return blocks, nil
+}
+
+func (r *messagesRepo) IsBlocked(userID, blockedID string) (bool, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, block := range r.blocks {
+		if block.UserID == userID && block.Blocked.ID == blockedID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
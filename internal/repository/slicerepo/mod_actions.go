@@ -0,0 +1,49 @@
+package slicerepo
+
+import (
+	"redditclone/internal/model"
+	"sync"
+)
+
+type modActionsRepo struct {
+	mutex   sync.RWMutex
+	actions []model.ModAction
+}
+
+func NewModActionsRepo() *modActionsRepo {
+	return &modActionsRepo{
+		actions: make([]model.ModAction, 0),
+	}
+}
+
+func (r *modActionsRepo) AddModAction(action model.ModAction) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.actions = append(r.actions, action)
+
+	return nil
+}
+
+// GetModActions walks the actions from the newest one, they are appended in the order of creation
+func (r *modActionsRepo) GetModActions(query model.ModLogQuery, after model.ModActionCursor) ([]model.ModAction, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	actions := make([]model.ModAction, 0)
+	for i := len(r.actions) - 1; i >= 0 && (query.Limit <= 0 || len(actions) < query.Limit); i-- {
+		action := r.actions[i]
+		if query.Community != "" && action.Community != query.Community ||
+			query.Moderator != "" && action.Moderator.Username != query.Moderator ||
+			query.Action != "" && action.Action != query.Action {
+			continue
+		}
+		if !after.IsZero() && (action.Created > after.Created ||
+			action.Created == after.Created && action.ID >= after.ID) {
+			continue
+		}
+		actions = append(actions, action)
+	}
+
+	return actions, nil
+}
@@ -0,0 +1,106 @@
+package slicerepo
+
+import (
+	"redditclone/internal/model"
+	"redditclone/internal/model/customerr"
+	"sort"
+	"sync"
+)
+
+type notificationsRepo struct {
+	mutex         sync.RWMutex
+	notifications []model.Notification
+	keys          map[string]struct{}
+}
+
+func NewNotificationsRepo() *notificationsRepo {
+	return &notificationsRepo{
+		notifications: make([]model.Notification, 0),
+		keys:          make(map[string]struct{}),
+	}
+}
+
+func (r *notificationsRepo) AddNotification(notification model.Notification) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if notification.Key != "" {
+		if _, ok := r.keys[notification.Key]; ok {
+			return nil
+		}
+		r.keys[notification.Key] = struct{}{}
+	}
+	r.notifications = append(r.notifications, notification)
+
+	return nil
+}
+
+func (r *notificationsRepo) GetNotifications(userID string, query model.NotificationsQuery, after model.NotificationCursor) ([]model.Notification, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	notifications := make([]model.Notification, 0)
+	for _, notification := range r.notifications {
+		if notification.UserID != userID || query.UnreadOnly && notification.Read {
+			continue
+		}
+		if !after.IsZero() && (notification.Created > after.Created ||
+			notification.Created == after.Created && notification.ID >= after.ID) {
+			continue
+		}
+		notifications = append(notifications, notification)
+	}
+
+	sort.Slice(notifications, func(i, j int) bool {
+		if notifications[i].Created != notifications[j].Created {
+			return notifications[i].Created > notifications[j].Created
+		}
+		return notifications[i].ID > notifications[j].ID
+	})
+	if query.Limit > 0 && len(notifications) > query.Limit {
+		notifications = notifications[:query.Limit]
+	}
+
+	return notifications, nil
+}
+
+func (r *notificationsRepo) CountUnread(userID string) (int, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	count := 0
+	for _, notification := range r.notifications {
+		if notification.UserID == userID && !notification.Read {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *notificationsRepo) MarkRead(userID, notificationID string) (model.Notification, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, notification := range r.notifications {
+		if notification.UserID == userID && notification.ID == notificationID {
+			r.notifications[i].Read = true
+			return r.notifications[i], nil
+		}
+	}
+
+	return model.Notification{}, customerr.NotificationNotFoundByID{NotificationID: notificationID}
+}
+
+func (r *notificationsRepo) MarkAllRead(userID string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, notification := range r.notifications {
+		if notification.UserID == userID {
+			r.notifications[i].Read = true
+		}
+	}
+
+	return nil
+}
@@ -3,25 +3,40 @@ package slicerepo
 import (
 	"redditclone/internal/model"
 	"redditclone/internal/model/customerr"
+	"sort"
 	"sync"
 )
 
 type postsRepo struct {
-	mutex sync.RWMutex
-	posts []model.Post
+	mutex     sync.RWMutex
+	posts     []model.Post
+	revisions map[string][]model.PostRevision
+	index     *invertedIndex
 }
 
 func NewPostsRepo() *postsRepo {
 	return &postsRepo{
-		posts: make([]model.Post, 0),
+		posts:     make([]model.Post, 0),
+		revisions: make(map[string][]model.PostRevision),
+		index:     newInvertedIndex(),
 	}
 }
 
+func (r *postsRepo) indexPost(post model.Post) {
+	r.index.Add(post.ID,
+		weightedText{text: post.Title, weight: 3},
+		weightedText{text: post.Text, weight: 1},
+		weightedText{text: post.URL, weight: 1},
+	)
+}
+
 func (r *postsRepo) GetAllPosts() ([]model.Post, error) {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 
-	return r.posts, nil
+	posts := make([]model.Post, len(r.posts))
+	copy(posts, r.posts)
+	return posts, nil
 }
 
 func (r *postsRepo) GetPostsByCategory(category string) ([]model.Post, error) {
@@ -51,11 +66,57 @@ func (r *postsRepo) GetPostsByAuthor(username string) ([]model.Post, error) {
 	return posts, nil
 }
 
+func (r *postsRepo) CountPostsByAuthor(username string) (int, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	count := 0
+	for _, existedPost := range r.posts {
+		if existedPost.Author.Username == username {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *postsRepo) GetPostsPage(query model.PostsQuery, after model.PostCursor) ([]model.Post, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	posts := make([]model.Post, 0)
+	for _, existedPost := range r.posts {
+		if query.Category != "" && existedPost.Category != query.Category {
+			continue
+		}
+		if query.Author != "" && existedPost.Author.Username != query.Author {
+			continue
+		}
+		if query.Since != "" && existedPost.Created < query.Since {
+			continue
+		}
+		if after.Precedes(existedPost) {
+			posts = append(posts, existedPost)
+		}
+	}
+
+	sort.Slice(posts, func(i, j int) bool {
+		return model.PostPrecedes(posts[i], posts[j], query.Sort)
+	})
+
+	if query.Limit > 0 && len(posts) > query.Limit {
+		posts = posts[:query.Limit]
+	}
+
+	return posts, nil
+}
+
 func (r *postsRepo) AddPost(newPost model.Post) error {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
 	r.posts = append(r.posts, newPost)
+	r.indexPost(newPost)
 
 	return nil
 }
@@ -92,8 +153,10 @@ func (r *postsRepo) DeletePost(postID string) error {
 	defer r.mutex.Unlock()
 
 	for idx, existedPost := range r.posts {
-		if existedPost.ID == postID {
+		if existedPost.ID == postID && existedPost.Removed == nil {
 			r.posts = append(r.posts[:idx], r.posts[idx+1:]...)
+			delete(r.revisions, postID)
+			r.index.Remove(postID)
 			return nil
 		}
 	}
@@ -101,69 +164,123 @@ func (r *postsRepo) DeletePost(postID string) error {
 	return customerr.PostNotFoundByID{PostID: postID}
 }
 
-func (r *postsRepo) AddComment(postID string, comment model.Comment) (model.Post, error) {
+func (r *postsRepo) LockPost(postID string) error {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	for idx, existedPost := range r.posts {
+	for i, existedPost := range r.posts {
 		if existedPost.ID == postID {
-			r.posts[idx].Comments = append(existedPost.Comments, comment)
-			return r.posts[idx], nil
+			if existedPost.Locked {
+				return customerr.PostChanged{PostID: postID}
+			}
+			r.posts[i].Locked = true
+			return nil
 		}
 	}
 
-	return model.Post{}, customerr.PostNotFoundByID{PostID: postID}
+	return customerr.PostNotFoundByID{PostID: postID}
 }
 
-func (r *postsRepo) GetCommentByID(postID, commentID string) (model.Comment, error) {
-	r.mutex.RLock()
-	defer r.mutex.RUnlock()
+func (r *postsRepo) RemovePost(postID string, removal *model.Removal) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
 
-	for _, existedPost := range r.posts {
+	for i, existedPost := range r.posts {
 		if existedPost.ID == postID {
-			for _, existedComment := range existedPost.Comments {
-				if existedComment.ID == commentID {
-					return existedComment, nil
-				}
+			if existedPost.Removed != nil {
+				return customerr.PostChanged{PostID: postID}
 			}
-			return model.Comment{}, customerr.CommentNotFoundByID{CommentID: commentID, PostID: postID}
+			r.posts[i].Removed = removal
+			r.index.Remove(postID)
+			return nil
 		}
 	}
 
-	return model.Comment{}, customerr.PostNotFoundByID{PostID: postID}
+	return customerr.PostNotFoundByID{PostID: postID}
 }
 
-func (r *postsRepo) DeleteComment(postID, commentID string) (model.Post, error) {
+func (r *postsRepo) EditPost(post model.Post, edited string, revision model.PostRevision) error {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	for idx, existedPost := range r.posts {
-		if existedPost.ID == postID {
-			for i, comment := range existedPost.Comments {
-				if comment.ID == commentID {
-					r.posts[idx].Comments = append(existedPost.Comments[:i], existedPost.Comments[i+1:]...)
-					return r.posts[idx], nil
-				}
+	for i, existedPost := range r.posts {
+		if existedPost.ID == post.ID {
+			if existedPost.Removed != nil || existedPost.Edited != edited {
+				return customerr.PostChanged{PostID: post.ID}
 			}
-			return model.Post{}, customerr.CommentNotFoundByID{CommentID: commentID, PostID: postID}
+			r.posts[i].Title = post.Title
+			r.posts[i].Text = post.Text
+			r.posts[i].Edited = post.Edited
+			r.revisions[post.ID] = append(r.revisions[post.ID], revision)
+			r.indexPost(r.posts[i])
+			return nil
 		}
 	}
 
-	return model.Post{}, customerr.PostNotFoundByID{PostID: postID}
+	return customerr.PostNotFoundByID{PostID: post.ID}
+}
+
+func (r *postsRepo) GetPostRevisions(postID string) ([]model.PostRevision, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, existedPost := range r.posts {
+		if existedPost.ID == postID {
+			revisions := make([]model.PostRevision, len(r.revisions[postID]))
+			copy(revisions, r.revisions[postID])
+			return revisions, nil
+		}
+	}
+
+	return nil, customerr.PostNotFoundByID{PostID: postID}
 }
 
-func (r *postsRepo) UpdateVotes(postID string, score int, upvotePercentage int, votes []model.Vote) error {
+func (r *postsRepo) UpdateCommentCount(postID string, delta int) error {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
 	for i, post := range r.posts {
 		if post.ID == postID {
-			r.posts[i].Score = score
-			r.posts[i].UpvotePercentage = upvotePercentage
-			r.posts[i].Votes = votes
+			r.posts[i].CommentCount += delta
 			return nil
 		}
 	}
 
 	return customerr.PostNotFoundByID{PostID: postID}
 }
+
+func (r *postsRepo) UpdateVoteCounts(postID string, ups, downs int) (model.Post, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, post := range r.posts {
+		if post.ID == postID {
+			r.posts[i].AddCounts(ups, downs)
+			r.posts[i].RecalculateRanks()
+			return r.posts[i], nil
+		}
+	}
+
+	return model.Post{}, customerr.PostNotFoundByID{PostID: postID}
+}
+
+// searchPosts returns the posts found by the inverted index and the filters, the community included
+func (r *postsRepo) searchPosts(query model.SearchQuery) []model.SearchResult {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	scores := r.index.Search(query.Text)
+	results := make([]model.SearchResult, 0, len(scores))
+	for _, post := range r.posts {
+		relevance, ok := scores[post.ID]
+		if !ok || !query.Matches(post.Author, post.Created) {
+			continue
+		}
+		if query.Community != "" && post.Category != query.Community {
+			continue
+		}
+		results = append(results, model.NewPostResult(post, relevance))
+	}
+
+	return results
+}
@@ -0,0 +1,105 @@
+package slicerepo
+
+import (
+	"redditclone/internal/model"
+	"sort"
+	"sync"
+)
+
+type reportsRepo struct {
+	mutex   sync.RWMutex
+	reports []model.Report
+}
+
+func NewReportsRepo() *reportsRepo {
+	return &reportsRepo{
+		reports: make([]model.Report, 0),
+	}
+}
+
+func isOpenReportOf(report model.Report, postID, commentID string) bool {
+	return report.PostID == postID && report.CommentID == commentID && report.Status == model.ReportStatusOpen
+}
+
+func (r *reportsRepo) AddReport(report model.Report) (model.Report, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, stored := range r.reports {
+		if isOpenReportOf(stored, report.PostID, report.CommentID) && stored.Reporter.ID == report.Reporter.ID {
+			return stored, nil
+		}
+	}
+	r.reports = append(r.reports, report)
+
+	return report, nil
+}
+
+func (r *reportsRepo) GetReportQueue(query model.ReportsQuery) ([]model.ReportedItem, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	positions := make(map[[2]string]int)
+	items := make([]model.ReportedItem, 0)
+	for _, report := range r.reports {
+		if report.Status != model.ReportStatusOpen || query.Community != "" && report.Community != query.Community {
+			continue
+		}
+
+		key := [2]string{report.PostID, report.CommentID}
+		i, ok := positions[key]
+		if !ok {
+			i = len(items)
+			positions[key] = i
+			items = append(items, model.ReportedItem{PostID: report.PostID, CommentID: report.CommentID, Community: report.Community})
+		}
+		items[i].Count++
+		items[i].Reports = append(items[i].Reports, report)
+		if report.Created > items[i].LastReported {
+			items[i].LastReported = report.Created
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Count != items[j].Count {
+			return items[i].Count > items[j].Count
+		}
+		return items[i].LastReported > items[j].LastReported
+	})
+	if query.Limit > 0 && len(items) > query.Limit {
+		items = items[:query.Limit]
+	}
+
+	return items, nil
+}
+
+func (r *reportsRepo) CountOpenReports(postID, commentID string) (int, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	count := 0
+	for _, report := range r.reports {
+		if isOpenReportOf(report, postID, commentID) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *reportsRepo) ResolveReports(postID, commentID string, status string, moderator model.Author, resolved string) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	count := 0
+	for i, report := range r.reports {
+		if isOpenReportOf(report, postID, commentID) {
+			r.reports[i].Status = status
+			r.reports[i].ResolvedBy = &moderator
+			r.reports[i].Resolved = resolved
+			count++
+		}
+	}
+
+	return count, nil
+}
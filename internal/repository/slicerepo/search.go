@@ -0,0 +1,53 @@
+package slicerepo
+
+import (
+	"redditclone/internal/model"
+	"sort"
+)
+
+// searchRepo reads the inverted indexes kept by the posts and the comments repositories
+type searchRepo struct {
+	posts    *postsRepo
+	comments *commentsRepo
+}
+
+func NewSearchRepo(posts *postsRepo, comments *commentsRepo) *searchRepo {
+	return &searchRepo{posts: posts, comments: comments}
+}
+
+func pageResults(results []model.SearchResult, query model.SearchQuery, skip int) []model.SearchResult {
+	sort.Slice(results, func(i, j int) bool {
+		return model.SearchPrecedes(results[i], results[j], query.Sort)
+	})
+
+	if skip >= len(results) {
+		return make([]model.SearchResult, 0)
+	}
+	results = results[skip:]
+	if query.Limit > 0 && len(results) > query.Limit {
+		results = results[:query.Limit]
+	}
+
+	return results
+}
+
+func (r *searchRepo) SearchPosts(query model.SearchQuery, skip int) ([]model.SearchResult, error) {
+	return pageResults(r.posts.searchPosts(query), query, skip), nil
+}
+
+func (r *searchRepo) SearchComments(query model.SearchQuery, skip int) ([]model.SearchResult, error) {
+	found := r.comments.searchComments(query)
+
+	results := make([]model.SearchResult, 0, len(found))
+	for _, result := range found {
+		if query.Community != "" {
+			post, err := r.posts.GetPostByID(result.Comment.PostID)
+			if err != nil || post.Category != query.Community {
+				continue
+			}
+		}
+		results = append(results, result)
+	}
+
+	return pageResults(results, query, skip), nil
+}
@@ -32,17 +32,17 @@ func (r *usersRepo) AddUser(user model.User) error {
 	return nil
 }
 
-func (r *usersRepo) GetUser(cred model.Credential) (model.User, error) {
+func (r *usersRepo) GetUserByUsername(username string) (model.User, error) {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 
 	for _, existedUser := range r.users {
-		if existedUser.Username == cred.Username && existedUser.Password == cred.Password {
+		if existedUser.Username == username {
 			return existedUser, nil
 		}
 	}
 
-	return model.User{}, customerr.WrongCredential{Username: cred.Username}
+	return model.User{}, customerr.UserNotFoundByUsername{Username: username}
 }
 
 func (r *usersRepo) GetUserByID(userID string) (model.User, error) {
@@ -57,3 +57,60 @@ func (r *usersRepo) GetUserByID(userID string) (model.User, error) {
 
 	return model.User{}, customerr.UserNotFoundByID{UserID: userID}
 }
+
+func (r *usersRepo) UpdatePassword(userID string, password string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, usr := range r.users {
+		if usr.ID == userID {
+			r.users[i].Password = password
+			return nil
+		}
+	}
+
+	return customerr.UserNotFoundByID{UserID: userID}
+}
+
+func (r *usersRepo) UpdateRole(userID string, role string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, usr := range r.users {
+		if usr.ID == userID {
+			r.users[i].Role = role
+			return nil
+		}
+	}
+
+	return customerr.UserNotFoundByID{UserID: userID}
+}
+
+func (r *usersRepo) UpdateSuspension(userID string, suspension *model.Suspension) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, usr := range r.users {
+		if usr.ID == userID {
+			r.users[i].Suspension = suspension
+			return nil
+		}
+	}
+
+	return customerr.UserNotFoundByID{UserID: userID}
+}
+
+func (r *usersRepo) UpdateKarma(userID string, karma model.Karma) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, usr := range r.users {
+		if usr.ID == userID {
+			r.users[i].Karma.Post += karma.Post
+			r.users[i].Karma.Comment += karma.Comment
+			return nil
+		}
+	}
+
+	return customerr.UserNotFoundByID{UserID: userID}
+}
@@ -0,0 +1,102 @@
+package slicerepo
+
+import (
+	"redditclone/internal/model"
+	"sort"
+	"sync"
+)
+
+type voteKey struct {
+	userID    string
+	postID    string
+	commentID string
+}
+
+type votesRepo struct {
+	mutex sync.RWMutex
+	votes map[voteKey]model.Vote
+}
+
+func NewVotesRepo() *votesRepo {
+	return &votesRepo{
+		votes: make(map[voteKey]model.Vote),
+	}
+}
+
+func (r *votesRepo) SetVote(vote model.Vote) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key := voteKey{userID: vote.UserID, postID: vote.PostID, commentID: vote.CommentID}
+	previous := r.votes[key].Vote
+	if vote.Vote == model.VoteNone {
+		delete(r.votes, key)
+	} else {
+		r.votes[key] = vote
+	}
+
+	return previous, nil
+}
+
+func (r *votesRepo) GetUserVotes(userID string, postIDs []string) ([]model.Vote, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	posts := make(map[string]bool, len(postIDs))
+	for _, postID := range postIDs {
+		posts[postID] = true
+	}
+
+	votes := make([]model.Vote, 0)
+	for key, vote := range r.votes {
+		if key.userID == userID && posts[key.postID] {
+			votes = append(votes, vote)
+		}
+	}
+
+	return votes, nil
+}
+
+func (r *votesRepo) GetVotesByUser(userID string) ([]model.Vote, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	votes := make([]model.Vote, 0)
+	for key, vote := range r.votes {
+		if key.userID == userID {
+			votes = append(votes, vote)
+		}
+	}
+
+	sort.Slice(votes, func(i, j int) bool {
+		return votes[i].Created > votes[j].Created
+	})
+
+	return votes, nil
+}
+
+func (r *votesRepo) DeletePostVotes(postID string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for key := range r.votes {
+		if key.postID == postID {
+			delete(r.votes, key)
+		}
+	}
+
+	return nil
+}
+
+func (r *votesRepo) DeleteCommentVotes(postID, commentID string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for key := range r.votes {
+		if key.postID == postID && key.commentID == commentID {
+			delete(r.votes, key)
+		}
+	}
+
+	return nil
+}
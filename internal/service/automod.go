@@ -0,0 +1,263 @@
+package service
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"redditclone/internal/model"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Automoderator checks the new and the reported content against the rules
+type Automoderator interface {
+	Evaluate(subject model.AutomodSubject) []model.AutomodMatch
+}
+
+var linkPattern = regexp.MustCompile(`https?://[^\s<>()\[\]"']+`)
+
+// compiledRule keeps the parsed conditions next to the rule
+type compiledRule struct {
+	model.AutomodRule
+	title           *regexp.Regexp
+	body            *regexp.Regexp
+	accountAgeBelow time.Duration
+}
+
+// automod holds the rules of the file, they are replaced as a whole on every reload
+type automod struct {
+	path    string
+	mutex   sync.RWMutex
+	modTime time.Time
+	dryRun  bool
+	rules   []compiledRule
+}
+
+func NewAutomod(path string) (*automod, error) {
+	a := &automod{path: path}
+	if err := a.Reload(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Reload keeps the rules in use when the file is broken
+func (a *automod) Reload() error {
+	info, err := os.Stat(a.path)
+	if err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadFile(a.path)
+	if err != nil {
+		return err
+	}
+
+	var file model.AutomodRules
+	if err = yaml.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("automod rules %s: %w", a.path, err)
+	}
+
+	rules, err := compileRules(file.Rules)
+	if err != nil {
+		return fmt.Errorf("automod rules %s: %w", a.path, err)
+	}
+
+	a.mutex.Lock()
+	a.modTime = info.ModTime()
+	a.dryRun = file.DryRun
+	a.rules = rules
+	a.mutex.Unlock()
+
+	logrus.Infof("automod rules loaded: %d from %s", len(rules), a.path)
+
+	return nil
+}
+
+// Watch reloads the rules when the file changes until stop is closed
+func (a *automod) Watch(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			info, err := os.Stat(a.path)
+			if err != nil {
+				logrus.Errorln(err)
+				continue
+			}
+
+			a.mutex.RLock()
+			changed := !info.ModTime().Equal(a.modTime)
+			a.mutex.RUnlock()
+
+			if changed {
+				if err = a.Reload(); err != nil {
+					logrus.Errorln(err)
+				}
+			}
+		}
+	}
+}
+
+func compileRules(rules []model.AutomodRule) ([]compiledRule, error) {
+	compiled := make([]compiledRule, 0, len(rules))
+	names := make(map[string]bool)
+
+	for _, rule := range rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("rule without a name")
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("rule %s: the name is repeated", rule.Name)
+		}
+		names[rule.Name] = true
+
+		c, err := compileRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", rule.Name, err)
+		}
+		compiled = append(compiled, c)
+	}
+
+	return compiled, nil
+}
+
+func compileRule(rule model.AutomodRule) (compiledRule, error) {
+	c := compiledRule{AutomodRule: rule}
+
+	switch rule.Target {
+	case "":
+		c.Target = model.AutomodTargetAny
+	case model.AutomodTargetAny, model.AutomodTargetPost, model.AutomodTargetComment:
+	default:
+		return compiledRule{}, fmt.Errorf("unknown target %s", rule.Target)
+	}
+
+	knownAction := false
+	for _, action := range model.AutomodActions {
+		knownAction = knownAction || action == rule.Action
+	}
+	if !knownAction {
+		return compiledRule{}, fmt.Errorf("unknown action %s", rule.Action)
+	}
+	if rule.Action == model.AutomodActionReply && rule.Reply == "" {
+		return compiledRule{}, fmt.Errorf("reply action without a reply")
+	}
+	if rule.Action == model.AutomodActionReply && rule.Reports != 0 {
+		return compiledRule{}, fmt.Errorf("reply action with a report count, it would reply to every report")
+	}
+
+	var err error
+	if rule.Title != "" {
+		if c.title, err = regexp.Compile(rule.Title); err != nil {
+			return compiledRule{}, err
+		}
+	}
+	if rule.Body != "" {
+		if c.body, err = regexp.Compile(rule.Body); err != nil {
+			return compiledRule{}, err
+		}
+	}
+
+	if rule.Author != nil && rule.Author.AccountAgeBelow != "" {
+		if c.accountAgeBelow, err = time.ParseDuration(rule.Author.AccountAgeBelow); err != nil {
+			return compiledRule{}, err
+		}
+	}
+
+	hasAuthorCondition := rule.Author != nil && (c.accountAgeBelow != 0 || rule.Author.KarmaBelow != nil)
+	if c.title == nil && c.body == nil && len(rule.Domains) == 0 && !hasAuthorCondition && rule.Reports == 0 {
+		return compiledRule{}, fmt.Errorf("rule without conditions")
+	}
+
+	return c, nil
+}
+
+// Evaluate returns the matched rules in the order of the file
+func (a *automod) Evaluate(subject model.AutomodSubject) []model.AutomodMatch {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	now := time.Now().UTC()
+	matches := make([]model.AutomodMatch, 0)
+	for _, rule := range a.rules {
+		if rule.matches(subject, now) {
+			matches = append(matches, model.AutomodMatch{Rule: rule.AutomodRule, DryRun: a.dryRun || rule.DryRun})
+		}
+	}
+
+	return matches
+}
+
+func (r compiledRule) matches(subject model.AutomodSubject, now time.Time) bool {
+	if r.Target != model.AutomodTargetAny && r.Target != subject.Target {
+		return false
+	}
+
+	// the rules without a report count run on creation, the others on every report from the count on,
+	// concurrent reports can skip the exact count, the actions don't repeat on the same content
+	if r.Reports == 0 && subject.Reports != 0 || subject.Reports < r.Reports {
+		return false
+	}
+
+	if subject.Edited && r.Action == model.AutomodActionReply {
+		return false
+	}
+
+	if r.title != nil && (subject.Target != model.AutomodTargetPost || !r.title.MatchString(subject.Title)) {
+		return false
+	}
+	if r.body != nil && !r.body.MatchString(subject.Body) {
+		return false
+	}
+	if len(r.Domains) != 0 && !linksDomain(subject, r.Domains) {
+		return false
+	}
+
+	if r.Author != nil {
+		if r.accountAgeBelow != 0 {
+			age, known := subject.AccountAge(now)
+			if !known || age >= r.accountAgeBelow {
+				return false
+			}
+		}
+		if r.Author.KarmaBelow != nil && subject.Author.Karma.Post+subject.Author.Karma.Comment >= *r.Author.KarmaBelow {
+			return false
+		}
+	}
+
+	return true
+}
+
+// linksDomain checks the link of the post and the links in the text, the subdomains match as well
+func linksDomain(subject model.AutomodSubject, domains []string) bool {
+	links := linkPattern.FindAllString(subject.Body, -1)
+	if subject.URL != "" {
+		links = append(links, subject.URL)
+	}
+
+	for _, link := range links {
+		parsed, err := url.Parse(link)
+		if err != nil {
+			continue
+		}
+		host := strings.ToLower(parsed.Hostname())
+		for _, domain := range domains {
+			domain = strings.ToLower(domain)
+			if host == domain || strings.HasSuffix(host, "."+domain) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
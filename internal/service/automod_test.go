@@ -0,0 +1,520 @@
+package service
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"redditclone/internal/model"
+	"redditclone/internal/repository/slicerepo"
+	"redditclone/pkg/broker"
+	"redditclone/pkg/lockout"
+	"strings"
+	"testing"
+	"time"
+)
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func TestCompileRule(t *testing.T) {
+	cases := []struct {
+		rule        model.AutomodRule
+		expectedErr string
+	}{
+		{
+			rule:        model.AutomodRule{Name: "spam", Body: "buy now", Action: model.AutomodActionRemove},
+			expectedErr: "",
+		},
+		{
+			rule:        model.AutomodRule{Name: "spam", Target: "wiki", Body: "buy now", Action: model.AutomodActionRemove},
+			expectedErr: "unknown target wiki",
+		},
+		{
+			rule:        model.AutomodRule{Name: "spam", Body: "buy now", Action: "ban"},
+			expectedErr: "unknown action ban",
+		},
+		{
+			rule:        model.AutomodRule{Name: "question", Title: "help", Action: model.AutomodActionReply},
+			expectedErr: "reply action without a reply",
+		},
+		{
+			rule:        model.AutomodRule{Name: "question", Reports: 2, Action: model.AutomodActionReply, Reply: "calm down"},
+			expectedErr: "reply action with a report count",
+		},
+		{
+			rule:        model.AutomodRule{Name: "everything", Target: model.AutomodTargetPost, Action: model.AutomodActionFlag},
+			expectedErr: "rule without conditions",
+		},
+		{
+			rule:        model.AutomodRule{Name: "author", Author: &model.AutomodAuthorCondition{}, Action: model.AutomodActionFlag},
+			expectedErr: "rule without conditions",
+		},
+		{
+			rule:        model.AutomodRule{Name: "broken", Body: "(", Action: model.AutomodActionRemove},
+			expectedErr: "missing closing )",
+		},
+		{
+			rule:        model.AutomodRule{Name: "young", Author: &model.AutomodAuthorCondition{AccountAgeBelow: "a day"}, Action: model.AutomodActionFlag},
+			expectedErr: "invalid duration",
+		},
+	}
+
+	for i, item := range cases {
+		_, err := compileRule(item.rule)
+		if item.expectedErr == "" && err != nil {
+			t.Errorf("[%d] unexpected error: %s", i, err)
+		}
+		if item.expectedErr != "" && (err == nil || !strings.Contains(err.Error(), item.expectedErr)) {
+			t.Errorf("[%d] expected error: %s, got: %v", i, item.expectedErr, err)
+		}
+	}
+}
+
+func TestCompileRulesRepeatedName(t *testing.T) {
+	rule := model.AutomodRule{Name: "spam", Body: "buy now", Action: model.AutomodActionRemove}
+	if _, err := compileRules([]model.AutomodRule{rule, rule}); err == nil || !strings.Contains(err.Error(), "the name is repeated") {
+		t.Errorf("expected a repeated name error, got: %v", err)
+	}
+}
+
+func TestRuleMatches(t *testing.T) {
+	now := time.Date(2022, 1, 10, 0, 0, 0, 0, time.UTC)
+	author := func(age time.Duration, karma int) model.User {
+		usr := model.User{Credential: model.Credential{Username: "van"}}
+		usr.Created = now.Add(-age).Format("2006-01-02T15:04:05.000Z")
+		usr.Karma.Post = karma
+		return usr
+	}
+	post := func(title, text, url string) model.AutomodSubject {
+		return model.AutomodSubject{Target: model.AutomodTargetPost, Title: title, Body: text, URL: url, Author: author(720*time.Hour, 100)}
+	}
+	comment := func(body string) model.AutomodSubject {
+		return model.AutomodSubject{Target: model.AutomodTargetComment, Body: body, Author: author(720*time.Hour, 100)}
+	}
+
+	cases := []struct {
+		name     string
+		rule     model.AutomodRule
+		subject  model.AutomodSubject
+		expected bool
+	}{
+		{
+			name:     "title",
+			rule:     model.AutomodRule{Title: "(?i)^\\[question\\]", Action: model.AutomodActionFlag},
+			subject:  post("[Question] how", "", ""),
+			expected: true,
+		},
+		{
+			name:     "title of a comment",
+			rule:     model.AutomodRule{Title: "(?i)question", Action: model.AutomodActionFlag},
+			subject:  comment("question"),
+			expected: false,
+		},
+		{
+			name:     "body",
+			rule:     model.AutomodRule{Body: "buy now", Action: model.AutomodActionRemove},
+			subject:  comment("please buy now"),
+			expected: true,
+		},
+		{
+			name:     "body not matched",
+			rule:     model.AutomodRule{Body: "buy now", Action: model.AutomodActionRemove},
+			subject:  comment("please don't"),
+			expected: false,
+		},
+		{
+			name:     "target",
+			rule:     model.AutomodRule{Target: model.AutomodTargetPost, Body: "buy now", Action: model.AutomodActionRemove},
+			subject:  comment("buy now"),
+			expected: false,
+		},
+		{
+			name:     "domain of the post link",
+			rule:     model.AutomodRule{Domains: []string{"bit.ly"}, Action: model.AutomodActionRemove},
+			subject:  post("look", "", "https://bit.ly/3abc"),
+			expected: true,
+		},
+		{
+			name:     "subdomain of a link in the text",
+			rule:     model.AutomodRule{Domains: []string{"youtube.com"}, Action: model.AutomodActionFlag},
+			subject:  comment("see https://www.YouTube.com/watch?v=1"),
+			expected: true,
+		},
+		{
+			name:     "domain only as a suffix",
+			rule:     model.AutomodRule{Domains: []string{"youtube.com"}, Action: model.AutomodActionFlag},
+			subject:  comment("see https://notyoutube.com/watch?v=1"),
+			expected: false,
+		},
+		{
+			name:     "young account",
+			rule:     model.AutomodRule{Author: &model.AutomodAuthorCondition{AccountAgeBelow: "24h"}, Action: model.AutomodActionFlag},
+			subject:  model.AutomodSubject{Target: model.AutomodTargetPost, Author: author(2*time.Hour, 100)},
+			expected: true,
+		},
+		{
+			name:     "old account",
+			rule:     model.AutomodRule{Author: &model.AutomodAuthorCondition{AccountAgeBelow: "24h"}, Action: model.AutomodActionFlag},
+			subject:  model.AutomodSubject{Target: model.AutomodTargetPost, Author: author(48*time.Hour, 100)},
+			expected: false,
+		},
+		{
+			name:     "unknown account age",
+			rule:     model.AutomodRule{Author: &model.AutomodAuthorCondition{AccountAgeBelow: "24h"}, Action: model.AutomodActionFlag},
+			subject:  model.AutomodSubject{Target: model.AutomodTargetPost, Author: model.User{}},
+			expected: false,
+		},
+		{
+			name:     "low karma",
+			rule:     model.AutomodRule{Author: &model.AutomodAuthorCondition{KarmaBelow: intPtr(10)}, Action: model.AutomodActionFlag},
+			subject:  model.AutomodSubject{Target: model.AutomodTargetPost, Author: author(720*time.Hour, 9)},
+			expected: true,
+		},
+		{
+			name:     "enough karma",
+			rule:     model.AutomodRule{Author: &model.AutomodAuthorCondition{KarmaBelow: intPtr(10)}, Action: model.AutomodActionFlag},
+			subject:  model.AutomodSubject{Target: model.AutomodTargetPost, Author: author(720*time.Hour, 10)},
+			expected: false,
+		},
+		{
+			name:     "creation rule on a report",
+			rule:     model.AutomodRule{Body: "buy now", Action: model.AutomodActionRemove},
+			subject:  model.AutomodSubject{Target: model.AutomodTargetComment, Body: "buy now", Reports: 1},
+			expected: false,
+		},
+		{
+			name:     "report count not reached",
+			rule:     model.AutomodRule{Reports: 3, Action: model.AutomodActionLock},
+			subject:  model.AutomodSubject{Target: model.AutomodTargetComment, Reports: 2},
+			expected: false,
+		},
+		{
+			name:     "report count reached",
+			rule:     model.AutomodRule{Reports: 3, Action: model.AutomodActionLock},
+			subject:  model.AutomodSubject{Target: model.AutomodTargetComment, Reports: 3},
+			expected: true,
+		},
+		{
+			name:     "report count passed",
+			rule:     model.AutomodRule{Reports: 3, Action: model.AutomodActionLock},
+			subject:  model.AutomodSubject{Target: model.AutomodTargetComment, Reports: 5},
+			expected: true,
+		},
+		{
+			name:     "report rule on creation",
+			rule:     model.AutomodRule{Reports: 3, Action: model.AutomodActionLock},
+			subject:  comment("anything"),
+			expected: false,
+		},
+		{
+			name:     "reply rule on creation",
+			rule:     model.AutomodRule{Title: "(?i)question", Action: model.AutomodActionReply, Reply: "tell what you tried"},
+			subject:  post("question", "", ""),
+			expected: true,
+		},
+		{
+			name:     "reply rule on an edit",
+			rule:     model.AutomodRule{Title: "(?i)question", Action: model.AutomodActionReply, Reply: "tell what you tried"},
+			subject:  model.AutomodSubject{Target: model.AutomodTargetPost, Title: "question", Edited: true},
+			expected: false,
+		},
+		{
+			name:     "remove rule on an edit",
+			rule:     model.AutomodRule{Body: "buy now", Action: model.AutomodActionRemove},
+			subject:  model.AutomodSubject{Target: model.AutomodTargetComment, Body: "buy now", Edited: true},
+			expected: true,
+		},
+	}
+
+	for i, item := range cases {
+		rule, err := compileRule(item.rule)
+		if err != nil {
+			t.Fatalf("[%d] %s: unexpected error: %s", i, item.name, err)
+		}
+		if matched := rule.matches(item.subject, now); matched != item.expected {
+			t.Errorf("[%d] %s: expected match %t, got %t", i, item.name, item.expected, matched)
+		}
+	}
+}
+
+func writeRules(t *testing.T, path string, rules string) {
+	if err := ioutil.WriteFile(path, []byte(rules), 0o600); err != nil {
+		t.Fatalf("cant write the rules: %s", err)
+	}
+}
+
+func TestAutomodReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "automod.yml")
+	writeRules(t, path, `
+rules:
+  - name: spam
+    body: "buy now"
+    action: remove
+`)
+
+	automod, err := NewAutomod(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	spam := model.AutomodSubject{Target: model.AutomodTargetComment, Body: "buy now"}
+	broken := []string{
+		"rules: [",
+		"rules:\n  - name: spam\n    body: \"buy now\"\n    action: ban\n",
+	}
+	for i, rules := range broken {
+		writeRules(t, path, rules)
+		if err = automod.Reload(); err == nil {
+			t.Errorf("[%d] expected an error for the broken rules", i)
+		}
+		if matches := automod.Evaluate(spam); len(matches) != 1 || matches[0].Rule.Name != "spam" {
+			t.Errorf("[%d] expected the old rules to stay, got: %v", i, matches)
+		}
+	}
+
+	writeRules(t, path, `
+dry_run: true
+rules:
+  - name: ads
+    body: "sale"
+    action: flag
+`)
+	if err = automod.Reload(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if matches := automod.Evaluate(spam); len(matches) != 0 {
+		t.Errorf("expected the new rules to replace the old ones, got: %v", matches)
+	}
+	ads := model.AutomodSubject{Target: model.AutomodTargetComment, Body: "big sale"}
+	if matches := automod.Evaluate(ads); len(matches) != 1 || !matches[0].DryRun {
+		t.Errorf("expected a dry run match, got: %v", matches)
+	}
+}
+
+const automodFlowRules = `
+rules:
+  - name: spam
+    body: "(?i)buy now"
+    action: remove
+    reason: "no ads"
+  - name: videos
+    target: post
+    domains: ["youtube.com"]
+    action: flag
+  - name: heated
+    target: comment
+    reports: 2
+    action: lock
+  - name: question
+    target: post
+    title: "(?i)^\\[question\\]"
+    action: reply
+    reply: "tell what you tried"
+`
+
+func newAutomodService(t *testing.T) (*service, model.User, model.User) {
+	path := filepath.Join(t.TempDir(), "automod.yml")
+	writeRules(t, path, automodFlowRules)
+	automod, err := NewAutomod(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	users := slicerepo.NewUsersRepo()
+	author := model.User{ID: "1", Credential: model.Credential{Username: "author"}}
+	commenter := model.User{ID: "2", Credential: model.Credential{Username: "commenter"}}
+	for _, usr := range []model.User{author, commenter, {ID: "3", Credential: model.Credential{Username: "third"}}} {
+		if err = users.AddUser(usr); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	posts := slicerepo.NewPostsRepo()
+	comments := slicerepo.NewCommentsRepo()
+	s := NewService(Repositories{
+		Users:         users,
+		Posts:         posts,
+		Comments:      comments,
+		Votes:         slicerepo.NewVotesRepo(),
+		Search:        slicerepo.NewSearchRepo(posts, comments),
+		Notifications: slicerepo.NewNotificationsRepo(),
+		Reports:       slicerepo.NewReportsRepo(),
+		Communities:   slicerepo.NewCommunitiesRepo(),
+		ModActions:    slicerepo.NewModActionsRepo(),
+		Bans:          slicerepo.NewBansRepo(),
+		LoginAttempts: lockout.NewMemoryStore(),
+	}, NewArgon2idHasher(), broker.NewMemoryBroker(), automod)
+
+	return s, author, commenter
+}
+
+// eventTypes reads what is already published, the memory broker delivers on Publish
+func eventTypes(t *testing.T, sub *broker.Subscription) []string {
+	types := make([]string, 0)
+	for {
+		select {
+		case message := <-sub.Messages():
+			var event model.Event
+			if err := json.Unmarshal(message, &event); err != nil {
+				t.Fatalf("cant decode the event: %s", err)
+			}
+			types = append(types, event.Type)
+		default:
+			return types
+		}
+	}
+}
+
+func TestAutomodRemove(t *testing.T) {
+	s, author, commenter := newAutomodService(t)
+
+	community, err := s.broker.Subscribe(model.CommunityTopic("programming"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer community.Close()
+
+	spam, err := s.CreateTextPost(model.TextPostInput{Category: "programming", Title: "deal", Type: "text", Text: "Buy now!"}, author)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if spam.Removed == nil || spam.Removed.Reason != "no ads" {
+		t.Errorf("expected the post to be removed, got: %v", spam.Removed)
+	}
+	if types := eventTypes(t, community); len(types) != 0 {
+		t.Errorf("expected no event for the removed post, got: %v", types)
+	}
+
+	post, err := s.CreateTextPost(model.TextPostInput{Category: "programming", Title: "go", Type: "text", Text: "generics"}, author)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if post.Removed != nil {
+		t.Errorf("expected the post to stay, got: %v", post.Removed)
+	}
+	if types := eventTypes(t, community); len(types) != 1 || types[0] != model.EventPostCreated {
+		t.Errorf("expected one event for the new post, got: %v", types)
+	}
+
+	thread, err := s.broker.Subscribe(model.PostTopic(post.ID))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer thread.Close()
+
+	if _, err = s.AddComment(post.ID, "buy NOW", "", commenter); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	comments, err := s.commentsRepo.GetCommentsByPost(post.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(comments) != 1 || comments[0].Removed == nil {
+		t.Errorf("expected the comment to be removed, got: %v", comments)
+	}
+	// the removal is announced like a deletion, the removed text is never sent
+	if types := eventTypes(t, thread); len(types) != 1 || types[0] != model.EventCommentDeleted {
+		t.Errorf("expected only the deletion event for the removed comment, got: %v", types)
+	}
+	notifications, err := s.GetNotifications(model.NotificationsQuery{}, author)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(notifications.Notifications) != 0 {
+		t.Errorf("expected no notification of the removed comment, got: %v", notifications.Notifications)
+	}
+
+	modlog, err := s.GetModLog(model.ModLogQuery{Moderator: model.AutomodUsername})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(modlog.Actions) != 2 {
+		t.Fatalf("expected two removals in the modlog, got: %v", modlog.Actions)
+	}
+	if modlog.Actions[0].Action != model.ModActionRemoveComment || modlog.Actions[1].Action != model.ModActionRemovePost {
+		t.Errorf("expected the comment and the post removals, got: %s, %s", modlog.Actions[0].Action, modlog.Actions[1].Action)
+	}
+}
+
+func TestAutomodFlag(t *testing.T) {
+	s, author, _ := newAutomodService(t)
+
+	post, err := s.CreateURLPost(model.URLPostInput{Category: "music", Title: "song", Type: "link", URL: "https://m.youtube.com/watch?v=1"}, author)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if post.Removed != nil {
+		t.Errorf("expected the flagged post to stay, got: %v", post.Removed)
+	}
+
+	count, err := s.reportsRepo.CountOpenReports(post.ID, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if count != 1 {
+		t.Errorf("expected one automod report, got: %d", count)
+	}
+}
+
+func TestAutomodLock(t *testing.T) {
+	s, author, commenter := newAutomodService(t)
+
+	post, err := s.CreateTextPost(model.TextPostInput{Category: "politics", Title: "taxes", Type: "text", Text: "discuss"}, author)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err = s.AddComment(post.ID, "hot take", "", commenter); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	comments, err := s.commentsRepo.GetCommentsByPost(post.ID)
+	if err != nil || len(comments) != 1 {
+		t.Fatalf("expected one comment, got: %v, %v", comments, err)
+	}
+
+	reporters := []model.User{author, author, {ID: "3", Credential: model.Credential{Username: "third"}}}
+	expectedLocked := []bool{false, false, true}
+	for i, reporter := range reporters {
+		if _, err = s.ReportComment(post.ID, comments[0].ID, model.ReportReasonOther, "rude", reporter); err != nil {
+			t.Fatalf("[%d] unexpected error: %s", i, err)
+		}
+		post, err = s.postsRepo.GetPostByID(post.ID)
+		if err != nil {
+			t.Fatalf("[%d] unexpected error: %s", i, err)
+		}
+		if post.Locked != expectedLocked[i] {
+			t.Errorf("[%d] expected locked %t, got %t", i, expectedLocked[i], post.Locked)
+		}
+	}
+
+	modlog, err := s.GetModLog(model.ModLogQuery{Action: model.ModActionLockPost})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(modlog.Actions) != 1 || modlog.Actions[0].Moderator.Username != model.AutomodUsername {
+		t.Errorf("expected one automod lock in the modlog, got: %v", modlog.Actions)
+	}
+}
+
+func TestAutomodReply(t *testing.T) {
+	s, author, _ := newAutomodService(t)
+
+	post, err := s.CreateTextPost(model.TextPostInput{Category: "golang", Title: "[Question] channels", Type: "text", Text: "how"}, author)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err = s.UpdatePost(post.ID, model.PostUpdateInput{Title: "[Question] channels", Text: "how do they block"}, author); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	comments, err := s.commentsRepo.GetCommentsByPost(post.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(comments) != 1 {
+		t.Fatalf("expected one automod reply, got: %v", comments)
+	}
+	if comments[0].Author.Username != model.AutomodUsername || comments[0].Body != "tell what you tried" {
+		t.Errorf("expected the automod reply, got: %s: %s", comments[0].Author.Username, comments[0].Body)
+	}
+}
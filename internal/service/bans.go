@@ -0,0 +1,169 @@
+package service
+
+import (
+	"github.com/sirupsen/logrus"
+	"redditclone/internal/model"
+	"redditclone/internal/model/customerr"
+	"redditclone/pkg/hexid"
+	"time"
+)
+
+type bansRepo interface {
+	AddBan(ban model.Ban) error
+	GetBan(community, userID string) (model.Ban, error)
+	GetActiveBans(community string, now string) ([]model.Ban, error)
+	DeleteBan(community, userID string) error
+}
+
+// checkBanned refuses a suspended user everywhere and a banned one in the community
+func (s *service) checkBanned(usr model.User, community string) error {
+	if usr.IsSuspended() {
+		return customerr.Banned{Username: usr.Username, Expires: usr.Suspension.Expires}
+	}
+
+	ban, err := s.bansRepo.GetBan(community, usr.ID)
+	if _, ok := err.(customerr.BanNotFound); ok {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if ban.IsActive() {
+		return customerr.Banned{Username: usr.Username, Community: community, Expires: ban.Expires}
+	}
+
+	return nil
+}
+
+// getBanTarget keeps the moderators from banning themselves and each other
+func (s *service) getBanTarget(username string, moderator model.User) (model.User, error) {
+	target, err := s.getCounterpart(username, moderator)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	if target.IsModerator() {
+		return model.User{}, customerr.PermissionDenied{Username: moderator.Username}
+	}
+
+	return target, nil
+}
+
+// banDetails puts the expiry before the reason into the modlog
+func banDetails(reason, expires string) string {
+	details := "permanent"
+	if expires != "" {
+		details = "until " + expires
+	}
+	if reason != "" {
+		details += ": " + reason
+	}
+	return details
+}
+
+// BanUser replaces the previous ban of the user in the community
+func (s *service) BanUser(community, username string, input model.BanInput, moderator model.User) (model.Ban, error) {
+	if _, err := s.communitiesRepo.GetCommunityByName(community); err != nil {
+		return model.Ban{}, err
+	}
+
+	target, err := s.getBanTarget(username, moderator)
+	if err != nil {
+		return model.Ban{}, err
+	}
+
+	banID, err := hexid.Generate()
+	if err != nil {
+		return model.Ban{}, err
+	}
+
+	targetAuthor := model.Author{ID: target.ID, Username: target.Username}
+	ban := model.NewBan(banID, community, targetAuthor, model.Author{ID: moderator.ID, Username: moderator.Username}, input)
+	if err = s.bansRepo.AddBan(ban); err != nil {
+		return model.Ban{}, err
+	}
+
+	s.recordModAction(model.ModActionBanUser, moderator, targetAuthor, func(a *model.ModAction) {
+		a.Community = community
+		a.Details = banDetails(ban.Reason, ban.Expires)
+	})
+
+	logrus.Infof("user banned: %s in %s", target.Username, community)
+
+	return ban, nil
+}
+
+func (s *service) GetBans(community string) ([]model.Ban, error) {
+	if _, err := s.communitiesRepo.GetCommunityByName(community); err != nil {
+		return nil, err
+	}
+
+	return s.bansRepo.GetActiveBans(community, time.Now().UTC().Format("2006-01-02T15:04:05.000Z"))
+}
+
+func (s *service) UnbanUser(community, username string, moderator model.User) error {
+	target, err := s.usersRepo.GetUserByUsername(username)
+	if err != nil {
+		return err
+	}
+
+	if err = s.bansRepo.DeleteBan(community, target.ID); err != nil {
+		return err
+	}
+
+	s.recordModAction(model.ModActionUnbanUser, moderator, model.Author{ID: target.ID, Username: target.Username}, func(a *model.ModAction) {
+		a.Community = community
+	})
+
+	logrus.Infof("user unbanned: %s in %s", target.Username, community)
+
+	return nil
+}
+
+// SuspendUser replaces the previous suspension, admins can't be suspended
+func (s *service) SuspendUser(userID string, input model.BanInput, admin model.User) (model.User, error) {
+	usr, err := s.usersRepo.GetUserByID(userID)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	if usr.ID == admin.ID {
+		return model.User{}, customerr.TargetIsSelf{Username: usr.Username}
+	}
+	if usr.IsAdmin() {
+		return model.User{}, customerr.PermissionDenied{Username: admin.Username}
+	}
+
+	suspension := model.NewSuspension(input)
+	if err = s.usersRepo.UpdateSuspension(userID, suspension); err != nil {
+		return model.User{}, err
+	}
+	usr.Suspension = suspension
+
+	s.recordModAction(model.ModActionSuspendUser, admin, model.Author{ID: usr.ID, Username: usr.Username}, func(a *model.ModAction) {
+		a.Details = banDetails(suspension.Reason, suspension.Expires)
+	})
+
+	logrus.Infof("user suspended: %s", usr.Username)
+
+	return usr, nil
+}
+
+func (s *service) UnsuspendUser(userID string, admin model.User) (model.User, error) {
+	usr, err := s.usersRepo.GetUserByID(userID)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	if err = s.usersRepo.UpdateSuspension(userID, nil); err != nil {
+		return model.User{}, err
+	}
+	usr.Suspension = nil
+
+	s.recordModAction(model.ModActionUnsuspendUser, admin, model.Author{ID: usr.ID, Username: usr.Username}, nil)
+
+	logrus.Infof("user unsuspended: %s", usr.Username)
+
+	return usr, nil
+}
@@ -0,0 +1,354 @@
+package service
+
+import (
+	"github.com/sirupsen/logrus"
+	"redditclone/internal/model"
+	"redditclone/internal/model/customerr"
+	"redditclone/pkg/hexid"
+)
+
+type commentsRepo interface {
+	AddComment(comment model.Comment) error
+	GetCommentByID(postID, commentID string) (model.Comment, error)
+	GetCommentsByPost(postID string) ([]model.Comment, error)
+	GetCommentsByAuthor(username string, limit int) ([]model.Comment, error)
+	CountCommentsByAuthor(username string) (int, error)
+	DeleteComment(postID, commentID string) error
+	MarkCommentDeleted(postID, commentID string) error
+	RemoveComment(postID, commentID string, removal *model.Removal) error
+	EditComment(comment model.Comment) error
+	UpdateCommentVoteCounts(postID, commentID string, ups, downs int) (model.Comment, error)
+	DeletePostComments(postID string) error
+}
+
+// withComments fills the comments of a single post, listings carry only the comment count
+func (s *service) withComments(post model.Post) (model.Post, error) {
+	comments, err := s.commentsRepo.GetCommentsByPost(post.ID)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	post.Comments = comments
+
+	return post, nil
+}
+
+func (s *service) AddComment(postID string, commentText string, parentID string, usr model.User) (model.Post, error) {
+	post, err := s.getLivePost(postID)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	if err = s.checkBanned(usr, post.Category); err != nil {
+		return model.Post{}, err
+	}
+
+	if post.Locked && !usr.IsModerator() {
+		return model.Post{}, customerr.PostLocked{PostID: postID}
+	}
+
+	commentID, err := hexid.Generate()
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	author := model.Author{ID: usr.ID, Username: usr.Username}
+	comment := model.NewComment(commentID, postID, commentText, author)
+
+	var parent model.Comment
+	if parentID != "" {
+		parent, err = s.commentsRepo.GetCommentByID(postID, parentID)
+		if err != nil {
+			return model.Post{}, err
+		}
+		if parent.Deleted {
+			return model.Post{}, customerr.CommentNotFoundByID{PostID: postID, CommentID: parentID}
+		}
+		if parent.Depth+1 > model.MaxCommentDepth {
+			return model.Post{}, customerr.CommentTooDeep{ParentID: parentID, MaxDepth: model.MaxCommentDepth}
+		}
+
+		comment = model.NewReply(commentID, commentText, author, parent)
+	}
+
+	if err = s.commentsRepo.AddComment(comment); err != nil {
+		return model.Post{}, err
+	}
+
+	if parentID != "" {
+		if err = s.checkReplyParent(comment); err != nil {
+			return model.Post{}, err
+		}
+	}
+
+	if err = s.postsRepo.UpdateCommentCount(postID, 1); err != nil {
+		return model.Post{}, err
+	}
+	post.CommentCount++
+
+	logrus.Infoln("comment added")
+
+	changed, removed := s.moderateContent(model.NewCommentSubject(post, comment, usr), post, &comment)
+	if changed {
+		if post, err = s.postsRepo.GetPostByID(postID); err != nil {
+			return model.Post{}, err
+		}
+	}
+	if !removed {
+		s.publish(model.PostTopic(postID), model.NewCommentEvent(model.EventCommentAdded, comment))
+		s.notifyComment(post, parent, comment)
+	}
+
+	return s.showPost(post, usr)
+}
+
+// checkReplyParent takes the reply back when its parent was deleted while the reply was being added,
+// DeleteComment sets the placeholder before it looks for the replies
+func (s *service) checkReplyParent(reply model.Comment) error {
+	parent, err := s.commentsRepo.GetCommentByID(reply.PostID, reply.ParentID)
+	if _, ok := err.(customerr.CommentNotFoundByID); ok || (err == nil && parent.Deleted) {
+		if err = s.commentsRepo.DeleteComment(reply.PostID, reply.ID); err != nil {
+			return err
+		}
+		return customerr.CommentNotFoundByID{PostID: reply.PostID, CommentID: reply.ParentID}
+	}
+	return err
+}
+
+func findComment(comments []model.Comment, commentID string) (model.Comment, bool) {
+	for _, c := range comments {
+		if c.ID == commentID {
+			return c, true
+		}
+	}
+	return model.Comment{}, false
+}
+
+func removeComment(comments []model.Comment, commentID string) []model.Comment {
+	for i, c := range comments {
+		if c.ID == commentID {
+			return append(comments[:i], comments[i+1:]...)
+		}
+	}
+	return comments
+}
+
+// DeleteComment keeps a placeholder in place of a comment with replies,
+// a leaf is removed along with the placeholders left without replies above it.
+// A moderator removes the comment of another user keeping it for the modlog.
+// The placeholder is set first and only one of concurrent deletions sets it, the replies are checked after it,
+// while AddComment checks the parent after adding a reply, so a reply never loses its parent.
+func (s *service) DeleteComment(postID, commentID string, reason string, usr model.User) (model.Post, error) {
+	post, err := s.postsRepo.GetPostByID(postID)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	comment, err := s.commentsRepo.GetCommentByID(postID, commentID)
+	if err != nil {
+		return model.Post{}, err
+	}
+	if comment.Deleted {
+		return model.Post{}, customerr.CommentNotFoundByID{PostID: postID, CommentID: commentID}
+	}
+
+	if comment.Author.ID != usr.ID && !usr.IsModerator() {
+		return model.Post{}, customerr.NotOwner{Username: usr.Username}
+	}
+
+	if comment.Author.ID != usr.ID {
+		if err = s.removeComment(post, comment, reason, usr); err != nil {
+			return model.Post{}, err
+		}
+		return s.showPost(post, usr)
+	}
+
+	if err = s.commentsRepo.MarkCommentDeleted(postID, commentID); err != nil {
+		return model.Post{}, err
+	}
+
+	comments, err := s.commentsRepo.GetCommentsByPost(postID)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	if model.HasReplies(comments, commentID) {
+		s.publish(model.PostTopic(postID), model.NewDeletionEvent(model.EventCommentDeleted, postID, commentID))
+	} else {
+		removed := 0
+		for deletedID := commentID; deletedID != ""; {
+			deleted, found := findComment(comments, deletedID)
+			if !found || !deleted.Deleted || model.HasReplies(comments, deletedID) {
+				break
+			}
+
+			// another deletion has cleaned the placeholder up already
+			if err = s.commentsRepo.DeleteComment(postID, deletedID); err != nil {
+				if _, ok := err.(customerr.CommentNotFoundByID); ok {
+					break
+				}
+				return model.Post{}, err
+			}
+			if err = s.votesRepo.DeleteCommentVotes(postID, deletedID); err != nil {
+				return model.Post{}, err
+			}
+			comments = removeComment(comments, deletedID)
+			removed++
+			s.publish(model.PostTopic(postID), model.NewDeletionEvent(model.EventCommentDeleted, postID, deletedID))
+			deletedID = deleted.ParentID
+		}
+
+		if removed != 0 {
+			if err = s.postsRepo.UpdateCommentCount(postID, -removed); err != nil {
+				return model.Post{}, err
+			}
+			post.CommentCount -= removed
+		}
+	}
+
+	logrus.Infoln("comment deleted")
+
+	return s.showPost(post, usr)
+}
+
+// removeComment leaves the comment in its place, a comment which is already removed stays as it is,
+// by another request as well
+func (s *service) removeComment(post model.Post, comment model.Comment, reason string, moderator model.User) error {
+	if comment.Removed != nil {
+		return nil
+	}
+
+	removal := model.NewRemoval(model.Author{ID: moderator.ID, Username: moderator.Username}, reason)
+	if err := s.commentsRepo.RemoveComment(post.ID, comment.ID, removal); err != nil {
+		if _, ok := err.(customerr.CommentChanged); ok {
+			return nil
+		}
+		return err
+	}
+	s.publish(model.PostTopic(post.ID), model.NewDeletionEvent(model.EventCommentDeleted, post.ID, comment.ID))
+
+	s.recordModAction(model.ModActionRemoveComment, moderator, comment.Author, func(a *model.ModAction) {
+		a.Community = post.Category
+		a.PostID = post.ID
+		a.CommentID = comment.ID
+		a.Details = reason
+	})
+
+	logrus.Infoln("comment removed")
+
+	return nil
+}
+
+// getLiveComment returns the comment unless only its placeholder is left or it was removed
+func (s *service) getLiveComment(postID, commentID string) (model.Comment, error) {
+	comment, err := s.commentsRepo.GetCommentByID(postID, commentID)
+	if err != nil {
+		return model.Comment{}, err
+	}
+	if comment.Deleted || comment.Removed != nil {
+		return model.Comment{}, customerr.CommentNotFoundByID{PostID: postID, CommentID: commentID}
+	}
+	return comment, nil
+}
+
+func (s *service) EditComment(postID, commentID string, commentText string, usr model.User) (model.Post, error) {
+	post, err := s.postsRepo.GetPostByID(postID)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	comment, err := s.getLiveComment(postID, commentID)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	if comment.Author.ID != usr.ID {
+		return model.Post{}, customerr.NotOwner{Username: usr.Username}
+	}
+
+	if err = s.commentsRepo.EditComment(*comment.Edit(commentText)); err != nil {
+		return model.Post{}, err
+	}
+
+	logrus.Infoln("comment edited")
+
+	subject := model.NewCommentSubject(post, comment, usr)
+	subject.Edited = true
+	changed, removed := s.moderateContent(subject, post, &comment)
+	if changed {
+		if post, err = s.postsRepo.GetPostByID(postID); err != nil {
+			return model.Post{}, err
+		}
+	}
+	if !removed {
+		s.publish(model.PostTopic(postID), model.NewCommentEvent(model.EventCommentEdited, comment))
+	}
+
+	return s.showPost(post, usr)
+}
+
+// voteComment leaves the vote to the store the same way as for posts
+func (s *service) voteComment(postID, commentID string, usr model.User, value int) (model.Post, error) {
+	post, err := s.getLivePost(postID)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	if err = s.checkBanned(usr, post.Category); err != nil {
+		return model.Post{}, err
+	}
+
+	comment, err := s.getLiveComment(postID, commentID)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	ups, downs, err := s.vote(usr, postID, commentID, value)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	voted, err := s.commentsRepo.UpdateCommentVoteCounts(postID, commentID, ups, downs)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	s.addKarma(comment.Author, model.Karma{Comment: ups - downs})
+	s.publish(model.PostTopic(postID), model.NewCommentEvent(model.EventCommentVoted, voted))
+	s.notifyMilestones(comment.Author, postID, commentID, voted.Score-(ups-downs), voted.Score)
+
+	return s.showPost(post, usr)
+}
+
+func (s *service) UpvoteComment(postID, commentID string, usr model.User) (model.Post, error) {
+	post, err := s.voteComment(postID, commentID, usr, model.VoteUp)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	logrus.Infoln("comment upvoted")
+
+	return post, nil
+}
+
+func (s *service) DownvoteComment(postID, commentID string, usr model.User) (model.Post, error) {
+	post, err := s.voteComment(postID, commentID, usr, model.VoteDown)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	logrus.Infoln("comment downvoted")
+
+	return post, nil
+}
+
+func (s *service) UnvoteComment(postID, commentID string, usr model.User) (model.Post, error) {
+	post, err := s.voteComment(postID, commentID, usr, model.VoteNone)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	logrus.Infoln("comment unvoted")
+
+	return post, nil
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"redditclone/internal/model"
+	"redditclone/internal/repository/slicerepo"
+	"redditclone/pkg/broker"
+	"testing"
+)
+
+func TestDeleteCommentDeletesVotes(t *testing.T) {
+	posts := slicerepo.NewPostsRepo()
+	votes := slicerepo.NewVotesRepo()
+	s := NewService(Repositories{
+		Users:         slicerepo.NewUsersRepo(),
+		Posts:         posts,
+		Comments:      slicerepo.NewCommentsRepo(),
+		Votes:         votes,
+		Notifications: slicerepo.NewNotificationsRepo(),
+		Bans:          slicerepo.NewBansRepo(),
+	}, NewArgon2idHasher(), broker.NewMemoryBroker(), nil)
+
+	author := model.User{ID: "1", Credential: model.Credential{Username: "author"}}
+	voter := model.User{ID: "2", Credential: model.Credential{Username: "voter"}}
+
+	post, err := s.CreateTextPost(model.TextPostInput{Category: "programming", Title: "go", Type: "text", Text: "generics"}, author)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if post, err = s.AddComment(post.ID, "first", "", author); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	commentID := post.Comments[0].ID
+	if post, err = s.AddComment(post.ID, "second", "", author); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for _, comment := range post.Comments {
+		if _, err = s.UpvoteComment(post.ID, comment.ID, voter); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	if _, err = s.DeleteComment(post.ID, commentID, "", author); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	left, err := votes.GetVotesByUser(voter.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(left) != 1 || left[0].CommentID == commentID {
+		t.Errorf("expected only the vote of the other comment, got: %+v", left)
+	}
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"github.com/sirupsen/logrus"
+	"redditclone/internal/model"
+	"redditclone/internal/model/customerr"
+)
+
+type communitiesRepo interface {
+	AddCommunity(community model.Community) error
+	GetCommunities() ([]model.Community, error)
+	GetCommunityByName(name string) (model.Community, error)
+	UpdateCommunity(name string, description string) (model.Community, error)
+	DeleteCommunity(name string) error
+}
+
+// defaultCommunities are the categories posts were created in before communities existed
+var defaultCommunities = []model.CommunityInput{
+	{Name: "music", Description: "Music"},
+	{Name: "funny", Description: "Funny"},
+	{Name: "videos", Description: "Videos"},
+	{Name: "programming", Description: "Programming"},
+	{Name: "news", Description: "News"},
+	{Name: "fashion", Description: "Fashion"},
+}
+
+func (s *service) SeedCommunities() error {
+	for _, input := range defaultCommunities {
+		err := s.communitiesRepo.AddCommunity(model.NewCommunity(input, model.Author{}))
+		if _, ok := err.(customerr.CommunityAlreadyExists); ok {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		logrus.Infof("community seeded: %s", input.Name)
+	}
+	return nil
+}
+
+func (s *service) GetCommunities() ([]model.Community, error) {
+	return s.communitiesRepo.GetCommunities()
+}
+
+func (s *service) GetCommunity(name string) (model.Community, error) {
+	return s.communitiesRepo.GetCommunityByName(name)
+}
+
+func (s *service) CreateCommunity(input model.CommunityInput, usr model.User) (model.Community, error) {
+	community := model.NewCommunity(input, model.Author{ID: usr.ID, Username: usr.Username})
+	if err := s.communitiesRepo.AddCommunity(community); err != nil {
+		return model.Community{}, err
+	}
+
+	logrus.Infof("community created: %s", community.Name)
+
+	return community, nil
+}
+
+// checkCommunityOwner lets the creator and admins manage a community, seeded ones have no creator and belong to admins
+func (s *service) checkCommunityOwner(name string, usr model.User) error {
+	community, err := s.communitiesRepo.GetCommunityByName(name)
+	if err != nil {
+		return err
+	}
+
+	if community.Creator.ID != usr.ID && !usr.IsAdmin() {
+		return customerr.NotOwner{Username: usr.Username}
+	}
+
+	return nil
+}
+
+func (s *service) UpdateCommunity(name string, description string, usr model.User) (model.Community, error) {
+	if err := s.checkCommunityOwner(name, usr); err != nil {
+		return model.Community{}, err
+	}
+
+	community, err := s.communitiesRepo.UpdateCommunity(name, description)
+	if err != nil {
+		return model.Community{}, err
+	}
+
+	logrus.Infof("community updated: %s", name)
+
+	return community, nil
+}
+
+func (s *service) DeleteCommunity(name string, usr model.User) error {
+	if err := s.checkCommunityOwner(name, usr); err != nil {
+		return err
+	}
+
+	posts, err := s.postsRepo.GetPostsByCategory(name)
+	if err != nil {
+		return err
+	}
+	if len(posts) != 0 {
+		return customerr.CommunityNotEmpty{Name: name}
+	}
+
+	if err = s.communitiesRepo.DeleteCommunity(name); err != nil {
+		return err
+	}
+
+	logrus.Infof("community deleted: %s", name)
+
+	return nil
+}
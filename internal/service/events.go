@@ -0,0 +1,45 @@
+package service
+
+import (
+	"encoding/json"
+	"github.com/sirupsen/logrus"
+	"redditclone/internal/model"
+	"redditclone/pkg/broker"
+)
+
+type EventBroker interface {
+	Publish(topic string, payload []byte) error
+	Subscribe(topic string) (*broker.Subscription, error)
+}
+
+// publish is fire and forget, the subscribers which miss an event see the change on the next reload
+func (s *service) publish(topic string, event model.Event) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		logrus.Errorln(err)
+		return
+	}
+
+	if err = s.broker.Publish(topic, payload); err != nil {
+		logrus.Errorln(err)
+	}
+}
+
+func (s *service) publishPostEvent(eventType string, post model.Post) {
+	s.publish(model.PostTopic(post.ID), model.NewPostEvent(eventType, post))
+}
+
+func (s *service) SubscribePost(postID string) (*broker.Subscription, error) {
+	if _, err := s.postsRepo.GetPostByID(postID); err != nil {
+		return nil, err
+	}
+	return s.broker.Subscribe(model.PostTopic(postID))
+}
+
+// SubscribeCommunity streams the posts created in the community
+func (s *service) SubscribeCommunity(name string) (*broker.Subscription, error) {
+	if _, err := s.communitiesRepo.GetCommunityByName(name); err != nil {
+		return nil, err
+	}
+	return s.broker.Subscribe(model.CommunityTopic(name))
+}
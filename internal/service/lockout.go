@@ -0,0 +1,108 @@
+package service
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"redditclone/internal/model"
+	"redditclone/internal/model/customerr"
+	"time"
+)
+
+type loginAttemptsRepo interface {
+	AddFailure(key string, ttl time.Duration) (int, error)
+	Lock(key string, duration time.Duration) error
+	LockedFor(key string) (time.Duration, error)
+	Reset(key string) error
+}
+
+func userLoginKey(username string) string {
+	return "user:" + username
+}
+
+func ipLoginKey(ip string) string {
+	return "ip:" + ip
+}
+
+// checkLoginLocked lets the login through when the store fails, a broken store must not lock everybody out
+func (s *service) checkLoginLocked(username, ip string) error {
+	lockedFor, err := s.loginAttemptsRepo.LockedFor(userLoginKey(username))
+	if err != nil {
+		logrus.Errorln(err)
+	}
+	if lockedFor > 0 {
+		return customerr.LoginLocked{Username: username, IP: ip, RetryAfter: lockedFor}
+	}
+
+	if ip == "" {
+		return nil
+	}
+	lockedFor, err = s.loginAttemptsRepo.LockedFor(ipLoginKey(ip))
+	if err != nil {
+		logrus.Errorln(err)
+	}
+	if lockedFor > 0 {
+		return customerr.LoginLocked{IP: ip, RetryAfter: lockedFor}
+	}
+
+	return nil
+}
+
+// loginFailed counts the failure for the username and for the address, the target is empty for an unknown username
+func (s *service) loginFailed(username string, target model.Author, ip string) {
+	s.countLoginFailure(userLoginKey(username), model.MaxLoginFailures, username, target, "")
+	if ip != "" {
+		s.countLoginFailure(ipLoginKey(ip), model.MaxLoginFailuresPerIP, username, model.Author{}, ip)
+	}
+}
+
+// countLoginFailure locks the key once the failures reach the limit. The locks of unknown usernames
+// stay out of the modlog, they are not accounts and anybody can fill the log with them.
+func (s *service) countLoginFailure(key string, maxFailures int, username string, target model.Author, ip string) {
+	failures, err := s.loginAttemptsRepo.AddFailure(key, model.LoginFailuresTTL)
+	if err != nil {
+		logrus.Errorln(err)
+		return
+	}
+
+	lockout := model.LoginLockoutFor(failures, maxFailures)
+	if lockout == 0 {
+		return
+	}
+
+	if err = s.loginAttemptsRepo.Lock(key, lockout); err != nil {
+		logrus.Errorln(err)
+		return
+	}
+
+	logrus.Infof("login locked: %s for %s", key, lockout)
+
+	if target.ID == "" && ip == "" {
+		return
+	}
+
+	details := fmt.Sprintf("user %s: %d failed logins, locked for %s", username, failures, lockout)
+	if ip != "" {
+		details = fmt.Sprintf("address %s, last tried user %s: %d failed logins, locked for %s", ip, username, failures, lockout)
+	}
+	s.recordModAction(model.ModActionLockAccount, model.System, target, func(a *model.ModAction) {
+		a.Details = details
+	})
+}
+
+// UnlockUser forgets the failed logins of the user, the locks of the addresses stay
+func (s *service) UnlockUser(userID string, admin model.User) error {
+	usr, err := s.usersRepo.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if err = s.loginAttemptsRepo.Reset(userLoginKey(usr.Username)); err != nil {
+		return err
+	}
+
+	s.recordModAction(model.ModActionUnlockAccount, admin, model.Author{ID: usr.ID, Username: usr.Username}, nil)
+
+	logrus.Infof("user unlocked: %s", usr.Username)
+
+	return nil
+}
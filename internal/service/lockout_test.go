@@ -0,0 +1,76 @@
+package service
+
+import (
+	"redditclone/internal/model"
+	"redditclone/internal/repository/slicerepo"
+	"redditclone/pkg/broker"
+	"redditclone/pkg/lockout"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoginLockoutFor(t *testing.T) {
+	cases := []struct {
+		failures int
+		expected time.Duration
+	}{
+		{failures: 0, expected: 0},
+		{failures: model.MaxLoginFailures - 1, expected: 0},
+		{failures: model.MaxLoginFailures, expected: model.LoginLockout},
+		{failures: model.MaxLoginFailures + 1, expected: 2 * model.LoginLockout},
+		{failures: model.MaxLoginFailures + 3, expected: 8 * model.LoginLockout},
+		{failures: model.MaxLoginFailures + 6, expected: model.MaxLoginLockout},
+		{failures: model.MaxLoginFailures + 100, expected: model.MaxLoginLockout},
+	}
+
+	for i, item := range cases {
+		if lockout := model.LoginLockoutFor(item.failures, model.MaxLoginFailures); lockout != item.expected {
+			t.Errorf("[%d] expected a lockout for %s, got %s", i, item.expected, lockout)
+		}
+	}
+}
+
+func TestLoginLockoutModLog(t *testing.T) {
+	modActions := slicerepo.NewModActionsRepo()
+	s := NewService(Repositories{
+		Users:         slicerepo.NewUsersRepo(),
+		ModActions:    modActions,
+		LoginAttempts: lockout.NewMemoryStore(),
+	}, NewArgon2idHasher(), broker.NewMemoryBroker(), nil)
+
+	lockouts := func() []model.ModAction {
+		page, err := s.GetModLog(model.ModLogQuery{Action: model.ModActionLockAccount, Limit: 10})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		return page.Actions
+	}
+
+	for i := 0; i < model.MaxLoginFailures; i++ {
+		s.loginFailed("ghost", model.Author{Username: "ghost"}, "")
+	}
+	if actions := lockouts(); len(actions) != 0 {
+		t.Errorf("expected no modlog entry for an unknown username, got: %+v", actions)
+	}
+
+	van := model.Author{ID: "1", Username: "van"}
+	for i := 0; i < model.MaxLoginFailures; i++ {
+		s.loginFailed("van", van, "")
+	}
+	actions := lockouts()
+	if len(actions) != 1 || actions[0].TargetUser != van || !strings.Contains(actions[0].Details, "user van") {
+		t.Fatalf("expected the lockout of the account, got: %+v", actions)
+	}
+
+	for i := 0; i < model.MaxLoginFailuresPerIP; i++ {
+		s.loginFailed("ghost", model.Author{Username: "ghost"}, "10.0.0.1")
+	}
+	actions = lockouts()
+	if len(actions) != 2 {
+		t.Fatalf("expected the lockout of the address, got: %+v", actions)
+	}
+	if details := actions[0].Details; !strings.Contains(details, "address 10.0.0.1") || !strings.Contains(details, "user ghost") {
+		t.Errorf("expected the address and the username in the details, got: %s", details)
+	}
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"github.com/sirupsen/logrus"
+	"redditclone/internal/model"
+	"redditclone/internal/model/customerr"
+	"redditclone/pkg/hexid"
+	"redditclone/pkg/ratelimit"
+	"time"
+)
+
+type messagesRepo interface {
+	AddMessage(message model.Message) error
+	GetConversations(userID string) ([]model.Conversation, error)
+	GetThread(userID, otherID string) ([]model.Message, error)
+	MarkThreadRead(userID, otherID string) error
+	AddBlock(block model.Block) error
+	DeleteBlock(userID, blockedID string) error
+	GetBlocks(userID string) ([]model.Block, error)
+	IsBlocked(userID, blockedID string) (bool, error)
+}
+
+// messageRatesRepo reserves a place in the sliding window of the sender
+type messageRatesRepo interface {
+	Allow(key string, limit ratelimit.Limit) (bool, time.Duration, error)
+}
+
+// getCounterpart resolves the other user of a conversation or a block
+func (s *service) getCounterpart(username string, usr model.User) (model.User, error) {
+	if username == usr.Username {
+		return model.User{}, customerr.TargetIsSelf{Username: username}
+	}
+	return s.usersRepo.GetUserByUsername(username)
+}
+
+// checkBlocks refuses the messages when either of the users has blocked the other one
+func (s *service) checkBlocks(usr model.User, other model.User) error {
+	for _, pair := range [][2]string{{usr.ID, other.ID}, {other.ID, usr.ID}} {
+		blocked, err := s.messagesRepo.IsBlocked(pair[0], pair[1])
+		if err != nil {
+			return err
+		}
+		if blocked {
+			return customerr.UserBlocked{Username: other.Username}
+		}
+	}
+	return nil
+}
+
+// checkMessageRate takes the place of the message in the window before it is sent,
+// so the concurrent sends of the user can't pass the limit together
+func (s *service) checkMessageRate(usr model.User) error {
+	limit := ratelimit.Limit{Requests: model.MessageRateLimit, Window: model.MessageRateWindow}
+	allowed, retryAfter, err := s.messageRatesRepo.Allow("messages:user:"+usr.ID, limit)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return customerr.RateLimited{Action: "messages", RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+func (s *service) SendMessage(username string, body string, usr model.User) (model.Message, error) {
+	recipient, err := s.getCounterpart(username, usr)
+	if err != nil {
+		return model.Message{}, err
+	}
+
+	if err = s.checkBlocks(usr, recipient); err != nil {
+		return model.Message{}, err
+	}
+
+	if err = s.checkMessageRate(usr); err != nil {
+		return model.Message{}, err
+	}
+
+	messageID, err := hexid.Generate()
+	if err != nil {
+		return model.Message{}, err
+	}
+
+	message := model.NewMessage(
+		messageID,
+		model.Author{ID: usr.ID, Username: usr.Username},
+		model.Author{ID: recipient.ID, Username: recipient.Username},
+		body,
+	)
+	if err = s.messagesRepo.AddMessage(message); err != nil {
+		return model.Message{}, err
+	}
+
+	logrus.Infoln("message sent")
+
+	return message, nil
+}
+
+func (s *service) GetConversations(usr model.User) ([]model.Conversation, error) {
+	return s.messagesRepo.GetConversations(usr.ID)
+}
+
+// GetThread returns the messages with the user from the oldest one, a blocked thread can still be read
+func (s *service) GetThread(username string, usr model.User) ([]model.Message, error) {
+	other, err := s.getCounterpart(username, usr)
+	if err != nil {
+		return nil, err
+	}
+	return s.messagesRepo.GetThread(usr.ID, other.ID)
+}
+
+func (s *service) MarkThreadRead(username string, usr model.User) error {
+	other, err := s.getCounterpart(username, usr)
+	if err != nil {
+		return err
+	}
+	return s.messagesRepo.MarkThreadRead(usr.ID, other.ID)
+}
+
+func (s *service) BlockUser(username string, usr model.User) error {
+	blocked, err := s.getCounterpart(username, usr)
+	if err != nil {
+		return err
+	}
+
+	if err = s.messagesRepo.AddBlock(model.NewBlock(usr.ID, model.Author{ID: blocked.ID, Username: blocked.Username})); err != nil {
+		return err
+	}
+
+	logrus.Infoln("user blocked")
+
+	return nil
+}
+
+func (s *service) UnblockUser(username string, usr model.User) error {
+	blocked, err := s.getCounterpart(username, usr)
+	if err != nil {
+		return err
+	}
+
+	if err = s.messagesRepo.DeleteBlock(usr.ID, blocked.ID); err != nil {
+		return err
+	}
+
+	logrus.Infoln("user unblocked")
+
+	return nil
+}
+
+func (s *service) GetBlocks(usr model.User) ([]model.Block, error) {
+	return s.messagesRepo.GetBlocks(usr.ID)
+}
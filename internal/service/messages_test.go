@@ -0,0 +1,65 @@
+package service
+
+import (
+	"redditclone/internal/model"
+	"redditclone/internal/model/customerr"
+	"redditclone/internal/repository/slicerepo"
+	"redditclone/pkg/broker"
+	"redditclone/pkg/ratelimit"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentMessagesKeepRateLimit(t *testing.T) {
+	users := slicerepo.NewUsersRepo()
+	sender := model.User{ID: "1", Credential: model.Credential{Username: "sender"}}
+	recipient := model.User{ID: "2", Credential: model.Credential{Username: "recipient"}}
+	for _, usr := range []model.User{sender, recipient} {
+		if err := users.AddUser(usr); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	messages := slicerepo.NewMessagesRepo()
+	s := NewService(Repositories{
+		Users:        users,
+		Messages:     messages,
+		MessageRates: ratelimit.NewMemoryStore(),
+	}, NewArgon2idHasher(), broker.NewMemoryBroker(), nil)
+
+	const sends = model.MessageRateLimit * 3
+	errs := make(chan error, sends)
+	wg := sync.WaitGroup{}
+	for i := 0; i < sends; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.SendMessage(recipient.Username, "hi", sender)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	sent := 0
+	for err := range errs {
+		if err == nil {
+			sent++
+			continue
+		}
+		if limited, ok := err.(customerr.RateLimited); !ok || limited.RetryAfter <= 0 {
+			t.Errorf("expected the rate limit error, got: %v", err)
+		}
+	}
+	if sent != model.MessageRateLimit {
+		t.Errorf("expected %d messages to pass, got: %d", model.MessageRateLimit, sent)
+	}
+
+	thread, err := messages.GetThread(sender.ID, recipient.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(thread) != model.MessageRateLimit {
+		t.Errorf("expected %d stored messages, got: %d", model.MessageRateLimit, len(thread))
+	}
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"redditclone/internal/model"
+	"redditclone/internal/model/customerr"
+	"redditclone/pkg/cursor"
+	"redditclone/pkg/hexid"
+)
+
+// modActionsRepo is append-only, the recorded actions are never changed
+type modActionsRepo interface {
+	AddModAction(action model.ModAction) error
+	GetModActions(query model.ModLogQuery, after model.ModActionCursor) ([]model.ModAction, error)
+}
+
+// recordModAction only logs its errors, a moderation done without a trail entry stays done
+func (s *service) recordModAction(action string, moderator model.User, target model.Author, fill func(a *model.ModAction)) {
+	actionID, err := hexid.Generate()
+	if err != nil {
+		logrus.Errorln(err)
+		return
+	}
+
+	modAction := model.NewModAction(actionID, action, model.Author{ID: moderator.ID, Username: moderator.Username}, target)
+	if fill != nil {
+		fill(&modAction)
+	}
+
+	if err = s.modActionsRepo.AddModAction(modAction); err != nil {
+		logrus.Errorln(err)
+		return
+	}
+
+	logrus.Infof("mod action recorded: %s by %s", action, moderator.Username)
+}
+
+func (s *service) SetUserRole(userID string, role string, admin model.User) (model.User, error) {
+	usr, err := s.usersRepo.GetUserByID(userID)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	if err = s.usersRepo.UpdateRole(userID, role); err != nil {
+		return model.User{}, err
+	}
+	usr.Role = role
+
+	s.recordModAction(model.ModActionSetRole, admin, model.Author{ID: usr.ID, Username: usr.Username}, func(a *model.ModAction) {
+		a.Details = role
+	})
+
+	logrus.Infof("user role changed: %s is %s", usr.Username, role)
+
+	return usr, nil
+}
+
+// GetModLog returns a page of the modlog from the newest action, one extra action is requested
+// to know whether the next page exists
+func (s *service) GetModLog(query model.ModLogQuery) (model.ModLogPage, error) {
+	var after model.ModActionCursor
+	if query.Cursor != "" {
+		if err := cursor.Decode(query.Cursor, &after); err != nil || after.IsZero() {
+			return model.ModLogPage{}, customerr.InvalidCursor{Cursor: query.Cursor}
+		}
+	}
+
+	if query.Limit <= 0 || query.Limit > model.MaxPageLimit {
+		query.Limit = model.DefaultPageLimit
+	}
+	limit := query.Limit
+	query.Limit++
+
+	actions, err := s.modActionsRepo.GetModActions(query, after)
+	if err != nil {
+		return model.ModLogPage{}, err
+	}
+
+	page := model.ModLogPage{Actions: actions}
+	if len(actions) > limit {
+		page.Actions = actions[:limit]
+		last := page.Actions[limit-1]
+		page.NextCursor, err = cursor.Encode(model.ModActionCursor{Created: last.Created, ID: last.ID})
+		if err != nil {
+			return model.ModLogPage{}, err
+		}
+	}
+
+	return page, nil
+}
+
+// moderateContent applies the matched automod rules to a post, or to a comment when it is given,
+// and tells whether anything was changed and whether the content was removed, which must not be published then.
+// A failed action is logged and doesn't fail the request.
+func (s *service) moderateContent(subject model.AutomodSubject, post model.Post, comment *model.Comment) (bool, bool) {
+	if s.automod == nil || subject.Author.Username == model.AutomodUsername {
+		return false, false
+	}
+
+	changed, removed := false, false
+	for _, match := range s.automod.Evaluate(subject) {
+		if match.DryRun {
+			logrus.Infof("automod dry run: rule %s matches a %s of %s in %s", match.Rule.Name, subject.Target, subject.Author.Username, subject.Community)
+			continue
+		}
+
+		if err := s.applyAutomodRule(match.Rule, post, comment); err != nil {
+			logrus.Errorln(err)
+			continue
+		}
+		changed = true
+		removed = removed || match.Rule.Action == model.AutomodActionRemove
+
+		logrus.Infof("automod rule applied: %s", match.Rule.Name)
+	}
+
+	return changed, removed
+}
+
+func (s *service) applyAutomodRule(rule model.AutomodRule, post model.Post, comment *model.Comment) error {
+	reason := rule.Reason
+	if reason == "" {
+		reason = "automod: " + rule.Name
+	}
+
+	switch rule.Action {
+	case model.AutomodActionRemove:
+		if comment != nil {
+			return s.removeComment(post, *comment, reason, model.Automoderator)
+		}
+		return s.removePost(post, reason, model.Automoderator)
+	case model.AutomodActionFlag:
+		commentID := ""
+		if comment != nil {
+			commentID = comment.ID
+		}
+		_, err := s.addReport(post, commentID, model.ReportReasonOther, reason, model.Automoderator)
+		return err
+	case model.AutomodActionLock:
+		return s.lockPost(post, reason, model.Automoderator)
+	case model.AutomodActionReply:
+		return s.addAutomodReply(post, comment, rule.Reply)
+	default:
+		return fmt.Errorf("unknown automod action: %s", rule.Action)
+	}
+}
+
+// lockPost does nothing for a post which is already locked, by another request as well
+func (s *service) lockPost(post model.Post, reason string, moderator model.User) error {
+	if post.Locked {
+		return nil
+	}
+
+	if err := s.postsRepo.LockPost(post.ID); err != nil {
+		if _, ok := err.(customerr.PostChanged); ok {
+			return nil
+		}
+		return err
+	}
+
+	s.recordModAction(model.ModActionLockPost, moderator, post.Author, func(a *model.ModAction) {
+		a.Community = post.Category
+		a.PostID = post.ID
+		a.Details = reason
+	})
+
+	logrus.Infoln("post locked")
+
+	return nil
+}
+
+// addAutomodReply answers the comment, or the post when the thread is too deep to answer the comment
+func (s *service) addAutomodReply(post model.Post, comment *model.Comment, text string) error {
+	replyID, err := hexid.Generate()
+	if err != nil {
+		return err
+	}
+
+	author := model.Author{ID: model.Automoderator.ID, Username: model.Automoderator.Username}
+	reply := model.NewComment(replyID, post.ID, text, author)
+	var parent model.Comment
+	if comment != nil && comment.Depth+1 <= model.MaxCommentDepth {
+		parent = *comment
+		reply = model.NewReply(replyID, text, author, parent)
+	}
+
+	if err = s.commentsRepo.AddComment(reply); err != nil {
+		return err
+	}
+
+	if err = s.postsRepo.UpdateCommentCount(post.ID, 1); err != nil {
+		return err
+	}
+	s.publish(model.PostTopic(post.ID), model.NewCommentEvent(model.EventCommentAdded, reply))
+	s.notifyComment(post, parent, reply)
+
+	return nil
+}
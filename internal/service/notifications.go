@@ -0,0 +1,151 @@
+package service
+
+import (
+	"github.com/sirupsen/logrus"
+	"redditclone/internal/model"
+	"redditclone/internal/model/customerr"
+	"redditclone/pkg/cursor"
+	"redditclone/pkg/hexid"
+)
+
+type notificationsRepo interface {
+	AddNotification(notification model.Notification) error
+	GetNotifications(userID string, query model.NotificationsQuery, after model.NotificationCursor) ([]model.Notification, error)
+	CountUnread(userID string) (int, error)
+	MarkRead(userID, notificationID string) (model.Notification, error)
+	MarkAllRead(userID string) error
+}
+
+// notify is best effort, a lost notification is logged and the caller goes on
+func (s *service) notify(notification model.Notification) {
+	notificationID, err := hexid.Generate()
+	if err != nil {
+		logrus.Errorln(err)
+		return
+	}
+	notification.ID = notificationID
+
+	if err = s.notificationsRepo.AddNotification(notification); err != nil {
+		logrus.Errorln(err)
+	}
+}
+
+// notifyComment tells the author of the parent comment or of the post and the mentioned users about the comment,
+// every user is told once and the commenter isn't told at all
+func (s *service) notifyComment(post model.Post, parent model.Comment, comment model.Comment) {
+	notified := map[string]struct{}{comment.Author.ID: {}}
+	notifyOnce := func(notificationType string, userID string) {
+		if _, ok := notified[userID]; ok {
+			return
+		}
+		notified[userID] = struct{}{}
+		s.notify(model.NewCommentNotification(notificationType, userID, comment))
+	}
+
+	if comment.ParentID != "" {
+		notifyOnce(model.NotificationCommentReply, parent.Author.ID)
+	} else {
+		notifyOnce(model.NotificationPostComment, post.Author.ID)
+	}
+
+	for _, username := range model.Mentions(comment.Body) {
+		usr, err := s.usersRepo.GetUserByUsername(username)
+		if err != nil {
+			if _, ok := err.(customerr.UserNotFoundByUsername); !ok {
+				logrus.Errorln(err)
+			}
+			continue
+		}
+		notifyOnce(model.NotificationMention, usr.ID)
+	}
+}
+
+// notifyMilestones tells the author about the milestones the vote has lifted the score to
+func (s *service) notifyMilestones(author model.Author, postID, commentID string, previous, current int) {
+	for _, milestone := range model.ReachedMilestones(previous, current) {
+		s.notify(model.NewMilestoneNotification(author.ID, postID, commentID, milestone))
+	}
+}
+
+// GetNotifications returns a page of the user's inbox, one extra notification is requested
+// to know whether the next page exists
+func (s *service) GetNotifications(query model.NotificationsQuery, usr model.User) (model.NotificationsPage, error) {
+	var after model.NotificationCursor
+	if query.Cursor != "" {
+		if err := cursor.Decode(query.Cursor, &after); err != nil || after.IsZero() {
+			return model.NotificationsPage{}, customerr.InvalidCursor{Cursor: query.Cursor}
+		}
+	}
+
+	if query.Limit <= 0 || query.Limit > model.MaxPageLimit {
+		query.Limit = model.DefaultPageLimit
+	}
+	limit := query.Limit
+	query.Limit++
+
+	notifications, err := s.notificationsRepo.GetNotifications(usr.ID, query, after)
+	if err != nil {
+		return model.NotificationsPage{}, err
+	}
+
+	unread, err := s.notificationsRepo.CountUnread(usr.ID)
+	if err != nil {
+		return model.NotificationsPage{}, err
+	}
+
+	s.fillCommentBodies(notifications)
+
+	page := model.NotificationsPage{Notifications: notifications, Unread: unread}
+	if len(notifications) > limit {
+		page.Notifications = notifications[:limit]
+		last := page.Notifications[limit-1]
+		page.NextCursor, err = cursor.Encode(model.NotificationCursor{Created: last.Created, ID: last.ID})
+		if err != nil {
+			return model.NotificationsPage{}, err
+		}
+	}
+
+	return page, nil
+}
+
+func (s *service) MarkNotificationRead(notificationID string, usr model.User) (model.Notification, error) {
+	notification, err := s.notificationsRepo.MarkRead(usr.ID, notificationID)
+	if err != nil {
+		return model.Notification{}, err
+	}
+
+	notifications := []model.Notification{notification}
+	s.fillCommentBodies(notifications)
+
+	return notifications[0], nil
+}
+
+// fillCommentBodies shows the current text of the comments, a deleted or removed comment shows none,
+// the text stored by older notifications is dropped as well
+func (s *service) fillCommentBodies(notifications []model.Notification) {
+	bodies := make(map[string]string)
+	for i := range notifications {
+		notification := &notifications[i]
+		notification.Body = ""
+		if notification.CommentID == "" || notification.Type == model.NotificationScoreMilestone {
+			continue
+		}
+
+		body, ok := bodies[notification.CommentID]
+		if !ok {
+			comment, err := s.getLiveComment(notification.PostID, notification.CommentID)
+			if err != nil {
+				if _, notFound := err.(customerr.CommentNotFoundByID); !notFound {
+					logrus.Errorln(err)
+				}
+			}
+			body = comment.Body
+			bodies[notification.CommentID] = body
+		}
+		notification.Body = body
+	}
+}
+
+func (s *service) MarkAllNotificationsRead(usr model.User) error {
+	return s.notificationsRepo.MarkAllRead(usr.ID)
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"regexp"
+	"strings"
+)
+
+const argon2idPrefix = "$argon2id$"
+
+var (
+	legacyMD5Pattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+	bcryptPattern    = regexp.MustCompile(`^\$2[aby]?\$`)
+
+	errUnknownPasswordHash = errors.New("unknown password hash format")
+)
+
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(password string, encoded string) (bool, error)
+	Recognizes(encoded string) bool
+	NeedsRehash(encoded string) bool
+}
+
+type argon2idHasher struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+	saltLen uint32
+	keyLen  uint32
+}
+
+func NewArgon2idHasher() *argon2idHasher {
+	return &argon2idHasher{
+		memory:  64 * 1024,
+		time:    1,
+		threads: 4,
+		saltLen: 16,
+		keyLen:  32,
+	}
+}
+
+func (h *argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.time, h.memory, h.threads, h.keyLen)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix,
+		argon2.Version,
+		h.memory,
+		h.time,
+		h.threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+type argon2idParams struct {
+	version int
+	memory  uint32
+	time    uint32
+	threads uint8
+	salt    []byte
+	key     []byte
+}
+
+func parseArgon2id(encoded string) (argon2idParams, error) {
+	// $argon2id$v=19$m=65536,t=1,p=4$salt$key
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return argon2idParams{}, errors.New("invalid argon2id hash")
+	}
+
+	var params argon2idParams
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &params.version); err != nil {
+		return argon2idParams{}, err
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads); err != nil {
+		return argon2idParams{}, err
+	}
+
+	var err error
+	if params.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return argon2idParams{}, err
+	}
+	if params.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil {
+		return argon2idParams{}, err
+	}
+
+	return params, nil
+}
+
+func (h *argon2idHasher) Verify(password string, encoded string) (bool, error) {
+	params, err := parseArgon2id(encoded)
+	if err != nil {
+		return false, err
+	}
+
+	key := argon2.IDKey([]byte(password), params.salt, params.time, params.memory, params.threads, uint32(len(params.key)))
+
+	return subtle.ConstantTimeCompare(key, params.key) == 1, nil
+}
+
+func (h *argon2idHasher) Recognizes(encoded string) bool {
+	return strings.HasPrefix(encoded, argon2idPrefix)
+}
+
+func (h *argon2idHasher) NeedsRehash(encoded string) bool {
+	if !h.Recognizes(encoded) {
+		return true
+	}
+
+	params, err := parseArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+
+	return params.version != argon2.Version ||
+		params.memory != h.memory ||
+		params.time != h.time ||
+		params.threads != h.threads ||
+		uint32(len(params.key)) != h.keyLen
+}
+
+type bcryptHasher struct {
+	cost int
+}
+
+func NewBcryptHasher(cost int) *bcryptHasher {
+	if cost < bcrypt.MinCost {
+		cost = bcrypt.DefaultCost
+	}
+	return &bcryptHasher{cost: cost}
+}
+
+func (h *bcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func (h *bcryptHasher) Verify(password string, encoded string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if err == bcrypt.ErrMismatchedHashAndPassword {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (h *bcryptHasher) Recognizes(encoded string) bool {
+	return bcryptPattern.MatchString(encoded)
+}
+
+func (h *bcryptHasher) NeedsRehash(encoded string) bool {
+	if !h.Recognizes(encoded) {
+		return true
+	}
+
+	cost, err := bcrypt.Cost([]byte(encoded))
+	if err != nil {
+		return true
+	}
+
+	return cost != h.cost
+}
+
+// md5Hasher only verifies unsalted hashes stored before the switch to slow hashes
+type md5Hasher struct{}
+
+func (h md5Hasher) Hash(password string) (string, error) {
+	hash := md5.Sum([]byte(password))
+	return hex.EncodeToString(hash[:]), nil
+}
+
+func (h md5Hasher) Verify(password string, encoded string) (bool, error) {
+	hash, _ := h.Hash(password)
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(encoded)) == 1, nil
+}
+
+func (h md5Hasher) Recognizes(encoded string) bool {
+	return legacyMD5Pattern.MatchString(encoded)
+}
+
+func (h md5Hasher) NeedsRehash(encoded string) bool {
+	return true
+}
+
+// verifyPassword checks the password against the hash regardless of the algorithm it was encoded with
+func (s *service) verifyPassword(password string, encoded string) (bool, error) {
+	verifiers := []PasswordHasher{s.hasher, NewArgon2idHasher(), NewBcryptHasher(bcrypt.DefaultCost), md5Hasher{}}
+
+	for _, verifier := range verifiers {
+		if verifier.Recognizes(encoded) {
+			return verifier.Verify(password, encoded)
+		}
+	}
+
+	return false, errUnknownPasswordHash
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"golang.org/x/crypto/bcrypt"
+	"redditclone/internal/model"
+	"redditclone/internal/model/customerr"
+	"redditclone/internal/repository/slicerepo"
+	"redditclone/pkg/broker"
+	"redditclone/pkg/lockout"
+	"strings"
+	"testing"
+)
+
+// md5 of "password", the way the passwords were stored before the slow hashes
+const legacyPasswordHash = "5f4dcc3b5aa765d61d8327deb882cf99"
+
+func TestHashers(t *testing.T) {
+	cases := []struct {
+		name   string
+		hasher PasswordHasher
+		prefix string
+	}{
+		{name: "argon2id", hasher: NewArgon2idHasher(), prefix: "$argon2id$v=19$m=65536,t=1,p=4$"},
+		{name: "bcrypt", hasher: NewBcryptHasher(bcrypt.MinCost), prefix: "$2a$04$"},
+		{name: "bcrypt below the min cost", hasher: NewBcryptHasher(0), prefix: "$2a$10$"},
+	}
+
+	for i, item := range cases {
+		encoded, err := item.hasher.Hash("password")
+		if err != nil {
+			t.Fatalf("[%d] %s: unexpected error: %s", i, item.name, err)
+		}
+		if !strings.HasPrefix(encoded, item.prefix) {
+			t.Errorf("[%d] %s: expected the prefix %s, got: %s", i, item.name, item.prefix, encoded)
+		}
+
+		again, err := item.hasher.Hash("password")
+		if err != nil {
+			t.Fatalf("[%d] %s: unexpected error: %s", i, item.name, err)
+		}
+		if again == encoded {
+			t.Errorf("[%d] %s: expected a new salt for every hash", i, item.name)
+		}
+
+		if !item.hasher.Recognizes(encoded) {
+			t.Errorf("[%d] %s: expected the hash to be recognized", i, item.name)
+		}
+		if item.hasher.Recognizes(legacyPasswordHash) {
+			t.Errorf("[%d] %s: expected the md5 hash not to be recognized", i, item.name)
+		}
+		if item.hasher.NeedsRehash(encoded) {
+			t.Errorf("[%d] %s: expected no rehash of its own hash", i, item.name)
+		}
+
+		matched, err := item.hasher.Verify("password", encoded)
+		if err != nil || !matched {
+			t.Errorf("[%d] %s: expected the password to match, got: %t, %v", i, item.name, matched, err)
+		}
+		matched, err = item.hasher.Verify("Password", encoded)
+		if err != nil || matched {
+			t.Errorf("[%d] %s: expected a wrong password not to match, got: %t, %v", i, item.name, matched, err)
+		}
+	}
+}
+
+func TestLegacyMD5Verify(t *testing.T) {
+	hasher := md5Hasher{}
+
+	cases := []struct {
+		password string
+		encoded  string
+		expected bool
+	}{
+		{password: "password", encoded: legacyPasswordHash, expected: true},
+		{password: "Password", encoded: legacyPasswordHash, expected: false},
+		{password: "password", encoded: strings.ToUpper(legacyPasswordHash), expected: false},
+	}
+
+	for i, item := range cases {
+		matched, err := hasher.Verify(item.password, item.encoded)
+		if err != nil {
+			t.Fatalf("[%d] unexpected error: %s", i, err)
+		}
+		if matched != item.expected {
+			t.Errorf("[%d] expected match %t, got %t", i, item.expected, matched)
+		}
+	}
+
+	if !hasher.Recognizes(legacyPasswordHash) || hasher.Recognizes("$2a$04$abc") {
+		t.Errorf("expected only the hex md5 hashes to be recognized")
+	}
+	if !hasher.NeedsRehash(legacyPasswordHash) {
+		t.Errorf("expected the md5 hashes to need a rehash")
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	argon2id := NewArgon2idHasher()
+	bcryptHash, err := NewBcryptHasher(bcrypt.MinCost).Hash("password")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	cases := []struct {
+		name     string
+		hasher   PasswordHasher
+		encoded  string
+		expected bool
+	}{
+		{
+			name:     "argon2id with the current params",
+			hasher:   argon2id,
+			encoded:  "$argon2id$v=19$m=65536,t=1,p=4$GHFEEoYCZc4mL1NlpYzmRQ$QAD67NMt7FMF4WLZMzcRgCxWjbjLS5B5p0qxt4vkMNo",
+			expected: false,
+		},
+		{
+			name:     "argon2id with less memory",
+			hasher:   argon2id,
+			encoded:  "$argon2id$v=19$m=32768,t=1,p=4$GHFEEoYCZc4mL1NlpYzmRQ$QAD67NMt7FMF4WLZMzcRgCxWjbjLS5B5p0qxt4vkMNo",
+			expected: true,
+		},
+		{
+			name:     "argon2id with an older version",
+			hasher:   argon2id,
+			encoded:  "$argon2id$v=16$m=65536,t=1,p=4$GHFEEoYCZc4mL1NlpYzmRQ$QAD67NMt7FMF4WLZMzcRgCxWjbjLS5B5p0qxt4vkMNo",
+			expected: true,
+		},
+		{
+			name:     "broken argon2id",
+			hasher:   argon2id,
+			encoded:  "$argon2id$v=19$broken",
+			expected: true,
+		},
+		{
+			name:     "bcrypt for argon2id",
+			hasher:   argon2id,
+			encoded:  bcryptHash,
+			expected: true,
+		},
+		{
+			name:     "md5 for argon2id",
+			hasher:   argon2id,
+			encoded:  legacyPasswordHash,
+			expected: true,
+		},
+		{
+			name:     "bcrypt with the current cost",
+			hasher:   NewBcryptHasher(bcrypt.MinCost),
+			encoded:  bcryptHash,
+			expected: false,
+		},
+		{
+			name:     "bcrypt with another cost",
+			hasher:   NewBcryptHasher(bcrypt.MinCost + 1),
+			encoded:  bcryptHash,
+			expected: true,
+		},
+	}
+
+	for i, item := range cases {
+		if needs := item.hasher.NeedsRehash(item.encoded); needs != item.expected {
+			t.Errorf("[%d] %s: expected rehash %t, got %t", i, item.name, item.expected, needs)
+		}
+	}
+}
+
+func TestVerifyPasswordFormats(t *testing.T) {
+	s := &service{hasher: NewBcryptHasher(bcrypt.MinCost)}
+	argon2idHash, err := NewArgon2idHasher().Hash("password")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for i, encoded := range []string{argon2idHash, legacyPasswordHash} {
+		matched, err := s.verifyPassword("password", encoded)
+		if err != nil || !matched {
+			t.Errorf("[%d] expected the password to match, got: %t, %v", i, matched, err)
+		}
+	}
+
+	if _, err = s.verifyPassword("password", "plain"); err != errUnknownPasswordHash {
+		t.Errorf("expected the unknown format error, got: %v", err)
+	}
+}
+
+func TestLoginRehashesLegacyPassword(t *testing.T) {
+	users := slicerepo.NewUsersRepo()
+	usr := model.User{ID: "1", Credential: model.Credential{Username: "van", Password: legacyPasswordHash}}
+	if err := users.AddUser(usr); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	s := NewService(Repositories{
+		Users:         users,
+		ModActions:    slicerepo.NewModActionsRepo(),
+		LoginAttempts: lockout.NewMemoryStore(),
+	}, NewArgon2idHasher(), broker.NewMemoryBroker(), nil)
+
+	if !strings.HasPrefix(s.dummyHash, argon2idPrefix) {
+		t.Errorf("expected the dummy hash made by the hasher, got: %s", s.dummyHash)
+	}
+
+	if _, err := s.LoginUser(model.Credential{Username: "van", Password: "Password"}, "127.0.0.1"); err == nil {
+		t.Fatalf("expected a wrong password to fail")
+	}
+	stored, err := users.GetUserByID("1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if stored.Password != legacyPasswordHash {
+		t.Errorf("expected no rehash after a failed login, got: %s", stored.Password)
+	}
+
+	logged, err := s.LoginUser(model.Credential{Username: "van", Password: "password"}, "127.0.0.1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if stored, err = users.GetUserByID("1"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.HasPrefix(stored.Password, argon2idPrefix) || logged.Password != stored.Password {
+		t.Errorf("expected the password rewritten to argon2id, got: %s", stored.Password)
+	}
+
+	if _, err = s.LoginUser(model.Credential{Username: "van", Password: "password"}, "127.0.0.1"); err != nil {
+		t.Errorf("expected the login with the new hash, got: %s", err)
+	}
+
+	_, err = s.LoginUser(model.Credential{Username: "nobody", Password: "password"}, "127.0.0.1")
+	if _, ok := err.(customerr.WrongCredential); !ok {
+		t.Errorf("expected wrong credentials for an unknown user, got: %v", err)
+	}
+}
@@ -4,28 +4,99 @@ import (
 	"github.com/sirupsen/logrus"
 	"redditclone/internal/model"
 	"redditclone/internal/model/customerr"
+	"redditclone/pkg/cursor"
 	"redditclone/pkg/hexid"
+	"time"
 )
 
 type postsRepo interface {
 	GetAllPosts() ([]model.Post, error)
 	GetPostsByCategory(category string) ([]model.Post, error)
 	GetPostsByAuthor(username string) ([]model.Post, error)
+	CountPostsByAuthor(username string) (int, error)
+	GetPostsPage(query model.PostsQuery, after model.PostCursor) ([]model.Post, error)
 	AddPost(newPost model.Post) error
 	GetPostByID(postID string) (model.Post, error)
 	GetPostByIDAndUpdateViews(postID string) (model.Post, error)
 	DeletePost(postID string) error
-	AddComment(postID string, comment model.Comment) (model.Post, error)
-	GetCommentByID(postID, commentID string) (model.Comment, error)
-	DeleteComment(postID, commentID string) (model.Post, error)
-	UpdateVotes(postID string, score int, upvotePercentage int, votes []model.Vote) error
+	RemovePost(postID string, removal *model.Removal) error
+	LockPost(postID string) error
+	EditPost(post model.Post, edited string, revision model.PostRevision) error
+	GetPostRevisions(postID string) ([]model.PostRevision, error)
+	UpdateCommentCount(postID string, delta int) error
+	UpdateVoteCounts(postID string, ups, downs int) (model.Post, error)
 }
 
 func (s *service) GetAllPosts() ([]model.Post, error) {
 	return s.postsRepo.GetAllPosts()
 }
 
+// prepareQuery defaults the sort mode to new and turns the sort window into the earliest creation time
+func prepareQuery(query model.PostsQuery) model.PostsQuery {
+	if query.Sort == "" {
+		query.Sort = model.SortNew
+	}
+
+	var window time.Duration
+	switch query.Sort {
+	case model.SortTop:
+		window = model.TopWindows[query.Window]
+	case model.SortRising:
+		window = model.RisingWindow
+	}
+	if window != 0 {
+		query.Since = time.Now().UTC().Add(-window).Format("2006-01-02T15:04:05.000Z")
+	}
+
+	return query
+}
+
+// GetPosts returns a page of posts, one extra post is requested to know whether the next page exists
+func (s *service) GetPosts(query model.PostsQuery) (model.PostsPage, error) {
+	query = prepareQuery(query)
+
+	var after model.PostCursor
+	if query.Cursor != "" {
+		if err := cursor.Decode(query.Cursor, &after); err != nil || after.Sort != query.Sort {
+			return model.PostsPage{}, customerr.InvalidCursor{Cursor: query.Cursor}
+		}
+	}
+
+	if query.Limit <= 0 || query.Limit > model.MaxPageLimit {
+		query.Limit = model.DefaultPageLimit
+	}
+	limit := query.Limit
+	query.Limit++
+
+	posts, err := s.postsRepo.GetPostsPage(query, after)
+	if err != nil {
+		return model.PostsPage{}, err
+	}
+
+	page := model.PostsPage{Posts: posts}
+	if len(posts) > limit {
+		page.Posts = posts[:limit]
+		page.NextCursor, err = cursor.Encode(model.NewPostCursor(page.Posts[limit-1], query.Sort))
+		if err != nil {
+			return model.PostsPage{}, err
+		}
+	}
+
+	return page, nil
+}
+
+// ListPosts returns the whole sorted listing for the clients which don't paginate
+func (s *service) ListPosts(query model.PostsQuery) ([]model.Post, error) {
+	query = prepareQuery(query)
+	query.Limit = 0
+	return s.postsRepo.GetPostsPage(query, model.PostCursor{})
+}
+
 func (s *service) CreateTextPost(input model.TextPostInput, usr model.User) (model.Post, error) {
+	if err := s.checkBanned(usr, input.Category); err != nil {
+		return model.Post{}, err
+	}
+
 	postID, err := hexid.Generate()
 	if err != nil {
 		return model.Post{}, err
@@ -38,10 +109,24 @@ func (s *service) CreateTextPost(input model.TextPostInput, usr model.User) (mod
 
 	logrus.Infoln("new text post created")
 
+	changed, removed := s.moderateContent(model.NewPostSubject(post, usr), post, nil)
+	if changed {
+		if post, err = s.postsRepo.GetPostByID(post.ID); err != nil {
+			return model.Post{}, err
+		}
+	}
+	if !removed {
+		s.publish(model.CommunityTopic(post.Category), model.NewPostEvent(model.EventPostCreated, post))
+	}
+
 	return post, nil
 }
 
 func (s *service) CreateURLPost(input model.URLPostInput, usr model.User) (model.Post, error) {
+	if err := s.checkBanned(usr, input.Category); err != nil {
+		return model.Post{}, err
+	}
+
 	postID, err := hexid.Generate()
 	if err != nil {
 		return model.Post{}, err
@@ -54,6 +139,16 @@ func (s *service) CreateURLPost(input model.URLPostInput, usr model.User) (model
 
 	logrus.Infoln("new url post created")
 
+	changed, removed := s.moderateContent(model.NewPostSubject(post, usr), post, nil)
+	if changed {
+		if post, err = s.postsRepo.GetPostByID(post.ID); err != nil {
+			return model.Post{}, err
+		}
+	}
+	if !removed {
+		s.publish(model.CommunityTopic(post.Category), model.NewPostEvent(model.EventPostCreated, post))
+	}
+
 	return post, nil
 }
 
@@ -66,124 +161,196 @@ func (s *service) GetPostsByAuthor(username string) ([]model.Post, error) {
 }
 
 func (s *service) GetPostByID(postID string) (model.Post, error) {
-	return s.postsRepo.GetPostByIDAndUpdateViews(postID)
-}
+	post, err := s.postsRepo.GetPostByIDAndUpdateViews(postID)
+	if err != nil {
+		return model.Post{}, err
+	}
 
-func (s *service) DeletePost(postID string, usr model.User) error {
-	s.postsMutex.Lock()
-	defer s.postsMutex.Unlock()
+	return s.withComments(post)
+}
 
+// DeletePost deletes the author's own post, a moderator removes the post of another user
+// keeping it for the modlog. The store deletes only a post which is not removed meanwhile.
+func (s *service) DeletePost(postID string, reason string, usr model.User) error {
 	post, err := s.postsRepo.GetPostByID(postID)
 	if err != nil {
 		return err
 	}
 
-	if post.Author.ID != usr.ID {
+	if post.Author.ID != usr.ID && !usr.IsModerator() {
 		return customerr.NotOwner{Username: usr.Username}
 	}
 
+	if post.Author.ID != usr.ID {
+		return s.removePost(post, reason, usr)
+	}
+
+	if post.Removed != nil {
+		return customerr.PostNotFoundByID{PostID: postID}
+	}
+
 	if err = s.postsRepo.DeletePost(postID); err != nil {
 		return err
 	}
 
+	if err = s.commentsRepo.DeletePostComments(postID); err != nil {
+		return err
+	}
+
+	if err = s.votesRepo.DeletePostVotes(postID); err != nil {
+		return err
+	}
+	s.publish(model.PostTopic(postID), model.NewDeletionEvent(model.EventPostDeleted, postID, ""))
+
 	logrus.Infoln("post deleted")
 
 	return nil
 }
 
-func (s *service) AddComment(postID string, commentText string, usr model.User) (model.Post, error) {
-	commentID, err := hexid.Generate()
+// getLivePost hides a removed post from the votes and the new comments
+func (s *service) getLivePost(postID string) (model.Post, error) {
+	post, err := s.postsRepo.GetPostByID(postID)
 	if err != nil {
 		return model.Post{}, err
 	}
+	if post.Removed != nil {
+		return model.Post{}, customerr.PostNotFoundByID{PostID: postID}
+	}
+	return post, nil
+}
 
-	comment := model.NewComment(commentID, commentText, model.Author{ID: usr.ID, Username: usr.Username})
-	post, err := s.postsRepo.AddComment(postID, comment)
-	if err != nil {
-		return model.Post{}, err
+// removePost does nothing for a post which is already removed, by another request as well
+func (s *service) removePost(post model.Post, reason string, moderator model.User) error {
+	if post.Removed != nil {
+		return nil
 	}
 
-	logrus.Infoln("comment added")
+	removal := model.NewRemoval(model.Author{ID: moderator.ID, Username: moderator.Username}, reason)
+	if err := s.postsRepo.RemovePost(post.ID, removal); err != nil {
+		if _, ok := err.(customerr.PostChanged); ok {
+			return nil
+		}
+		return err
+	}
+	s.publish(model.PostTopic(post.ID), model.NewDeletionEvent(model.EventPostDeleted, post.ID, ""))
 
-	return post, nil
-}
+	s.recordModAction(model.ModActionRemovePost, moderator, post.Author, func(a *model.ModAction) {
+		a.Community = post.Category
+		a.PostID = post.ID
+		a.Details = reason
+	})
+
+	logrus.Infoln("post removed")
 
-func (s *service) DeleteComment(postID, commentID string, usr model.User) (model.Post, error) {
-	s.postsMutex.Lock()
-	defer s.postsMutex.Unlock()
+	return nil
+}
 
-	comment, err := s.postsRepo.GetCommentByID(postID, commentID)
+// UpdatePost lets the author edit a text post, the replaced version is kept as a revision.
+// The store writes the edit only over the version it was made from, a concurrent change gives PostChanged.
+func (s *service) UpdatePost(postID string, input model.PostUpdateInput, usr model.User) (model.Post, error) {
+	post, err := s.postsRepo.GetPostByID(postID)
 	if err != nil {
 		return model.Post{}, err
 	}
 
-	if comment.Author.ID != usr.ID {
+	if post.Author.ID != usr.ID {
 		return model.Post{}, customerr.NotOwner{Username: usr.Username}
 	}
 
-	post, err := s.postsRepo.DeleteComment(postID, commentID)
-	if err != nil {
+	if post.Type != "text" || post.Removed != nil {
+		return model.Post{}, customerr.PostNotEditable{PostID: postID}
+	}
+
+	edited := post.Edited
+	revision := post.Edit(input)
+	if err = s.postsRepo.EditPost(post, edited, revision); err != nil {
 		return model.Post{}, err
 	}
 
-	logrus.Infoln("comment deleted")
+	logrus.Infoln("post edited")
 
-	return post, nil
-}
+	subject := model.NewPostSubject(post, usr)
+	subject.Edited = true
+	changed, removed := s.moderateContent(subject, post, nil)
+	if changed {
+		if post, err = s.postsRepo.GetPostByID(postID); err != nil {
+			return model.Post{}, err
+		}
+	}
+	if !removed {
+		s.publishPostEvent(model.EventPostEdited, post)
+	}
 
-func (s *service) UpvotePost(postID string, usr model.User) (model.Post, error) {
-	s.postsMutex.Lock()
-	defer s.postsMutex.Unlock()
+	return s.showPost(post, usr)
+}
 
+// GetPostRevisions keeps the revisions of a removed post hidden along with its content
+func (s *service) GetPostRevisions(postID string) ([]model.PostRevision, error) {
 	post, err := s.postsRepo.GetPostByID(postID)
 	if err != nil {
-		return model.Post{}, err
+		return nil, err
+	}
+	if post.Removed != nil {
+		return make([]model.PostRevision, 0), nil
 	}
 
-	post.Upvote(usr.ID).RecalculatePercentage()
+	return s.postsRepo.GetPostRevisions(postID)
+}
 
-	if err = s.postsRepo.UpdateVotes(postID, post.Score, post.UpvotePercentage, post.Votes); err != nil {
-		logrus.Errorln(err)
+// votePost leaves the counts to the store, which applies them and recalculates the score and the ranks atomically
+func (s *service) votePost(postID string, usr model.User, value int) (model.Post, error) {
+	post, err := s.getLivePost(postID)
+	if err != nil {
 		return model.Post{}, err
 	}
 
-	logrus.Infoln("post upvoted")
-
-	return post, nil
-}
+	if err = s.checkBanned(usr, post.Category); err != nil {
+		return model.Post{}, err
+	}
 
-func (s *service) DownvotePost(postID string, usr model.User) (model.Post, error) {
-	s.postsMutex.Lock()
-	defer s.postsMutex.Unlock()
+	ups, downs, err := s.vote(usr, postID, "", value)
+	if err != nil {
+		return model.Post{}, err
+	}
 
-	post, err := s.postsRepo.GetPostByID(postID)
+	post, err = s.postsRepo.UpdateVoteCounts(postID, ups, downs)
 	if err != nil {
 		return model.Post{}, err
 	}
 
-	post.Downvote(usr.ID).RecalculatePercentage()
+	s.addKarma(post.Author, model.Karma{Post: ups - downs})
+	s.publishPostEvent(model.EventPostVoted, post)
+	s.notifyMilestones(post.Author, postID, "", post.Score-(ups-downs), post.Score)
+
+	return s.showPost(post, usr)
+}
 
-	if err = s.postsRepo.UpdateVotes(postID, post.Score, post.UpvotePercentage, post.Votes); err != nil {
+func (s *service) UpvotePost(postID string, usr model.User) (model.Post, error) {
+	post, err := s.votePost(postID, usr, model.VoteUp)
+	if err != nil {
+		logrus.Errorln(err)
 		return model.Post{}, err
 	}
 
-	logrus.Infoln("post downvoted")
+	logrus.Infoln("post upvoted")
 
 	return post, nil
 }
 
-func (s *service) UnvotePost(postID string, usr model.User) (model.Post, error) {
-	s.postsMutex.Lock()
-	defer s.postsMutex.Unlock()
-
-	post, err := s.postsRepo.GetPostByID(postID)
+func (s *service) DownvotePost(postID string, usr model.User) (model.Post, error) {
+	post, err := s.votePost(postID, usr, model.VoteDown)
 	if err != nil {
 		return model.Post{}, err
 	}
 
-	post.Unvote(usr.ID).RecalculatePercentage()
+	logrus.Infoln("post downvoted")
+
+	return post, nil
+}
 
-	if err = s.postsRepo.UpdateVotes(postID, post.Score, post.UpvotePercentage, post.Votes); err != nil {
+func (s *service) UnvotePost(postID string, usr model.User) (model.Post, error) {
+	post, err := s.votePost(postID, usr, model.VoteNone)
+	if err != nil {
 		return model.Post{}, err
 	}
 
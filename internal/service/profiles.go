@@ -0,0 +1,72 @@
+package service
+
+import (
+	"github.com/sirupsen/logrus"
+	"redditclone/internal/model"
+	"sort"
+)
+
+// GetProfile collects the public profile, the karma is kept up to date by the votes.
+// The removed posts and comments stay in the counts and in the activity, redacted.
+func (s *service) GetProfile(username string) (model.Profile, error) {
+	usr, err := s.usersRepo.GetUserByUsername(username)
+	if err != nil {
+		return model.Profile{}, err
+	}
+
+	postCount, err := s.postsRepo.CountPostsByAuthor(username)
+	if err != nil {
+		return model.Profile{}, err
+	}
+
+	commentCount, err := s.commentsRepo.CountCommentsByAuthor(username)
+	if err != nil {
+		return model.Profile{}, err
+	}
+
+	query := model.PostsQuery{Author: username, Sort: model.SortNew, Limit: model.RecentActivityLimit}
+	posts, err := s.postsRepo.GetPostsPage(query, model.PostCursor{})
+	if err != nil {
+		return model.Profile{}, err
+	}
+
+	comments, err := s.commentsRepo.GetCommentsByAuthor(username, model.RecentActivityLimit)
+	if err != nil {
+		return model.Profile{}, err
+	}
+
+	activity := make([]model.Activity, 0, len(posts)+len(comments))
+	for _, post := range posts {
+		activity = append(activity, model.NewPostActivity(post.Redacted()))
+	}
+	for _, comment := range comments {
+		activity = append(activity, model.NewCommentActivity(comment.Redacted()))
+	}
+	sort.SliceStable(activity, func(i, j int) bool {
+		return activity[i].Created > activity[j].Created
+	})
+	if len(activity) > model.RecentActivityLimit {
+		activity = activity[:model.RecentActivityLimit]
+	}
+
+	return model.Profile{
+		ID:             usr.ID,
+		Username:       usr.Username,
+		Created:        usr.Created,
+		Karma:          usr.Karma,
+		PostCount:      postCount,
+		CommentCount:   commentCount,
+		RecentActivity: activity,
+	}, nil
+}
+
+// addKarma adds the change of the vote to the author's karma, the vote stands when the update fails
+func (s *service) addKarma(author model.Author, karma model.Karma) {
+	if karma == (model.Karma{}) {
+		return
+	}
+
+	if err := s.usersRepo.UpdateKarma(author.ID, karma); err != nil {
+		logrus.Errorln(err)
+	}
+}
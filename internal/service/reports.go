@@ -0,0 +1,220 @@
+package service
+
+import (
+	"github.com/sirupsen/logrus"
+	"redditclone/internal/model"
+	"redditclone/internal/model/customerr"
+	"redditclone/pkg/hexid"
+	"time"
+)
+
+type reportsRepo interface {
+	AddReport(report model.Report) (model.Report, error)
+	GetReportQueue(query model.ReportsQuery) ([]model.ReportedItem, error)
+	CountOpenReports(postID, commentID string) (int, error)
+	ResolveReports(postID, commentID string, status string, moderator model.Author, resolved string) (int, error)
+}
+
+// addReport collapses a repeated report of the user into the open one
+func (s *service) addReport(post model.Post, commentID string, reason string, text string, usr model.User) (model.Report, error) {
+	reportID, err := hexid.Generate()
+	if err != nil {
+		return model.Report{}, err
+	}
+
+	report := model.NewReport(reportID, post, commentID, model.Author{ID: usr.ID, Username: usr.Username}, reason, text)
+	stored, err := s.reportsRepo.AddReport(report)
+	if err != nil {
+		return model.Report{}, err
+	}
+
+	if stored.ID == report.ID {
+		logrus.Infoln("report added")
+		s.moderateReported(post, commentID, usr)
+	}
+
+	return stored, nil
+}
+
+// moderateReported runs the automod rules with a report count, the reports of the automod itself don't count
+func (s *service) moderateReported(post model.Post, commentID string, reporter model.User) {
+	if s.automod == nil || reporter.Username == model.AutomodUsername {
+		return
+	}
+
+	count, err := s.reportsRepo.CountOpenReports(post.ID, commentID)
+	if err != nil {
+		logrus.Errorln(err)
+		return
+	}
+
+	authorID := post.Author.ID
+	var comment *model.Comment
+	if commentID != "" {
+		reported, err := s.getLiveComment(post.ID, commentID)
+		if err != nil {
+			logrus.Errorln(err)
+			return
+		}
+		comment = &reported
+		authorID = reported.Author.ID
+	}
+
+	author, err := s.usersRepo.GetUserByID(authorID)
+	if err != nil {
+		logrus.Errorln(err)
+		return
+	}
+
+	subject := model.NewPostSubject(post, author)
+	if comment != nil {
+		subject = model.NewCommentSubject(post, *comment, author)
+	}
+	subject.Reports = count
+
+	s.moderateContent(subject, post, comment)
+}
+
+func (s *service) ReportPost(postID string, reason string, text string, usr model.User) (model.Report, error) {
+	post, err := s.postsRepo.GetPostByID(postID)
+	if err != nil {
+		return model.Report{}, err
+	}
+
+	return s.addReport(post, "", reason, text, usr)
+}
+
+func (s *service) ReportComment(postID, commentID string, reason string, text string, usr model.User) (model.Report, error) {
+	post, err := s.postsRepo.GetPostByID(postID)
+	if err != nil {
+		return model.Report{}, err
+	}
+
+	if _, err = s.getLiveComment(postID, commentID); err != nil {
+		return model.Report{}, err
+	}
+
+	return s.addReport(post, commentID, reason, text, usr)
+}
+
+// GetReportQueue returns the reported items from the most reported one,
+// an item whose content is already gone is listed without it
+func (s *service) GetReportQueue(query model.ReportsQuery) ([]model.ReportedItem, error) {
+	if query.Limit <= 0 {
+		query.Limit = model.DefaultPageLimit
+	}
+
+	items, err := s.reportsRepo.GetReportQueue(query)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, item := range items {
+		post, err := s.postsRepo.GetPostByID(item.PostID)
+		if _, ok := err.(customerr.PostNotFoundByID); ok {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if item.CommentID == "" {
+			items[i].Post = &post
+			continue
+		}
+
+		comment, err := s.getLiveComment(item.PostID, item.CommentID)
+		if _, ok := err.(customerr.CommentNotFoundByID); ok {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		items[i].Comment = &comment
+	}
+
+	return items, nil
+}
+
+// describeReported returns the author and the community of the item for the modlog,
+// the content can be gone already
+func (s *service) describeReported(postID, commentID string) (model.Author, string) {
+	post, err := s.postsRepo.GetPostByID(postID)
+	if err != nil {
+		return model.Author{}, ""
+	}
+	if commentID == "" {
+		return post.Author, post.Category
+	}
+
+	comment, err := s.commentsRepo.GetCommentByID(postID, commentID)
+	if err != nil {
+		return model.Author{}, post.Category
+	}
+	return comment.Author, post.Category
+}
+
+// removeReported removes the reported post or comment even when the moderator wrote it, a removal keeps the content for the modlog
+func (s *service) removeReported(postID, commentID string, reason string, moderator model.User) error {
+	post, err := s.postsRepo.GetPostByID(postID)
+	if err != nil {
+		return err
+	}
+	if commentID == "" {
+		return s.removePost(post, reason, moderator)
+	}
+
+	comment, err := s.commentsRepo.GetCommentByID(postID, commentID)
+	if err != nil {
+		return err
+	}
+	return s.removeComment(post, comment, reason, moderator)
+}
+
+// ResolveReports closes the open reports of a post or a comment, removing the content first when asked to
+func (s *service) ResolveReports(postID, commentID string, action string, reason string, moderator model.User) (model.ReportResolution, error) {
+	count, err := s.reportsRepo.CountOpenReports(postID, commentID)
+	if err != nil {
+		return model.ReportResolution{}, err
+	}
+	if count == 0 {
+		return model.ReportResolution{}, customerr.ReportsNotFound{PostID: postID, CommentID: commentID}
+	}
+
+	status := model.ReportResolutions[action]
+	if status == model.ReportStatusRemoved {
+		if err = s.removeReported(postID, commentID, reason, moderator); err != nil {
+			return model.ReportResolution{}, err
+		}
+	}
+
+	resolution := model.ReportResolution{
+		PostID:    postID,
+		CommentID: commentID,
+		Status:    status,
+		Moderator: model.Author{ID: moderator.ID, Username: moderator.Username},
+		Created:   time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
+	}
+	resolution.Resolved, err = s.reportsRepo.ResolveReports(postID, commentID, status, resolution.Moderator, resolution.Created)
+	if err != nil {
+		return model.ReportResolution{}, err
+	}
+
+	modAction := model.ModActionApproveReport
+	if status == model.ReportStatusDismissed {
+		modAction = model.ModActionDismissReport
+	}
+	if status != model.ReportStatusRemoved {
+		author, community := s.describeReported(postID, commentID)
+		s.recordModAction(modAction, moderator, author, func(a *model.ModAction) {
+			a.Community = community
+			a.PostID = postID
+			a.CommentID = commentID
+			a.Details = reason
+		})
+	}
+
+	logrus.Infof("reports resolved: %d %s", resolution.Resolved, status)
+
+	return resolution, nil
+}
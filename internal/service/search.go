@@ -0,0 +1,73 @@
+package service
+
+import (
+	"redditclone/internal/model"
+	"redditclone/internal/model/customerr"
+	"redditclone/pkg/cursor"
+)
+
+type searchRepo interface {
+	SearchPosts(query model.SearchQuery, skip int) ([]model.SearchResult, error)
+	SearchComments(query model.SearchQuery, skip int) ([]model.SearchResult, error)
+}
+
+// Search merges the posts and the comments found, one extra result of each kind is requested
+// to know whether the next page exists
+func (s *service) Search(query model.SearchQuery) (model.SearchPage, error) {
+	if query.Sort == "" {
+		query.Sort = model.SearchSortRelevance
+	}
+
+	after := model.SearchCursor{Sort: query.Sort}
+	if query.Cursor != "" {
+		if err := cursor.Decode(query.Cursor, &after); err != nil || after.Sort != query.Sort {
+			return model.SearchPage{}, customerr.InvalidCursor{Cursor: query.Cursor}
+		}
+	}
+
+	if query.Limit <= 0 || query.Limit > model.MaxPageLimit {
+		query.Limit = model.DefaultPageLimit
+	}
+	limit := query.Limit
+	query.Limit++
+
+	posts, err := s.searchRepo.SearchPosts(query, after.Posts)
+	if err != nil {
+		return model.SearchPage{}, err
+	}
+
+	comments, err := s.searchRepo.SearchComments(query, after.Comments)
+	if err != nil {
+		return model.SearchPage{}, err
+	}
+
+	results := make([]model.SearchResult, 0, len(posts)+len(comments))
+	for len(posts) != 0 || len(comments) != 0 {
+		if len(comments) == 0 || len(posts) != 0 && model.SearchPrecedes(posts[0], comments[0], query.Sort) {
+			results = append(results, posts[0])
+			posts = posts[1:]
+		} else {
+			results = append(results, comments[0])
+			comments = comments[1:]
+		}
+	}
+
+	page := model.SearchPage{Results: results}
+	if len(results) > limit {
+		page.Results = results[:limit]
+		next := after
+		for _, result := range page.Results {
+			if result.Type == model.SearchResultPost {
+				next.Posts++
+			} else {
+				next.Comments++
+			}
+		}
+		page.NextCursor, err = cursor.Encode(next)
+		if err != nil {
+			return model.SearchPage{}, err
+		}
+	}
+
+	return page, nil
+}
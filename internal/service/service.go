@@ -1,16 +1,68 @@
 package service
 
-import "sync"
+import "github.com/sirupsen/logrus"
+
+type Repositories struct {
+	Users         usersRepo
+	Posts         postsRepo
+	Comments      commentsRepo
+	Votes         votesRepo
+	Search        searchRepo
+	Notifications notificationsRepo
+	Messages      messagesRepo
+	MessageRates  messageRatesRepo
+	Reports       reportsRepo
+	Communities   communitiesRepo
+	ModActions    modActionsRepo
+	Bans          bansRepo
+	LoginAttempts loginAttemptsRepo
+}
 
 type service struct {
-	usersRepo  usersRepo
-	postsMutex sync.Mutex
-	postsRepo  postsRepo
+	usersRepo         usersRepo
+	postsRepo         postsRepo
+	commentsRepo      commentsRepo
+	votesRepo         votesRepo
+	searchRepo        searchRepo
+	notificationsRepo notificationsRepo
+	messagesRepo      messagesRepo
+	messageRatesRepo  messageRatesRepo
+	reportsRepo       reportsRepo
+	communitiesRepo   communitiesRepo
+	modActionsRepo    modActionsRepo
+	bansRepo          bansRepo
+	loginAttemptsRepo loginAttemptsRepo
+	hasher            PasswordHasher
+	// dummyHash is verified for the unknown usernames, the login takes as long as for a wrong password
+	dummyHash string
+	broker    EventBroker
+	automod   Automoderator
 }
 
-func NewService(usersRepo usersRepo, postsRepo postsRepo) *service {
+// NewService takes a nil automod when no rules are set
+func NewService(repos Repositories, hasher PasswordHasher, broker EventBroker, automod Automoderator) *service {
+	dummyHash, err := hasher.Hash("dummy password")
+	if err != nil {
+		logrus.Errorln(err)
+	}
+
 	return &service{
-		usersRepo: usersRepo,
-		postsRepo: postsRepo,
+		usersRepo:         repos.Users,
+		postsRepo:         repos.Posts,
+		commentsRepo:      repos.Comments,
+		votesRepo:         repos.Votes,
+		searchRepo:        repos.Search,
+		notificationsRepo: repos.Notifications,
+		messagesRepo:      repos.Messages,
+		messageRatesRepo:  repos.MessageRates,
+		reportsRepo:       repos.Reports,
+		communitiesRepo:   repos.Communities,
+		modActionsRepo:    repos.ModActions,
+		bansRepo:          repos.Bans,
+		loginAttemptsRepo: repos.LoginAttempts,
+		hasher:            hasher,
+		dummyHash:         dummyHash,
+		broker:            broker,
+		automod:           automod,
 	}
 }
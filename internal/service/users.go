@@ -1,23 +1,21 @@
 package service
 
 import (
-	"crypto/md5"
-	"encoding/hex"
 	"github.com/sirupsen/logrus"
 	"redditclone/internal/model"
 	"redditclone/internal/model/customerr"
 	"redditclone/pkg/hexid"
+	"time"
 )
 
 type usersRepo interface {
 	AddUser(user model.User) error
-	GetUser(cred model.Credential) (model.User, error)
+	GetUserByUsername(username string) (model.User, error)
 	GetUserByID(userID string) (model.User, error)
-}
-
-func hashPassword(password string) string {
-	hash := md5.Sum([]byte(password))
-	return hex.EncodeToString(hash[:])
+	UpdatePassword(userID string, password string) error
+	UpdateRole(userID string, role string) error
+	UpdateSuspension(userID string, suspension *model.Suspension) error
+	UpdateKarma(userID string, karma model.Karma) error
 }
 
 func (s *service) RegisterUser(cred model.Credential) (model.User, error) {
@@ -26,8 +24,16 @@ func (s *service) RegisterUser(cred model.Credential) (model.User, error) {
 		return model.User{}, err
 	}
 
-	usr := model.User{ID: id, Credential: cred}
-	usr.Password = hashPassword(usr.Password)
+	usr := model.User{
+		ID:         id,
+		Credential: cred,
+		Role:       model.RoleUser,
+		Created:    time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
+	}
+	usr.Password, err = s.hasher.Hash(usr.Password)
+	if err != nil {
+		return model.User{}, err
+	}
 
 	err = s.usersRepo.AddUser(usr)
 	if err == nil {
@@ -37,18 +43,61 @@ func (s *service) RegisterUser(cred model.Credential) (model.User, error) {
 	return usr, err
 }
 
-func (s *service) LoginUser(cred model.Credential) (model.User, error) {
-	cred.Password = hashPassword(cred.Password)
+// LoginUser counts the failures of unknown usernames as well, so a lockout doesn't tell which users exist
+func (s *service) LoginUser(cred model.Credential, ip string) (model.User, error) {
+	if err := s.checkLoginLocked(cred.Username, ip); err != nil {
+		return model.User{}, err
+	}
 
-	usr, err := s.usersRepo.GetUser(cred)
-	if _, ok := err.(customerr.WrongCredential); ok {
+	usr, err := s.usersRepo.GetUserByUsername(cred.Username)
+	if _, ok := err.(customerr.UserNotFoundByUsername); ok {
+		if _, err = s.hasher.Verify(cred.Password, s.dummyHash); err != nil {
+			logrus.Errorln(err)
+		}
+		s.loginFailed(cred.Username, model.Author{Username: cred.Username}, ip)
 		return model.User{}, customerr.WrongCredential{Username: cred.Username}
 	}
-	if err == nil {
-		logrus.Infof("user logged: %s", cred.Username)
+	if err != nil {
+		return model.User{}, err
 	}
 
-	return usr, err
+	matched, err := s.verifyPassword(cred.Password, usr.Password)
+	if err != nil {
+		return model.User{}, err
+	}
+	if !matched {
+		s.loginFailed(cred.Username, model.Author{ID: usr.ID, Username: usr.Username}, ip)
+		return model.User{}, customerr.WrongCredential{Username: cred.Username}
+	}
+
+	if err = s.loginAttemptsRepo.Reset(userLoginKey(cred.Username)); err != nil {
+		logrus.Errorln(err)
+	}
+
+	if s.hasher.NeedsRehash(usr.Password) {
+		s.rehashPassword(&usr, cred.Password)
+	}
+
+	logrus.Infof("user logged: %s", cred.Username)
+
+	return usr, nil
+}
+
+// rehashPassword upgrades a hash made by an outdated algorithm, a failure must not break the login
+func (s *service) rehashPassword(usr *model.User, password string) {
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		logrus.Errorln(err)
+		return
+	}
+
+	if err = s.usersRepo.UpdatePassword(usr.ID, hash); err != nil {
+		logrus.Errorln(err)
+		return
+	}
+
+	usr.Password = hash
+	logrus.Infof("password rehashed: %s", usr.Username)
 }
 
 func (s *service) GetUserByID(userID string) (model.User, error) {
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"redditclone/internal/model"
+	"time"
+)
+
+type votesRepo interface {
+	SetVote(vote model.Vote) (int, error)
+	GetUserVotes(userID string, postIDs []string) ([]model.Vote, error)
+	GetVotesByUser(userID string) ([]model.Vote, error)
+	DeletePostVotes(postID string) error
+	DeleteCommentVotes(postID, commentID string) error
+}
+
+// vote replaces the vote of the user and returns the change of the ups and the downs it makes,
+// the counts are applied by the stores atomically, so no lock is needed
+func (s *service) vote(usr model.User, postID, commentID string, value int) (int, int, error) {
+	previous, err := s.votesRepo.SetVote(model.Vote{
+		UserID:    usr.ID,
+		PostID:    postID,
+		CommentID: commentID,
+		Vote:      value,
+		Created:   time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	ups, downs := model.VoteDelta(previous, value)
+	return ups, downs, nil
+}
+
+type voteTarget struct {
+	postID    string
+	commentID string
+}
+
+// FillUserVotes shows the user their own votes on the posts and on the comments of the posts
+func (s *service) FillUserVotes(posts []model.Post, usr model.User) ([]model.Post, error) {
+	postIDs := make([]string, 0, len(posts))
+	for _, post := range posts {
+		postIDs = append(postIDs, post.ID)
+	}
+
+	votes, err := s.votesRepo.GetUserVotes(usr.ID, postIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	byTarget := make(map[voteTarget]model.Vote, len(votes))
+	for _, vote := range votes {
+		byTarget[voteTarget{postID: vote.PostID, commentID: vote.CommentID}] = vote
+	}
+
+	for i := range posts {
+		posts[i].SetUserVote(byTarget[voteTarget{postID: posts[i].ID}])
+		for j := range posts[i].Comments {
+			comment := &posts[i].Comments[j]
+			comment.SetUserVote(byTarget[voteTarget{postID: comment.PostID, commentID: comment.ID}])
+		}
+	}
+
+	return posts, nil
+}
+
+// showPost prepares a single post for the user acting on it, with the comments and the user's votes,
+// the removed content is hidden unless the user is a moderator
+func (s *service) showPost(post model.Post, usr model.User) (model.Post, error) {
+	post, err := s.withComments(post)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	posts, err := s.FillUserVotes([]model.Post{post}, usr)
+	if err != nil {
+		return model.Post{}, err
+	}
+
+	if !usr.IsModerator() {
+		return posts[0].Redacted(), nil
+	}
+	return posts[0], nil
+}
+
+func (s *service) GetVotesByUser(usr model.User) ([]model.Vote, error) {
+	return s.votesRepo.GetVotesByUser(usr.ID)
+}
@@ -0,0 +1,2 @@
+DROP TABLE mod_action;
+ALTER TABLE user DROP COLUMN role;
\ No newline at end of file
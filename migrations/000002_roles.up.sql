@@ -0,0 +1,18 @@
+ALTER TABLE user ADD COLUMN role VARCHAR(16) NOT NULL DEFAULT 'user';
+
+CREATE TABLE mod_action (
+    id VARCHAR(24) PRIMARY KEY,
+    moderator_id VARCHAR(24) NOT NULL,
+    moderator_username VARCHAR(255) NOT NULL,
+    action VARCHAR(32) NOT NULL,
+    target_user_id VARCHAR(24) NOT NULL,
+    target_username VARCHAR(255) NOT NULL,
+    post_id VARCHAR(24) NOT NULL DEFAULT '',
+    comment_id VARCHAR(24) NOT NULL DEFAULT '',
+    details VARCHAR(255) NOT NULL DEFAULT '',
+    created VARCHAR(24) NOT NULL,
+    INDEX (created)
+);
+
+-- the first admin has to be promoted by hand:
+-- UPDATE user SET role = 'admin' WHERE username = '...';
\ No newline at end of file
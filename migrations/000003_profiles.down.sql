@@ -0,0 +1,4 @@
+ALTER TABLE user
+    DROP COLUMN created,
+    DROP COLUMN post_karma,
+    DROP COLUMN comment_karma;
\ No newline at end of file
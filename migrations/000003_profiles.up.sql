@@ -0,0 +1,4 @@
+ALTER TABLE user
+    ADD COLUMN created VARCHAR(24) NOT NULL DEFAULT '',
+    ADD COLUMN post_karma INT NOT NULL DEFAULT 0,
+    ADD COLUMN comment_karma INT NOT NULL DEFAULT 0;
\ No newline at end of file
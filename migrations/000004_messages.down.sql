@@ -0,0 +1,3 @@
+DROP TABLE user_block;
+DROP TABLE conversation;
+DROP TABLE message;
\ No newline at end of file
@@ -0,0 +1,32 @@
+CREATE TABLE message (
+    id VARCHAR(24) PRIMARY KEY,
+    sender_id VARCHAR(24) NOT NULL,
+    sender_username VARCHAR(255) NOT NULL,
+    recipient_id VARCHAR(24) NOT NULL,
+    recipient_username VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    is_read BOOLEAN NOT NULL DEFAULT FALSE,
+    created VARCHAR(24) NOT NULL,
+    INDEX (sender_id, recipient_id, created),
+    INDEX (recipient_id, sender_id, is_read),
+    INDEX (sender_id, created)
+);
+
+-- a row per side of a conversation, kept by the repository along with the messages
+CREATE TABLE conversation (
+    user_id VARCHAR(24) NOT NULL,
+    other_id VARCHAR(24) NOT NULL,
+    last_message_id VARCHAR(24) NOT NULL,
+    last_created VARCHAR(24) NOT NULL,
+    unread INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, other_id),
+    INDEX (user_id, last_created)
+);
+
+CREATE TABLE user_block (
+    user_id VARCHAR(24) NOT NULL,
+    blocked_id VARCHAR(24) NOT NULL,
+    blocked_username VARCHAR(255) NOT NULL,
+    created VARCHAR(24) NOT NULL,
+    PRIMARY KEY (user_id, blocked_id)
+);
\ No newline at end of file
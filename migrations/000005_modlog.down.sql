@@ -0,0 +1,8 @@
+DROP TRIGGER mod_action_no_delete;
+DROP TRIGGER mod_action_no_update;
+
+ALTER TABLE mod_action
+    DROP INDEX mod_action_action,
+    DROP INDEX mod_action_moderator,
+    DROP INDEX mod_action_community,
+    DROP COLUMN community;
\ No newline at end of file
@@ -0,0 +1,12 @@
+ALTER TABLE mod_action
+    ADD COLUMN community VARCHAR(21) NOT NULL DEFAULT '',
+    ADD INDEX mod_action_community (community, created),
+    ADD INDEX mod_action_moderator (moderator_username, created),
+    ADD INDEX mod_action_action (action, created);
+
+-- the modlog is append-only
+CREATE TRIGGER mod_action_no_update BEFORE UPDATE ON mod_action
+    FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'mod_action is append-only';
+
+CREATE TRIGGER mod_action_no_delete BEFORE DELETE ON mod_action
+    FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'mod_action is append-only';
\ No newline at end of file
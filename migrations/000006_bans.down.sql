@@ -0,0 +1,6 @@
+DROP TABLE community_ban;
+
+ALTER TABLE user
+    DROP COLUMN suspension_reason,
+    DROP COLUMN suspension_expires,
+    DROP COLUMN suspension_created;
\ No newline at end of file
@@ -0,0 +1,18 @@
+ALTER TABLE user
+    ADD COLUMN suspension_reason VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN suspension_expires VARCHAR(24) NOT NULL DEFAULT '',
+    ADD COLUMN suspension_created VARCHAR(24) NOT NULL DEFAULT '';
+
+-- an empty expires makes the ban permanent, a user has a single ban per community
+CREATE TABLE community_ban (
+    id VARCHAR(24) PRIMARY KEY,
+    community VARCHAR(255) NOT NULL,
+    user_id VARCHAR(24) NOT NULL,
+    username VARCHAR(255) NOT NULL,
+    moderator_id VARCHAR(24) NOT NULL,
+    moderator_username VARCHAR(255) NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    expires VARCHAR(24) NOT NULL DEFAULT '',
+    created VARCHAR(24) NOT NULL,
+    UNIQUE KEY community_ban_user (community, user_id)
+);
\ No newline at end of file
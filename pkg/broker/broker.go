@@ -0,0 +1,74 @@
+package broker
+
+import "sync"
+
+// subscriptionBuffer is how many messages wait for a slow subscriber before the next ones are dropped
+const subscriptionBuffer = 64
+
+// Subscription receives the messages of one topic until it is closed
+type Subscription struct {
+	messages chan []byte
+	once     sync.Once
+	close    func()
+}
+
+func (s *Subscription) Messages() <-chan []byte {
+	return s.messages
+}
+
+// Close stops the delivery, the messages already received can still be read
+func (s *Subscription) Close() {
+	s.once.Do(s.close)
+}
+
+// memoryBroker fans the messages out to the subscribers of the process,
+// it serves single-node setups and delivers the messages received by the redis broker
+type memoryBroker struct {
+	mutex       sync.RWMutex
+	subscribers map[string]map[*Subscription]struct{}
+}
+
+func NewMemoryBroker() *memoryBroker {
+	return &memoryBroker{
+		subscribers: make(map[string]map[*Subscription]struct{}),
+	}
+}
+
+// Publish never blocks, a subscriber with the full buffer misses the message
+func (b *memoryBroker) Publish(topic string, payload []byte) error {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	for sub := range b.subscribers[topic] {
+		select {
+		case sub.messages <- payload:
+		default:
+		}
+	}
+
+	return nil
+}
+
+func (b *memoryBroker) Subscribe(topic string) (*Subscription, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	sub := &Subscription{messages: make(chan []byte, subscriptionBuffer)}
+	sub.close = func() {
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+
+		delete(b.subscribers[topic], sub)
+		if len(b.subscribers[topic]) == 0 {
+			delete(b.subscribers, topic)
+		}
+		close(sub.messages)
+	}
+
+	if _, ok := b.subscribers[topic]; !ok {
+		b.subscribers[topic] = make(map[*Subscription]struct{})
+	}
+	b.subscribers[topic][sub] = struct{}{}
+
+	return sub, nil
+}
@@ -0,0 +1,98 @@
+package broker
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMemoryPublish(t *testing.T) {
+	b := NewMemoryBroker()
+
+	first, err := b.Subscribe("post:1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer first.Close()
+	second, err := b.Subscribe("post:1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer second.Close()
+	other, err := b.Subscribe("post:2")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer other.Close()
+
+	if err = b.Publish("post:1", []byte("hi")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for i, sub := range []*Subscription{first, second} {
+		select {
+		case message := <-sub.Messages():
+			if string(message) != "hi" {
+				t.Errorf("[%d] expected the message, got: %s", i, message)
+			}
+		default:
+			t.Errorf("[%d] expected the message to be delivered", i)
+		}
+	}
+
+	select {
+	case message := <-other.Messages():
+		t.Errorf("expected nothing on the other topic, got: %s", message)
+	default:
+	}
+}
+
+func TestMemoryClose(t *testing.T) {
+	b := NewMemoryBroker()
+
+	sub, err := b.Subscribe("post:1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err = b.Publish("post:1", []byte("before")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	sub.Close()
+	sub.Close()
+	if err = b.Publish("post:1", []byte("after")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if message, ok := <-sub.Messages(); !ok || string(message) != "before" {
+		t.Errorf("expected the message received before the close, got: %s, %t", message, ok)
+	}
+	if _, ok := <-sub.Messages(); ok {
+		t.Errorf("expected the messages to end after the close")
+	}
+	if _, ok := b.subscribers["post:1"]; ok {
+		t.Errorf("expected the topic without subscribers to be dropped")
+	}
+}
+
+func TestMemorySlowSubscriber(t *testing.T) {
+	b := NewMemoryBroker()
+
+	sub, err := b.Subscribe("post:1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer sub.Close()
+
+	for i := 0; i < subscriptionBuffer+10; i++ {
+		if err = b.Publish("post:1", []byte(fmt.Sprint(i))); err != nil {
+			t.Fatalf("[%d] unexpected error: %s", i, err)
+		}
+	}
+
+	if received := len(sub.Messages()); received != subscriptionBuffer {
+		t.Errorf("expected the messages over the buffer to be dropped, got: %d", received)
+	}
+	if message := <-sub.Messages(); string(message) != "0" {
+		t.Errorf("expected the oldest message first, got: %s", message)
+	}
+}
@@ -0,0 +1,103 @@
+package broker
+
+import (
+	"github.com/gomodule/redigo/redis"
+	"github.com/sirupsen/logrus"
+	"strings"
+	"sync"
+	"time"
+)
+
+const reconnectDelay = time.Second
+
+// redisBroker publishes through redis, so every instance gets the messages of the others.
+// A single pattern subscription per process receives them and hands them to the local subscribers.
+type redisBroker struct {
+	pool      *redis.Pool
+	namespace string
+	local     *memoryBroker
+
+	mutex  sync.Mutex
+	conn   redis.Conn
+	closed bool
+}
+
+func NewRedisBroker(pool *redis.Pool, namespace string) *redisBroker {
+	b := &redisBroker{pool: pool, namespace: namespace, local: NewMemoryBroker()}
+	go b.listen()
+	return b
+}
+
+func (b *redisBroker) channel(topic string) string {
+	return b.namespace + ":" + topic
+}
+
+func (b *redisBroker) Publish(topic string, payload []byte) error {
+	conn := b.pool.Get()
+	defer conn.Close()
+
+	_, err := conn.Do("PUBLISH", b.channel(topic), payload)
+	return err
+}
+
+func (b *redisBroker) Subscribe(topic string) (*Subscription, error) {
+	return b.local.Subscribe(topic)
+}
+
+// listen keeps the pattern subscription, the messages published while it reconnects are lost
+func (b *redisBroker) listen() {
+	for {
+		b.mutex.Lock()
+		if b.closed {
+			b.mutex.Unlock()
+			return
+		}
+		b.conn = b.pool.Get()
+		psc := redis.PubSubConn{Conn: b.conn}
+		b.mutex.Unlock()
+
+		if err := b.receive(psc); err != nil && !b.isClosed() {
+			logrus.Errorf("redis broker: %s", err)
+			time.Sleep(reconnectDelay)
+		}
+		if err := psc.Close(); err != nil && !b.isClosed() {
+			logrus.Errorln(err)
+		}
+	}
+}
+
+func (b *redisBroker) receive(psc redis.PubSubConn) error {
+	if err := psc.PSubscribe(b.channel("*")); err != nil {
+		return err
+	}
+
+	prefix := b.channel("")
+	for {
+		switch v := psc.Receive().(type) {
+		case redis.Message:
+			if err := b.local.Publish(strings.TrimPrefix(v.Channel, prefix), v.Data); err != nil {
+				logrus.Errorln(err)
+			}
+		case error:
+			return v
+		}
+	}
+}
+
+func (b *redisBroker) isClosed() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.closed
+}
+
+// Close stops the listening, the local subscriptions are left to their owners
+func (b *redisBroker) Close() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.closed = true
+	if b.conn == nil {
+		return nil
+	}
+	return b.conn.Close()
+}
@@ -1,8 +1,16 @@
 package cookie
 
+import "errors"
+
+var (
+	ErrNotFound = errors.New("cookie not found")
+)
+
 type storage interface {
-	Add(mkey string, serialized []byte) error
+	Add(userID string, mkey string, serialized []byte) error
 	Get(mkey string) ([]byte, error)
+	Delete(userID string, mkey string) error
+	DeleteAll(userID string) error
 }
 
 type Manager struct {
@@ -13,10 +21,18 @@ func NewManager(storage storage) Manager {
 	return Manager{storage: storage}
 }
 
-func (m Manager) AddCookie(mkey string, serialized []byte) error {
-	return m.storage.Add(mkey, serialized)
+func (m Manager) AddCookie(userID string, mkey string, serialized []byte) error {
+	return m.storage.Add(userID, mkey, serialized)
 }
 
 func (m Manager) GetCookie(mkey string) ([]byte, error) {
 	return m.storage.Get(mkey)
-}
\ No newline at end of file
+}
+
+func (m Manager) DeleteCookie(userID string, mkey string) error {
+	return m.storage.Delete(userID, mkey)
+}
+
+func (m Manager) DeleteUserCookies(userID string) error {
+	return m.storage.DeleteAll(userID)
+}
@@ -1,18 +1,76 @@
 package cookie
 
+import "sync"
+
 type mapStorage struct {
+	mutex   sync.RWMutex
 	storage map[string][]byte
+	users   map[string]map[string]struct{}
 }
 
 func NewMapStorage() *mapStorage {
-	return &mapStorage{storage: make(map[string][]byte)}
+	return &mapStorage{
+		storage: make(map[string][]byte),
+		users:   make(map[string]map[string]struct{}),
+	}
 }
 
-func (s *mapStorage) Add(mkey string, serialized []byte) error {
+func (s *mapStorage) Add(userID string, mkey string, serialized []byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	s.storage[mkey] = serialized
+	if _, ok := s.users[userID]; !ok {
+		s.users[userID] = make(map[string]struct{})
+	}
+	s.users[userID][mkey] = struct{}{}
 	return nil
 }
 
+// AddIfAbsent stores the value only when the key is free and tells whether it did
+func (s *mapStorage) AddIfAbsent(userID string, mkey string, serialized []byte) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.storage[mkey]; ok {
+		return false, nil
+	}
+
+	s.storage[mkey] = serialized
+	if _, ok := s.users[userID]; !ok {
+		s.users[userID] = make(map[string]struct{})
+	}
+	s.users[userID][mkey] = struct{}{}
+	return true, nil
+}
+
 func (s *mapStorage) Get(mkey string) ([]byte, error) {
-	return s.storage[mkey], nil
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	serialized, ok := s.storage[mkey]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return serialized, nil
+}
+
+func (s *mapStorage) Delete(userID string, mkey string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.storage, mkey)
+	delete(s.users[userID], mkey)
+	return nil
+}
+
+func (s *mapStorage) DeleteAll(userID string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for mkey := range s.users[userID] {
+		delete(s.storage, mkey)
+	}
+	delete(s.users, userID)
+	return nil
 }
@@ -3,25 +3,119 @@ package cookie
 import "github.com/gomodule/redigo/redis"
 
 type redisStorage struct {
-	conn redis.Conn
+	pool      *redis.Pool
+	namespace string
+	ttl       int
 }
 
-func NewRedisStorage(conn redis.Conn) *redisStorage {
-	return &redisStorage{conn: conn}
+func NewRedisStorage(pool *redis.Pool, namespace string, ttl int) *redisStorage {
+	return &redisStorage{pool: pool, namespace: namespace, ttl: ttl}
 }
 
-func (s *redisStorage) Add(mkey string, serialized []byte) error {
-	_, err := s.conn.Do("SET", mkey, serialized, "EX", 86400)
-	if err != nil {
+func (s *redisStorage) key(mkey string) string {
+	return s.namespace + ":" + mkey
+}
+
+func (s *redisStorage) userKey(userID string) string {
+	return s.namespace + ":user:" + userID
+}
+
+func (s *redisStorage) Add(userID string, mkey string, serialized []byte) error {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	if err := conn.Send("MULTI"); err != nil {
+		return err
+	}
+	if err := conn.Send("SET", s.key(mkey), serialized, "EX", s.ttl); err != nil {
+		return err
+	}
+	if err := conn.Send("SADD", s.userKey(userID), mkey); err != nil {
+		return err
+	}
+	if err := conn.Send("EXPIRE", s.userKey(userID), s.ttl); err != nil {
 		return err
 	}
-	return nil
+	_, err := conn.Do("EXEC")
+	return err
+}
+
+// AddIfAbsent stores the value only when the key is free and tells whether it did, the check and the write are one command
+func (s *redisStorage) AddIfAbsent(userID string, mkey string, serialized []byte) (bool, error) {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	if err := conn.Send("MULTI"); err != nil {
+		return false, err
+	}
+	if err := conn.Send("SET", s.key(mkey), serialized, "EX", s.ttl, "NX"); err != nil {
+		return false, err
+	}
+	if err := conn.Send("SADD", s.userKey(userID), mkey); err != nil {
+		return false, err
+	}
+	if err := conn.Send("EXPIRE", s.userKey(userID), s.ttl); err != nil {
+		return false, err
+	}
+	replies, err := redis.Values(conn.Do("EXEC"))
+	if err != nil {
+		return false, err
+	}
+	// SET with NX replies nil when the key is taken
+	return replies[0] != nil, nil
 }
 
 func (s *redisStorage) Get(mkey string) ([]byte, error) {
-	data, err := s.conn.Do("GET", mkey)
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	data, err := redis.Bytes(conn.Do("GET", s.key(mkey)))
+	if err == redis.ErrNil {
+		return nil, ErrNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
-	return data.([]byte), nil
+	return data, nil
+}
+
+func (s *redisStorage) Delete(userID string, mkey string) error {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	if err := conn.Send("MULTI"); err != nil {
+		return err
+	}
+	if err := conn.Send("DEL", s.key(mkey)); err != nil {
+		return err
+	}
+	if err := conn.Send("SREM", s.userKey(userID), mkey); err != nil {
+		return err
+	}
+	_, err := conn.Do("EXEC")
+	return err
+}
+
+func (s *redisStorage) DeleteAll(userID string) error {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	mkeys, err := redis.Strings(conn.Do("SMEMBERS", s.userKey(userID)))
+	if err != nil {
+		return err
+	}
+
+	if err = conn.Send("MULTI"); err != nil {
+		return err
+	}
+	for _, mkey := range mkeys {
+		if err = conn.Send("DEL", s.key(mkey)); err != nil {
+			return err
+		}
+	}
+	if err = conn.Send("DEL", s.userKey(userID)); err != nil {
+		return err
+	}
+	_, err = conn.Do("EXEC")
+	return err
 }
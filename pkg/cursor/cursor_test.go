@@ -0,0 +1,67 @@
+package cursor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type position struct {
+	Sort    string  `json:"sort"`
+	Created string  `json:"created,omitempty"`
+	Rank    float64 `json:"rank,omitempty"`
+	ID      string  `json:"id"`
+}
+
+func TestRoundTrip(t *testing.T) {
+	cases := []position{
+		{Sort: "new", Created: "2022-04-10T12:00:00.000Z", ID: "1"},
+		{Sort: "hot", Rank: 12.0625, ID: "61f3b2c8a4e5d6f7a8b9c0d1"},
+		{Sort: "top", Rank: -3, ID: "2"},
+		{ID: "plus+slash/equals="},
+	}
+
+	for i, item := range cases {
+		encoded, err := Encode(item)
+		if err != nil {
+			t.Fatalf("[%d] unexpected error: %s", i, err)
+		}
+		if strings.ContainsAny(encoded, "+/=") {
+			t.Errorf("[%d] expected a url-safe cursor, got: %s", i, encoded)
+		}
+
+		var decoded position
+		if err = Decode(encoded, &decoded); err != nil {
+			t.Fatalf("[%d] unexpected error: %s", i, err)
+		}
+		if !reflect.DeepEqual(item, decoded) {
+			t.Errorf("[%d] expected position: %+v, got: %+v", i, item, decoded)
+		}
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	notJSON, err := Encode("plain string")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	cases := []string{
+		"not base64!",
+		"eyJzb3J0Ijoi",
+		notJSON,
+	}
+
+	for i, encoded := range cases {
+		var decoded position
+		if err = Decode(encoded, &decoded); err != ErrInvalidCursor {
+			t.Errorf("[%d] expected the invalid cursor error, got: %v", i, err)
+		}
+	}
+}
+
+func TestEncodeUnsupported(t *testing.T) {
+	if _, err := Encode(make(chan int)); err == nil {
+		t.Errorf("expected an error for a position which is not json")
+	}
+}
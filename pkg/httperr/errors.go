@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 )
 
@@ -31,14 +32,34 @@ func (e Unauthorized) Error() string {
 	return fmt.Sprintf("unauthorized: %s", e.Message)
 }
 
+// Forbidden carries the expiry of a ban, a permanent one has none
 type Forbidden struct {
 	Message string `json:"message"`
+	Expires string `json:"expires,omitempty"`
 }
 
 func (e Forbidden) Error() string {
 	return fmt.Sprintf("forbidden: %s", e.Message)
 }
 
+type Conflict struct {
+	Message string `json:"message"`
+}
+
+func (e Conflict) Error() string {
+	return fmt.Sprintf("conflict: %s", e.Message)
+}
+
+// TooManyRequests tells the client when to retry in the Retry-After header
+type TooManyRequests struct {
+	Message    string `json:"message"`
+	RetryAfter int    `json:"-"`
+}
+
+func (e TooManyRequests) Error() string {
+	return fmt.Sprintf("too many requests: %s", e.Message)
+}
+
 type UnprocessableEntityItem struct {
 	Location string `json:"location"`
 	Param    string `json:"param"`
@@ -89,6 +110,13 @@ func HandleError(w http.ResponseWriter, inputErr error) {
 	case Forbidden:
 		resp, err = json.Marshal(inputErr.(Forbidden))
 		statusCode = http.StatusForbidden
+	case Conflict:
+		resp, err = json.Marshal(inputErr.(Conflict))
+		statusCode = http.StatusConflict
+	case TooManyRequests:
+		resp, err = json.Marshal(inputErr.(TooManyRequests))
+		statusCode = http.StatusTooManyRequests
+		w.Header().Set("Retry-After", strconv.Itoa(inputErr.(TooManyRequests).RetryAfter))
 	case UnprocessableEntity:
 		resp, err = json.Marshal(inputErr.(UnprocessableEntity))
 		statusCode = http.StatusUnprocessableEntity
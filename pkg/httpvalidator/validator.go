@@ -26,15 +26,19 @@ type Bodies map[string]RequestBody
 
 type PathValues map[string][]Rule
 
+type QueryValues map[string][]Rule
+
 type Validator struct {
-	BodyTemplates      Bodies
-	PathValueTemplates PathValues
+	BodyTemplates       Bodies
+	PathValueTemplates  PathValues
+	QueryValueTemplates QueryValues
 }
 
 func NewValidator() Validator {
 	return Validator{
-		BodyTemplates:      make(map[string]RequestBody),
-		PathValueTemplates: make(map[string][]Rule),
+		BodyTemplates:       make(map[string]RequestBody),
+		PathValueTemplates:  make(map[string][]Rule),
+		QueryValueTemplates: make(map[string][]Rule),
 	}
 }
 
@@ -46,6 +50,10 @@ func (v *Validator) AddPathValueTemplate(templateName string, rules []Rule) {
 	v.PathValueTemplates[templateName] = rules
 }
 
+func (v *Validator) AddQueryValueTemplate(templateName string, rules []Rule) {
+	v.QueryValueTemplates[templateName] = rules
+}
+
 type ValidationError struct {
 	Location string
 	Param    string
@@ -87,3 +95,21 @@ func (v *Validator) ValidatePathValue(param string, value string) []ValidationEr
 
 	return response
 }
+
+func (v *Validator) ValidateQueryValue(param string, value string) []ValidationError {
+	return v.ValidateQueryValueAs(param, param, value)
+}
+
+// ValidateQueryValueAs checks the value against a template named apart from the param,
+// for the params which mean different things on different endpoints
+func (v *Validator) ValidateQueryValueAs(templateName string, param string, value string) []ValidationError {
+	response := make([]ValidationError, 0)
+
+	for _, rule := range v.QueryValueTemplates[templateName] {
+		if !rule.Validate(value) {
+			response = append(response, ValidationError{Location: "query", Param: param, Value: value, Message: rule.Description})
+		}
+	}
+
+	return response
+}
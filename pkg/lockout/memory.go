@@ -0,0 +1,91 @@
+package lockout
+
+import (
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the expired keys are dropped
+const sweepInterval = time.Minute
+
+type memoryEntry struct {
+	failures      int
+	failuresUntil time.Time
+	lockedUntil   time.Time
+}
+
+// memoryStore counts the failures in a map, a restart forgets them and lifts every lock
+type memoryStore struct {
+	mutex   sync.Mutex
+	entries map[string]*memoryEntry
+	swept   time.Time
+}
+
+func NewMemoryStore() *memoryStore {
+	return &memoryStore{entries: make(map[string]*memoryEntry)}
+}
+
+// entry drops the failures and the lock which have expired
+func (s *memoryStore) entry(key string, now time.Time) *memoryEntry {
+	if now.Sub(s.swept) >= sweepInterval {
+		s.swept = now
+		for k, e := range s.entries {
+			if !e.failuresUntil.After(now) && !e.lockedUntil.After(now) {
+				delete(s.entries, k)
+			}
+		}
+	}
+
+	e, ok := s.entries[key]
+	if !ok {
+		e = &memoryEntry{}
+		s.entries[key] = e
+	}
+	if !e.failuresUntil.After(now) {
+		e.failures = 0
+	}
+	return e
+}
+
+// AddFailure counts the failed login, the count is forgotten after ttl without failures
+func (s *memoryStore) AddFailure(key string, ttl time.Duration) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	e := s.entry(key, now)
+	e.failures++
+	e.failuresUntil = now.Add(ttl)
+	return e.failures, nil
+}
+
+func (s *memoryStore) Lock(key string, duration time.Duration) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	s.entry(key, now).lockedUntil = now.Add(duration)
+	return nil
+}
+
+// LockedFor is zero when the key is not locked
+func (s *memoryStore) LockedFor(key string) (time.Duration, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	lockedUntil := s.entry(key, now).lockedUntil
+	if !lockedUntil.After(now) {
+		return 0, nil
+	}
+	return lockedUntil.Sub(now), nil
+}
+
+// Reset forgets the failures and lifts the lock
+func (s *memoryStore) Reset(key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
@@ -0,0 +1,94 @@
+package lockout
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryFailuresTTL(t *testing.T) {
+	store := NewMemoryStore()
+	ttl := 100 * time.Millisecond
+
+	for i := 1; i <= 3; i++ {
+		failures, err := store.AddFailure("user:van", ttl)
+		if err != nil || failures != i {
+			t.Fatalf("[%d] expected %d failures, got: %d, %v", i, i, failures, err)
+		}
+		time.Sleep(ttl / 2)
+	}
+
+	if failures, _ := store.AddFailure("user:ivan", ttl); failures != 1 {
+		t.Errorf("expected the other key to count on its own, got: %d", failures)
+	}
+
+	// the ttl restarts with every failure, the count is forgotten only after a quiet ttl
+	time.Sleep(ttl + ttl/2)
+	if failures, _ := store.AddFailure("user:van", ttl); failures != 1 {
+		t.Errorf("expected the failures to be forgotten after the ttl, got: %d", failures)
+	}
+}
+
+func TestMemoryLock(t *testing.T) {
+	store := NewMemoryStore()
+
+	lockedFor, err := store.LockedFor("user:van")
+	if err != nil || lockedFor != 0 {
+		t.Fatalf("expected no lock, got: %s, %v", lockedFor, err)
+	}
+
+	if err = store.Lock("user:van", 50*time.Millisecond); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if lockedFor, _ = store.LockedFor("user:van"); lockedFor <= 0 || lockedFor > 50*time.Millisecond {
+		t.Errorf("expected the lock for up to 50ms, got: %s", lockedFor)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if lockedFor, _ = store.LockedFor("user:van"); lockedFor != 0 {
+		t.Errorf("expected the lock to expire, got: %s", lockedFor)
+	}
+}
+
+func TestMemoryReset(t *testing.T) {
+	store := NewMemoryStore()
+
+	if _, err := store.AddFailure("user:van", time.Hour); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := store.Lock("user:van", time.Hour); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := store.Reset("user:van"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if lockedFor, _ := store.LockedFor("user:van"); lockedFor != 0 {
+		t.Errorf("expected the reset to lift the lock, got: %s", lockedFor)
+	}
+	if failures, _ := store.AddFailure("user:van", time.Hour); failures != 1 {
+		t.Errorf("expected the reset to forget the failures, got: %d", failures)
+	}
+}
+
+func TestMemorySweep(t *testing.T) {
+	store := NewMemoryStore()
+
+	if _, err := store.AddFailure("user:van", 10*time.Millisecond); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := store.Lock("user:ivan", time.Hour); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	store.swept = time.Time{}
+	if _, err := store.LockedFor("user:other"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, ok := store.entries["user:van"]; ok {
+		t.Errorf("expected the expired failures to be dropped")
+	}
+	if _, ok := store.entries["user:ivan"]; !ok {
+		t.Errorf("expected the lock to stay")
+	}
+}
@@ -0,0 +1,75 @@
+package lockout
+
+import (
+	"github.com/gomodule/redigo/redis"
+	"time"
+)
+
+// redisStore shares the failed logins between the instances, the failures and the lock expire by themselves
+type redisStore struct {
+	pool      *redis.Pool
+	namespace string
+}
+
+func NewRedisStore(pool *redis.Pool, namespace string) *redisStore {
+	return &redisStore{pool: pool, namespace: namespace}
+}
+
+func (s *redisStore) failuresKey(key string) string {
+	return s.namespace + ":failures:" + key
+}
+
+func (s *redisStore) lockKey(key string) string {
+	return s.namespace + ":lock:" + key
+}
+
+func (s *redisStore) AddFailure(key string, ttl time.Duration) (int, error) {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	if err := conn.Send("MULTI"); err != nil {
+		return 0, err
+	}
+	if err := conn.Send("INCR", s.failuresKey(key)); err != nil {
+		return 0, err
+	}
+	if err := conn.Send("PEXPIRE", s.failuresKey(key), ttl.Milliseconds()); err != nil {
+		return 0, err
+	}
+	replies, err := redis.Values(conn.Do("EXEC"))
+	if err != nil {
+		return 0, err
+	}
+	return redis.Int(replies[0], nil)
+}
+
+func (s *redisStore) Lock(key string, duration time.Duration) error {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	_, err := conn.Do("SET", s.lockKey(key), 1, "PX", duration.Milliseconds())
+	return err
+}
+
+func (s *redisStore) LockedFor(key string) (time.Duration, error) {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	ttl, err := redis.Int64(conn.Do("PTTL", s.lockKey(key)))
+	if err != nil {
+		return 0, err
+	}
+	// the negative ttl means there is no lock
+	if ttl <= 0 {
+		return 0, nil
+	}
+	return time.Duration(ttl) * time.Millisecond, nil
+}
+
+func (s *redisStore) Reset(key string) error {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	_, err := conn.Do("DEL", s.failuresKey(key), s.lockKey(key))
+	return err
+}
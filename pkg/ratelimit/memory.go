@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the keys without recent requests are dropped
+const sweepInterval = time.Minute
+
+type memoryWindow struct {
+	hits   []time.Time
+	window time.Duration
+}
+
+// memoryStore keeps the request times of every key in memory, each process counts its own requests
+type memoryStore struct {
+	mutex   sync.Mutex
+	windows map[string]*memoryWindow
+	swept   time.Time
+}
+
+func NewMemoryStore() *memoryStore {
+	return &memoryStore{windows: make(map[string]*memoryWindow)}
+}
+
+// Allow records the request when it fits the limit, otherwise it tells how long to wait
+func (s *memoryStore) Allow(key string, limit Limit) (bool, time.Duration, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	w, ok := s.windows[key]
+	if !ok {
+		w = &memoryWindow{}
+		s.windows[key] = w
+	}
+	w.window = limit.Window
+
+	start := now.Add(-limit.Window)
+	kept := 0
+	for kept < len(w.hits) && !w.hits[kept].After(start) {
+		kept++
+	}
+	w.hits = w.hits[kept:]
+
+	if len(w.hits) >= limit.Requests {
+		return false, w.hits[0].Add(limit.Window).Sub(now), nil
+	}
+
+	w.hits = append(w.hits, now)
+	return true, 0, nil
+}
+
+func (s *memoryStore) sweep(now time.Time) {
+	if now.Sub(s.swept) < sweepInterval {
+		return
+	}
+	s.swept = now
+
+	for key, w := range s.windows {
+		if len(w.hits) == 0 || !w.hits[len(w.hits)-1].After(now.Add(-w.window)) {
+			delete(s.windows, key)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemorySlidingWindow(t *testing.T) {
+	store := NewMemoryStore()
+	limit := Limit{Requests: 2, Window: 300 * time.Millisecond}
+
+	for i := 0; i < limit.Requests; i++ {
+		if allowed, _, err := store.Allow("user:1", limit); err != nil || !allowed {
+			t.Fatalf("[%d] expected the request to pass, got: %t, %v", i, allowed, err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	allowed, retryAfter, err := store.Allow("user:1", limit)
+	if err != nil || allowed {
+		t.Fatalf("expected the request over the limit to wait, got: %t, %v", allowed, err)
+	}
+	if retryAfter <= 0 || retryAfter > limit.Window-100*time.Millisecond {
+		t.Errorf("expected the wait until the first request leaves the window, got: %s", retryAfter)
+	}
+
+	if allowed, _, _ = store.Allow("user:2", limit); !allowed {
+		t.Errorf("expected the other key to keep its own budget")
+	}
+
+	// the first request leaves the window, the second one still counts
+	time.Sleep(retryAfter + 5*time.Millisecond)
+	if allowed, _, _ = store.Allow("user:1", limit); !allowed {
+		t.Errorf("expected the request to pass once the first one left the window")
+	}
+	if allowed, _, _ = store.Allow("user:1", limit); allowed {
+		t.Errorf("expected the window to hold the second and the third requests")
+	}
+}
+
+func TestMemorySweep(t *testing.T) {
+	store := NewMemoryStore()
+	limit := Limit{Requests: 1, Window: 10 * time.Millisecond}
+
+	if allowed, _, _ := store.Allow("user:1", limit); !allowed {
+		t.Fatalf("expected the first request to pass")
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	store.swept = time.Time{}
+	if allowed, _, _ := store.Allow("user:2", limit); !allowed {
+		t.Fatalf("expected the first request to pass")
+	}
+	if _, ok := store.windows["user:1"]; ok {
+		t.Errorf("expected the idle key to be dropped")
+	}
+	if _, ok := store.windows["user:2"]; !ok {
+		t.Errorf("expected the active key to stay")
+	}
+}
@@ -0,0 +1,9 @@
+package ratelimit
+
+import "time"
+
+// Limit lets Requests requests through in any Window, the window slides with every request
+type Limit struct {
+	Requests int
+	Window   time.Duration
+}
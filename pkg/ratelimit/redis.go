@@ -0,0 +1,58 @@
+package ratelimit
+
+import (
+	"github.com/gomodule/redigo/redis"
+	"redditclone/pkg/hexid"
+	"time"
+)
+
+// slidingWindow keeps the request times of a key in a sorted set, the requests older than the window are dropped.
+// It returns zero when the request is recorded, otherwise the milliseconds until the oldest request leaves the window.
+var slidingWindow = redis.NewScript(1, `
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+if redis.call('ZCARD', KEYS[1]) < tonumber(ARGV[3]) then
+	redis.call('ZADD', KEYS[1], now, ARGV[4])
+	redis.call('PEXPIRE', KEYS[1], window)
+	return 0
+end
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+return math.max(tonumber(oldest[2]) + window - now, 1)
+`)
+
+// redisStore shares the limits between the instances, the check and the record are one atomic script
+type redisStore struct {
+	pool      *redis.Pool
+	namespace string
+}
+
+func NewRedisStore(pool *redis.Pool, namespace string) *redisStore {
+	return &redisStore{pool: pool, namespace: namespace}
+}
+
+func (s *redisStore) key(key string) string {
+	return s.namespace + ":" + key
+}
+
+func (s *redisStore) Allow(key string, limit Limit) (bool, time.Duration, error) {
+	conn := s.pool.Get()
+	defer conn.Close()
+
+	// the member is unique, so the requests of the same millisecond are all counted
+	member, err := hexid.Generate()
+	if err != nil {
+		return false, 0, err
+	}
+
+	now := time.Now().UnixMilli()
+	wait, err := redis.Int64(slidingWindow.Do(conn, s.key(key), now, limit.Window.Milliseconds(), limit.Requests, member))
+	if err != nil {
+		return false, 0, err
+	}
+
+	if wait > 0 {
+		return false, time.Duration(wait) * time.Millisecond, nil
+	}
+	return true, 0, nil
+}
@@ -1,12 +1,12 @@
 package cookie
 
 import (
-	"crypto/md5"
+	"crypto/rand"
 	"encoding/base64"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
+	cookiestorage "redditclone/pkg/cookie"
 	"redditclone/pkg/session"
 )
 
@@ -14,35 +14,25 @@ const (
 	cookieName = "RedditcloneCookie"
 )
 
-type storage interface {
-	Add(mkey string, serialized []byte) error
-	Get(mkey string) ([]byte, error)
-	GetTTL() int
-}
-
 type manager struct {
-	storage storage
+	cookies cookiestorage.Manager
 	ttl     int
 }
 
-func NewManager(storage storage) *manager {
-	return &manager{storage: storage, ttl: storage.GetTTL()}
+func NewManager(cookies cookiestorage.Manager, ttl int) *manager {
+	return &manager{cookies: cookies, ttl: ttl}
 }
 
 func (m *manager) GetTokenFromRequest(r *http.Request) (session.Token, error) {
-	var cookie *http.Cookie
-	for _, c := range r.Cookies() {
-		cookie = c
-		break
-	}
-	if cookie == nil {
-		return session.Token{}, errors.New(fmt.Sprintf("cookie with name '%s' not found", cookieName))
+	cookie, err := r.Cookie(cookieName)
+	if err != nil {
+		return session.Token{}, fmt.Errorf("cookie with name '%s' not found", cookieName)
 	}
 	return session.Token{Value: cookie.Value}, nil
 }
 
 func (m *manager) GetUserByToken(token session.Token) (session.AuthUser, error) {
-	serialized, err := m.storage.Get(token.Value)
+	serialized, err := m.cookies.GetCookie(token.Value)
 	if err != nil {
 		return session.AuthUser{}, err
 	}
@@ -54,17 +44,18 @@ func (m *manager) GetUserByToken(token session.Token) (session.AuthUser, error)
 }
 
 func (m *manager) CreateToken(user session.AuthUser) (session.Token, error) {
-	idHash := md5.Sum([]byte(user.ID))
-	usernameHash := md5.Sum([]byte(user.ID))
-	wholeHash := md5.Sum(append(idHash[:], usernameHash[:]...))
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return session.Token{}, err
+	}
 
-	mkey := base64.StdEncoding.EncodeToString(wholeHash[:])
+	mkey := base64.RawURLEncoding.EncodeToString(b)
 	serialized, err := json.Marshal(user)
 	if err != nil {
 		return session.Token{}, err
 	}
 
-	err = m.storage.Add(mkey, serialized)
+	err = m.cookies.AddCookie(user.ID, mkey, serialized)
 	if err != nil {
 		return session.Token{}, err
 	}
@@ -74,10 +65,31 @@ func (m *manager) CreateToken(user session.AuthUser) (session.Token, error) {
 
 func (m *manager) WriteToken(w http.ResponseWriter, token session.Token) error {
 	cookie := &http.Cookie{
-		Name:   cookieName,
-		Value:  token.Value,
-		MaxAge: m.ttl,
+		Name:     cookieName,
+		Value:    token.Value,
+		Path:     "/",
+		MaxAge:   m.ttl,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
 	}
 	http.SetCookie(w, cookie)
+
+	if token.Refresh == "" {
+		return nil
+	}
+
+	resp := []byte(fmt.Sprintf("{\"refresh_token\": \"%s\"}", token.Refresh))
+	if _, err := w.Write(resp); err != nil {
+		return err
+	}
+
 	return nil
 }
+
+func (m *manager) DeleteToken(userID string, token session.Token) error {
+	return m.cookies.DeleteCookie(userID, token.Value)
+}
+
+func (m *manager) DeleteUserTokens(userID string) error {
+	return m.cookies.DeleteUserCookies(userID)
+}
@@ -2,18 +2,12 @@ package jwtsession
 
 import (
 	"errors"
-	"fmt"
 	"github.com/dgrijalva/jwt-go"
 	"net/http"
 	"redditclone/pkg/session"
-	"strings"
 	"time"
 )
 
-const (
-	authorizationHeader = "Authorization"
-)
-
 type tokenClaims struct {
 	User session.AuthUser `json:"user"`
 	jwt.StandardClaims
@@ -29,17 +23,7 @@ func NewManager(signingKey string, ttl int) *manager {
 }
 
 func (m *manager) GetTokenFromRequest(r *http.Request) (session.Token, error) {
-	header := r.Header.Get(authorizationHeader)
-	if header == "" {
-		return session.Token{}, errors.New("empty auth header")
-	}
-
-	headerParts := strings.Split(header, " ")
-	if len(headerParts) != 2 {
-		return session.Token{}, errors.New("invalid auth header")
-	}
-
-	return session.Token{Value: headerParts[1]}, nil
+	return session.GetBearerToken(r)
 }
 
 func (m *manager) GetUserByToken(token session.Token) (session.AuthUser, error) {
@@ -70,6 +54,7 @@ func (m *manager) CreateToken(user session.AuthUser) (session.Token, error) {
 		session.AuthUser{
 			Username: user.Username,
 			ID:       user.ID,
+			Role:     user.Role,
 		},
 
 		jwt.StandardClaims{
@@ -84,13 +69,15 @@ func (m *manager) CreateToken(user session.AuthUser) (session.Token, error) {
 }
 
 func (m *manager) WriteToken(w http.ResponseWriter, token session.Token) error {
-	resp := []byte(
-		fmt.Sprintf("{\"token\": \"%s\"}", token.Value),
-	)
+	return session.WriteTokenBody(w, token)
+}
 
-	if _, err := w.Write(resp); err != nil {
-		return err
-	}
+// DeleteToken is not supported, a stateless token stays valid until it expires,
+// so its ttl must be kept short and long-lived sessions rely on refresh tokens
+func (m *manager) DeleteToken(userID string, token session.Token) error {
+	return session.ErrNotRevocable
+}
 
-	return nil
+func (m *manager) DeleteUserTokens(userID string) error {
+	return session.ErrNotRevocable
 }
@@ -1,16 +1,29 @@
 package session
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
+)
+
+const (
+	authorizationHeader = "Authorization"
+)
+
+var (
+	ErrNotRevocable = errors.New("tokens of this session strategy can't be revoked")
 )
 
 type Token struct {
-	Value string
+	Value   string
+	Refresh string
 }
 
 type AuthUser struct {
 	ID       string `json:"id"`
 	Username string `json:"username"`
+	Role     string `json:"role"`
 }
 
 type Manager interface {
@@ -18,4 +31,35 @@ type Manager interface {
 	GetUserByToken(token Token) (AuthUser, error)
 	CreateToken(user AuthUser) (Token, error)
 	WriteToken(w http.ResponseWriter, token Token) error
+	DeleteToken(userID string, token Token) error
+	DeleteUserTokens(userID string) error
+}
+
+func GetBearerToken(r *http.Request) (Token, error) {
+	header := r.Header.Get(authorizationHeader)
+	if header == "" {
+		return Token{}, errors.New("empty auth header")
+	}
+
+	headerParts := strings.Split(header, " ")
+	if len(headerParts) != 2 {
+		return Token{}, errors.New("invalid auth header")
+	}
+
+	return Token{Value: headerParts[1]}, nil
+}
+
+func WriteTokenBody(w http.ResponseWriter, token Token) error {
+	var resp []byte
+	if token.Refresh == "" {
+		resp = []byte(fmt.Sprintf("{\"token\": \"%s\"}", token.Value))
+	} else {
+		resp = []byte(fmt.Sprintf("{\"token\": \"%s\", \"refresh_token\": \"%s\"}", token.Value, token.Refresh))
+	}
+
+	if _, err := w.Write(resp); err != nil {
+		return err
+	}
+
+	return nil
 }
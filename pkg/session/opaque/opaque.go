@@ -0,0 +1,66 @@
+package opaquesession
+
+import (
+	"encoding/json"
+	"net/http"
+	"redditclone/pkg/cookie"
+	"redditclone/pkg/session"
+	"redditclone/pkg/token"
+)
+
+// manager issues signed tokens, but trusts only those found in the server-side storage
+type manager struct {
+	signer  token.Signer
+	cookies cookie.Manager
+}
+
+func NewManager(signer token.Signer, cookies cookie.Manager) *manager {
+	return &manager{signer: signer, cookies: cookies}
+}
+
+func (m *manager) GetTokenFromRequest(r *http.Request) (session.Token, error) {
+	return session.GetBearerToken(r)
+}
+
+func (m *manager) GetUserByToken(t session.Token) (session.AuthUser, error) {
+	serialized, err := m.cookies.GetCookie(t.Value)
+	if err != nil {
+		return session.AuthUser{}, err
+	}
+
+	var user session.AuthUser
+	err = json.Unmarshal(serialized, &user)
+
+	return user, err
+}
+
+func (m *manager) CreateToken(user session.AuthUser) (session.Token, error) {
+	authUser := token.AuthUser{ID: user.ID, Username: user.Username, Role: user.Role}
+	t, err := m.signer.CreateToken(authUser)
+	if err != nil {
+		return session.Token{}, err
+	}
+
+	serialized, err := json.Marshal(user)
+	if err != nil {
+		return session.Token{}, err
+	}
+
+	if err = m.cookies.AddCookie(user.ID, t, serialized); err != nil {
+		return session.Token{}, err
+	}
+
+	return session.Token{Value: t}, nil
+}
+
+func (m *manager) WriteToken(w http.ResponseWriter, t session.Token) error {
+	return session.WriteTokenBody(w, t)
+}
+
+func (m *manager) DeleteToken(userID string, t session.Token) error {
+	return m.cookies.DeleteCookie(userID, t.Value)
+}
+
+func (m *manager) DeleteUserTokens(userID string) error {
+	return m.cookies.DeleteUserCookies(userID)
+}
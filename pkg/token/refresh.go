@@ -0,0 +1,173 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"redditclone/pkg/hexid"
+)
+
+var (
+	ErrRefreshTokenInvalid = errors.New("refresh token is invalid or expired")
+	ErrRefreshTokenReused  = errors.New("refresh token was already used, token family revoked")
+)
+
+type refreshStorage interface {
+	Add(userID string, mkey string, serialized []byte) error
+	AddIfAbsent(userID string, mkey string, serialized []byte) (bool, error)
+	Get(mkey string) ([]byte, error)
+	Delete(userID string, mkey string) error
+	DeleteAll(userID string) error
+}
+
+// refreshRecord is not changed after the token is issued, the rotation is marked by a key of its own
+type refreshRecord struct {
+	User   AuthUser `json:"user"`
+	Family string   `json:"family"`
+}
+
+// Refresher issues rotating refresh tokens. Every token belongs to a family started at login,
+// a family is indexed by its user and its tokens are indexed by the family,
+// so presenting an already rotated token revokes the whole family.
+type Refresher struct {
+	storage refreshStorage
+}
+
+func NewRefresher(storage refreshStorage) Refresher {
+	return Refresher{storage: storage}
+}
+
+func familyKey(family string) string {
+	return "family:" + family
+}
+
+func familyOwner(family string) string {
+	return "family-tokens:" + family
+}
+
+// tokenKey keeps raw refresh tokens out of the storage
+func tokenKey(refreshToken string) string {
+	hash := sha256.Sum256([]byte(refreshToken))
+	return "token:" + hex.EncodeToString(hash[:])
+}
+
+// usedKey marks a rotated token, the mark is set atomically so only one of concurrent rotations passes
+func usedKey(refreshToken string) string {
+	hash := sha256.Sum256([]byte(refreshToken))
+	return "used:" + hex.EncodeToString(hash[:])
+}
+
+func generateRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func (r Refresher) addToken(record refreshRecord) (string, error) {
+	refreshToken, err := generateRefreshToken()
+	if err != nil {
+		return "", err
+	}
+
+	serialized, err := json.Marshal(record)
+	if err != nil {
+		return "", err
+	}
+
+	if err = r.storage.Add(familyOwner(record.Family), tokenKey(refreshToken), serialized); err != nil {
+		return "", err
+	}
+
+	return refreshToken, nil
+}
+
+func (r Refresher) getRecord(refreshToken string) (refreshRecord, error) {
+	serialized, err := r.storage.Get(tokenKey(refreshToken))
+	if err != nil {
+		return refreshRecord{}, ErrRefreshTokenInvalid
+	}
+
+	var record refreshRecord
+	if err = json.Unmarshal(serialized, &record); err != nil {
+		return refreshRecord{}, err
+	}
+
+	if _, err = r.storage.Get(familyKey(record.Family)); err != nil {
+		return refreshRecord{}, ErrRefreshTokenInvalid
+	}
+
+	return record, nil
+}
+
+func (r Refresher) revokeFamily(userID string, family string) error {
+	if err := r.storage.Delete(userID, familyKey(family)); err != nil {
+		return err
+	}
+	return r.storage.DeleteAll(familyOwner(family))
+}
+
+func (r Refresher) Issue(usr AuthUser) (string, error) {
+	family, err := hexid.Generate()
+	if err != nil {
+		return "", err
+	}
+
+	if err = r.storage.Add(usr.ID, familyKey(family), []byte(usr.ID)); err != nil {
+		return "", err
+	}
+
+	return r.addToken(refreshRecord{User: usr, Family: family})
+}
+
+func (r Refresher) Rotate(refreshToken string) (AuthUser, string, error) {
+	record, err := r.getRecord(refreshToken)
+	if err != nil {
+		return AuthUser{}, "", err
+	}
+
+	marked, err := r.storage.AddIfAbsent(familyOwner(record.Family), usedKey(refreshToken), []byte("1"))
+	if err != nil {
+		return AuthUser{}, "", err
+	}
+
+	if !marked {
+		if err = r.revokeFamily(record.User.ID, record.Family); err != nil {
+			return AuthUser{}, "", err
+		}
+		return AuthUser{}, "", ErrRefreshTokenReused
+	}
+
+	next, err := r.addToken(refreshRecord{User: record.User, Family: record.Family})
+	if err != nil {
+		return AuthUser{}, "", err
+	}
+
+	return record.User, next, nil
+}
+
+// Revoke ignores the tokens of other users the same way as the invalid ones
+func (r Refresher) Revoke(refreshToken string, userID string) error {
+	record, err := r.getRecord(refreshToken)
+	if err == ErrRefreshTokenInvalid {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if record.User.ID != userID {
+		return nil
+	}
+
+	return r.revokeFamily(record.User.ID, record.Family)
+}
+
+// RevokeUser drops the family markers, tokens of the dropped families expire on their own
+func (r Refresher) RevokeUser(userID string) error {
+	return r.storage.DeleteAll(userID)
+}
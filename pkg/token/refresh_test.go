@@ -0,0 +1,112 @@
+package token
+
+import (
+	"redditclone/pkg/cookie"
+	"sync"
+	"testing"
+)
+
+var refreshUser = AuthUser{ID: "1", Username: "van", Role: "user"}
+
+func TestRefreshRotation(t *testing.T) {
+	refresher := NewRefresher(cookie.NewMapStorage())
+
+	issued, err := refresher.Issue(refreshUser)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	usr, rotated, err := refresher.Rotate(issued)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if usr != refreshUser || rotated == issued {
+		t.Errorf("expected a new token of the user, got: %+v, %s", usr, rotated)
+	}
+
+	if _, _, err = refresher.Rotate(issued); err != ErrRefreshTokenReused {
+		t.Errorf("expected the reuse to be detected, got: %v", err)
+	}
+	if _, _, err = refresher.Rotate(rotated); err != ErrRefreshTokenInvalid {
+		t.Errorf("expected the family to be revoked after the reuse, got: %v", err)
+	}
+
+	if _, _, err = refresher.Rotate("unknown"); err != ErrRefreshTokenInvalid {
+		t.Errorf("expected an unknown token to be invalid, got: %v", err)
+	}
+}
+
+func TestRefreshConcurrentRotation(t *testing.T) {
+	refresher := NewRefresher(cookie.NewMapStorage())
+
+	issued, err := refresher.Issue(refreshUser)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	const rotations = 10
+	errs := make(chan error, rotations)
+	wg := sync.WaitGroup{}
+	for i := 0; i < rotations; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := refresher.Rotate(issued)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	passed := 0
+	for err = range errs {
+		if err == nil {
+			passed++
+		} else if err != ErrRefreshTokenReused && err != ErrRefreshTokenInvalid {
+			t.Errorf("unexpected error: %s", err)
+		}
+	}
+	if passed != 1 {
+		t.Errorf("expected exactly one rotation to pass, got: %d", passed)
+	}
+}
+
+func TestRefreshRevoke(t *testing.T) {
+	refresher := NewRefresher(cookie.NewMapStorage())
+
+	first, err := refresher.Issue(refreshUser)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	second, err := refresher.Issue(refreshUser)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err = refresher.Revoke(first, "2"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, first, err = refresher.Rotate(first); err != nil {
+		t.Errorf("expected the token to stay after a revoke by another user, got: %s", err)
+	}
+
+	if err = refresher.Revoke(first, refreshUser.ID); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, _, err = refresher.Rotate(first); err != ErrRefreshTokenInvalid {
+		t.Errorf("expected the revoked token to be invalid, got: %v", err)
+	}
+	if err = refresher.Revoke(first, refreshUser.ID); err != nil {
+		t.Errorf("expected a repeated revoke to be ignored, got: %s", err)
+	}
+
+	if _, second, err = refresher.Rotate(second); err != nil {
+		t.Fatalf("expected the other family to stay, got: %s", err)
+	}
+	if err = refresher.RevokeUser(refreshUser.ID); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, _, err = refresher.Rotate(second); err != ErrRefreshTokenInvalid {
+		t.Errorf("expected every family of the user to be revoked, got: %v", err)
+	}
+}
@@ -3,24 +3,23 @@ package token
 import (
 	"errors"
 	"github.com/dgrijalva/jwt-go"
+	"redditclone/pkg/hexid"
 	"time"
 )
 
-var (
-	tokenTTL = 12 * time.Hour
-)
-
 type Signer struct {
 	signingKey string
+	ttl        time.Duration
 }
 
-func NewSigner(signingKey string) Signer {
-	return Signer{signingKey: signingKey}
+func NewSigner(signingKey string, ttl time.Duration) Signer {
+	return Signer{signingKey: signingKey, ttl: ttl}
 }
 
 type AuthUser struct {
 	ID       string `json:"id"`
 	Username string `json:"username"`
+	Role     string `json:"role"`
 }
 
 type tokenClaims struct {
@@ -29,15 +28,23 @@ type tokenClaims struct {
 }
 
 func (s Signer) CreateToken(usr AuthUser) (string, error) {
+	// unique ID keeps tokens issued within the same second apart
+	tokenID, err := hexid.Generate()
+	if err != nil {
+		return "", err
+	}
+
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &tokenClaims{
 		AuthUser{
 			Username: usr.Username,
 			ID:       usr.ID,
+			Role:     usr.Role,
 		},
 
 		jwt.StandardClaims{
+			Id:        tokenID,
 			IssuedAt:  time.Now().Unix(),
-			ExpiresAt: time.Now().Add(tokenTTL).Unix(),
+			ExpiresAt: time.Now().Add(s.ttl).Unix(),
 		},
 	})
 